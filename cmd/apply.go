@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyFile   string
+	applyPrune  bool
+	applyDryRun bool
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply -f <file>",
+	Short: "Make the configured servers match a file of definitions",
+	Long: `Treat a file of server definitions as the desired state of your configuration.
+The file uses the config file's shape (servers under "mcpServers") in YAML or JSON.
+Servers missing from the configuration are added and changed ones are updated. With
+--prune, configured servers the file does not list are removed, and stopped first if
+running. Servers running in Claude keep their old definition until restarted.`,
+	Example: `  cmcp apply -f servers.yaml
+  cmcp apply -f servers.yaml --prune --dry-run`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if applyFile == "" {
+			return fmt.Errorf("-f is required")
+		}
+
+		var data []byte
+		var err error
+		if applyFile == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(applyFile)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", applyFile, err)
+		}
+		desired, err := config.ParseDesiredState(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", applyFile, err)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		changes := cfg.PlanApply(desired, applyPrune)
+
+		counts := make(map[string]int)
+		var restart []string
+		for _, change := range changes {
+			counts[change.Action]++
+			if change.Action == config.ApplyConfigured && !applyDryRun && builder.IsRunning(builder.ClaudeName(change.Name)) {
+				restart = append(restart, change.Name)
+			}
+		}
+
+		if !applyDryRun && counts[config.ApplyUnchanged] < len(changes) {
+			for _, change := range changes {
+				if change.Action != config.ApplyPruned || !builder.IsRunning(builder.ClaudeName(change.Name)) {
+					continue
+				}
+				if err := builder.StopServer(builder.ClaudeName(change.Name), false); err != nil {
+					ui.Failure.Printf("Warning: Failed to stop server '%s': %v\n", change.Name, err)
+				}
+			}
+			if err := cfg.Apply(desired, changes); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+		}
+
+		actionColors := map[string]*color.Color{
+			config.ApplyCreated:    ui.Success.Color(),
+			config.ApplyConfigured: ui.Warning.Color(),
+			config.ApplyUnchanged:  ui.Muted.Color(),
+			config.ApplyPruned:     ui.Failure.Color(),
+		}
+		suffix := ""
+		if applyDryRun {
+			suffix = " (dry run)"
+		}
+		for _, change := range changes {
+			fmt.Printf("server/%s %s%s\n", change.Name, actionColors[change.Action].Sprint(change.Action), suffix)
+		}
+
+		var summary []string
+		for _, action := range []string{config.ApplyCreated, config.ApplyConfigured, config.ApplyUnchanged, config.ApplyPruned} {
+			if counts[action] > 0 {
+				summary = append(summary, fmt.Sprintf("%d %s", counts[action], action))
+			}
+		}
+		if len(summary) > 0 {
+			ui.Muted.Println(strings.Join(summary, ", "))
+		}
+		if len(restart) > 0 {
+			ui.Muted.Printf("→ Running in Claude with the old definition: %s. Restart with 'cmcp stop' and 'cmcp start' to pick up the changes.\n",
+				strings.Join(restart, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	applyCmd.Flags().StringVarP(&applyFile, "filename", "f", "", "File of server definitions to apply (- for stdin)")
+	applyCmd.Flags().BoolVar(&applyPrune, "prune", false, "Remove configured servers the file does not list")
+	applyCmd.Flags().BoolVarP(&applyDryRun, "dry-run", "n", false, "Show what would change without saving")
+}
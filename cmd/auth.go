@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/auth"
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	authClientID  string
+	authScopes    []string
+	authDevice    bool
+	authNoBrowser bool
+	authTimeout   time.Duration
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Log in to remote servers that use OAuth",
+	Long: `Run the MCP OAuth login for remote servers and cache the tokens under
+~/.cmcp/tokens/. Cached tokens are refreshed when they expire and sent as the
+Authorization header when the server is started, inspected, or pinged.`,
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login <server-name>",
+	Short: "Log in to a remote server",
+	Long: `Log in to a remote server with OAuth. The authorization server is discovered from
+the server's metadata and cmcp registers itself as a client unless --client-id is given.
+By default the login page opens in the browser and the result is received on a local
+callback; use --device on machines without a browser.`,
+	Example: `  cmcp auth login linear
+  cmcp auth login internal-api --device --client-id cmcp-cli`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireTerminal("Logging in"); err != nil {
+			return err
+		}
+		serverName := args[0]
+		server, err := remoteServer(serverName)
+		if err != nil {
+			return err
+		}
+
+		ui.Info.Printf("Logging in to '%s'...\n", serverName)
+
+		ctx, cancel := context.WithTimeout(context.Background(), authTimeout)
+		defer cancel()
+		token, err := auth.Login(ctx, server.URL, auth.LoginOptions{
+			ClientID: authClientID,
+			Scopes:   authScopes,
+			Device:   authDevice,
+			OpenURL: func(authURL string) error {
+				fmt.Printf("Open this URL to log in:\n  %s\n", authURL)
+				if !authNoBrowser {
+					if err := openBrowser(authURL); err != nil {
+						ui.Warning.Printf("Could not open a browser: %v\n", err)
+					}
+				}
+				fmt.Println("Waiting for the login to complete...")
+				return nil
+			},
+			ShowCode: func(userCode, verificationURI string) {
+				fmt.Printf("Go to %s and enter the code:\n  %s\n", verificationURI, ui.Emphasis.Color().Sprint(userCode))
+				fmt.Println("Waiting for approval...")
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("login failed: %w", err)
+		}
+
+		if err := mcp.TokenStore().Save(token); err != nil {
+			return err
+		}
+		ui.Success.Printf("✓ Logged in to '%s'\n", serverName)
+		fmt.Printf("Run 'cmcp start %s' to start it with the new token.\n", serverName)
+		return nil
+	},
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:          "logout <server-name>",
+	Short:        "Remove the cached token of a remote server",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		server, err := remoteServer(args[0])
+		if err != nil {
+			return err
+		}
+		removed, err := mcp.TokenStore().Delete(server.URL)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			ui.Warning.Printf("Not logged in to '%s'\n", args[0])
+			return nil
+		}
+		ui.Success.Printf("✓ Logged out of '%s'\n", args[0])
+		return nil
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which remote servers have a cached token",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		store := mcp.TokenStore()
+		found := false
+		for _, name := range cfg.GetServerNames() {
+			server := cfg.MCPServers[name]
+			if !server.IsRemote() {
+				continue
+			}
+			found = true
+
+			token, err := store.Load(server.URL)
+			switch {
+			case err != nil:
+				fmt.Printf("%s %s %s\n", ui.Warning.Sprint("!"), name, ui.Muted.Sprint(err.Error()))
+			case token == nil:
+				fmt.Printf("%s %s %s\n", ui.Muted.Sprint("○"), name, ui.Muted.Sprint("not logged in"))
+			case token.Expired() && token.RefreshToken == "":
+				fmt.Printf("%s %s %s\n", ui.Warning.Sprint("●"), name, ui.Warning.Sprint("expired, log in again"))
+			case token.ExpiresAt.IsZero():
+				fmt.Printf("%s %s\n", ui.Success.Sprint("●"), name)
+			default:
+				fmt.Printf("%s %s %s\n", ui.Success.Sprint("●"), name, ui.Muted.Sprint("expires "+token.ExpiresAt.Local().Format(time.RFC822)))
+			}
+		}
+		if !found {
+			fmt.Println("No remote servers configured.")
+		}
+		return nil
+	},
+}
+
+// remoteServer looks up a configured server that is reached over a URL
+func remoteServer(name string) (*config.MCPServer, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	server, exists := cfg.FindServer(name)
+	if !exists {
+		return nil, &config.ServerNotFoundError{Name: name}
+	}
+	if !server.IsRemote() {
+		return nil, fmt.Errorf("server '%s' is not a remote server", name)
+	}
+	if strings.Contains(server.URL, "${") {
+		return nil, fmt.Errorf("server '%s' has an unresolved variable in its URL", name)
+	}
+	return server, nil
+}
+
+// openBrowser opens a URL in the user's default browser
+func openBrowser(url string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+	default:
+		return exec.Command("xdg-open", url).Start()
+	}
+}
+
+func init() {
+	authLoginCmd.Flags().StringVar(&authClientID, "client-id", "", "Pre-registered OAuth client ID (default: register dynamically)")
+	authLoginCmd.Flags().StringSliceVar(&authScopes, "scope", nil, "Scopes to request (default: the scopes the server advertises)")
+	authLoginCmd.Flags().BoolVar(&authDevice, "device", false, "Log in with a code on another device instead of a local browser")
+	authLoginCmd.Flags().BoolVar(&authNoBrowser, "no-browser", false, "Print the login URL without opening a browser")
+	authLoginCmd.Flags().DurationVar(&authTimeout, "timeout", 5*time.Minute, "How long to wait for the login to complete")
+
+	authCmd.AddCommand(authLoginCmd)
+	authCmd.AddCommand(authLogoutCmd)
+	authCmd.AddCommand(authStatusCmd)
+}
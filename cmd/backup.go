@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/lopezm94/cmcp/internal/backup"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// passphraseEnv supplies the secrets passphrase without a prompt
+const passphraseEnv = "CMCP_BACKUP_PASSPHRASE"
+
+var (
+	backupSecrets bool
+	backupForce   bool
+	backupYes     bool
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Bundle the config into one file and restore it on another machine",
+	Long: `Pack the config with its config.d fragments and environment overlays, every
+profile with its groups, the files they include, the selected profile and, with
+--secrets, the cached OAuth tokens of remote servers into one gzipped tarball, and
+unpack it on another machine in one step.
+
+Included files outside the config directory are not bundled, since restoring
+them could write anywhere; they are listed so they can be copied by hand.
+
+Tokens are encrypted with a passphrase, asked for or read from
+CMCP_BACKUP_PASSPHRASE; they are never written to the bundle in plain text.`,
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create <file>",
+	Short: "Write a backup bundle",
+	Example: `  cmcp backup create cmcp-backup.tar.gz
+  cmcp backup create cmcp-backup.tar.gz --secrets`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := args[0]
+		if _, err := os.Stat(file); err == nil && !backupForce {
+			return fmt.Errorf("%s already exists; pass --force to overwrite it", file)
+		}
+
+		var passphrase string
+		if backupSecrets {
+			var err error
+			if passphrase, err = askPassphrase(true); err != nil {
+				return err
+			}
+		}
+
+		out, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", file, err)
+		}
+		manifest, err := backup.Create(out, backup.DefaultLayout(), passphrase)
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(file)
+			return err
+		}
+
+		ui.Success.Printf("✓ Wrote %s\n", file)
+		printManifest(manifest)
+		if !backupSecrets {
+			ui.Muted.Println("OAuth tokens were left out; pass --secrets to include them encrypted.")
+		}
+		return nil
+	},
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Restore a backup bundle, replacing the current config and profiles",
+	Long: `Write the config, profiles and selected profile of a bundle, replacing the
+files that exist. The files that will be replaced are listed and must be
+confirmed. Encrypted OAuth tokens are restored with --secrets and the
+passphrase the bundle was created with.
+
+Servers already running in Claude are unchanged; start them from the restored
+config with 'cmcp start'.`,
+	Example: `  cmcp backup restore cmcp-backup.tar.gz
+  cmcp backup restore cmcp-backup.tar.gz --secrets --yes`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		in, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open bundle: %w", err)
+		}
+		bundle, err := backup.Read(in)
+		in.Close()
+		if err != nil {
+			return err
+		}
+
+		ui.Info.Printf("Bundle created %s\n", bundle.Manifest.Created.Local().Format("2006-01-02 15:04"))
+		printManifest(&bundle.Manifest)
+		if backupSecrets && bundle.Manifest.Secrets == 0 {
+			return errors.New("the bundle holds no OAuth tokens; restore it without --secrets")
+		}
+
+		layout := backup.DefaultLayout()
+		var existing []string
+		for _, file := range bundle.Destinations(layout) {
+			if _, err := os.Stat(file); err == nil {
+				existing = append(existing, file)
+			}
+		}
+		if len(existing) > 0 {
+			ui.Warning.Println("\nThese files will be replaced:")
+			for _, file := range existing {
+				fmt.Printf("  • %s\n", file)
+			}
+			fmt.Println()
+			ok, err := confirm("Restore the bundle", backupYes)
+			if err != nil || !ok {
+				return err
+			}
+		}
+
+		var passphrase string
+		if backupSecrets {
+			if passphrase, err = askPassphrase(false); err != nil {
+				return err
+			}
+		}
+
+		written, err := bundle.Restore(layout, passphrase)
+		if err != nil {
+			return err
+		}
+		ui.Success.Printf("✓ Restored %d file(s)\n", len(written))
+		if bundle.Manifest.Secrets > 0 && !backupSecrets {
+			ui.Muted.Println("OAuth tokens were skipped; pass --secrets to restore them.")
+		}
+		ui.Muted.Println("Servers already running in Claude are unchanged; start them with 'cmcp start'.")
+		return nil
+	},
+}
+
+// printManifest summarizes what a bundle holds
+func printManifest(manifest *backup.Manifest) {
+	profiles := strings.Join(manifest.Profiles, ", ")
+	if profiles == "" {
+		profiles = "none"
+	}
+	fmt.Printf("  Profiles:   %s\n", profiles)
+	if manifest.ActiveProfile != "" {
+		fmt.Printf("  Active:     %s\n", manifest.ActiveProfile)
+	}
+	fmt.Printf("  Tokens:     %d\n", manifest.Secrets)
+	if len(manifest.Unbundled) > 0 {
+		ui.Warning.Println("Included files outside the config directory are not in the bundle; copy them yourself:")
+		for _, file := range manifest.Unbundled {
+			fmt.Printf("  • %s\n", file)
+		}
+	}
+}
+
+// askPassphrase returns the secrets passphrase from CMCP_BACKUP_PASSPHRASE or a
+// prompt. A new passphrase is asked for twice.
+func askPassphrase(confirmIt bool) (string, error) {
+	if value := os.Getenv(passphraseEnv); value != "" {
+		return value, nil
+	}
+	if !ui.IsInteractive() {
+		return "", fmt.Errorf("a passphrase is required for --secrets; set %s in non-interactive mode", passphraseEnv)
+	}
+
+	var passphrase string
+	if err := survey.AskOne(&survey.Password{Message: "Passphrase:"}, &passphrase, survey.WithValidator(survey.Required)); err != nil {
+		return "", err
+	}
+	if confirmIt {
+		var again string
+		if err := survey.AskOne(&survey.Password{Message: "Repeat passphrase:"}, &again); err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+func init() {
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+
+	backupCreateCmd.Flags().BoolVar(&backupSecrets, "secrets", false, "Include cached OAuth tokens, encrypted with a passphrase")
+	backupCreateCmd.Flags().BoolVarP(&backupForce, "force", "f", false, "Overwrite the file if it exists")
+	backupRestoreCmd.Flags().BoolVar(&backupSecrets, "secrets", false, "Decrypt and restore the bundle's OAuth tokens")
+	backupRestoreCmd.Flags().BoolVarP(&backupYes, "yes", "y", false, "Replace existing files without asking for confirmation")
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	callArgs    string
+	callRaw     bool
+	callTimeout time.Duration
+)
+
+var callCmd = &cobra.Command{
+	Use:   "call <server-name> <tool>",
+	Short: "Invoke a server's tool directly for smoke testing",
+	Long: `Launch a configured MCP server, call one of its tools with the given JSON arguments,
+and print the result. Claude is not involved, which makes this useful for verifying
+credentials and server behavior in isolation.`,
+	Example:      `  cmcp call github search_repositories --args '{"query": "mcp"}'`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		serverName, toolName := args[0], args[1]
+		server, exists := cfg.FindServer(serverName)
+		if !exists {
+			return &config.ServerNotFoundError{Name: serverName}
+		}
+
+		var toolArgs map[string]interface{}
+		if callArgs != "" {
+			if err := json.Unmarshal([]byte(callArgs), &toolArgs); err != nil {
+				return fmt.Errorf("--args must be a JSON object: %w", err)
+			}
+		}
+
+		server, err = mcp.ResolveLaunch(serverName, server)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+		defer cancel()
+
+		client, err := mcp.Connect(ctx, server)
+		if err != nil {
+			return fmt.Errorf("failed to connect to server '%s': %w", serverName, err)
+		}
+		defer client.Close()
+
+		result, err := client.CallTool(ctx, toolName, toolArgs)
+		if err != nil {
+			return fmt.Errorf("failed to call tool '%s': %w", toolName, err)
+		}
+
+		if callRaw {
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			printToolResult(result)
+		}
+
+		if result.IsError {
+			return fmt.Errorf("tool '%s' reported an error", toolName)
+		}
+		return nil
+	},
+}
+
+// printToolResult prints text content directly and other content types as JSON
+func printToolResult(result *mcp.ToolResult) {
+	for _, content := range result.Content {
+		switch content.Type {
+		case "text":
+			fmt.Println(content.Text)
+		case "image", "audio":
+			ui.Muted.Printf("[%s content, %s]\n", content.Type, content.MimeType)
+		default:
+			data, _ := json.MarshalIndent(content, "", "  ")
+			fmt.Println(string(data))
+		}
+	}
+
+	if len(result.StructuredContent) > 0 && len(result.Content) == 0 {
+		data, _ := json.MarshalIndent(result.StructuredContent, "", "  ")
+		fmt.Println(string(data))
+	}
+}
+
+func init() {
+	callCmd.Flags().StringVar(&callArgs, "args", "", "Tool arguments as a JSON object")
+	callCmd.Flags().BoolVar(&callRaw, "raw", false, "Print the raw tool result as JSON")
+	callCmd.Flags().DurationVar(&callTimeout, "timeout", 60*time.Second, "Maximum time to wait for the tool result")
+}
@@ -1,30 +1,409 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"sort"
 	"strings"
+	"time"
 
-	"cmcp/internal/config"
 	"github.com/AlecAivazis/survey/v2"
-	"github.com/fatih/color"
-	"github.com/manifoldco/promptui"
+	"github.com/lopezm94/cmcp/internal/auth"
+	"github.com/lopezm94/cmcp/internal/catalog"
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/health"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/registry"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/lopezm94/cmcp/internal/usage"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Manage MCP server configuration",
-	Long:  `List, remove, or edit MCP servers in your configuration.`,
+	Long:  `List, add, remove, or edit MCP servers in your configuration.`,
+}
+
+var (
+	configAddTemplate  string
+	configAddJSON      string
+	configListTags     []string
+	configListSort     string
+	configListFilter   string
+	configListWide     bool
+	configListCompact  bool
+	configGetOutput    string
+	configExportFmt    string
+	configExportFile   string
+	configImportFrom   string
+	configImportName   string
+	configImportURL    string
+	configGetReveal    bool
+	configPruneDelete  bool
+	configPruneDisable bool
+	configPruneDryRun  bool
+	configRmPurge      bool
+	configRmYes        bool
+)
+
+var configAddCmd = &cobra.Command{
+	Use:   "add <server-name>",
+	Short: "Add an MCP server to configuration",
+	Long: `Add an MCP server to configuration from a built-in template, prompting for
+the tokens and paths the template needs, or from a JSON definition in the same
+shape as 'claude mcp add-json'. Use --json - to read the JSON from stdin.
+
+Available templates: ` + strings.Join(catalog.TemplateNames(), ", "),
+	Example: `  cmcp config add my-fetch --template uvx
+  echo '{"command": "npx", "args": ["-y", "@modelcontextprotocol/server-github"]}' | cmcp config add github --json -`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		if configAddTemplate != "" && configAddJSON != "" {
+			return fmt.Errorf("--template and --json cannot be used together")
+		}
+		if configAddTemplate == "" && configAddJSON == "" {
+			return fmt.Errorf("--template or --json is required (available templates: %s)", strings.Join(catalog.TemplateNames(), ", "))
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if _, exists := cfg.MCPServers[name]; exists {
+			return fmt.Errorf("server '%s' already exists in configuration", name)
+		}
+
+		var server config.MCPServer
+		if configAddJSON != "" {
+			server, err = readServerJSON(configAddJSON)
+		} else {
+			server, err = serverFromTemplate(configAddTemplate)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := cfg.AddServer(name, server); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		ui.Success.Printf("✓ Added server '%s' to configuration\n", name)
+		warnConflicts(cfg, name)
+		fmt.Printf("Run 'cmcp start %s' to start it in Claude for this project.\n", name)
+		return nil
+	},
+}
+
+// readServerJSON parses a server definition given inline or, for "-", on stdin
+func readServerJSON(value string) (config.MCPServer, error) {
+	data := []byte(value)
+	if value == "-" {
+		var err error
+		data, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			return config.MCPServer{}, fmt.Errorf("failed to read stdin: %w", err)
+		}
+	}
+	return config.ParseServerJSON(data)
+}
+
+// serverFromTemplate prompts for a template's inputs and renders the server
+func serverFromTemplate(name string) (config.MCPServer, error) {
+	tmpl, err := catalog.GetTemplate(name)
+	if err != nil {
+		return config.MCPServer{}, err
+	}
+
+	values, err := promptInputs(tmpl.Inputs)
+	if err != nil {
+		return config.MCPServer{}, err
+	}
+
+	return catalog.Render(tmpl.Server, values)
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <server-name>",
+	Short: "Print a server's definition",
+	Long: `Print the full definition of a configured server as JSON or YAML.
+Secret environment values are masked unless --reveal is given.`,
+	Example:      `  cmcp config get github --output yaml`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		server, exists := cfg.FindServer(args[0])
+		if !exists {
+			return &config.ServerNotFoundError{Name: args[0]}
+		}
+
+		definition := *server
+		if !configGetReveal {
+			definition = mcp.MaskServer(definition)
+		}
+
+		var data []byte
+		switch configGetOutput {
+		case "json":
+			data, err = json.MarshalIndent(definition.ToMap(), "", "  ")
+			data = append(data, '\n')
+		case "yaml":
+			var buf bytes.Buffer
+			encoder := yaml.NewEncoder(&buf)
+			encoder.SetIndent(2)
+			err = encoder.Encode(definition.ToMap())
+			data = buf.Bytes()
+		default:
+			return fmt.Errorf("unknown output format '%s' (use json or yaml)", configGetOutput)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to encode server: %w", err)
+		}
+
+		fmt.Print(string(data))
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <server>.<field> <value>",
+	Short: "Set a field of a server's definition",
+	Long: `Set a single field of a configured server using a dotted path.
+Env values and args are stored as strings, autoRestart as a bool, and
+whole lists (args, tags) accept a JSON array. Other fields accept any JSON value.`,
+	Example: `  cmcp config set github.env.GITHUB_TOKEN '${env:GH_TOKEN}'
+  cmcp config set fs.args[1] /new/path
+  cmcp config set fs.args '["-y", "@modelcontextprotocol/server-filesystem", "/tmp"]'`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := cfg.Set(args[0], args[1]); err != nil {
+			return err
+		}
+
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		ui.Success.Printf("✓ Set %s\n", args[0])
+		warnConflicts(cfg, strings.SplitN(args[0], ".", 2)[0])
+		return nil
+	},
+}
+
+var configLintCmd = &cobra.Command{
+	Use:   "lint [server-name...]",
+	Short: "Check server definitions for common mistakes",
+	Long: `Check configured servers for mistakes that make Claude reject or fail to launch
+them: empty commands, arguments typed into the command string, missing cwd
+directories, and names Claude will not accept. Env values, args, and headers that
+reference shell variables not set in this shell are reported as warnings.
+Each problem comes with a suggested fix. Exits with an error if any problem is not
+just a warning.`,
+	Example: `  cmcp config lint
+  cmcp config lint github 'gh-*'`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		var names []string
+		if len(args) > 0 {
+			if names, err = cfg.MatchServers(args); err != nil {
+				return err
+			}
+		}
+
+		issues := cfg.Lint(names)
+		if len(issues) == 0 {
+			checked := len(names)
+			if checked == 0 {
+				checked = len(cfg.MCPServers)
+			}
+			ui.Success.Color().Printf("✓ No problems found in %d server(s)\n", checked)
+			return nil
+		}
+
+		errorCount := 0
+		for _, issue := range issues {
+			if issue.Error {
+				errorCount++
+				ui.Failure.Printf("✗ %s: %s\n", issue.Server, issue.Message)
+			} else {
+				ui.Warning.Printf("⚠ %s: %s\n", issue.Server, issue.Message)
+			}
+			for i, fix := range issue.Fixes {
+				label := "  fix: "
+				if i > 0 {
+					label = "       "
+				}
+				ui.Muted.Printf("%s%s\n", label, fix)
+			}
+		}
+
+		fmt.Printf("\n%d error(s), %d warning(s)\n", errorCount, len(issues)-errorCount)
+		if errorCount > 0 {
+			return fmt.Errorf("found %d configuration error(s)", errorCount)
+		}
+		return nil
+	},
+}
+
+var configPruneCmd = &cobra.Command{
+	Use:   "prune [server-name...]",
+	Short: "Find servers that can no longer be launched and delete or disable them",
+	Long: `Check configured stdio servers for a command that is no longer on PATH, a script
+path that no longer exists, or a docker image that was removed, and offer to delete
+or disable each one. Disabled servers stay in the config but are skipped by start;
+re-enable one with 'cmcp config set <server>.disabled false'.
+Use --delete or --disable to handle every dead server without prompting.`,
+	Example: `  cmcp config prune
+  cmcp config prune --dry-run
+  cmcp config prune 'gh-*' --disable`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if configPruneDelete && configPruneDisable {
+			return fmt.Errorf("--delete and --disable cannot be used together")
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		dead := mcp.FindDeadServers(cfg)
+		if len(args) > 0 {
+			names, err := cfg.MatchServers(args)
+			if err != nil {
+				return err
+			}
+			selected := make(map[string]bool, len(names))
+			for _, name := range names {
+				selected[name] = true
+			}
+			var filtered []mcp.DeadServer
+			for _, server := range dead {
+				if selected[server.Name] {
+					filtered = append(filtered, server)
+				}
+			}
+			dead = filtered
+		}
+
+		if len(dead) == 0 {
+			ui.Success.Color().Println("✓ No dead servers found")
+			return nil
+		}
+
+		for _, server := range dead {
+			ui.Failure.Printf("✗ %s: %s\n", server.Name, server.Reason)
+		}
+		fmt.Println()
+
+		if configPruneDryRun {
+			return nil
+		}
+		interactive := !configPruneDelete && !configPruneDisable
+		if interactive && !ui.IsInteractive() {
+			return fmt.Errorf("found %d dead server(s); use --delete or --disable to prune them without a terminal", len(dead))
+		}
+
+		const (
+			actionKeep    = "Keep"
+			actionDisable = "Disable"
+			actionDelete  = "Delete"
+		)
+		var deleted, disabled []string
+		for _, target := range dead {
+			action := actionKeep
+			switch {
+			case configPruneDelete:
+				action = actionDelete
+			case configPruneDisable:
+				action = actionDisable
+			default:
+				prompt := &survey.Select{
+					Message: fmt.Sprintf("%s (%s):", target.Name, target.Reason),
+					Options: []string{actionKeep, actionDisable, actionDelete},
+				}
+				if err := survey.AskOne(prompt, &action); err != nil {
+					return err
+				}
+			}
+			if action == actionKeep {
+				continue
+			}
+			if action == actionDelete {
+				if err := cfg.CanRemove(target.Name); err != nil {
+					ui.Warning.Printf("Skipping '%s': %v\n", target.Name, err)
+					continue
+				}
+			}
+
+			// A server that is still registered in Claude would keep failing there
+			if builder.IsRunning(builder.ClaudeName(target.Name)) {
+				if err := builder.StopServer(builder.ClaudeName(target.Name), false); err != nil {
+					ui.Failure.Printf("Warning: Failed to stop server '%s': %v\n", target.Name, err)
+				}
+			}
+
+			if action == actionDelete {
+				if err := cfg.RemoveServer(target.Name); err != nil {
+					return fmt.Errorf("failed to delete server '%s': %w", target.Name, err)
+				}
+				deleted = append(deleted, target.Name)
+				continue
+			}
+			server := cfg.MCPServers[target.Name]
+			server.SetDisabled(true)
+			cfg.MCPServers[target.Name] = server
+			if err := config.Save(cfg); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+			disabled = append(disabled, target.Name)
+		}
+
+		if len(deleted) > 0 {
+			ui.Success.Printf("✓ Deleted %d server(s): %s\n", len(deleted), strings.Join(deleted, ", "))
+		}
+		if len(disabled) > 0 {
+			ui.Success.Printf("✓ Disabled %d server(s): %s\n", len(disabled), strings.Join(disabled, ", "))
+		}
+		if len(deleted) == 0 && len(disabled) == 0 {
+			ui.Muted.Println("No servers changed")
+		}
+		return nil
+	},
 }
 
 var configRmCmd = &cobra.Command{
 	Use:   "rm [server-name...]",
 	Short: "Remove MCP servers from configuration",
 	Long:  `Remove one or more MCP servers from configuration.
-You can specify server names as arguments or run without arguments for interactive selection.`,
+You can specify server names or shell-style patterns (e.g. 'test-*') as arguments,
+or run without arguments for interactive selection. With --purge, the OAuth token
+cached for a remote server, its debug logs and its health history are removed too.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.Load()
 		if err != nil {
@@ -32,45 +411,36 @@ You can specify server names as arguments or run without arguments for interacti
 		}
 
 		if len(cfg.MCPServers) == 0 {
-			yellow := color.New(color.FgYellow).SprintFunc()
-			gray := color.New(color.FgHiBlack).SprintFunc()
-			fmt.Printf("%s\n", yellow("No servers configured"))
-			fmt.Printf("%s %s\n", gray("→"), gray("Use 'cmcp config open' to add servers"))
+			fmt.Printf("%s\n", ui.Warning.Sprint("No servers configured"))
+			fmt.Printf("%s %s\n", ui.Muted.Sprint("→"), ui.Muted.Sprint("Use 'cmcp config open' to add servers"))
 			return nil
 		}
 
 		// Color functions
-		cyan := color.New(color.FgCyan)
-		green := color.New(color.FgGreen)
-		red := color.New(color.FgRed)
-		gray := color.New(color.FgHiBlack)
-		yellow := color.New(color.FgYellow)
 
 		var selectedServers []string
 		runningServers := make(map[string]bool)
 
 		// Check which servers are running
 		for name := range cfg.MCPServers {
-			if builder.IsRunning(name) {
+			if builder.IsRunning(builder.ClaudeName(name)) {
 				runningServers[name] = true
 			}
 		}
 
-		// If server names are provided as arguments, use those (non-interactive mode)
+		// If server names or patterns are provided as arguments, use those (non-interactive mode).
+		// The removal confirmation below already shows the matched set.
 		if len(args) > 0 {
-			for _, serverName := range args {
-				// Check if server exists in config
-				if _, exists := cfg.MCPServers[serverName]; !exists {
-					return fmt.Errorf("server '%s' not found in configuration", serverName)
-				}
-				selectedServers = append(selectedServers, serverName)
+			selectedServers, err = resolveServerArgs(cfg, args, nil, false)
+			if err != nil {
+				return err
 			}
 		} else {
 			// Interactive mode - multi-select
 			serverNames := cfg.GetServerNames()
 			
 			if len(serverNames) == 0 {
-				yellow.Println("No servers to remove.")
+				ui.Warning.Println("No servers to remove.")
 				return nil
 			}
 
@@ -84,6 +454,9 @@ You can specify server names as arguments or run without arguments for interacti
 				}
 			}
 
+			if !ui.IsInteractive() {
+				return errNamesRequired
+			}
 			prompt := &survey.MultiSelect{
 				Message: "Select servers to remove (use space to select, enter to confirm):",
 				Options: options,
@@ -103,16 +476,20 @@ You can specify server names as arguments or run without arguments for interacti
 		}
 
 		if len(selectedServers) == 0 {
-			yellow.Println("No servers selected.")
+			ui.Warning.Println("No servers selected.")
 			return nil
 		}
 
 		// Show what will be removed
 		fmt.Println()
-		cyan.Println("The following servers will be removed:")
+		if configRmPurge {
+			ui.Info.Println("The following servers will be removed, with their cached tokens, debug logs and health history:")
+		} else {
+			ui.Info.Println("The following servers will be removed:")
+		}
 		for _, name := range selectedServers {
 			if runningServers[name] {
-				fmt.Printf("  • %s %s\n", name, gray.Sprint("(will be stopped)"))
+				fmt.Printf("  • %s %s\n", name, ui.Muted.Sprint("(will be stopped)"))
 			} else {
 				fmt.Printf("  • %s\n", name)
 			}
@@ -120,26 +497,31 @@ You can specify server names as arguments or run without arguments for interacti
 		fmt.Println()
 
 		// Confirm removal
-		confirmPrompt := promptui.Prompt{
-			Label:     fmt.Sprintf("Are you sure you want to remove %d server(s)", len(selectedServers)),
-			IsConfirm: true,
-		}
-
-		_, err = confirmPrompt.Run()
-		if err != nil {
-			return nil
+		confirmed, err := confirm(fmt.Sprintf("Are you sure you want to remove %d server(s)", len(selectedServers)), configRmYes)
+		if err != nil || !confirmed {
+			return err
 		}
 
 		// Remove each selected server
 		var removed []string
 		var errors []error
+		definitions := make(map[string]config.MCPServer, len(selectedServers))
+		for _, serverName := range selectedServers {
+			definitions[serverName] = cfg.MCPServers[serverName]
+		}
 
 		for _, serverName := range selectedServers {
+			// Servers from other files stay in the config, so leave them running too
+			if err := cfg.CanRemove(serverName); err != nil {
+				errors = append(errors, fmt.Errorf("%s: %v", serverName, err))
+				continue
+			}
+
 			// Stop server if running
 			if runningServers[serverName] {
-				cyan.Printf("Stopping server '%s'...\n", serverName)
-				if err := builder.StopServer(serverName, false); err != nil {
-					red.Printf("Warning: Failed to stop server '%s': %v\n", serverName, err)
+				ui.Info.Printf("Stopping server '%s'...\n", serverName)
+				if err := builder.StopServer(builder.ClaudeName(serverName), false); err != nil {
+					ui.Failure.Printf("Warning: Failed to stop server '%s': %v\n", serverName, err)
 					// Continue with removal anyway
 				}
 			}
@@ -154,13 +536,18 @@ You can specify server names as arguments or run without arguments for interacti
 		// Show results
 		fmt.Println()
 		if len(removed) > 0 {
-			green.Printf("✓ Successfully removed %d server(s): %v\n", len(removed), removed)
+			ui.Success.Printf("✓ Successfully removed %d server(s): %v\n", len(removed), removed)
+		}
+		if configRmPurge {
+			for _, serverName := range removed {
+				purgeServer(cfg, serverName, definitions[serverName])
+			}
 		}
 
 		if len(errors) > 0 {
-			red.Printf("✗ Failed to remove %d server(s):\n", len(errors))
+			ui.Failure.Printf("✗ Failed to remove %d server(s):\n", len(errors))
 			for _, err := range errors {
-				red.Printf("  • %v\n", err)
+				ui.Failure.Printf("  • %v\n", err)
 			}
 			return fmt.Errorf("some servers could not be removed")
 		}
@@ -174,72 +561,128 @@ var configListCmd = &cobra.Command{
 	Aliases: []string{"ls"},
 	Short:   "List all configured MCP servers",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateListFlags(configListSort, configListFilter); err != nil {
+			return err
+		}
+		if configListWide && configListCompact {
+			return fmt.Errorf("--wide and --compact cannot be used together")
+		}
+		if configListFilter == "orphaned" {
+			return fmt.Errorf("orphaned servers are not in the config; use 'cmcp online --filter orphaned'")
+		}
+
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
 		if len(cfg.MCPServers) == 0 {
-			color.Yellow("No servers configured")
+			ui.Warning.Println("No servers configured")
 			return nil
 		}
 
-		// Color functions
-		blue := color.New(color.FgBlue).SprintFunc()
-		green := color.New(color.FgGreen).SprintFunc()
-		yellow := color.New(color.FgYellow).SprintFunc()
-		gray := color.New(color.FgHiBlack).SprintFunc()
-		bold := color.New(color.Bold).SprintFunc()
+		serverNames := cfg.FilterByTags(cfg.GetServerNames(), configListTags)
+		if len(serverNames) == 0 {
+			ui.Warning.Printf("No servers tagged %s\n", strings.Join(configListTags, " or "))
+			return nil
+		}
 
-		// Get server names and check which are running
-		runningServers := make(map[string]bool)
-		for name := range cfg.MCPServers {
-			if builder.IsRunning(name) {
-				runningServers[name] = true
+		// Sorting or filtering by status needs connection health from one claude mcp
+		// list; otherwise the cheaper per-server registration check is enough
+		statuses := make([]mcp.ServerStatus, len(serverNames))
+		var live map[string]string
+		if configListSort == "status" || configListFilter != "" {
+			servers, _ := builder.GetServerStatuses(cfg)
+			live = make(map[string]string, len(servers))
+			for _, server := range servers {
+				if server.InConfig {
+					live[server.ConfigName] = server.Status
+				}
+			}
+		}
+		for i, name := range serverNames {
+			status := "stopped"
+			if live != nil {
+				if s, ok := live[name]; ok {
+					status = s
+				}
+			} else if builder.IsRunning(builder.ClaudeName(name)) {
+				status = "running"
+			}
+			statuses[i] = mcp.ServerStatus{Name: name, Status: status, InConfig: true, ConfigName: name}
+		}
+
+		statuses, _ = mcp.FilterStatuses(statuses, configListFilter)
+		mcp.SortStatuses(statuses, configListSort)
+		if len(statuses) == 0 {
+			ui.Warning.Printf("No %s servers\n", configListFilter)
+			return nil
+		}
+
+		// Servers registered in Claude count as running
+		runningCount := 0
+		for _, status := range statuses {
+			if status.Status != "stopped" {
+				runningCount++
 			}
 		}
 
+		if configListCompact {
+			for _, status := range statuses {
+				if status.Status == "stopped" {
+					fmt.Printf("%s %s\n", ui.Muted.Sprint("○"), status.Name)
+				} else {
+					fmt.Printf("%s %s\n", ui.Success.Sprint("●"), status.Name)
+				}
+			}
+			return nil
+		}
+
 		// Show summary first
-		runningCount := len(runningServers)
-		totalCount := len(cfg.MCPServers)
-		
-		fmt.Printf("%s %s", bold(fmt.Sprintf("%d", totalCount)), gray("server(s) configured"))
+		fmt.Printf("%s %s", ui.Emphasis.Sprint(fmt.Sprintf("%d", len(statuses))), ui.Muted.Sprint("server(s) configured"))
 		if runningCount > 0 {
-			fmt.Printf(" • %s %s", green(fmt.Sprintf("%d", runningCount)), gray("running"))
+			fmt.Printf(" • %s %s", ui.Success.Sprint(fmt.Sprintf("%d", runningCount)), ui.Muted.Sprint("running"))
 		}
 		fmt.Println()
 		fmt.Println()
 
-		// List servers
-		for name, server := range cfg.MCPServers {
-			// Status indicator and name
-			if runningServers[name] {
-				fmt.Printf("%s %s\n", green("●"), bold(name))
-			} else {
-				fmt.Printf("%s %s\n", gray("○"), bold(name))
+		table := ui.NewTable(
+			ui.Column{},
+			ui.Column{Header: "NAME"},
+			ui.Column{Header: "COMMAND", Flex: true},
+			ui.Column{Header: "ENV", Flex: true},
+			ui.Column{Header: "TAGS", Flex: true},
+		)
+		if configListWide {
+			table.Width = 0
+		}
+		for _, status := range statuses {
+			server := cfg.MCPServers[status.Name]
+
+			icon := ui.Cell{Text: "○", Color: ui.Muted.Color()}
+			if status.Status != "stopped" {
+				icon = ui.Cell{Text: "●", Color: ui.Success.Color()}
 			}
 
-			// Command on the next line with indentation
-			fmt.Printf("  %s", blue(server.Command))
-			if len(server.Args) > 0 {
-				fmt.Printf(" %s", strings.Join(server.Args, " "))
+			command := server.URL
+			if !server.IsRemote() {
+				command = strings.TrimSpace(server.Command + " " + strings.Join(server.Args, " "))
 			}
-			fmt.Println()
 
-			// Environment variables if any
-			if len(server.Env) > 0 {
-				fmt.Printf("  %s", gray("env:"))
-				for i, key := range getSortedKeys(server.Env) {
-					if i > 0 {
-						fmt.Printf(",")
-					}
-					fmt.Printf(" %s", yellow(key))
-				}
-				fmt.Println()
+			name := ui.Cell{Text: status.Name, Color: ui.Emphasis.Color()}
+			if server.IsDisabled() {
+				name = ui.Cell{Text: status.Name + " (disabled)", Color: ui.Muted.Color()}
 			}
-			
-			fmt.Println() // Empty line between servers
+
+			table.Add(
+				icon,
+				name,
+				ui.Cell{Text: command, Color: ui.Command.Color()},
+				ui.Cell{Text: strings.Join(getSortedKeys(server.Env), ", "), Color: ui.Secret.Color()},
+				ui.Cell{Text: strings.Join(server.Tags, ", ")},
+			)
 		}
+		table.Render()
 
 		return nil
 	},
@@ -250,6 +693,9 @@ var configOpenCmd = &cobra.Command{
 	Short: "Open the config file in an editor",
 	Long:  `Open the configuration file in nano editor. Optionally select a specific server to jump to.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireTerminal("Opening the config in an editor"); err != nil {
+			return err
+		}
 		configPath, err := config.GetConfigPath()
 		if err != nil {
 			return fmt.Errorf("failed to get config path: %w", err)
@@ -268,7 +714,7 @@ var configOpenCmd = &cobra.Command{
 			}
 		}
 
-		color.Cyan("Opening config file...\n")
+		ui.Info.Println("Opening config file...")
 		if err := openInEditor(configPath, ""); err != nil {
 			return err
 		}
@@ -284,11 +730,272 @@ var configOpenCmd = &cobra.Command{
 			return fmt.Errorf("failed to reformat config: %w", err)
 		}
 
-		color.Green("Config file reformatted successfully.\n")
+		ui.Success.Println("Config file reformatted successfully.")
+		return nil
+	},
+}
+
+var configExportCmd = &cobra.Command{
+	Use:   "export [server-name...]",
+	Short: "Export servers to another format",
+	Long: `Export configured servers for use outside of Claude.
+With --format compose, servers launched with 'docker run' become services in a
+docker-compose.yml (image, env, volumes, ports). Secret env values are written as
+${KEY} references to be supplied by the environment or a .env file.`,
+	Example:      `  cmcp config export --format compose --file docker-compose.yml`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if configExportFmt != "compose" {
+			return fmt.Errorf("unsupported export format '%s' (supported: compose)", configExportFmt)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		names := cfg.GetServerNames()
+		if len(args) > 0 {
+			if names, err = cfg.MatchServers(args); err != nil {
+				return err
+			}
+		}
+		servers := make(map[string]config.MCPServer, len(names))
+		for _, name := range names {
+			servers[name] = cfg.MCPServers[name]
+		}
+
+		data, skipped, err := mcp.ExportCompose(servers)
+		if err != nil {
+			return fmt.Errorf("failed to build compose file: %w", err)
+		}
+		if len(skipped) == len(servers) {
+			return fmt.Errorf("no servers are launched with 'docker run'")
+		}
+
+		// Notes go to stderr so the compose file can be piped
+		if len(skipped) > 0 {
+			ui.Warning.Fprintf(os.Stderr, "Skipped %d server(s) not launched with 'docker run': %s\n", len(skipped), strings.Join(skipped, ", "))
+		}
+
+		if configExportFile == "" {
+			fmt.Print(string(data))
+			return nil
+		}
+		if err := mcp.WriteFileAtomic(configExportFile, data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", configExportFile, err)
+		}
+		ui.Success.Printf("✓ Exported %d server(s) to %s\n", len(servers)-len(skipped), configExportFile)
+		return nil
+	},
+}
+
+var configImportCmd = &cobra.Command{
+	Use:   "import <name-or-file>",
+	Short: "Import a server definition from another source",
+	Long: `Create a server entry from an external definition.
+With --from smithery, the argument is either a local smithery.yaml or a server name in
+the Smithery registry (set SMITHERY_API_KEY if the registry requires it). The declared
+start command and config schema are converted, and required settings are prompted for.
+With --url, the servers of a snippet made by 'cmcp share' are downloaded, from a gist
+link or any other URL.
+If the name is taken by a different definition, choose whether to keep the local one,
+replace it, or save the incoming one under another name.`,
+	Example: `  cmcp config import --from smithery ./smithery.yaml
+  cmcp config import --from smithery @acme/search --name search
+  cmcp config import --url https://gist.github.com/octo/abc123`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if configImportURL != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if configImportURL != "" {
+			if configImportFrom != "" {
+				return fmt.Errorf("--url and --from cannot be used together")
+			}
+			return importFromURL(configImportURL, configImportName)
+		}
+		if configImportFrom != "smithery" {
+			return fmt.Errorf("unsupported import source '%s' (supported: smithery)", configImportFrom)
+		}
+
+		var entry catalog.Entry
+		var err error
+		if _, statErr := os.Stat(args[0]); statErr == nil {
+			entry, err = registry.LoadSmitheryManifest(args[0])
+		} else {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			entry, err = registry.GetSmitheryServer(ctx, args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to import '%s': %w", args[0], err)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		name := configImportName
+		if name == "" {
+			name = entry.ID
+		}
+
+		values, err := promptInputs(entry.Inputs)
+		if err != nil {
+			return err
+		}
+		server, err := catalog.Render(entry.Server, values)
+		if err != nil {
+			return err
+		}
+
+		name, replace, ok, err := resolveNameConflict(cfg, name, server)
+		if err != nil || !ok {
+			return err
+		}
+		if err := saveIncoming(cfg, name, server, replace); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		if replace {
+			ui.Success.Printf("✓ Replaced server '%s' in configuration\n", name)
+		} else {
+			ui.Success.Printf("✓ Added server '%s' to configuration\n", name)
+		}
+		warnConflicts(cfg, name)
+		fmt.Printf("Run 'cmcp start %s' to start it in Claude for this project.\n", name)
 		return nil
 	},
 }
 
+// importFromURL adds the servers of a shared snippet, renaming a single server to
+// name when given, and lists the variables the servers expect from the environment
+func importFromURL(rawURL, name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	data, err := registry.FetchSnippet(ctx, rawURL)
+	if err != nil {
+		return fmt.Errorf("failed to import '%s': %w", rawURL, err)
+	}
+	servers, err := config.ParseDesiredState(data)
+	if err != nil {
+		return fmt.Errorf("failed to import '%s': %w", rawURL, err)
+	}
+	if len(servers) == 0 {
+		return fmt.Errorf("'%s' defines no servers", rawURL)
+	}
+	if name != "" && len(servers) > 1 {
+		return fmt.Errorf("--name needs a snippet with one server; '%s' has %d", rawURL, len(servers))
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	incomingNames := make([]string, 0, len(servers))
+	for incoming := range servers {
+		incomingNames = append(incomingNames, incoming)
+	}
+	sort.Strings(incomingNames)
+
+	var saved []string
+	for _, incoming := range incomingNames {
+		server := servers[incoming]
+		target := incoming
+		if name != "" {
+			target = name
+		}
+		target, replace, ok, err := resolveNameConflict(cfg, target, server)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := saveIncoming(cfg, target, server, replace); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		if replace {
+			ui.Success.Printf("✓ Replaced server '%s' in configuration\n", target)
+		} else {
+			ui.Success.Printf("✓ Added server '%s' to configuration\n", target)
+		}
+		saved = append(saved, target)
+	}
+	if len(saved) == 0 {
+		return nil
+	}
+	warnConflicts(cfg, saved...)
+
+	for _, issue := range cfg.Lint(saved) {
+		if strings.Contains(issue.Message, "not set in this shell") {
+			ui.Warning.Printf("⚠ %s: %s\n", issue.Server, issue.Message)
+		}
+	}
+	fmt.Printf("Run 'cmcp start %s' to start it in Claude for this project.\n", strings.Join(saved, " "))
+	return nil
+}
+
+// purgeServer removes what cmcp keeps about a server besides its config entry: the
+// OAuth token cached for its URL (unless a remaining server uses the same URL), its
+// debug logs, its health history and usage stats. Failures are reported and do not stop the removal.
+func purgeServer(cfg *config.Config, name string, server config.MCPServer) {
+	var purged []string
+
+	if server.IsRemote() && !urlStillUsed(cfg, server.URL) {
+		if deleted, err := mcp.TokenStore().Delete(server.URL); err != nil {
+			ui.Failure.Printf("Warning: Failed to remove the cached token of '%s': %v\n", name, err)
+		} else if deleted {
+			purged = append(purged, "cached token")
+		}
+	}
+
+	if count, err := mcp.RemoveDebugLogs(name); err != nil {
+		ui.Failure.Printf("Warning: Failed to remove the debug logs of '%s': %v\n", name, err)
+	} else if count > 0 {
+		purged = append(purged, fmt.Sprintf("%d debug log(s)", count))
+	}
+
+	if history, err := health.Load(config.StatePath("health.json")); err == nil && history.Forget(name) {
+		if err := history.Save(); err != nil {
+			ui.Failure.Printf("Warning: Failed to remove the health history of '%s': %v\n", name, err)
+		} else {
+			purged = append(purged, "health history")
+		}
+	}
+
+	if u, err := usage.Load(config.StatePath("usage.json")); err == nil && u.Forget(name) {
+		if err := u.Save(); err != nil {
+			ui.Failure.Printf("Warning: Failed to remove the usage stats of '%s': %v\n", name, err)
+		} else {
+			purged = append(purged, "usage stats")
+		}
+	}
+
+	if len(purged) > 0 {
+		ui.Muted.Printf("  Purged %s of '%s'\n", strings.Join(purged, ", "), name)
+	}
+}
+
+// urlStillUsed reports whether a configured server connects to the same resource as url
+func urlStillUsed(cfg *config.Config, url string) bool {
+	resource, err := auth.Resource(url)
+	if err != nil {
+		return false
+	}
+	for _, server := range cfg.MCPServers {
+		if other, err := auth.Resource(server.URL); server.IsRemote() && err == nil && other == resource {
+			return true
+		}
+	}
+	return false
+}
+
 func openInEditor(configPath, serverName string) error {
 	// Check if nano is available, fallback to other editors
 	var editorCmd *exec.Cmd
@@ -332,7 +1039,34 @@ func getSortedKeys(m map[string]string) []string {
 }
 
 func init() {
+	configAddCmd.Flags().StringVarP(&configAddTemplate, "template", "t", "", "Template to build the server from")
+	configAddCmd.Flags().StringVar(&configAddJSON, "json", "", "Server definition as JSON, or - to read it from stdin")
+	configListCmd.Flags().StringSliceVar(&configListTags, "tag", nil, "Only list servers with this tag (repeatable)")
+	configListCmd.Flags().StringVar(&configListSort, "sort", "name", "Sort by name or status (failed first)")
+	configListCmd.Flags().StringVar(&configListFilter, "filter", "", "Only list connected or failed servers")
+	configListCmd.Flags().BoolVar(&configListWide, "wide", false, "Show full commands and env names without truncating to the terminal")
+	configListCmd.Flags().BoolVar(&configListCompact, "compact", false, "Show one short line per server")
+	configGetCmd.Flags().StringVarP(&configGetOutput, "output", "o", "json", "Output format (json or yaml)")
+	configGetCmd.Flags().BoolVar(&configGetReveal, "reveal", false, "Show secret values instead of masking them")
+	configExportCmd.Flags().StringVar(&configExportFmt, "format", "", "Export format (compose)")
+	configExportCmd.Flags().StringVar(&configExportFile, "file", "", "Write the export to this file instead of stdout")
+	configImportCmd.Flags().StringVar(&configImportFrom, "from", "", "Source format (smithery)")
+	configImportCmd.Flags().StringVar(&configImportName, "name", "", "Name to store the server under")
+	configImportCmd.Flags().StringVar(&configImportURL, "url", "", "Download a snippet made by 'cmcp share' from this URL")
+	configPruneCmd.Flags().BoolVar(&configPruneDelete, "delete", false, "Delete every dead server without prompting")
+	configPruneCmd.Flags().BoolVar(&configPruneDisable, "disable", false, "Disable every dead server without prompting")
+	configPruneCmd.Flags().BoolVar(&configPruneDryRun, "dry-run", false, "Only report dead servers")
+	configRmCmd.Flags().BoolVar(&configRmPurge, "purge", false, "Also remove the servers' cached tokens, debug logs and health history")
+	configRmCmd.Flags().BoolVarP(&configRmYes, "yes", "y", false, "Remove without asking for confirmation")
+
 	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configAddCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configLintCmd)
+	configCmd.AddCommand(configPruneCmd)
 	configCmd.AddCommand(configRmCmd)
 	configCmd.AddCommand(configOpenCmd)
+	configCmd.AddCommand(configExportCmd)
+	configCmd.AddCommand(configImportCmd)
 }
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+)
+
+// Choices offered when an imported server's name is already taken
+const (
+	keepLocal    = "Keep the local definition (skip this server)"
+	takeIncoming = "Replace it with the incoming definition"
+	renameNew    = "Save the incoming definition under another name"
+)
+
+// resolveNameConflict decides where an incoming server goes when its name is already
+// configured. It returns the name to save under, whether that replaces the existing
+// entry, and false for ok when the server should be skipped. Without a terminal to
+// ask on, a conflicting definition is an error.
+func resolveNameConflict(cfg *config.Config, name string, incoming config.MCPServer) (string, bool, bool, error) {
+	local, exists := cfg.FindServer(name)
+	if !exists {
+		return name, false, true, nil
+	}
+	if reflect.DeepEqual(local.ToMap(), incoming.ToMap()) {
+		ui.Muted.Color().Printf("Server '%s' is already configured with the same definition\n", name)
+		return name, false, false, nil
+	}
+	if !ui.IsInteractive() {
+		return "", false, false, fmt.Errorf("server '%s' already exists in configuration with a different definition (use --name to pick another name)", name)
+	}
+
+	ui.Warning.Printf("⚠ Server '%s' already exists with a different definition\n", name)
+	ui.Muted.Printf("  local:    %s\n", maskedDefinition(*local))
+	ui.Muted.Printf("  incoming: %s\n", maskedDefinition(incoming))
+
+	var choice string
+	prompt := &survey.Select{
+		Message: fmt.Sprintf("What should happen to '%s'?", name),
+		Options: []string{keepLocal, takeIncoming, renameNew},
+	}
+	if err := survey.AskOne(prompt, &choice); err != nil {
+		return "", false, false, err
+	}
+
+	switch choice {
+	case takeIncoming:
+		return name, true, true, nil
+	case renameNew:
+		var renamed string
+		rename := &survey.Input{Message: "New name:", Default: freeServerName(cfg, name)}
+		validate := func(answer interface{}) error {
+			if _, taken := cfg.MCPServers[answer.(string)]; taken {
+				return fmt.Errorf("server '%s' already exists", answer)
+			}
+			return nil
+		}
+		if err := survey.AskOne(rename, &renamed, survey.WithValidator(survey.Required), survey.WithValidator(validate)); err != nil {
+			return "", false, false, err
+		}
+		return renamed, false, true, nil
+	}
+	return name, false, false, nil
+}
+
+// saveIncoming stores a server resolved by resolveNameConflict
+func saveIncoming(cfg *config.Config, name string, server config.MCPServer, replace bool) error {
+	if replace {
+		cfg.MCPServers[name] = server
+		return config.Save(cfg)
+	}
+	return cfg.AddServer(name, server)
+}
+
+// freeServerName returns name-2, name-3, ... whichever is not configured yet
+func freeServerName(cfg *config.Config, name string) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", name, i)
+		if _, taken := cfg.MCPServers[candidate]; !taken {
+			return candidate
+		}
+	}
+}
+
+// maskedDefinition returns a server definition as one line of JSON with secrets masked
+func maskedDefinition(server config.MCPServer) string {
+	data, _ := json.Marshal(mcp.MaskServer(server).ToMap())
+	return string(data)
+}
+
+// warnConflicts warns when freshly saved servers duplicate another configured
+// server or differ from what Claude has registered under the same name
+func warnConflicts(cfg *config.Config, names ...string) {
+	saved := make(map[string]bool)
+	for _, name := range names {
+		saved[name] = true
+	}
+
+	for _, group := range cfg.FindDuplicates() {
+		var others []string
+		involved := false
+		for _, name := range group {
+			if saved[name] {
+				involved = true
+			} else {
+				others = append(others, name)
+			}
+		}
+		if !involved || len(others) == 0 {
+			continue
+		}
+		ui.Warning.Printf("⚠ Servers %s have identical definitions\n", quoteNames(group))
+		ui.Muted.Printf("  Keep one and remove the others with 'cmcp config rm %s', or use tags to group them.\n", strings.Join(others, " "))
+	}
+
+	// The active definition is already reported below; skip it among the shadowed ones
+	reported := make(map[string]bool)
+	for _, conflict := range builder.FindConflicts(cfg, names) {
+		reported[conflict.Name+"\x00"+conflict.Scope] = true
+		scope := ""
+		if conflict.Scope != "" {
+			scope = fmt.Sprintf(" (%s)", shortScope(conflict.Scope))
+		}
+		ui.Warning.Printf("⚠ Server '%s' is registered in Claude%s with a different definition\n", conflict.Name, scope)
+		ui.Muted.Printf("  claude: %s\n", maskCommandLine(conflict.Registered))
+		ui.Muted.Printf("  cmcp:   %s\n", maskCommandLine(conflict.Configured))
+		ui.Muted.Printf("  Run 'cmcp stop %s && cmcp start %s' to apply the configured definition, or rename one of them.\n",
+			conflict.Name, conflict.Name)
+	}
+
+	var shadowed []mcp.Conflict
+	for _, conflict := range mcp.FindShadowed(cfg, names) {
+		if !reported[conflict.Name+"\x00"+conflict.Scope] {
+			shadowed = append(shadowed, conflict)
+		}
+	}
+	printShadowed(shadowed)
+}
+
+// warnShadowed warns when servers about to be started are also defined in Claude's
+// user or project scope with a different definition
+func warnShadowed(cfg *config.Config, names []string) {
+	printShadowed(mcp.FindShadowed(cfg, names))
+}
+
+func printShadowed(conflicts []mcp.Conflict) {
+	for _, conflict := range conflicts {
+		flag := "user"
+		if conflict.Scope == mcp.ScopeProject {
+			flag = "project"
+		}
+		ui.Warning.Printf("⚠ Server '%s' is also defined in Claude's %s with a different definition\n", conflict.Name, shortScope(conflict.Scope))
+		ui.Muted.Printf("  claude: %s\n", maskCommandLine(conflict.Registered))
+		ui.Muted.Printf("  cmcp:   %s\n", maskCommandLine(conflict.Configured))
+		ui.Muted.Printf("  cmcp's definition wins while it runs in this project; elsewhere Claude uses the other one.\n")
+		ui.Muted.Printf("  Remove it with 'claude mcp remove %s -s %s', or rename one of them.\n", conflict.Name, flag)
+	}
+}
+
+// printDrift shows how a running server's definition in Claude differs from the config
+func printDrift(drift mcp.Conflict) {
+	ui.Warning.Printf("⚠ Server '%s' is running with a definition that differs from the config\n", drift.Name)
+	ui.Muted.Printf("  claude: %s\n", maskCommandLine(drift.Registered))
+	ui.Muted.Printf("  cmcp:   %s\n", maskCommandLine(drift.Configured))
+}
+
+// shortScope turns "Local config (private to you in this project)" into "Local config"
+func shortScope(scope string) string {
+	short, _, _ := strings.Cut(scope, " (")
+	return short
+}
+
+// quoteNames formats names as 'a', 'b' and 'c'
+func quoteNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + name + "'"
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " and " + quoted[len(quoted)-1]
+}
+
+// maskCommandLine hides sensitive values in a space-separated command line
+func maskCommandLine(line string) string {
+	return strings.Join(mcp.MaskSensitiveArgs(strings.Fields(line)), " ")
+}
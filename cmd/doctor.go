@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the claude installations cmcp can run",
+	Long: `Report each claude installation cmcp knows about, with the executable it resolves
+to and its version. Installations are configured under "claude" in the config file
+and picked with --claude-bin; claude on PATH is always available as "claude".`,
+	Example: `  cmcp doctor
+  cmcp --claude-bin canary start github`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		installs := mcp.InspectClaudeInstalls(context.Background(), cfg.ClaudeBinaries(), cfg.DefaultClaude())
+		width := 0
+		for _, install := range installs {
+			width = max(width, len(install.Name))
+		}
+
+		ui.Emphasis.Println("Claude installations:")
+		failed := 0
+		for _, install := range installs {
+			label := fmt.Sprintf("%-*s", width, install.Name)
+			if install.Default {
+				label += ui.Muted.Sprint(" (default)")
+			} else {
+				label += "          "
+			}
+			if install.Err != nil {
+				failed++
+				fmt.Printf("  %s %s  %s\n", ui.Failure.Sprint("✗"), label, ui.Failure.Sprint(install.Err))
+				ui.Muted.Printf("      command: %s\n", install.Command)
+				continue
+			}
+			fmt.Printf("  %s %s  %s\n", ui.Success.Sprint("✓"), label, install.Version)
+			ui.Muted.Printf("      %s\n", install.Path)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d claude installation(s) cannot be run", failed, len(installs))
+		}
+		return nil
+	},
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envReveal bool
+	envYes    bool
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env <server-name>",
+	Short: "Show the environment a server would receive",
+	Long: `Print each env variable of a configured server with the value the server would
+receive once started and where it comes from: the config, the shell for "$inherit"
+entries, or Claude's expansion of ${VAR} and ${VAR:-default} references, resolved
+here from this shell. Variables that are not set are flagged.
+
+Secret values are masked, with their length shown, unless --reveal is given and
+confirmed.`,
+	Example: `  cmcp env github
+  cmcp env github --reveal`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		serverName := args[0]
+		server, exists := cfg.FindServer(serverName)
+		if !exists {
+			return &config.ServerNotFoundError{Name: serverName}
+		}
+
+		vars := mcp.ResolvedEnv(server)
+		if len(vars) == 0 {
+			ui.Muted.Printf("Server '%s' sets no env variables; it gets the environment Claude runs in.\n", serverName)
+			return nil
+		}
+		if envReveal {
+			ok, err := confirm("Show secret values in plain text", envYes)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+		}
+
+		width := 0
+		for _, v := range vars {
+			width = max(width, len(v.Key))
+		}
+
+		ui.Emphasis.Printf("Environment of '%s':\n", serverName)
+		var missing []string
+		for _, v := range vars {
+			value := v.Value
+			switch {
+			case len(v.Missing) > 0:
+				value = ui.Failure.Sprint("(not set)")
+				missing = append(missing, v.Missing...)
+			case v.Sensitive && !envReveal:
+				value = ui.Secret.Sprint("***") + ui.Muted.Sprintf(" (%d chars)", len(v.Value))
+			case v.Sensitive:
+				value = ui.Secret.Sprint(value)
+			}
+			fmt.Printf("  %-*s  %s\n", width, v.Key, value)
+			ui.Muted.Printf("  %-*s  %s\n", width, "", v.Source)
+			if len(v.Missing) == 0 && v.Value != strings.TrimSpace(v.Value) {
+				ui.Warning.Printf("  %-*s  ⚠ value has leading or trailing whitespace\n", width, "")
+			}
+		}
+
+		if len(missing) > 0 {
+			ui.Warning.Printf("\n⚠ Not set in this shell: %s. Export them before starting '%s'.\n", strings.Join(missing, ", "), serverName)
+		}
+		ui.Muted.Println("\nThe server also gets the rest of the environment Claude runs in.")
+		return nil
+	},
+}
+
+func init() {
+	envCmd.Flags().BoolVar(&envReveal, "reveal", false, "Show secret values instead of masking them")
+	envCmd.Flags().BoolVarP(&envYes, "yes", "y", false, "Reveal without asking for confirmation")
+}
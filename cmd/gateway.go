@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var gatewayTags []string
+
+var gatewayCmd = &cobra.Command{
+	Use:   "gateway [server-name|pattern...]",
+	Short: "Serve several servers' tools as one MCP server",
+	Long: `Run a single stdio MCP server in front of the configured servers, every enabled one
+unless names, patterns or --tag narrow the selection. Their tools are listed as
+<server>__<tool> and each call is forwarded to the server that owns the tool.
+
+Register the gateway in Claude once instead of each server: servers then connect
+when Claude launches the gateway, and changing the configuration needs no claude
+mcp add or remove. Servers that fail to connect are reported on stderr and left out.`,
+	Example: `  cmcp config add gateway --json '{"command": "cmcp", "args": ["gateway", "--tag", "work"]}'
+  cmcp start gateway
+  claude mcp add all-servers -- cmcp gateway`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		names := cfg.FilterByTags(cfg.GetServerNames(), gatewayTags)
+		if len(args) > 0 {
+			if names, err = resolveServerArgs(cfg, args, gatewayTags, false); err != nil {
+				return err
+			}
+		}
+
+		// stdout carries the protocol, so report on stderr
+		servers := make(map[string]*config.MCPServer)
+		var selected []string
+		for _, name := range names {
+			server, _ := cfg.FindServer(name)
+			if server.IsDisabled() || isGateway(server) {
+				continue
+			}
+			servers[name] = server
+			selected = append(selected, name)
+		}
+		if len(selected) == 0 {
+			ui.Warning.Fprintf(os.Stderr, "cmcp gateway: no servers selected; serving no tools\n")
+		} else {
+			ui.Muted.Fprintf(os.Stderr, "cmcp gateway: connecting to %s\n", strings.Join(selected, ", "))
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return mcp.NewGateway(servers, os.Stderr).Serve(ctx, os.Stdin, os.Stdout)
+	},
+}
+
+// isGateway reports whether a server runs cmcp gateway itself, which must not be
+// launched from inside a gateway
+func isGateway(server *config.MCPServer) bool {
+	base := strings.TrimSuffix(filepath.Base(server.Command), ".exe")
+	return base == "cmcp" && len(server.Args) > 0 && server.Args[0] == "gateway"
+}
+
+func init() {
+	gatewayCmd.Flags().StringSliceVar(&gatewayTags, "tag", nil, "Only serve servers with this tag (repeatable)")
+}
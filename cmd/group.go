@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var groupCmd = &cobra.Command{
+	Use:   "group",
+	Short: "Manage named server groups",
+	Long: `Create and edit named lists of servers. Groups are stored in your cmcp config
+and can be started or stopped together with 'cmcp start --group <name>'.`,
+}
+
+var groupCreateCmd = &cobra.Command{
+	Use:          "create <group> [server...]",
+	Short:        "Create a group, optionally with initial servers",
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateGroups(func(cfg *config.Config) (string, error) {
+			if err := cfg.CreateGroup(args[0], args[1:]); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Created group '%s' with %d server(s)", args[0], len(cfg.Groups[args[0]])), nil
+		})
+	},
+}
+
+var groupAddCmd = &cobra.Command{
+	Use:          "add <group> <server...>",
+	Short:        "Add servers (names or patterns) to a group",
+	Args:         cobra.MinimumNArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateGroups(func(cfg *config.Config) (string, error) {
+			before := len(cfg.Groups[args[0]])
+			if err := cfg.AddToGroup(args[0], args[1:]); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Added %d server(s) to group '%s'", len(cfg.Groups[args[0]])-before, args[0]), nil
+		})
+	},
+}
+
+var groupRemoveCmd = &cobra.Command{
+	Use:          "remove <group> [server...]",
+	Short:        "Remove servers from a group, or the whole group when none are given",
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateGroups(func(cfg *config.Config) (string, error) {
+			if len(args) == 1 {
+				if err := cfg.DeleteGroup(args[0]); err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("Removed group '%s'", args[0]), nil
+			}
+			if err := cfg.RemoveFromGroup(args[0], args[1:]); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Removed %d server(s) from group '%s'", len(args)-1, args[0]), nil
+		})
+	},
+}
+
+var groupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List groups and their servers",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if len(cfg.Groups) == 0 {
+			ui.Warning.Println("No groups defined. Use 'cmcp group create <group> <server...>' to create one.")
+			return nil
+		}
+
+		for _, name := range cfg.GetGroupNames() {
+			members := cfg.Groups[name]
+			fmt.Printf("%s %s\n", ui.Emphasis.Sprint(name), ui.Muted.Sprint(fmt.Sprintf("(%d)", len(members))))
+			if len(members) > 0 {
+				fmt.Printf("  %s\n", strings.Join(members, ", "))
+			}
+		}
+		return nil
+	},
+}
+
+// updateGroups loads the config, applies a group change, saves the result,
+// and prints the change's success message
+func updateGroups(change func(cfg *config.Config) (string, error)) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	message, err := change(cfg)
+	if err != nil {
+		return err
+	}
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	ui.Success.Printf("✓ %s\n", message)
+	return nil
+}
+
+func init() {
+	groupCmd.AddCommand(groupCreateCmd)
+	groupCmd.AddCommand(groupAddCmd)
+	groupCmd.AddCommand(groupRemoveCmd)
+	groupCmd.AddCommand(groupListCmd)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var inspectTimeout time.Duration
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <server-name>",
+	Short: "Show the tools, resources, and prompts a server offers",
+	Long: `Launch a configured MCP server (or connect to its URL), perform the MCP handshake,
+and list the tools, resources, and prompts it advertises. Claude is not involved.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		serverName := args[0]
+		server, exists := cfg.FindServer(serverName)
+		if !exists {
+			return &config.ServerNotFoundError{Name: serverName}
+		}
+
+		server, err = mcp.ResolveLaunch(serverName, server)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), inspectTimeout)
+		defer cancel()
+
+		ui.Info.Printf("Connecting to server '%s'...\n", serverName)
+		client, err := mcp.Connect(ctx, server)
+		if err != nil {
+			return fmt.Errorf("failed to connect to server '%s': %w", serverName, err)
+		}
+		defer client.Close()
+
+		tools, err := client.ListTools(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list tools: %w", err)
+		}
+		resources, err := client.ListResources(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list resources: %w", err)
+		}
+		prompts, err := client.ListPrompts(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list prompts: %w", err)
+		}
+
+		info := client.Initialize.ServerInfo
+		fmt.Println()
+		fmt.Printf("%s %s\n", ui.Emphasis.Sprint(info.Name), ui.Muted.Sprint(info.Version))
+		fmt.Printf("%s\n", ui.Muted.Sprint("protocol "+client.Initialize.ProtocolVersion))
+
+		fmt.Printf("\n%s %s\n", ui.Emphasis.Sprint("Tools"), ui.Muted.Sprint(fmt.Sprintf("(%d)", len(tools))))
+		for _, tool := range tools {
+			printInspectItem(ui.Info.Sprint(tool.Name), tool.Description)
+		}
+
+		fmt.Printf("\n%s %s\n", ui.Emphasis.Sprint("Resources"), ui.Muted.Sprint(fmt.Sprintf("(%d)", len(resources))))
+		for _, resource := range resources {
+			label := resource.URI
+			if resource.Name != "" {
+				label = fmt.Sprintf("%s (%s)", resource.URI, resource.Name)
+			}
+			printInspectItem(ui.Info.Sprint(label), resource.Description)
+		}
+
+		fmt.Printf("\n%s %s\n", ui.Emphasis.Sprint("Prompts"), ui.Muted.Sprint(fmt.Sprintf("(%d)", len(prompts))))
+		for _, prompt := range prompts {
+			printInspectItem(ui.Info.Sprint(prompt.Name), prompt.Description)
+		}
+
+		return nil
+	},
+}
+
+// printInspectItem prints a name with its description indented on the next line
+func printInspectItem(name, description string) {
+	fmt.Printf("  • %s\n", name)
+	if description != "" {
+		fmt.Printf("    %s\n", ui.Muted.Color().Sprint(description))
+	}
+}
+
+func init() {
+	inspectCmd.Flags().DurationVar(&inspectTimeout, "timeout", 30*time.Second, "Maximum time to wait for the server to respond")
+}
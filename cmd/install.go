@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/lopezm94/cmcp/internal/catalog"
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	installName  string
+	installStart bool
+)
+
+var installCmd = &cobra.Command{
+	Use:   "install [catalog-id]",
+	Short: "Add a well-known MCP server from the built-in catalog",
+	Long: `Add a ready-made configuration for a well-known MCP server, prompting for any
+required secrets or paths. Run without arguments to list the catalog.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			printCatalog()
+			return nil
+		}
+
+		entry, ok := catalog.Get(args[0])
+		if !ok {
+			return fmt.Errorf("'%s' is not in the catalog. Run 'cmcp install' to list available servers", args[0])
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		name := installName
+		if name == "" {
+			name = entry.ID
+		}
+
+		values, err := promptInputs(entry.Inputs)
+		if err != nil {
+			return err
+		}
+
+		server, err := catalog.Render(entry.Server, values)
+		if err != nil {
+			return err
+		}
+
+		name, replace, ok, err := resolveNameConflict(cfg, name, server)
+		if err != nil || !ok {
+			return err
+		}
+		if err := saveIncoming(cfg, name, server, replace); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		if replace {
+			ui.Success.Printf("✓ Replaced server '%s' in configuration\n", name)
+		} else {
+			ui.Success.Printf("✓ Added server '%s' to configuration\n", name)
+		}
+		warnConflicts(cfg, name)
+
+		if !installStart {
+			fmt.Printf("Run 'cmcp start %s' to start it in Claude for this project.\n", name)
+			return nil
+		}
+
+		ui.Info.Printf("Starting server '%s' in Claude for this project...\n", name)
+		err = builder.StartServer(name, &server, verbose)
+		noteStart(name, err == nil)
+		if err != nil {
+			return fmt.Errorf("failed to start server '%s': %w", name, err)
+		}
+		ui.Success.Printf("✓ Successfully started server '%s'\n", name)
+		return nil
+	},
+}
+
+// printCatalog lists the available catalog entries
+func printCatalog() {
+	fmt.Println("Available servers:")
+	fmt.Println()
+	for _, entry := range catalog.List() {
+		fmt.Printf("  %-22s %s\n", ui.Emphasis.Sprint(entry.ID), ui.Muted.Sprint(entry.Description))
+	}
+	fmt.Println()
+	fmt.Println("Install one with: cmcp install <id>")
+}
+
+// promptInputs asks the user for each input, hiding secret values
+func promptInputs(inputs []catalog.Input) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, input := range inputs {
+		var value string
+		var prompt survey.Prompt
+		if input.Secret {
+			prompt = &survey.Password{Message: input.Prompt + ":"}
+		} else {
+			prompt = &survey.Input{Message: input.Prompt + ":", Default: input.Default}
+		}
+
+		// Without a terminal, inputs with a default take it and the rest cannot be asked
+		if !ui.IsInteractive() {
+			if input.Default == "" {
+				return nil, fmt.Errorf("%s is required and cannot be asked for in non-interactive mode", input.Prompt)
+			}
+			values[input.Name] = input.Default
+			continue
+		}
+
+		var opts []survey.AskOpt
+		if input.Default == "" {
+			opts = append(opts, survey.WithValidator(survey.Required))
+		}
+		if err := survey.AskOne(prompt, &value, opts...); err != nil {
+			return nil, err
+		}
+		values[input.Name] = value
+	}
+	return values, nil
+}
+
+func init() {
+	installCmd.Flags().StringVar(&installName, "name", "", "Name to store the server under (defaults to the catalog id)")
+	installCmd.Flags().BoolVarP(&installStart, "start", "s", false, "Start the server in Claude right after adding it")
+}
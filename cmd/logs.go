@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsClaude bool
+	logsFollow bool
+	logsLines  int
+)
+
+// logsPollInterval is how often --follow checks for new output
+const logsPollInterval = 500 * time.Millisecond
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <server-name>",
+	Short: "Show the latest debug log for a server",
+	Long: `Show the end of the latest debug log cmcp wrote while starting, verifying, or stopping
+a server. With --claude, show Claude Code's own MCP log for the server instead: the
+messages Claude logged while connecting to it, including the server's stderr. Claude
+keeps these per project in its cache directory; logs for the current directory are
+preferred.`,
+	Example: `  cmcp logs github
+  cmcp logs --claude github
+  cmcp logs --claude github --follow`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serverName := args[0]
+		if logsClaude {
+			return showClaudeLog(builder.ClaudeName(serverName))
+		}
+
+		log, ok := mcp.FindLatestDebugLog(serverName)
+		if !ok {
+			return fmt.Errorf("no debug log found for server '%s'; try 'cmcp logs --claude %s'", serverName, serverName)
+		}
+		ui.Muted.Color().Printf("==> %s <==\n", log.Path)
+		return tailFile(log.Path)
+	},
+}
+
+// showClaudeLog prints the end of Claude's latest log for a server and, with --follow,
+// keeps printing new entries, moving on to newer log files as Claude creates them
+func showClaudeLog(name string) error {
+	log, ok := mcp.FindLatestClaudeLog(name)
+	if !ok {
+		return fmt.Errorf("no Claude log found for server '%s'; Claude writes one when it connects to the server", name)
+	}
+
+	ui.Muted.Printf("==> %s <==\n", log.Path)
+	entries, err := mcp.ReadClaudeLog(log.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read Claude log: %w", err)
+	}
+	start := 0
+	if logsLines > 0 && len(entries) > logsLines {
+		start = len(entries) - logsLines
+	}
+	printClaudeLogEntries(entries[start:])
+	if !logsFollow {
+		return nil
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	ticker := time.NewTicker(logsPollInterval)
+	defer ticker.Stop()
+
+	path, seen := log.Path, len(entries)
+	for {
+		select {
+		case <-interrupt:
+			return nil
+		case <-ticker.C:
+		}
+		if latest, ok := mcp.FindLatestClaudeLog(name); ok && latest.Path != path {
+			path, seen = latest.Path, 0
+			ui.Muted.Printf("==> %s <==\n", path)
+		}
+		entries, err := mcp.ReadClaudeLog(path)
+		if err != nil {
+			continue // Claude rewrites the file; try again on the next tick
+		}
+		if len(entries) < seen {
+			seen = 0
+		}
+		printClaudeLogEntries(entries[seen:])
+		seen = len(entries)
+	}
+}
+
+func printClaudeLogEntries(entries []mcp.ClaudeLogEntry) {
+	for _, entry := range entries {
+		if !entry.Time.IsZero() {
+			ui.Muted.Printf("%s ", entry.Time.Local().Format("15:04:05.000"))
+		}
+		if entry.Level == "error" {
+			ui.Failure.Println(entry.Message)
+		} else {
+			fmt.Println(entry.Message)
+		}
+	}
+}
+
+// tailFile prints the last --lines lines of a file and, with --follow, what is appended
+func tailFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open log: %w", err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if logsLines > 0 && len(lines) > logsLines {
+			lines = lines[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read log: %w", err)
+	}
+	if len(lines) > 0 {
+		fmt.Println(strings.Join(lines, "\n"))
+	}
+	if !logsFollow {
+		return nil
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	ticker := time.NewTicker(logsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-interrupt:
+			return nil
+		case <-ticker.C:
+			if _, err := io.Copy(os.Stdout, file); err != nil {
+				return fmt.Errorf("failed to read log: %w", err)
+			}
+		}
+	}
+}
+
+func init() {
+	logsCmd.Flags().BoolVar(&logsClaude, "claude", false, "Show Claude Code's own MCP log for the server")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep printing new log output until interrupted")
+	logsCmd.Flags().IntVarP(&logsLines, "lines", "n", 50, "Number of lines or entries to show (0 for all)")
+}
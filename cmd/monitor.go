@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// restartResetAfter is how long a restarted server must stay connected before its
+// restart count starts over, so only crash loops hit --max-restarts
+const restartResetAfter = 5 * time.Minute
+
+var (
+	monitorInterval    time.Duration
+	monitorMaxRestarts int
+	monitorAll         bool
+)
+
+var monitorCmd = &cobra.Command{
+	Use:   "monitor",
+	Short: "Watch servers and restart the ones that fail",
+	Long: `Run in the foreground, periodically checking the connection status of servers in
+Claude for this project, and re-add servers that drop into the failed state.
+
+Only servers with "autoRestart": true in the config are restarted, unless --all is given.
+Each server is restarted at most --max-restarts times in a row; the count starts
+over once it stays connected for 5 minutes. Press Ctrl-C to stop.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := withInterrupt()
+		defer stop()
+
+		restarts := make(map[string]int)
+		connectedSince := make(map[string]time.Time)
+		ui.Info.Printf("Monitoring servers every %v (Ctrl-C to stop)...\n", monitorInterval)
+
+		ticker := time.NewTicker(monitorInterval)
+		defer ticker.Stop()
+
+		for {
+			// Reload every tick so config edits take effect without restarting the monitor
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			servers, err := builder.GetServerStatuses(cfg)
+			if err != nil {
+				ui.Failure.Printf("%s ✗ Failed to get server statuses: %v\n", timestamp(), err)
+			}
+			recordHealth(servers)
+
+			for _, status := range servers {
+				if !status.InConfig {
+					continue
+				}
+				if status.Status == "connected" {
+					if restarts[status.ConfigName] == 0 {
+						continue
+					}
+					if since, ok := connectedSince[status.ConfigName]; !ok {
+						connectedSince[status.ConfigName] = time.Now()
+					} else if time.Since(since) >= restartResetAfter {
+						delete(restarts, status.ConfigName)
+						delete(connectedSince, status.ConfigName)
+						ui.Muted.Printf("%s Server '%s' stayed connected, restart count reset\n", timestamp(), status.ConfigName)
+					}
+					continue
+				}
+				delete(connectedSince, status.ConfigName)
+				if status.Status != "failed" {
+					continue
+				}
+				server, _ := cfg.FindServer(status.ConfigName)
+				if !monitorAll && !server.AutoRestart {
+					continue
+				}
+				if restarts[status.ConfigName] >= monitorMaxRestarts {
+					if restarts[status.ConfigName] == monitorMaxRestarts {
+						ui.Warning.Printf("%s ⚠ Server '%s' reached the restart limit (%d), giving up\n", timestamp(), status.ConfigName, monitorMaxRestarts)
+						restarts[status.ConfigName]++
+					}
+					continue
+				}
+
+				restarts[status.ConfigName]++
+				ui.Info.Printf("%s Server '%s' failed, restarting (attempt %d/%d)...\n", timestamp(), status.ConfigName, restarts[status.ConfigName], monitorMaxRestarts)
+				if err := builder.StopServer(status.Name, false); err != nil {
+					ui.Failure.Printf("%s ✗ Failed to stop server '%s': %v\n", timestamp(), status.ConfigName, err)
+					continue
+				}
+				err := builder.StartServer(status.ConfigName, server, false)
+				noteStart(status.ConfigName, err == nil)
+				if err != nil {
+					ui.Failure.Printf("%s ✗ Failed to restart server '%s': %v\n", timestamp(), status.ConfigName, err)
+					continue
+				}
+				connectedSince[status.ConfigName] = time.Now()
+				ui.Success.Printf("%s ✓ Restarted server '%s'\n", timestamp(), status.ConfigName)
+			}
+
+			ui.Muted.Printf("%s checked %d server(s)\n", timestamp(), len(servers))
+
+			select {
+			case <-ctx.Done():
+				fmt.Println()
+				ui.Info.Println("Monitor stopped.")
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+// timestamp returns the current time formatted for monitor log lines
+func timestamp() string {
+	return time.Now().Format("15:04:05")
+}
+
+func init() {
+	monitorCmd.Flags().DurationVarP(&monitorInterval, "interval", "i", 30*time.Second, "Time between health checks")
+	monitorCmd.Flags().IntVar(&monitorMaxRestarts, "max-restarts", 3, "Maximum restarts per server")
+	monitorCmd.Flags().BoolVarP(&monitorAll, "all", "a", false, "Restart any failed server from the config, not just those with autoRestart")
+}
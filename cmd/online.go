@@ -4,16 +4,27 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
-	"cmcp/internal/config"
-	"github.com/fatih/color"
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/health"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
-	onlineDryRun bool
-	onlineClear  bool
-	onlineClean  bool
+	onlineDryRun     bool
+	onlineDryRunJSON bool
+	onlineClear      bool
+	onlineClean      bool
+	onlineExport     string
+	onlineExportFile string
+	onlineTags       []string
+	onlineLong       bool
+	onlineCompact    bool
+	onlineSort       string
+	onlineFilter     string
 )
 
 var onlineCmd = &cobra.Command{
@@ -22,12 +33,27 @@ var onlineCmd = &cobra.Command{
 	Long:  `Display a list of all MCP servers that are currently running in Claude for this project.
 	
 Use --clear to remove servers from Claude that are not in your cmcp config.
-Use --clean to remove servers that are failing to connect.`,
+Use --clean to remove servers that are failing to connect.
+Use --wide (or --long) to look up each server's scope, transport, full command, and env names,
+or --compact for one short line per server. Servers whose config changed since they
+were started are flagged, since Claude keeps running the old definition.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if onlineDryRunJSON && (!onlineDryRun || (!onlineClear && !onlineClean)) {
+			return fmt.Errorf("--json can only be used with --dry-run and --clear or --clean")
+		}
+		if len(onlineTags) > 0 && onlineClear {
+			return fmt.Errorf("--tag cannot be used with --clear (orphaned servers have no tags)")
+		}
+		if err := validateListFlags(onlineSort, onlineFilter); err != nil {
+			return err
+		}
+		if onlineCompact && onlineLong {
+			return fmt.Errorf("--compact cannot be used with --wide/--long")
+		}
+
 		// Handle dry-run mode for list command only
 		if onlineDryRun && !onlineClear && !onlineClean {
-			yellow := color.New(color.FgYellow)
-			yellow.Println("Would execute the following command:")
+			ui.Warning.Println("Would execute the following command:")
 			fmt.Println()
 			fmt.Printf("$ %s\n", builder.BuildListCommand())
 			return nil
@@ -42,10 +68,21 @@ Use --clean to remove servers that are failing to connect.`,
 
 		// Get server statuses from Claude
 		servers, err := builder.GetServerStatuses(cfg)
+		servers = filterStatusesByTags(cfg, servers, onlineTags)
+		if onlineExport != "" {
+			// "No servers" is a valid, empty status for monitoring purposes
+			if err != nil && !strings.Contains(err.Error(), "No MCP servers configured") {
+				return fmt.Errorf("failed to get server statuses: %w", err)
+			}
+			return exportStatuses(servers)
+		}
 		if err != nil {
 			// Check if it's the "no servers" case
 			if strings.Contains(err.Error(), "No MCP servers configured") {
-				color.Yellow("No servers are currently running in Claude for this project.")
+				if onlineDryRunJSON {
+					return printPlan(nil)
+				}
+				ui.Warning.Println("No servers are currently running in Claude for this project.")
 				fmt.Println("Use 'cmcp start' to start a server.")
 				return nil
 			}
@@ -53,21 +90,24 @@ Use --clean to remove servers that are failing to connect.`,
 		}
 
 		if len(servers) == 0 {
+			if onlineDryRunJSON {
+				return printPlan(nil)
+			}
 			// Special handling for --clean flag when no servers are running
 			if onlineClean {
-				color.Green("✓ No failed servers to clean.")
+				ui.Success.Println("✓ No failed servers to clean.")
 				fmt.Println()
-				color.Cyan("Note: --clean only removes servers that are failing to connect.")
+				ui.Info.Println("Note: --clean only removes servers that are failing to connect.")
 				return nil
 			}
 			// Special handling for --clear flag when no servers are running  
 			if onlineClear {
-				color.Green("✓ No orphaned servers to clear.")
+				ui.Success.Println("✓ No orphaned servers to clear.")
 				fmt.Println()
-				color.Cyan("Note: --clear only removes servers that are NOT in your cmcp config.")
+				ui.Info.Println("Note: --clear only removes servers that are NOT in your cmcp config.")
 				return nil
 			}
-			color.Yellow("No servers are currently running in Claude for this project.")
+			ui.Warning.Println("No servers are currently running in Claude for this project.")
 			fmt.Println("Use 'cmcp start' to start a server.")
 			return nil
 		}
@@ -82,46 +122,50 @@ Use --clean to remove servers that are failing to connect.`,
 
 		// Handle --clear flag
 		if onlineClear {
+			if onlineDryRunJSON {
+				var steps []mcp.PlanStep
+				for _, name := range orphanedServers {
+					steps = append(steps, builder.PlanStop(name))
+				}
+				return printPlan(steps)
+			}
+
 			if len(orphanedServers) == 0 {
-				color.Green("✓ No orphaned servers to clear.")
+				ui.Success.Println("✓ No orphaned servers to clear.")
 				fmt.Println()
-				color.Cyan("Note: --clear only removes servers that are NOT in your cmcp config.")
+				ui.Info.Println("Note: --clear only removes servers that are NOT in your cmcp config.")
 				fmt.Println("Failed servers that are in your config will remain until you stop them.")
 				return nil
 			}
 
 			// Show what will be cleared
-			color.Yellow("The following servers will be cleared from Claude:")
+			ui.Warning.Println("The following servers will be cleared from Claude:")
 			for _, name := range orphanedServers {
 				fmt.Printf("  - %s\n", name)
 			}
 			fmt.Println()
 
 			if onlineDryRun {
-				yellow := color.New(color.FgYellow)
-				yellow.Println("Would execute the following commands:")
+				ui.Warning.Println("Would execute the following commands:")
 				for _, name := range orphanedServers {
-					fmt.Printf("$ claude mcp remove %s\n", name)
+					fmt.Printf("$ %s\n", builder.BuildStopCommand(name))
 				}
 				return nil
 			}
 
 			// Clear each orphaned server
-			cyan := color.New(color.FgCyan)
-			green := color.New(color.FgGreen)
-			red := color.New(color.FgRed)
 			
 			for _, name := range orphanedServers {
-				cyan.Printf("Clearing server '%s' from Claude...\n", name)
+				ui.Info.Printf("Clearing server '%s' from Claude...\n", name)
 				if err := builder.StopServer(name, false); err != nil {
-					red.Printf("✗ Failed to clear server '%s': %v\n", name, err)
+					ui.Failure.Printf("✗ Failed to clear server '%s': %v\n", name, err)
 				} else {
-					green.Printf("✓ Cleared server '%s'\n", name)
+					ui.Success.Printf("✓ Cleared server '%s'\n", name)
 				}
 			}
 			
 			fmt.Println()
-			color.Green("✓ Cleanup complete!")
+			ui.Success.Println("✓ Cleanup complete!")
 			return nil
 		}
 
@@ -135,103 +179,206 @@ Use --clean to remove servers that are failing to connect.`,
 				}
 			}
 
+			if onlineDryRunJSON {
+				var steps []mcp.PlanStep
+				for _, name := range failedServers {
+					steps = append(steps, builder.PlanStop(name))
+				}
+				return printPlan(steps)
+			}
+
 			if len(failedServers) == 0 {
-				color.Green("✓ No failed servers to clean.")
+				ui.Success.Println("✓ No failed servers to clean.")
 				fmt.Println()
-				color.Cyan("Note: --clean only removes servers that are failing to connect.")
+				ui.Info.Println("Note: --clean only removes servers that are failing to connect.")
 				return nil
 			}
 
 			// Show what will be cleaned
-			color.Yellow("The following failed servers will be removed from Claude:")
+			ui.Warning.Println("The following failed servers will be removed from Claude:")
 			for _, name := range failedServers {
 				fmt.Printf("  - %s\n", name)
 			}
 			fmt.Println()
 
 			if onlineDryRun {
-				yellow := color.New(color.FgYellow)
-				yellow.Println("Would execute the following commands:")
+				ui.Warning.Println("Would execute the following commands:")
 				for _, name := range failedServers {
-					fmt.Printf("$ claude mcp remove %s\n", name)
+					fmt.Printf("$ %s\n", builder.BuildStopCommand(name))
 				}
 				return nil
 			}
 
 			// Clean each failed server
-			cyan := color.New(color.FgCyan)
-			green := color.New(color.FgGreen)
-			red := color.New(color.FgRed)
 			
 			for _, name := range failedServers {
-				cyan.Printf("Removing failed server '%s' from Claude...\n", name)
+				ui.Info.Printf("Removing failed server '%s' from Claude...\n", name)
 				if err := builder.StopServer(name, false); err != nil {
-					red.Printf("✗ Failed to remove server '%s': %v\n", name, err)
+					ui.Failure.Printf("✗ Failed to remove server '%s': %v\n", name, err)
 				} else {
-					green.Printf("✓ Removed failed server '%s'\n", name)
+					ui.Success.Printf("✓ Removed failed server '%s'\n", name)
 				}
 			}
 			
 			fmt.Println()
-			color.Green("✓ Failed servers cleaned!")
+			ui.Success.Println("✓ Failed servers cleaned!")
 			return nil
 		}
 
 		// Normal online display mode
+		history := recordHealth(servers)
+		now := time.Now()
+
+		servers, _ = mcp.FilterStatuses(servers, onlineFilter)
+		mcp.SortStatuses(servers, onlineSort)
+		if len(servers) == 0 {
+			ui.Warning.Printf("No %s servers are running in Claude for this project.\n", onlineFilter)
+			return nil
+		}
+
+		if onlineCompact {
+			for _, server := range servers {
+				icon := statusIcon(server.Status)
+				fmt.Printf("%s %s\n", icon.Color.Sprint(icon.Text), server.Name)
+			}
+			return nil
+		}
+
+		var definitions map[string]*mcp.ClaudeDefinition
+		if onlineLong {
+			names := make([]string, len(servers))
+			for i, server := range servers {
+				names[i] = server.Name
+			}
+			definitions = builder.GetClaudeDefinitions(names)
+		}
+
+		// Servers with a healthCheck get it run while connected, beside Claude's status
+		var connected []string
+		showChecks := false
+		for _, server := range servers {
+			if server.InConfig && cfg.MCPServers[server.ConfigName].HealthCheck != "" {
+				showChecks = true
+				if server.Status == "connected" {
+					connected = append(connected, server.ConfigName)
+				}
+			}
+		}
+		var checks map[string]mcp.HealthCheckResult
+		if len(connected) > 0 {
+			checks = mcp.RunHealthChecks(cmd.Context(), cfg, connected)
+		}
+
 		// Get current directory for context
 		cwd, _ := os.Getwd()
 		
 		// Print header with project context
 		fmt.Println()
-		color.Cyan("MCP servers running in Claude for this project:")
-		grayColor := color.New(color.FgHiBlack)
-		grayColor.Printf("Project: %s\n", cwd)
+		ui.Info.Println("MCP servers running in Claude for this project:")
+		ui.Muted.Printf("Project: %s\n", cwd)
 		fmt.Println()
 
-		// Define colors for different statuses
-		greenCheck := color.New(color.FgGreen).Sprint("✓")
-		redCross := color.New(color.FgRed).Sprint("✗")
-		yellowDot := color.New(color.FgYellow).Sprint("•")
-		cyanColor := color.New(color.FgCyan)
+		columns := []ui.Column{
+			{},
+			{Header: "NAME"},
+			{Header: "COMMAND", Flex: true},
+			{Header: "STATUS"},
+			{Header: "TAGS", Flex: true},
+		}
+		if onlineLong {
+			columns = append(columns, ui.Column{Header: "SCOPE"}, ui.Column{Header: "TYPE"}, ui.Column{Header: "ENV"})
+		}
+		if showChecks {
+			columns = append(columns, ui.Column{Header: "CHECK", Flex: true})
+		}
+		columns = append(columns, ui.Column{Header: "HEALTH", Flex: true})
+		table := ui.NewTable(columns...)
+		if onlineLong {
+			// --wide shows commands in full
+			table.Width = 0
+		}
 
-		// Print servers
 		for _, server := range servers {
-			// Determine status icon
-			statusIcon := yellowDot
-			statusText := "Unknown"
-			statusColor := color.New(color.FgYellow)
-			
+			icon := statusIcon(server.Status)
+			status := ui.Cell{Text: "Unknown", Color: icon.Color}
 			switch server.Status {
 			case "connected":
-				statusIcon = greenCheck
-				statusText = "Connected"
-				statusColor = color.New(color.FgGreen)
+				status.Text = "Connected"
 			case "failed":
-				statusIcon = redCross
-				statusText = "Failed to connect"
-				statusColor = color.New(color.FgRed)
+				status.Text = "Failed to connect"
 			}
 
-			// Print server info
-			if server.InConfig {
-				fmt.Printf("%s %s: ", statusIcon, cyanColor.Sprint(server.Name))
-			} else {
-				fmt.Printf("%s %s: ", statusIcon, color.New(color.FgYellow).Sprint(server.Name))
+			// Orphaned servers stand out in ui.Warning.Color()
+			name := ui.Cell{Text: server.Name, Color: ui.Info.Color()}
+			if !server.InConfig {
+				name.Color = ui.Warning.Color()
+			} else if server.ConfigName != server.Name {
+				name.Text += " (" + server.ConfigName + ")"
 			}
-			
-			// Truncate command if too long
-			command := server.Command
-			if len(command) > 50 {
-				command = command[:47] + "..."
+
+			row := []ui.Cell{
+				icon,
+				name,
+				{Text: server.Command, Color: ui.Muted.Color()},
+				status,
+				{Text: strings.Join(cfg.MCPServers[server.ConfigName].Tags, ", ")},
 			}
-			fmt.Printf("%s - %s\n", grayColor.Sprint(command), statusColor.Sprint(statusText))
+
+			if onlineLong {
+				var scope, transport, env string
+				if def, ok := definitions[server.Name]; ok {
+					scope, _, _ = strings.Cut(def.Scope, " (")
+					transport = def.Type
+					env = strings.Join(def.EnvKeys, ", ")
+				}
+				row = append(row, ui.Cell{Text: scope}, ui.Cell{Text: transport}, ui.Cell{Text: env, Color: ui.Secret.Color()})
+			}
+
+			if showChecks {
+				check := ui.Cell{}
+				if result, ok := checks[server.ConfigName]; ok {
+					check = ui.Cell{Text: "✓ " + result.Detail, Color: ui.Success.Color()}
+					if !result.OK {
+						check = ui.Cell{Text: "✗ " + result.Detail, Color: ui.Failure.Color()}
+					}
+				}
+				row = append(row, check)
+			}
+
+			// Health history from previous observations
+			var notes []string
+			if h, ok := history.Get(server.Name); ok {
+				if server.Status != "connected" {
+					notes = append(notes, "last connected "+health.FormatAgo(h.LastConnected, now))
+				}
+				if h.Failures > 0 {
+					notes = append(notes, fmt.Sprintf("%d failure(s)", h.Failures))
+				}
+				if h.IsFlapping(now) {
+					notes = append(notes, "flapping")
+				}
+			}
+			row = append(row, ui.Cell{Text: strings.Join(notes, ", "), Color: ui.Muted.Color()})
+
+			table.Add(row...)
+		}
+		table.Render()
+
+		// Servers whose config changed since they were started still run the old definition
+		if drifted := mcp.FindDrift(cfg, servers); len(drifted) > 0 {
+			fmt.Println()
+			var names []string
+			for _, drift := range drifted {
+				printDrift(drift)
+				names = append(names, drift.Name)
+			}
+			ui.Muted.Color().Printf("  Run 'cmcp start %s --force' to re-add with the configured definition.\n", strings.Join(names, " "))
 		}
 
 		// If there are orphaned servers, show how to clear them
-		if len(orphanedServers) > 0 {
+		if len(orphanedServers) > 0 && (onlineFilter == "" || onlineFilter == "orphaned") {
 			fmt.Println()
-			yellowWarning := color.New(color.FgYellow)
-			yellowWarning.Printf("⚠ Found %d server(s) in Claude that are not in your cmcp config:\n", len(orphanedServers))
+			ui.Warning.Printf("⚠ Found %d server(s) in Claude that are not in your cmcp config:\n", len(orphanedServers))
 			
 			for _, name := range orphanedServers {
 				fmt.Printf("  - %s\n", name)
@@ -239,15 +386,93 @@ Use --clean to remove servers that are failing to connect.`,
 			
 			fmt.Println()
 			fmt.Println("To clear these servers from Claude, run:")
-			fmt.Printf("  $ %s\n", color.New(color.FgCyan).Sprint("cmcp online --clear"))
+			fmt.Printf("  $ %s\n", ui.Info.Color().Sprint("cmcp online --clear"))
 		}
 
 		return nil
 	},
 }
 
+// statusIcon returns the colored icon for a Claude connection status
+func statusIcon(status string) ui.Cell {
+	switch status {
+	case "connected":
+		return ui.Cell{Text: "✓", Color: ui.Success.Color()}
+	case "failed":
+		return ui.Cell{Text: "✗", Color: ui.Failure.Color()}
+	}
+	return ui.Cell{Text: "•", Color: ui.Warning.Color()}
+}
+
+// validateListFlags checks --sort and --filter values before any claude calls are made
+func validateListFlags(sortBy, filter string) error {
+	if err := mcp.SortStatuses(nil, sortBy); err != nil {
+		return err
+	}
+	_, err := mcp.FilterStatuses(nil, filter)
+	return err
+}
+
+// filterStatusesByTags keeps configured servers with one of the given tags (if any)
+func filterStatusesByTags(cfg *config.Config, servers []mcp.ServerStatus, tags []string) []mcp.ServerStatus {
+	if len(tags) == 0 {
+		return servers
+	}
+
+	var filtered []mcp.ServerStatus
+	for _, server := range servers {
+		if configured, exists := cfg.MCPServers[server.ConfigName]; exists && configured.HasAnyTag(tags) {
+			filtered = append(filtered, server)
+		}
+	}
+	return filtered
+}
+
+// exportStatuses writes server statuses for external monitoring, to a file or stdout
+func exportStatuses(servers []mcp.ServerStatus) error {
+	history := recordHealth(servers)
+	output, err := mcp.ExportStatuses(onlineExport, servers, history, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if onlineExportFile == "" {
+		fmt.Print(output)
+		return nil
+	}
+	if err := mcp.WriteFileAtomic(onlineExportFile, []byte(output)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", onlineExportFile, err)
+	}
+	return nil
+}
+
+// recordHealth stores the observed statuses in the health history and returns it.
+// History is best-effort: failures to read or write it never fail the command.
+func recordHealth(servers []mcp.ServerStatus) *health.History {
+	history, err := health.Load(config.StatePath("health.json"))
+	if err != nil {
+		return health.New("")
+	}
+
+	now := time.Now()
+	for _, server := range servers {
+		history.Record(server.Name, server.Status, now)
+	}
+	history.Save()
+	return history
+}
+
 func init() {
 	onlineCmd.Flags().BoolVarP(&onlineDryRun, "dry-run", "n", false, "Show command that would be executed without running it")
+	onlineCmd.Flags().BoolVar(&onlineDryRunJSON, "json", false, "With --dry-run and --clear/--clean, print the plan as JSON")
 	onlineCmd.Flags().BoolVarP(&onlineClear, "clear", "c", false, "Clear orphaned servers (servers in Claude but NOT in your cmcp config)")
 	onlineCmd.Flags().BoolVar(&onlineClean, "clean", false, "Remove failed servers from Claude")
+	onlineCmd.Flags().StringVar(&onlineExport, "export", "", "Export statuses for monitoring (prometheus or statusfile)")
+	onlineCmd.Flags().StringVar(&onlineExportFile, "export-file", "", "Write the export to this file instead of stdout")
+	onlineCmd.Flags().StringSliceVar(&onlineTags, "tag", nil, "Only show configured servers with this tag (repeatable)")
+	onlineCmd.Flags().BoolVar(&onlineLong, "wide", false, "Show full commands plus scope, transport, and env names for each server")
+	onlineCmd.Flags().BoolVarP(&onlineLong, "long", "l", false, "Same as --wide")
+	onlineCmd.Flags().BoolVar(&onlineCompact, "compact", false, "Show one short line per server")
+	onlineCmd.Flags().StringVar(&onlineSort, "sort", "name", "Sort by name or status (failed first)")
+	onlineCmd.Flags().StringVar(&onlineFilter, "filter", "", "Only show connected, failed, or orphaned servers")
 }
\ No newline at end of file
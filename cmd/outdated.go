@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/registry"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	upgradeNoRestart bool
+	upgradeDryRun    bool
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "Check npx/uvx servers for newer package versions",
+	Long: `Check the npm and PyPI packages that npx and uvx servers are launched from,
+and show which ones have newer releases than the pinned version.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		names := cfg.GetServerNames()
+
+		checked := 0
+		for _, name := range names {
+			server, _ := cfg.FindServer(name)
+			ref, ok := registry.FindPackage(server)
+			if !ok {
+				continue
+			}
+			checked++
+
+			current := ref.Version
+			if current == "" {
+				current = "unpinned"
+			}
+
+			latest, err := registry.LatestVersion(context.Background(), ref)
+			if err != nil {
+				fmt.Printf("%s %s %s\n", ui.Emphasis.Sprint(name), ui.Muted.Sprint(ref.Name), ui.Failure.Sprint(fmt.Sprintf("(check failed: %v)", err)))
+				continue
+			}
+
+			status := ui.Success.Sprint("up to date")
+			if ref.Version != latest {
+				status = ui.Warning.Sprint(fmt.Sprintf("→ %s", latest))
+			}
+			fmt.Printf("%s %s %s %s\n", ui.Emphasis.Sprint(name), ui.Muted.Sprint(ref.Name), current, status)
+		}
+
+		if checked == 0 {
+			ui.Warning.Println("No npx or uvx servers configured.")
+			return nil
+		}
+
+		fmt.Println()
+		fmt.Println("Run 'cmcp upgrade <server>' to pin the latest version.")
+		return nil
+	},
+}
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade <server-name...>",
+	Short: "Pin npx/uvx servers to their latest package version",
+	Long: `Update the pinned package version of npx and uvx servers to the latest release
+and restart them in Claude if they are currently running.`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		var upgraded []string
+		for _, name := range args {
+			server, exists := cfg.FindServer(name)
+			if !exists {
+				return &config.ServerNotFoundError{Name: name}
+			}
+			ref, ok := registry.FindPackage(server)
+			if !ok {
+				return fmt.Errorf("server '%s' is not launched from an npm or PyPI package", name)
+			}
+
+			latest, err := registry.LatestVersion(context.Background(), ref)
+			if err != nil {
+				ui.Failure.Printf("✗ Failed to check '%s': %v\n", name, err)
+				continue
+			}
+			if ref.Version == latest {
+				fmt.Printf("Server '%s' is already at %s\n", name, latest)
+				continue
+			}
+
+			from := ref.Version
+			if from == "" {
+				from = "unpinned"
+			}
+			if upgradeDryRun {
+				fmt.Printf("Would upgrade '%s': %s → %s\n", name, from, ref.Spec(latest))
+				continue
+			}
+
+			args := make([]string, len(server.Args))
+			copy(args, server.Args)
+			args[ref.ArgIndex] = ref.Spec(latest)
+			server.Args = args
+			cfg.MCPServers[name] = *server
+			upgraded = append(upgraded, name)
+			ui.Success.Printf("✓ Upgraded '%s': %s → %s\n", name, from, latest)
+		}
+
+		if len(upgraded) == 0 {
+			return nil
+		}
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		if upgradeNoRestart {
+			return nil
+		}
+
+		// Restart running servers so Claude picks up the new version
+		for _, name := range upgraded {
+			if !builder.IsRunning(builder.ClaudeName(name)) {
+				continue
+			}
+			server, _ := cfg.FindServer(name)
+			ui.Info.Printf("Restarting server '%s' in Claude for this project...\n", name)
+			if err := builder.StopServer(builder.ClaudeName(name), false); err != nil {
+				ui.Failure.Printf("✗ Failed to stop server '%s': %v\n", name, err)
+				continue
+			}
+			err := builder.StartServer(name, server, false)
+			noteStart(name, err == nil)
+			if err != nil {
+				ui.Failure.Printf("✗ Failed to start server '%s': %v\n", name, err)
+				continue
+			}
+			ui.Success.Printf("✓ Restarted server '%s'\n", name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	upgradeCmd.Flags().BoolVar(&upgradeNoRestart, "no-restart", false, "Only update the config, don't restart running servers")
+	upgradeCmd.Flags().BoolVarP(&upgradeDryRun, "dry-run", "n", false, "Show upgrades that would be made without changing anything")
+}
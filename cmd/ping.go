@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pingCount   int
+	pingTimeout time.Duration
+)
+
+var pingCmd = &cobra.Command{
+	Use:   "ping <server-name>",
+	Short: "Measure a server's startup and handshake latency",
+	Long: `Launch a configured MCP server several times and measure process spawn time,
+time until the initialize response, and the tools/list round trip.
+Useful for diagnosing servers that exceed Claude's connection timeout.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if pingCount < 1 {
+			return fmt.Errorf("--count must be at least 1")
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		serverName := args[0]
+		server, exists := cfg.FindServer(serverName)
+		if !exists {
+			return &config.ServerNotFoundError{Name: serverName}
+		}
+
+		server, err = mcp.ResolveLaunch(serverName, server)
+		if err != nil {
+			return err
+		}
+
+		ui.Info.Printf("Pinging server '%s' %d time(s)...\n\n", serverName, pingCount)
+
+		var spawn, initialize, toolsList []time.Duration
+		failures := 0
+		for i := 0; i < pingCount; i++ {
+			ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+			result, err := mcp.Ping(ctx, server)
+			cancel()
+
+			if err != nil {
+				failures++
+				ui.Failure.Printf("  run %d: ✗ %v\n", i+1, err)
+				continue
+			}
+
+			spawn = append(spawn, result.Spawn)
+			initialize = append(initialize, result.Initialize)
+			toolsList = append(toolsList, result.ToolsList)
+			ui.Muted.Printf("  run %d: spawn %v, initialize %v, tools/list %v (%d tools)\n",
+				i+1, roundDuration(result.Spawn), roundDuration(result.Initialize),
+				roundDuration(result.ToolsList), result.Tools)
+		}
+
+		if len(initialize) == 0 {
+			return fmt.Errorf("all %d run(s) failed", pingCount)
+		}
+
+		fmt.Println()
+		fmt.Printf("%-12s %10s %10s %10s\n", "", "p50", "p90", "max")
+		printPingRow("spawn", spawn)
+		printPingRow("initialize", initialize)
+		printPingRow("tools/list", toolsList)
+
+		if failures > 0 {
+			fmt.Println()
+			ui.Failure.Printf("%d of %d run(s) failed\n", failures, pingCount)
+		}
+		return nil
+	},
+}
+
+// printPingRow prints percentile columns for one phase
+func printPingRow(label string, durations []time.Duration) {
+	fmt.Printf("%-12s %10v %10v %10v\n", label,
+		roundDuration(mcp.Percentile(durations, 50)),
+		roundDuration(mcp.Percentile(durations, 90)),
+		roundDuration(mcp.Percentile(durations, 100)))
+}
+
+// roundDuration rounds to a readable precision for display
+func roundDuration(d time.Duration) time.Duration {
+	return d.Round(100 * time.Microsecond)
+}
+
+func init() {
+	pingCmd.Flags().IntVarP(&pingCount, "count", "c", 5, "Number of runs")
+	pingCmd.Flags().DurationVar(&pingTimeout, "timeout", 30*time.Second, "Maximum time for each run")
+}
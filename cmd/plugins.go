@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/plugin"
+	"github.com/spf13/cobra"
+)
+
+// registerPlugins adds a subcommand for every cmcp-<name> executable on PATH,
+// git-style. Built-in commands take precedence over plugins with the same name.
+func registerPlugins() {
+	plugins := plugin.Find(os.Getenv("PATH"))
+	if len(plugins) == 0 {
+		return
+	}
+
+	// Once groups exist cobra files ungrouped commands under "Additional Commands",
+	// so give the built-in commands their own group
+	rootCmd.AddGroup(
+		&cobra.Group{ID: "builtin", Title: "Available Commands:"},
+		&cobra.Group{ID: "plugins", Title: "Plugin Commands:"},
+	)
+	for _, c := range rootCmd.Commands() {
+		if c.GroupID == "" {
+			c.GroupID = "builtin"
+		}
+	}
+	rootCmd.SetHelpCommandGroupID("builtin")
+	rootCmd.SetCompletionCommandGroupID("builtin")
+
+	for _, p := range plugins {
+		if existing, _, err := rootCmd.Find([]string{p.Name}); err == nil && existing != rootCmd {
+			continue
+		}
+
+		p := p
+		rootCmd.AddCommand(&cobra.Command{
+			Use:                p.Name,
+			Short:              "Plugin provided by " + p.Path,
+			GroupID:            "plugins",
+			DisableFlagParsing: true,
+			SilenceUsage:       true,
+			SilenceErrors:      true,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				configPath, _ := config.GetConfigPath()
+				return plugin.Run(p, args, configPath)
+			},
+		})
+	}
+}
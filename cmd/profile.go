@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage configuration profiles",
+	Long: `Keep several independent server configurations and switch between them.
+The default profile is your main config file; other profiles are stored in the
+profiles directory next to it.`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles and mark the active one",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profiles, err := config.ListProfiles()
+		if err != nil {
+			return fmt.Errorf("failed to list profiles: %w", err)
+		}
+
+		active := config.ActiveProfile()
+		for _, name := range profiles {
+			if name == active {
+				fmt.Printf("%s %s %s\n", ui.Success.Sprint("●"), name, ui.Muted.Sprint("(active)"))
+			} else {
+				fmt.Printf("%s %s\n", ui.Muted.Sprint("○"), name)
+			}
+		}
+		return nil
+	},
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:          "create <profile>",
+	Short:        "Create an empty profile",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.CreateProfile(args[0]); err != nil {
+			return err
+		}
+		ui.Success.Printf("✓ Created profile '%s'\n", args[0])
+		fmt.Printf("Switch to it with 'cmcp profile switch %s'\n", args[0])
+		return nil
+	},
+}
+
+var profileCopyCmd = &cobra.Command{
+	Use:          "copy <source> <profile>",
+	Short:        "Create a profile from a copy of another profile",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.CopyProfile(args[0], args[1]); err != nil {
+			return err
+		}
+		ui.Success.Printf("✓ Copied profile '%s' to '%s'\n", args[0], args[1])
+		return nil
+	},
+}
+
+var profileSwitchCmd = &cobra.Command{
+	Use:          "switch <profile>",
+	Short:        "Make a profile the active configuration",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.SwitchProfile(args[0]); err != nil {
+			return err
+		}
+		ui.Success.Printf("✓ Switched to profile '%s'\n", args[0])
+		ui.Muted.Println("Servers already running in Claude are unchanged; stop them with 'cmcp reset' if needed.")
+		return nil
+	},
+}
+
+var profileDeleteCmd = &cobra.Command{
+	Use:          "delete <profile>",
+	Short:        "Delete a profile",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.DeleteProfile(args[0]); err != nil {
+			return err
+		}
+		ui.Success.Printf("✓ Deleted profile '%s'\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileSwitchCmd)
+	profileCmd.AddCommand(profileDeleteCmd)
+	profileCmd.AddCommand(profileCopyCmd)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var pullVerbose bool
+
+var pullCmd = &cobra.Command{
+	Use:   "pull [server-name...]",
+	Short: "Pull docker images for docker-based servers",
+	Long: `Pull the latest docker image for each docker-based server, so starting them
+doesn't fail or stall on a missing image. Pulls all docker servers when no names are given.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		names := args
+		if len(names) == 0 {
+			names = cfg.GetServerNames()
+		}
+
+		pulled, failed := 0, 0
+		for _, name := range names {
+			server, exists := cfg.FindServer(name)
+			if !exists {
+				return &config.ServerNotFoundError{Name: name}
+			}
+			if server.Command != "docker" {
+				if len(args) > 0 {
+					ui.Warning.Printf("Server '%s' is not a docker server, skipping.\n", name)
+				}
+				continue
+			}
+			image, ok := mcp.DockerImage(server.Args)
+			if !ok {
+				ui.Warning.Printf("Could not find an image in the args of '%s', skipping.\n", name)
+				continue
+			}
+
+			ui.Info.Printf("Pulling %s for '%s'...\n", image, name)
+			if err := mcp.PullImage(image, pullVerbose); err != nil {
+				ui.Failure.Printf("✗ %v\n", err)
+				failed++
+				continue
+			}
+			ui.Success.Printf("✓ Pulled %s\n", image)
+			pulled++
+		}
+
+		if pulled == 0 && failed == 0 {
+			ui.Warning.Println("No docker servers to pull.")
+			return nil
+		}
+		if failed > 0 {
+			return fmt.Errorf("failed to pull %d image(s)", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	pullCmd.Flags().BoolVarP(&pullVerbose, "verbose", "v", false, "Show docker pull progress")
+}
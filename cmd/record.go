@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var recordOutput string
+
+var recordCmd = &cobra.Command{
+	Use:   "record <server-name>",
+	Short: "Run a server and record its JSON-RPC session to a file",
+	Long: `Run a configured server in the foreground like 'cmcp run', and write every JSON-RPC
+message exchanged on stdin and stdout to a JSON lines file, with timestamps. Output on
+stdout that isn't JSON is recorded too, since it usually breaks clients.
+
+Use it as the command of a server to capture what Claude sends, then attach the file
+to a bug report. 'cmcp replay' sends the recorded requests to the server again.`,
+	Example: `  cmcp record github -o github-session.jsonl
+  claude mcp add github-recorded -- cmcp record github -o /tmp/github-session.jsonl
+  cmcp replay github /tmp/github-session.jsonl`,
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true, // Left to main, which exits with the recorded server's status
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		serverName := args[0]
+		server, exists := cfg.FindServer(serverName)
+		if !exists {
+			return &config.ServerNotFoundError{Name: serverName}
+		}
+		if server.IsRemote() {
+			return fmt.Errorf("server '%s' is a remote server (%s); only stdio servers can be recorded", serverName, server.URL)
+		}
+
+		path := recordOutput
+		if path == "" {
+			path = fmt.Sprintf("cmcp-record-%s-%s.jsonl", serverName, time.Now().Format("20060102-150405"))
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create recording: %w", err)
+		}
+		defer file.Close()
+
+		// stdout carries the session, so report on stderr
+		ui.Muted.Color().Fprintf(os.Stderr, "Recording '%s' to %s\n", serverName, path)
+
+		recorder := mcp.NewRecorder(file)
+		runErr := mcp.Run(serverName, server, recorder.Client(os.Stdin), recorder.Server(os.Stdout), os.Stderr)
+		if err := recorder.Err(); err != nil {
+			return fmt.Errorf("failed to write recording: %w", err)
+		}
+		return runErr
+	},
+}
+
+func init() {
+	recordCmd.Flags().StringVarP(&recordOutput, "output", "o", "", "Recording file (default: cmcp-record-<server>-<time>.jsonl)")
+}
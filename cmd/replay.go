@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replayTimeout time.Duration
+	replayShow    bool
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <server-name> <recording>",
+	Short: "Send the requests of a recorded session to a server again",
+	Long: `Launch a configured server (or connect to its URL) and send the client messages of a
+recording made with 'cmcp record', in order, starting with the recorded initialize.
+Each response is compared with the recorded one, so a server author can reproduce a
+reported session and check whether a fix changes the outcome.`,
+	Example: `  cmcp replay github github-session.jsonl
+  cmcp replay github github-session.jsonl --show`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		serverName := args[0]
+		server, exists := cfg.FindServer(serverName)
+		if !exists {
+			return &config.ServerNotFoundError{Name: serverName}
+		}
+
+		file, err := os.Open(args[1])
+		if err != nil {
+			return fmt.Errorf("failed to open recording: %w", err)
+		}
+		messages, err := mcp.LoadRecording(file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+
+		server, err = mcp.ResolveLaunch(serverName, server)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+		defer cancel()
+
+		var same, differ, failed int
+		err = mcp.Replay(ctx, server, messages, func(result mcp.ReplayResult) {
+			switch {
+			case result.Err != nil:
+				failed++
+				ui.Failure.Printf("✗ %s: %v\n", result.Method, result.Err)
+			case result.Recorded == nil:
+				differ++
+				ui.Warning.Printf("? %s (no recorded response)\n", result.Method)
+				printRawJSON(result.Response)
+			case result.Same():
+				same++
+				ui.Success.Printf("✓ %s", result.Method)
+				ui.Muted.Println(" same as recording")
+				if replayShow {
+					printRawJSON(result.Response)
+				}
+			default:
+				differ++
+				ui.Warning.Printf("≠ %s differs from recording\n", result.Method)
+				ui.Muted.Println("  recorded:")
+				printRawJSON(result.Recorded)
+				ui.Muted.Println("  now:")
+				printRawJSON(result.Response)
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to replay recording: %w", err)
+		}
+
+		fmt.Printf("\n%d same, %d different, %d failed\n", same, differ, failed)
+		if differ > 0 || failed > 0 {
+			return fmt.Errorf("replay of '%s' did not match the recording", serverName)
+		}
+		return nil
+	},
+}
+
+func init() {
+	replayCmd.Flags().DurationVar(&replayTimeout, "timeout", 2*time.Minute, "Timeout for the whole replay")
+	replayCmd.Flags().BoolVar(&replayShow, "show", false, "Print responses that match the recording too")
+}
@@ -2,48 +2,127 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
-	"cmcp/internal/config"
-	"github.com/fatih/color"
-	"github.com/manifoldco/promptui"
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
 	"github.com/spf13/cobra"
 )
 
-var resetDryRun bool
+var (
+	resetDryRun     bool
+	resetDryRunJSON bool
+	resetTags       []string
+	resetKeep       []string
+	resetOrphans    bool
+	resetScope      string
+	resetYes        bool
+)
 
 var resetCmd = &cobra.Command{
 	Use:   "reset",
 	Short: "Stop all running MCP servers in Claude for this project",
-	Long:  `Stop all currently running MCP servers in Claude for the current project.`,
+	Long: `Stop all currently running MCP servers in Claude for the current project.
+Use --tag to only stop servers with a given tag, and --keep to leave specific servers running.
+With --orphans (or --everything), servers registered in Claude that are not in your cmcp
+config are removed as well, wiping every MCP registration for the project.
+With --scope local|project|user|all, servers are removed from those Claude scopes
+instead of whichever scope they were started in; user scope servers are removed for
+all your projects.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if resetDryRunJSON && !resetDryRun {
+			return fmt.Errorf("--json can only be used with --dry-run")
+		}
+		if resetOrphans && len(resetTags) > 0 {
+			return fmt.Errorf("--tag cannot be used with --orphans (orphaned servers have no tags)")
+		}
+		if resetScope != "all" && mcp.ValidateScope(resetScope) != nil {
+			return fmt.Errorf("invalid scope '%s': expected local, project, user or all", resetScope)
+		}
+
 		// Load config to get our registered servers
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
+		if resetScope != "" {
+			return resetScopes(cfg)
+		}
+
+		// Orphans come from Claude's list; no servers at all is not an error here
+		var orphanedServers []string
+		if resetOrphans {
+			statuses, err := builder.GetServerStatuses(cfg)
+			if err != nil && !strings.Contains(err.Error(), "No MCP servers configured") {
+				return fmt.Errorf("failed to get server statuses: %w", err)
+			}
+			for _, status := range statuses {
+				if !status.InConfig {
+					orphanedServers = append(orphanedServers, status.Name)
+				}
+			}
+		}
+
+		kept := make(map[string]bool)
+		for _, name := range resetKeep {
+			if _, exists := cfg.MCPServers[name]; !exists && !slices.Contains(orphanedServers, name) {
+				return &config.ServerNotFoundError{Name: name}
+			}
+			kept[name] = true
+		}
+		orphanedServers = removeNames(orphanedServers, kept)
+
 		// Find which servers from our config are actually running in Claude
 		var runningServers []string
-		for name := range cfg.MCPServers {
-			if builder.IsRunning(name) {
-				runningServers = append(runningServers, name)
+		for _, name := range cfg.FilterByTags(cfg.GetServerNames(), resetTags) {
+			if kept[name] {
+				continue
+			}
+			// Servers sharing an alias are one server in Claude
+			claudeName := builder.ClaudeName(name)
+			if !slices.Contains(runningServers, claudeName) && builder.IsRunning(claudeName) {
+				runningServers = append(runningServers, claudeName)
 			}
 		}
+		configured := len(runningServers)
+		runningServers = append(runningServers, orphanedServers...)
+
+		if resetDryRun && resetDryRunJSON {
+			var steps []mcp.PlanStep
+			for _, name := range runningServers {
+				steps = append(steps, builder.PlanStop(name))
+			}
+			return printPlan(steps)
+		}
 
 		if len(runningServers) == 0 {
-			color.Yellow("No servers from your config are currently running in Claude for this project.")
+			if resetOrphans {
+				ui.Warning.Println("No servers are currently registered in Claude for this project.")
+			} else {
+				ui.Warning.Println("No servers from your config are currently running in Claude for this project.")
+			}
 			return nil
 		}
 
-		color.Cyan("Found %d running server(s) from your config in Claude for this project:\n", len(runningServers))
-		for _, name := range runningServers {
-			fmt.Printf("  - %s\n", name)
+		if configured > 0 {
+			ui.Info.Printf("Found %d running server(s) from your config in Claude for this project:\n", configured)
+			for _, name := range runningServers[:configured] {
+				fmt.Printf("  - %s\n", name)
+			}
+		}
+		if len(orphanedServers) > 0 {
+			ui.Info.Printf("Found %d server(s) in Claude that are not in your cmcp config:\n", len(orphanedServers))
+			for _, name := range orphanedServers {
+				fmt.Printf("  - %s\n", name)
+			}
 		}
 
 		// Handle dry-run mode
 		if resetDryRun {
-			yellow := color.New(color.FgYellow)
-			yellow.Println("\nWould execute the following commands:")
+			ui.Warning.Println("\nWould execute the following commands:")
 			fmt.Println()
 
 			// Build commands for all running servers
@@ -54,26 +133,154 @@ var resetCmd = &cobra.Command{
 			return nil
 		}
 
-		prompt := promptui.Prompt{
-			Label:     "Are you sure you want to stop all servers in Claude for this project",
-			IsConfirm: true,
+		confirmed, err := confirm("Are you sure you want to stop all servers in Claude for this project", resetYes)
+		if err != nil || !confirmed {
+			return err
 		}
 
-		_, err = prompt.Run()
-		if err != nil {
-			return nil
-		}
+		_, stop := withInterrupt()
+		defer stop()
 
-		color.Cyan("Stopping all servers...")
-		if err := builder.StopAllServers(); err != nil {
+		ui.Info.Println("Stopping all servers...")
+		builder.Progress = ui.NewSpinner()
+		if err := builder.StopServers(runningServers); err != nil {
 			return fmt.Errorf("failed to stop servers: %w", err)
 		}
 
-		color.Green("Successfully stopped all servers.")
+		ui.Success.Println("Successfully stopped all servers.")
 		return nil
 	},
 }
 
 func init() {
 	resetCmd.Flags().BoolVarP(&resetDryRun, "dry-run", "n", false, "Show commands that would be executed without running them")
+	resetCmd.Flags().BoolVar(&resetDryRunJSON, "json", false, "With --dry-run, print the plan as JSON")
+	resetCmd.Flags().StringSliceVar(&resetTags, "tag", nil, "Only stop servers with this tag (repeatable)")
+	resetCmd.Flags().StringArrayVar(&resetKeep, "keep", nil, "Leave this server running (repeatable)")
+	resetCmd.Flags().BoolVar(&resetOrphans, "orphans", false, "Also remove servers registered in Claude that are not in your cmcp config")
+	resetCmd.Flags().BoolVar(&resetOrphans, "everything", false, "Same as --orphans")
+	resetCmd.Flags().StringVarP(&resetScope, "scope", "s", "", "Remove servers from this Claude scope (local, project, user or all)")
+	resetCmd.Flags().BoolVarP(&resetYes, "yes", "y", false, "Stop the servers without asking for confirmation")
+}
+
+// scopedServers are the servers to remove from one Claude scope
+type scopedServers struct {
+	scope string
+	names []string
+}
+
+// resetScopes removes servers from the Claude scopes chosen with --scope. Servers from
+// the config are looked up in each scope; with --orphans, every other server the
+// scope's settings file lists is removed too.
+func resetScopes(cfg *config.Config) error {
+	scopes := []string{resetScope}
+	if resetScope == "all" {
+		scopes = []string{"local", "project", "user"}
+	}
+
+	var targets []scopedServers
+	found := make(map[string]bool)
+	total := 0
+	for _, scope := range scopes {
+		builder.Scope = scope
+		var names []string
+		for _, name := range cfg.FilterByTags(cfg.GetServerNames(), resetTags) {
+			claudeName := builder.ClaudeName(name)
+			if !slices.Contains(names, claudeName) && builder.IsRunning(claudeName) {
+				names = append(names, claudeName)
+			}
+		}
+		if resetOrphans {
+			for _, name := range mcp.ScopeServerNames(scope) {
+				if mcp.ConfigNameFor(cfg, name, "") == "" {
+					names = append(names, name)
+				}
+			}
+		}
+		for _, name := range names {
+			found[name] = true
+		}
+		targets = append(targets, scopedServers{scope, names})
+	}
+
+	kept := make(map[string]bool)
+	for _, name := range resetKeep {
+		if _, exists := cfg.MCPServers[name]; !exists && !found[name] {
+			return &config.ServerNotFoundError{Name: name}
+		}
+		kept[name] = true
+	}
+	for i := range targets {
+		targets[i].names = removeNames(targets[i].names, kept)
+		total += len(targets[i].names)
+	}
+
+	if resetDryRun && resetDryRunJSON {
+		var steps []mcp.PlanStep
+		for _, target := range targets {
+			builder.Scope = target.scope
+			for _, name := range target.names {
+				steps = append(steps, builder.PlanStop(name))
+			}
+		}
+		return printPlan(steps)
+	}
+
+	if total == 0 {
+		if resetOrphans {
+			ui.Warning.Printf("No servers are registered in Claude's %s scope.\n", strings.Join(scopes, ", "))
+		} else {
+			ui.Warning.Printf("No servers from your config are registered in Claude's %s scope.\n", strings.Join(scopes, ", "))
+		}
+		return nil
+	}
+
+	userScope := false
+	for _, target := range targets {
+		if len(target.names) == 0 {
+			continue
+		}
+		userScope = userScope || target.scope == "user"
+		ui.Info.Printf("Found %d server(s) in Claude's %s scope:\n", len(target.names), target.scope)
+		for _, name := range target.names {
+			fmt.Printf("  - %s\n", name)
+		}
+	}
+	if userScope {
+		ui.Muted.Color().Println("User scope servers are removed for all your projects.")
+	}
+
+	if resetDryRun {
+		ui.Warning.Println("\nWould execute the following commands:")
+		fmt.Println()
+		for _, target := range targets {
+			builder.Scope = target.scope
+			for _, command := range builder.BuildResetCommands(target.names) {
+				fmt.Printf("$ %s\n", command)
+			}
+		}
+		return nil
+	}
+
+	if confirmed, err := confirm(fmt.Sprintf("Are you sure you want to remove %d server(s) from Claude", total), resetYes); err != nil || !confirmed {
+		return err
+	}
+
+	_, stop := withInterrupt()
+	defer stop()
+
+	ui.Info.Println("Stopping all servers...")
+	builder.Progress = ui.NewSpinner()
+	for _, target := range targets {
+		if len(target.names) == 0 {
+			continue
+		}
+		builder.Scope = target.scope
+		if err := builder.StopServers(target.names); err != nil {
+			return fmt.Errorf("failed to stop servers in the %s scope: %w", target.scope, err)
+		}
+	}
+
+	ui.Success.Println("Successfully stopped all servers.")
+	return nil
 }
@@ -1,27 +1,283 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 )
 
+var (
+	nonInteractive bool
+	claudeBin      string
+	showTimings    bool
+	envName        string
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "cmcp",
 	Short: "A CLI tool to manage MCP servers",
 	Long:  `cmcp is a command-line tool for managing Model Context Protocol (MCP) servers on your system.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		ui.SetNonInteractive(nonInteractive)
+		if cmd.Flags().Changed("env-name") {
+			if err := config.SetEnvironment(envName); err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+		}
+		if showTimings {
+			builder.Timings = mcp.NewTimings()
+			config.TrackSave = func() func() {
+				return builder.Timings.Track("", "config save", mcp.TimingCmcp)
+			}
+		}
+		done := builder.Timings.Track("", "config load", mcp.TimingCmcp)
+		cfg, err := config.Load()
+		done()
+		if err != nil {
+			// Commands that need the config report why it failed to load
+			cfg = &config.Config{}
+		}
+		// Commands name servers by their cmcp name; the builder registers and looks
+		// them up in Claude under their alias
+		builder.Aliases = cfg.Aliases()
+		if builder.ClaudeBin, err = cfg.ClaudeBinary(claudeBin); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		return nil
+	},
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	registerPlugins()
+	applyTheme()
+	cmd, err := rootCmd.ExecuteC()
+	printTimings(builder.Timings)
+	recordUsage(cmd)
+	return err
+}
+
+// printTimings reports on stderr how long each phase took, per server, and whose
+// time it was: cmcp's own work, claude commands, or waiting on servers
+func printTimings(timings *mcp.Timings) {
+	if timings == nil {
+		return
+	}
+	phases := timings.Phases()
+	width := len("total")
+	for _, phase := range phases {
+		width = max(width, len(phase.Name))
+	}
+
+	var servers []string
+	byServer := make(map[string][]mcp.Phase)
+	for _, phase := range phases {
+		if _, seen := byServer[phase.Server]; !seen {
+			servers = append(servers, phase.Server)
+		}
+		byServer[phase.Server] = append(byServer[phase.Server], phase)
+	}
+
+	ui.Emphasis.Fprintf(os.Stderr, "\nTimings:\n")
+	for _, server := range servers {
+		label := server
+		if label == "" {
+			label = "(shared)"
+		}
+		ui.Info.Fprintf(os.Stderr, "  %s\n", label)
+		for _, phase := range byServer[server] {
+			fmt.Fprintf(os.Stderr, "    %-*s  %8s  %s\n", width, phase.Name, formatTiming(phase.Duration), ui.Muted.Sprint(phase.Category))
+		}
+	}
+	sums := timings.ByCategory()
+	fmt.Fprintf(os.Stderr, "  %-*s    %8s  ", width, "total", formatTiming(timings.Total()))
+	ui.Muted.Fprintf(os.Stderr, "cmcp %s, claude %s, server %s\n",
+		formatTiming(sums[mcp.TimingCmcp]), formatTiming(sums[mcp.TimingClaude]), formatTiming(sums[mcp.TimingServer]))
+}
+
+// formatTiming rounds a duration for the timings report
+func formatTiming(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
+
+// applyTheme sets the output colors from the config's theme section, with the
+// preset overridden by CMCP_THEME. A bad theme is reported and the default kept.
+func applyTheme() {
+	var preset string
+	var colors map[string]string
+	if cfg, err := config.Load(); err == nil && cfg.Theme != nil {
+		preset, colors = cfg.Theme.Preset, cfg.Theme.Colors
+	}
+	if env := os.Getenv("CMCP_THEME"); env != "" {
+		preset = env
+	}
+	if err := ui.SetTheme(preset, colors); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; using the default colors\n", err)
+	}
+}
+
+// withInterrupt returns a context that is cancelled on Ctrl-C or SIGTERM and
+// hands it to the builder so in-flight claude subprocesses are terminated
+func withInterrupt() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	builder.Context = ctx
+	return ctx, stop
+}
+
+// errNamesRequired is returned instead of showing a selection prompt when cmcp
+// cannot prompt, e.g. under pipes or in CI
+var errNamesRequired = errors.New("server names required in non-interactive mode; pass them as arguments")
+
+// errNotConfirmed is returned when the user declines or aborts the confirmation
+// of servers matched by a pattern
+var errNotConfirmed = errors.New("matched servers not confirmed; nothing was changed")
+
+// confirm asks a yes/no question unless yes is set. When cmcp cannot prompt, with
+// --non-interactive or without a terminal, it fails instead of asking, pointing at --yes.
+func confirm(label string, yes bool) (bool, error) {
+	if yes {
+		return true, nil
+	}
+	if !ui.IsInteractive() {
+		return false, errors.New("confirmation required in non-interactive mode; pass --yes")
+	}
+	prompt := promptui.Prompt{
+		Label:     label,
+		IsConfirm: true,
+	}
+	_, err := prompt.Run()
+	return err == nil, nil
+}
+
+// requireTerminal fails with --non-interactive for actions that need a person at a terminal
+func requireTerminal(action string) error {
+	if ui.NonInteractive() {
+		return fmt.Errorf("%s needs a terminal and is not available in non-interactive mode", action)
+	}
+	return nil
+}
+
+// printSkipped reports servers that were not processed because of an interrupt
+func printSkipped(skipped []string) {
+	if len(skipped) > 0 {
+		ui.Warning.Printf("\nInterrupted, skipped %d server(s): %v\n", len(skipped), skipped)
+	}
+}
+
+// writeFailureReport saves the failures of a multi-server operation to one report and
+// prints its path
+func writeFailureReport(operation string, total int, failures []mcp.Failure) {
+	if len(failures) == 0 {
+		return
+	}
+	path, err := mcp.WriteFailureReport(&mcp.FailureReport{
+		Operation: operation,
+		Time:      time.Now(),
+		Total:     total,
+		Failures:  failures,
+	})
+	if err != nil {
+		ui.Failure.Printf("Warning: %v\n", err)
+		return
+	}
+	fmt.Printf("\n%s %s\n", ui.Info.Sprintf("ℹ Failure report with diagnostics and debug logs:"), path)
+	ui.Muted.Color().Println("  A JSON version is saved next to it.")
+}
+
+// resolveServerArgs expands server names and glob patterns against the config and
+// keeps only servers with one of the given tags (if any).
+// When a pattern was used and confirm is set, the matched servers are shown and
+// the user must confirm; a declined confirmation returns errNotConfirmed. When
+// cmcp cannot prompt, with --non-interactive or without a terminal, the pattern
+// counts as explicit and is not confirmed.
+func resolveServerArgs(cfg *config.Config, args []string, tags []string, confirm bool) ([]string, error) {
+	names, err := cfg.MatchServers(args)
+	if err != nil {
+		return nil, err
+	}
+	names = cfg.FilterByTags(names, tags)
+
+	usedPattern := false
+	for _, arg := range args {
+		if config.IsPattern(arg) {
+			usedPattern = true
+			break
+		}
+	}
+	if !usedPattern || !confirm || len(names) == 0 || !ui.IsInteractive() {
+		return names, nil
+	}
+
+	ui.Info.Printf("Matched %d server(s):\n", len(names))
+	for _, name := range names {
+		fmt.Printf("  • %s\n", name)
+	}
+	fmt.Println()
+
+	prompt := promptui.Prompt{
+		Label:     "Continue with these servers",
+		IsConfirm: true,
+	}
+	if _, err := prompt.Run(); err != nil {
+		return nil, errNotConfirmed
+	}
+	return names, nil
 }
 
 func init() {
+	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "Never prompt; fail when a command needs names, flags or confirmation it was not given")
+	rootCmd.PersistentFlags().BoolVar(&showTimings, "timings", false, "Report how long each phase took per server, split between cmcp, claude and the servers")
+	rootCmd.PersistentFlags().StringVar(&envName, "env-name", "", "Merge the config.<name>.json overlay over the config (default $CMCP_ENV)")
+	rootCmd.PersistentFlags().StringVar(&claudeBin, "claude-bin", "", "Claude installation to run, by its name in the config's claude.binaries or a path")
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(onlineCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(logsCmd)
 	rootCmd.AddCommand(resetCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(groupCmd)
+	rootCmd.AddCommand(profileCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(authCmd)
+	rootCmd.AddCommand(inspectCmd)
+	rootCmd.AddCommand(callCmd)
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(shellCmd)
+	rootCmd.AddCommand(recordCmd)
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(pingCmd)
+	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(installCmd)
+	rootCmd.AddCommand(outdatedCmd)
+	rootCmd.AddCommand(upgradeCmd)
+	rootCmd.AddCommand(pullCmd)
+	rootCmd.AddCommand(monitorCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(gatewayCmd)
+	rootCmd.AddCommand(envCmd)
+	rootCmd.AddCommand(shellenvCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(shareCmd)
 	rootCmd.AddCommand(completionCmd)
 }
 
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var runQuiet bool
+
+var runCmd = &cobra.Command{
+	Use:   "run <server-name>",
+	Short: "Run a server in the foreground attached to the terminal",
+	Long: `Launch a configured server's command with its env and cwd, exactly as Claude would,
+with stdin, stdout, and stderr attached to the terminal. Claude is not involved.
+Type or pipe JSON-RPC messages to talk to the server and watch its stderr; press
+Ctrl-C to stop it. The server's exit status is passed through.`,
+	Example: `  cmcp run github
+  echo '{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}' | cmcp run github`,
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true, // main prints the error once, with the server's exit status
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		serverName := args[0]
+		server, exists := cfg.FindServer(serverName)
+		if !exists {
+			return &config.ServerNotFoundError{Name: serverName}
+		}
+
+		// Keep stdout for the server's JSON-RPC output
+		if !runQuiet && !server.IsRemote() {
+			command := mcp.ShellJoin(append([]string{server.Command}, mcp.MaskSensitiveArgs(server.Args)...))
+			ui.Muted.Fprintf(os.Stderr, "Running '%s': %s\n", serverName, command)
+			if server.Cwd != "" {
+				ui.Muted.Fprintf(os.Stderr, "  cwd: %s\n", server.Cwd)
+			}
+			if len(server.Env) > 0 {
+				ui.Muted.Fprintf(os.Stderr, "  env: %s\n", strings.Join(getSortedKeys(server.Env), ", "))
+			}
+		}
+
+		return mcp.Run(serverName, server, os.Stdin, os.Stdout, os.Stderr)
+	},
+}
+
+func init() {
+	runCmd.Flags().BoolVarP(&runQuiet, "quiet", "q", false, "Don't print the command before running it")
+}
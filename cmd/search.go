@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/registry"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchSource string
+	searchLimit  int
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search MCP server registries",
+	Long: `Search the official MCP server registry (or npm with --source npm) and show
+how to run each matching server.`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query := strings.Join(args, " ")
+
+		entries, err := registry.Search(context.Background(), searchSource, query, searchLimit)
+		if err != nil {
+			return fmt.Errorf("search failed: %w", err)
+		}
+
+		if len(entries) == 0 {
+			ui.Warning.Printf("No servers found for '%s'\n", query)
+			return nil
+		}
+
+		fmt.Printf("%s %s\n\n", ui.Emphasis.Sprint(fmt.Sprintf("%d", len(entries))), ui.Muted.Sprint(fmt.Sprintf("result(s) from %s", searchSource)))
+		for _, entry := range entries {
+			fmt.Printf("%s", ui.Emphasis.Sprint(entry.Name))
+			if entry.Version != "" {
+				fmt.Printf(" %s", ui.Muted.Sprint(entry.Version))
+			}
+			fmt.Println()
+			if entry.Description != "" {
+				fmt.Printf("  %s\n", entry.Description)
+			}
+			if hint := entry.InstallHint(); hint != "" {
+				fmt.Printf("  %s %s\n", ui.Muted.Sprint("run:"), ui.Command.Sprint(hint))
+			}
+			fmt.Println()
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	searchCmd.Flags().StringVarP(&searchSource, "source", "s", registry.SourceMCP, "Registry to search (mcp or npm)")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "l", 20, "Maximum number of results")
+}
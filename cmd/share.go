@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/registry"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	shareGist bool
+	shareYes  bool
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share <server-name>",
+	Short: "Print a server definition others can import, with secrets left out",
+	Long: `Print a server's definition as an {"mcpServers": ...} snippet that
+'cmcp config import --url' and 'cmcp apply' accept. Secret env values, args and
+credentials in the URL, proxy and healthCheck are replaced by ${VAR} references, and
+secret headers by ${env:VAR} references, so whoever imports the snippet supplies
+their own values from the environment. The server's tokenCommand, tls files and
+fields cmcp does not know are left out.
+
+With --gist, the snippet is uploaded as a secret GitHub gist and its URL printed.
+Secret gists are unlisted, not private: anyone with the URL can read them. The
+GitHub token is taken from GH_TOKEN, GITHUB_TOKEN or 'gh auth token'.`,
+	Example: `  cmcp share github > github.cmcp.json
+  cmcp share github --gist`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		name := args[0]
+		server, exists := cfg.FindServer(name)
+		if !exists {
+			return &config.ServerNotFoundError{Name: name}
+		}
+
+		data, variables, err := mcp.ShareSnippet(name, *server)
+		if err != nil {
+			return fmt.Errorf("failed to build snippet: %w", err)
+		}
+
+		fmt.Print(string(data))
+		if !shareGist {
+			printShareVariables(variables)
+			return nil
+		}
+
+		fmt.Println()
+		ok, err := confirm("Upload this as a secret gist", shareYes)
+		if err != nil || !ok {
+			return err
+		}
+		token, err := githubToken()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		gistURL, err := registry.CreateGist(ctx, token, name+".cmcp.json", fmt.Sprintf("cmcp server '%s'", name), data)
+		if err != nil {
+			return fmt.Errorf("failed to create gist: %w", err)
+		}
+		ui.Success.Printf("✓ Uploaded to %s\n", gistURL)
+		fmt.Printf("Import it with: cmcp config import --url %s\n", gistURL)
+		printShareVariables(variables)
+		return nil
+	},
+}
+
+// printShareVariables lists the variables a snippet's recipient must set. Notes go to
+// stderr so the snippet can be redirected to a file.
+func printShareVariables(variables []string) {
+	if len(variables) > 0 {
+		ui.Muted.Fprintf(os.Stderr, "Secrets replaced by references; set before starting: %s\n", strings.Join(variables, ", "))
+	}
+}
+
+// githubToken returns a token for the GitHub API from the environment or the gh CLI
+func githubToken() (string, error) {
+	for _, key := range []string{"GH_TOKEN", "GITHUB_TOKEN"} {
+		if token := os.Getenv(key); token != "" {
+			return token, nil
+		}
+	}
+	if _, err := exec.LookPath("gh"); err == nil {
+		if out, err := exec.Command("gh", "auth", "token").Output(); err == nil {
+			if token := strings.TrimSpace(string(out)); token != "" {
+				return token, nil
+			}
+		}
+	}
+	return "", errors.New("no GitHub token found; set GH_TOKEN or log in with 'gh auth login'")
+}
+
+func init() {
+	shareCmd.Flags().BoolVar(&shareGist, "gist", false, "Upload the snippet as a secret GitHub gist")
+	shareCmd.Flags().BoolVarP(&shareYes, "yes", "y", false, "Upload without asking for confirmation")
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var shellTimeout time.Duration
+
+const shellHelp = `Type a method with optional JSON params, or a raw JSON-RPC message:
+  tools/list
+  tools/call <tool> {"arg": "value"}
+  resources/list
+  resources/read <uri>
+  prompts/get <prompt> {"arg": "value"}
+  ping
+  notify notifications/roots/list_changed
+  {"method": "tools/list", "params": {"cursor": "..."}}
+Type 'exit' or press Ctrl-D to quit.`
+
+var shellCmd = &cobra.Command{
+	Use:   "shell <server-name>",
+	Short: "Send MCP requests to a server interactively",
+	Long: `Launch a configured server (or connect to its URL), perform the MCP handshake, and
+read requests from a prompt. Each request is sent as JSON-RPC and the response is
+pretty-printed. Claude is not involved. Lines can also be piped in to script a session.
+
+` + shellHelp,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		serverName := args[0]
+		server, exists := cfg.FindServer(serverName)
+		if !exists {
+			return &config.ServerNotFoundError{Name: serverName}
+		}
+
+		server, err = mcp.ResolveLaunch(serverName, server)
+		if err != nil {
+			return err
+		}
+
+		interactive := term.IsTerminal(int(os.Stdin.Fd()))
+
+		connectCtx, cancel := context.WithTimeout(context.Background(), shellTimeout)
+		client, err := mcp.Connect(connectCtx, server)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to connect to server '%s': %w", serverName, err)
+		}
+		defer client.Close()
+
+		if interactive {
+			info := client.Initialize.ServerInfo
+			ui.Info.Printf("Connected to '%s' (%s %s, protocol %s)\n", serverName, info.Name, info.Version, client.Initialize.ProtocolVersion)
+			ui.Muted.Println("Type 'help' for examples, 'exit' to quit.")
+		}
+
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for {
+			if interactive {
+				ui.Info.Print("mcp> ")
+			}
+			if !scanner.Scan() {
+				break
+			}
+			line := strings.TrimSpace(scanner.Text())
+			switch {
+			case line == "" || strings.HasPrefix(line, "#"):
+				continue
+			case line == "exit" || line == "quit":
+				return nil
+			case line == "help":
+				fmt.Println(shellHelp)
+				continue
+			}
+
+			req, err := mcp.ParseShellLine(line)
+			if err != nil {
+				ui.Failure.Printf("✗ %v\n", err)
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), shellTimeout)
+			if req.Notify {
+				err = client.Notify(ctx, req.Method, req.Params)
+				if err == nil {
+					ui.Muted.Println("(notification sent)")
+				}
+			} else {
+				var result json.RawMessage
+				if result, err = client.Call(ctx, req.Method, req.Params); err == nil {
+					printRawJSON(result)
+				}
+			}
+			cancel()
+			if err != nil {
+				ui.Failure.Printf("✗ %v\n", err)
+				var rpcErr *mcp.RPCError
+				if errors.As(err, &rpcErr) && len(rpcErr.Data) > 0 {
+					printRawJSON(rpcErr.Data)
+				}
+			}
+		}
+		if interactive {
+			fmt.Println()
+		}
+		return scanner.Err()
+	},
+}
+
+// printRawJSON pretty-prints a JSON value as received from a server
+func printRawJSON(data json.RawMessage) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		fmt.Println(string(data))
+		return
+	}
+	fmt.Println(buf.String())
+}
+
+func init() {
+	shellCmd.Flags().DurationVar(&shellTimeout, "timeout", 60*time.Second, "Timeout for connecting and for each request")
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var shellenvShell string
+
+var shellenvCmd = &cobra.Command{
+	Use:   "shellenv <server-name>",
+	Short: "Print export lines for a server's env, to eval in a shell",
+	Long: `Print one line per env variable of a configured server that sets it in your shell,
+with the value the server would receive once started: "$inherit" entries and ${VAR}
+references are resolved from this shell as cmcp and Claude would. Eval the output to
+run the server by hand with the same environment.
+
+The lines are written for the shell named by --shell, by default the one in $SHELL
+(sh for bash and zsh, fish or powershell). Values, secrets included, are printed in
+plain text. The server's command line is shown on stderr.`,
+	Example: `  eval "$(cmcp shellenv github)"
+  cmcp shellenv github --shell fish | source
+  cmcp shellenv github --shell powershell | Invoke-Expression`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		serverName := args[0]
+		server, exists := cfg.FindServer(serverName)
+		if !exists {
+			return &config.ServerNotFoundError{Name: serverName}
+		}
+		if server.IsRemote() {
+			return fmt.Errorf("server '%s' is a remote server (%s); it has no env to export", serverName, server.URL)
+		}
+
+		shell := shellenvShell
+		if shell == "" {
+			shell = defaultShellSyntax(os.Getenv("SHELL"))
+		}
+		lines, err := mcp.ShellEnv(server, shell)
+		if err != nil {
+			return fmt.Errorf("failed to resolve the env of '%s': %w", serverName, err)
+		}
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+
+		// Only the export lines go to stdout, so the output can be evaluated as is
+		if len(lines) == 0 {
+			ui.Muted.Fprintf(os.Stderr, "Server '%s' sets no env variables.\n", serverName)
+		}
+		if term.IsTerminal(int(os.Stdout.Fd())) {
+			ui.Muted.Fprintf(os.Stderr, "To apply: eval \"$(cmcp shellenv %s)\"\n", serverName)
+		}
+		if expanded, err := mcp.ExpandPlaceholders(serverName, server); err == nil {
+			expanded = mcp.ResolvePaths(expanded)
+			commandLine := mcp.ShellJoin(append([]string{expanded.Command}, expanded.Args...))
+			if expanded.Cwd != "" {
+				commandLine = "cd " + mcp.ShellQuote(expanded.Cwd) + " && " + commandLine
+			}
+			ui.Muted.Fprintf(os.Stderr, "Then run: %s\n", commandLine)
+		}
+		return nil
+	},
+}
+
+// defaultShellSyntax picks the export syntax for a login shell path such as $SHELL
+func defaultShellSyntax(shell string) string {
+	switch strings.TrimSuffix(filepath.Base(shell), ".exe") {
+	case "fish":
+		return "fish"
+	case "pwsh", "powershell":
+		return "powershell"
+	}
+	return "sh"
+}
+
+func init() {
+	shellenvCmd.Flags().StringVar(&shellenvShell, "shell", "", "Shell syntax to print: "+strings.Join(mcp.ShellSyntaxes, ", ")+" (default from $SHELL)")
+}
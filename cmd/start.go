@@ -1,53 +1,127 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
-	"cmcp/internal/config"
-	"cmcp/internal/mcp"
 	"github.com/AlecAivazis/survey/v2"
-	"github.com/fatih/color"
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
 	builder = mcp.NewClaudeCmdBuilder()
-	verbose bool
-	dryRun  bool
+	verbose    bool
+	dryRun     bool
+	dryRunJSON bool
+	dryRunPlain bool
+	startPull  bool
+	startTags   []string
+	startGroups []string
+	startRetry  int
+	startScope  string
+	startFile   string
+	startForce  bool
+	startDeep   bool
 )
 
 var startCmd = &cobra.Command{
 	Use:          "start [server-name...]",
 	Short:        "Start MCP servers in Claude for this project",
 	Long:         `Start one or more MCP servers from your registered servers in Claude for the current project. 
-You can specify server names as arguments or run without arguments for interactive selection.
-Only servers that are not currently running will be started.`,
+You can specify server names or shell-style patterns (e.g. 'gh-*') as arguments,
+select groups with --group, list them in a file with --from-file (one name or
+pattern per line, # starts a comment), or run without arguments for interactive
+selection. Servers that are already running are skipped, unless Claude's definition
+differs from the config: then you are asked to remove and re-add them, or --force
+does so without asking.`,
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRunJSON && !dryRun {
+			return fmt.Errorf("--json can only be used with --dry-run")
+		}
+		if dryRunPlain && (!dryRun || dryRunJSON) {
+			return fmt.Errorf("--plain can only be used with --dry-run and not with --json")
+		}
+		if startRetry < 0 {
+			return fmt.Errorf("--retry must not be negative")
+		}
+		if err := mcp.ValidateScope(startScope); err != nil {
+			return err
+		}
+
+		builder.PullImages = startPull
+		builder.Scope = startScope
+		builder.DeepVerify = startDeep
+		if !verbose {
+			builder.Progress = ui.NewSpinner()
+		}
+
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
 		if len(cfg.MCPServers) == 0 {
-			color.Yellow("No servers configured. Use 'cmcp config open' to add servers.")
+			ui.Warning.Println("No servers configured. Use 'cmcp config open' to add servers.")
 			return nil
 		}
 
+		// Group members and names listed in --from-file are selected the same way as
+		// server names given as arguments
+		members, err := cfg.GroupMembers(startGroups)
+		if err != nil {
+			return err
+		}
+		args = append(args, members...)
+		if startFile != "" {
+			listed, err := readServerList(startFile)
+			if err != nil {
+				return err
+			}
+			if len(listed) == 0 {
+				return fmt.Errorf("no server names in %s", startFile)
+			}
+			args = append(args, listed...)
+		}
+
 		var selectedServers []string
+		var replacing []string // Running with an outdated definition; removed before being added again
 
-		// If server names are provided as arguments, use those
+		// If server names or patterns are provided as arguments, use those
 		if len(args) > 0 {
-			for _, serverName := range args {
-				// Check if server exists in config
-				if _, exists := cfg.MCPServers[serverName]; !exists {
-					return fmt.Errorf("server '%s' not found in configuration", serverName)
-				}
-				// Check if server is not already running
-				if builder.IsRunning(serverName) {
-					color.Yellow("Server '%s' is already running.", serverName)
+			// A list file already declares the servers, so patterns in it are not confirmed
+			names, err := resolveServerArgs(cfg, args, startTags, !dryRun && startFile == "")
+			if err != nil {
+				return err
+			}
+			for _, serverName := range names {
+				server, _ := cfg.FindServer(serverName)
+				if server.IsDisabled() {
+					ui.Warning.Printf("Server '%s' is disabled. Enable it with 'cmcp config set %s.disabled false'.\n", serverName, serverName)
 					continue
 				}
+				// Running servers are only started again when their definition changed
+				if builder.IsRunning(server.ClaudeName(serverName)) {
+					drift := builder.Drift(serverName, server)
+					if drift == nil {
+						ui.Warning.Printf("Server '%s' is already running%s.\n", serverName, aliasNote(serverName, server))
+						continue
+					}
+					replace, err := confirmReplace(*drift)
+					if err != nil {
+						return err
+					}
+					if !replace {
+						continue
+					}
+					replacing = append(replacing, serverName)
+				}
 				selectedServers = append(selectedServers, serverName)
 			}
 		} else {
@@ -55,74 +129,206 @@ Only servers that are not currently running will be started.`,
 			var availableServers []string
 			var serverLabels []string
 
-			for name := range cfg.MCPServers {
-				if !builder.IsRunning(name) {
+			for _, name := range cfg.FilterByTags(cfg.GetServerNames(), startTags) {
+				server, _ := cfg.FindServer(name)
+				if server.IsDisabled() {
+					continue
+				}
+				// Running servers are offered again only when their definition changed
+				if !builder.IsRunning(server.ClaudeName(name)) {
 					availableServers = append(availableServers, name)
 					serverLabels = append(serverLabels, name)
+				} else if builder.Outdated(name, server) {
+					availableServers = append(availableServers, name)
+					serverLabels = append(serverLabels, name+outdatedLabel)
 				}
 			}
 
 			if len(availableServers) == 0 {
-				color.Yellow("All registered servers are already running.")
+				ui.Warning.Println("All registered servers are already running.")
 				return nil
 			}
 
+			if !ui.IsInteractive() {
+				return errNamesRequired
+			}
 			prompt := &survey.MultiSelect{
 				Message: "Select servers to start (use space to select, enter to confirm):",
 				Options: serverLabels,
 			}
 
-			err = survey.AskOne(prompt, &selectedServers, survey.WithPageSize(10))
+			var selectedLabels []string
+			err = survey.AskOne(prompt, &selectedLabels, survey.WithPageSize(10))
 			if err != nil {
 				return err
 			}
+			for _, label := range selectedLabels {
+				name := strings.TrimSuffix(label, outdatedLabel)
+				if name != label {
+					replacing = append(replacing, name)
+				}
+				selectedServers = append(selectedServers, name)
+			}
 		}
 
 		if len(selectedServers) == 0 {
-			color.Yellow("No servers selected.")
+			ui.Warning.Println("No servers selected.")
 			return nil
 		}
 
+		// Variants sharing an alias take turns under the same name in Claude
+		claimed := make(map[string]string)
+		for _, serverName := range selectedServers {
+			server, _ := cfg.FindServer(serverName)
+			claudeName := server.ClaudeName(serverName)
+			if other, taken := claimed[claudeName]; taken {
+				return fmt.Errorf("servers '%s' and '%s' are both registered in Claude as '%s'; start one of them", other, serverName, claudeName)
+			}
+			claimed[claudeName] = serverName
+		}
+
 		// Handle dry-run mode
+		if dryRun && dryRunJSON {
+			var steps []mcp.PlanStep
+			for _, serverName := range replacing {
+				steps = append(steps, builder.PlanStop(builder.ClaudeName(serverName)))
+			}
+			for _, serverName := range selectedServers {
+				selectedServer, _ := cfg.FindServer(serverName)
+				steps = append(steps, builder.PlanStart(serverName, selectedServer))
+			}
+			return printPlan(steps)
+		}
+
+		// One runnable command per line, without colors or headings
+		if dryRun && dryRunPlain {
+			for _, serverName := range replacing {
+				fmt.Println(builder.BuildStopCommand(builder.ClaudeName(serverName)))
+			}
+			for _, serverName := range selectedServers {
+				selectedServer, _ := cfg.FindServer(serverName)
+				fmt.Println(builder.BuildStartCommandPlain(serverName, selectedServer))
+			}
+			return nil
+		}
+
 		if dryRun {
-			yellow := color.New(color.FgYellow)
-			yellow.Println("Would execute the following commands:")
+			ui.Warning.Println("Would execute the following commands:")
 			fmt.Println()
 
+			for _, serverName := range replacing {
+				fmt.Printf("$ %s\n", builder.BuildStopCommand(builder.ClaudeName(serverName)))
+			}
 			for _, serverName := range selectedServers {
 				selectedServer, _ := cfg.FindServer(serverName)
 
-				// Use appropriate command based on whether server has env vars
-				if len(selectedServer.Env) > 0 {
-					fmt.Printf("$ claude mcp add-json %s ", serverName)
+				// Use appropriate command based on whether server needs add-json
+				if builder.UsesAddJSON(selectedServer) {
+					fmt.Printf("$ %s %s ", builder.ClaudeCommand("add-json"), mcp.ShellQuote(selectedServer.ClaudeName(serverName)))
 					builder.PrintPrettyJSONPublic(selectedServer)
 					fmt.Println() // Extra line after pretty JSON
 				} else {
 					command := builder.BuildStartCommand(serverName, selectedServer)
 					fmt.Printf("$ %s\n", command)
 				}
+				printValueSources(serverName, selectedServer)
 			}
 			return nil
 		}
 
+		warnShadowed(cfg, selectedServers)
+
+		ctx, stop := withInterrupt()
+		defer stop()
+
 		// Start each selected server
 		var errors []error
 		var started []string
-		cyan := color.New(color.FgCyan)
-		green := color.New(color.FgGreen)
-		red := color.New(color.FgRed)
+		var skipped []string
 
-		for _, serverName := range selectedServers {
-			selectedServer, _ := cfg.FindServer(serverName)
-			cyan.Printf("Starting server '%s' in Claude for this project...\n", serverName)
+		// With several servers, failures are summarized in one line each and detailed
+		// in a report written at the end
+		total := len(selectedServers)
+		var failures []mcp.Failure
+		fail := func(serverName, action string, err error) {
+			errors = append(errors, fmt.Errorf("%s", serverName))
+			if action == "start" {
+				noteStart(serverName, false)
+			}
+			if total == 1 {
+				ui.Failure.Printf("✗ Failed to %s server '%s': %v\n", action, serverName, err)
+				return
+			}
+			failure := mcp.NewFailure(serverName, err)
+			failures = append(failures, failure)
+			ui.Failure.Printf("✗ Failed to %s server '%s': %s\n", action, serverName, failure.Summary)
+		}
+
+		// Outdated definitions are removed first; a server that cannot be removed is not re-added
+		for _, serverName := range replacing {
+			ui.Info.Printf("Removing the outdated definition of '%s' from Claude...\n", serverName)
+			if err := builder.StopServer(builder.ClaudeName(serverName), verbose); err != nil {
+				fail(serverName, "remove", err)
+				selectedServers = removeNames(selectedServers, map[string]bool{serverName: true})
+			}
+		}
+
+		// --retry overrides the per-server retries setting
+		retriesFor := func(server *config.MCPServer) int {
+			if cmd.Flags().Changed("retry") {
+				return startRetry
+			}
+			return server.Retries
+		}
+
+		// Several servers are added first and verified together with a shared
+		// claude mcp list per poll; verbose mode keeps the per-server output
+		if len(selectedServers) > 1 && !verbose {
+			var requests []mcp.StartRequest
+			for _, serverName := range selectedServers {
+				selectedServer, _ := cfg.FindServer(serverName)
+				requests = append(requests, mcp.StartRequest{Name: serverName, Server: selectedServer, Retries: retriesFor(selectedServer)})
+			}
+
+			ui.Info.Printf("Starting %d servers in Claude for this project...\n", len(requests))
+			for i, err := range builder.StartServers(requests, verbose) {
+				serverName := requests[i].Name
+				switch {
+				case err == nil:
+					started = append(started, serverName)
+					noteStart(serverName, true)
+					ui.Success.Printf("✓ Successfully started server '%s'%s\n", serverName, aliasNote(serverName, requests[i].Server))
+					reportHealthCheck(ctx, serverName, requests[i].Server)
+				case ctx.Err() != nil:
+					skipped = append(skipped, serverName)
+				default:
+					fail(serverName, "start", err)
+				}
+			}
+		} else {
+			for i, serverName := range selectedServers {
+				if ctx.Err() != nil {
+					skipped = selectedServers[i:]
+					break
+				}
+
+				selectedServer, _ := cfg.FindServer(serverName)
+				ui.Info.Printf("Starting server '%s' in Claude for this project...\n", serverName)
 
-			if err := builder.StartServer(serverName, selectedServer, verbose); err != nil {
-				// Show concise error (verbose mode will have shown debug output already)
-				red.Printf("✗ Failed to start server '%s': %v\n", serverName, err)
-				errors = append(errors, fmt.Errorf("%s", serverName))
-			} else {
-				started = append(started, serverName)
-				green.Printf("✓ Successfully started server '%s'\n", serverName)
+				if err := builder.StartServerWithRetry(serverName, selectedServer, verbose, retriesFor(selectedServer)); err != nil {
+					if ctx.Err() != nil {
+						ui.Failure.Printf("✗ Interrupted while starting server '%s'\n", serverName)
+						skipped = selectedServers[i:]
+						break
+					}
+					// Show concise error (verbose mode will have shown debug output already)
+					fail(serverName, "start", err)
+				} else {
+					started = append(started, serverName)
+					noteStart(serverName, true)
+					ui.Success.Printf("✓ Successfully started server '%s'%s\n", serverName, aliasNote(serverName, selectedServer))
+					reportHealthCheck(ctx, serverName, selectedServer)
+				}
 			}
 		}
 
@@ -131,18 +337,150 @@ Only servers that are not currently running will be started.`,
 		}
 
 		if len(errors) > 0 {
-			red.Printf("\nFailed to start %d server(s):\n", len(errors))
+			ui.Failure.Printf("\nFailed to start %d server(s):\n", len(errors))
 			for _, err := range errors {
-				red.Printf("  • %v\n", err)
+				ui.Failure.Printf("  • %v\n", err)
 			}
 		}
+		writeFailureReport("start", total, failures)
 
+		printSkipped(skipped)
 		return nil
 	},
 }
 
+// aliasNote mentions the name a server was registered under in Claude, when it has an alias
+func aliasNote(name string, server *config.MCPServer) string {
+	if claudeName := server.ClaudeName(name); claudeName != name {
+		return fmt.Sprintf(" as '%s'", claudeName)
+	}
+	return ""
+}
+
+// reportHealthCheck runs a started server's healthCheck, if it has one. A failed
+// check is a warning: Claude connected, so the server stays running.
+func reportHealthCheck(ctx context.Context, name string, server *config.MCPServer) {
+	if server.HealthCheck == "" || ctx.Err() != nil {
+		return
+	}
+	result := mcp.RunHealthCheck(ctx, name, server)
+	builder.Timings.Record(name, "health check", mcp.TimingServer, result.Duration)
+	if result.OK {
+		ui.Success.Printf("  ✓ Health check passed (%s)\n", result.Detail)
+	} else {
+		ui.Warning.Printf("  ⚠ Health check failed: %s\n", result.Detail)
+	}
+}
+
 func init() {
 	startCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show debug output directly in the shell instead of saving to temp file")
 	startCmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "Show commands that would be executed without running them")
+	startCmd.Flags().BoolVar(&dryRunJSON, "json", false, "With --dry-run, print the plan as JSON")
+	startCmd.Flags().BoolVar(&dryRunPlain, "plain", false, "With --dry-run, print each command on one uncolored line that can be run as is (secrets read from shell variables)")
+	startCmd.Flags().BoolVar(&startPull, "pull", false, "Pull missing docker images before starting and warn about stale ones")
+	startCmd.Flags().StringSliceVar(&startTags, "tag", nil, "Only start servers with this tag (repeatable)")
+	startCmd.Flags().StringSliceVarP(&startGroups, "group", "g", nil, "Start the servers in this group (repeatable)")
+	startCmd.Flags().StringVarP(&startFile, "from-file", "f", "", "Start the servers listed in this file, one name or pattern per line (- for stdin)")
+	startCmd.Flags().StringVarP(&startScope, "scope", "s", "", "Claude scope to add servers to (local, project or user; default: Claude's default, local)")
+	startCmd.Flags().BoolVar(&startForce, "force", false, "Remove and re-add running servers whose definition in Claude differs from the config, without asking")
+	startCmd.Flags().BoolVar(&startDeep, "deep", false, "Also connect to each server directly after it connects and fail it if it advertises no tools (stdio servers are launched a second time)")
+	startCmd.Flags().IntVar(&startRetry, "retry", 0, "Retry failed starts this many times with exponential backoff (overrides the server's retries setting)")
+}
+
+// outdatedLabel marks running servers with a changed definition in the selection list
+const outdatedLabel = " (definition changed)"
+
+// confirmReplace decides whether a running server whose Claude definition differs from
+// the config is removed and added again. --force replaces it without asking; without a
+// terminal, or in a dry run, it is left running with a hint.
+func confirmReplace(drift mcp.Conflict) (bool, error) {
+	name := drift.Name
+	if startForce {
+		return true, nil
+	}
+	printDrift(drift)
+	if dryRun || !ui.IsInteractive() {
+		ui.Muted.Printf("  Use --force to remove and re-add it with the configured definition.\n")
+		return false, nil
+	}
+
+	replace := true
+	prompt := &survey.Confirm{
+		Message: fmt.Sprintf("Remove '%s' from Claude and add it again with the configured definition?", name),
+		Default: true,
+	}
+	if err := survey.AskOne(prompt, &replace); err != nil {
+		return false, err
+	}
+	return replace, nil
 }
 
+// removeNames returns names without those in drop
+func removeNames(names []string, drop map[string]bool) []string {
+	var kept []string
+	for _, name := range names {
+		if !drop[name] {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
+// readServerList reads server names or patterns from a file, one per line. Blank lines
+// and text after # are ignored.
+func readServerList(path string) ([]string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server list: %w", err)
+	}
+
+	var names []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		if line = strings.TrimSpace(line); line != "" {
+			names = append(names, line)
+		}
+	}
+	return names, nil
+}
+
+// printPlan prints a dry-run plan as JSON for external tooling
+func printPlan(steps []mcp.PlanStep) error {
+	plan, err := mcp.PlanJSON(steps)
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+	fmt.Println(plan)
+	return nil
+}
+
+// printValueSources notes under a dry-run command where each env value and each
+// templated arg, cwd and header of the server comes from
+func printValueSources(name string, server *config.MCPServer) {
+	sources := mcp.ValueSources(name, server)
+	if len(sources) == 0 {
+		return
+	}
+	for _, source := range sources {
+		line := fmt.Sprintf("  # %s: %s", source.Field, source.Source)
+		switch {
+		case len(source.Missing) > 0:
+			ui.Muted.Print(line)
+			ui.Warning.Printf(" (not set: %s)\n", strings.Join(source.Missing, ", "))
+			continue
+		case source.Value == "":
+		case source.Sensitive:
+			line += " → ***"
+		case source.Source != "config literal":
+			line += " → " + source.Value
+		}
+		ui.Muted.Println(line)
+	}
+	fmt.Println()
+}
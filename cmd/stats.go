@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/lopezm94/cmcp/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+// statsTop is how many commands and flaky servers cmcp stats lists
+const statsTop = 10
+
+var (
+	statsDays  int
+	statsJSON  bool
+	statsClear bool
+	statsYes   bool
+)
+
+// startOutcome is a server start made by this run, counted with the command when
+// it finishes
+type startOutcome struct {
+	name string
+	ok   bool
+}
+
+var startOutcomes []startOutcome
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show the commands and servers you use most and the flakiest servers",
+	Long: `Show how often each cmcp command was run and how often each server was started
+and failed to start, over the last days.
+
+The counts are kept in usage.json next to the config and never leave this
+computer. Set CMCP_NO_STATS=1 to stop recording them and use --clear to delete them.`,
+	Example: `  cmcp stats
+  cmcp stats --days 7
+  cmcp stats --days 0 --json
+  cmcp stats --clear`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		u, err := usage.Load(config.StatePath("usage.json"))
+		if err != nil {
+			return fmt.Errorf("failed to load usage stats: %w", err)
+		}
+
+		if statsClear {
+			ok, err := confirm("Delete all recorded usage stats", statsYes)
+			if err != nil || !ok {
+				return err
+			}
+			u.Clear()
+			if err := u.Save(); err != nil {
+				return fmt.Errorf("failed to save usage stats: %w", err)
+			}
+			ui.Success.Println("✓ Cleared the usage stats")
+			return nil
+		}
+
+		summary := u.Summarize(time.Now(), statsDays)
+		if statsJSON {
+			data, err := json.MarshalIndent(summary, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if len(summary.Commands) == 0 && len(summary.Servers) == 0 {
+			ui.Muted.Println("No usage recorded yet.")
+			if os.Getenv("CMCP_NO_STATS") != "" {
+				ui.Muted.Println("Recording is turned off by CMCP_NO_STATS.")
+			}
+			return nil
+		}
+
+		period := "all recorded days"
+		if statsDays > 0 {
+			period = fmt.Sprintf("last %d day(s)", statsDays)
+		}
+		ui.Emphasis.Printf("Usage since %s (%s)\n", summary.Since, period)
+		ui.Muted.Println("Recorded on this computer only.")
+
+		if len(summary.Commands) > 0 {
+			ui.Info.Println("\nMost used commands")
+			table := ui.NewTable(ui.Column{Header: "COMMAND"}, ui.Column{Header: "RUNS"})
+			for _, command := range summary.Commands[:min(statsTop, len(summary.Commands))] {
+				table.Add(ui.Cell{Text: command.Command, Color: ui.Command.Color()}, ui.Cell{Text: fmt.Sprint(command.Count)})
+			}
+			table.Render()
+		}
+
+		if len(summary.Servers) > 0 {
+			ui.Info.Println("\nMost started servers")
+			table := ui.NewTable(ui.Column{Header: "SERVER"}, ui.Column{Header: "STARTS"}, ui.Column{Header: "FAILED"})
+			for _, server := range summary.Servers[:min(statsTop, len(summary.Servers))] {
+				failed := ui.Cell{Text: "0", Color: ui.Muted.Color()}
+				if server.Failures > 0 {
+					failed = ui.Cell{Text: fmt.Sprint(server.Failures), Color: ui.Failure.Color()}
+				}
+				table.Add(ui.Cell{Text: server.Name, Color: ui.Emphasis.Color()}, ui.Cell{Text: fmt.Sprint(server.Attempts())}, failed)
+			}
+			table.Render()
+		}
+
+		if flaky := summary.Flaky(); len(flaky) > 0 {
+			ui.Info.Println("\nMost flaky servers")
+			table := ui.NewTable(ui.Column{Header: "SERVER"}, ui.Column{Header: "FAILURE RATE"}, ui.Column{Header: "LAST FAILURE"})
+			for _, server := range flaky[:min(statsTop, len(flaky))] {
+				table.Add(
+					ui.Cell{Text: server.Name, Color: ui.Emphasis.Color()},
+					ui.Cell{Text: fmt.Sprintf("%.0f%% (%d/%d)", 100*server.FailureRate(), server.Failures, server.Attempts()), Color: ui.Failure.Color()},
+					ui.Cell{Text: server.LastFailure, Color: ui.Muted.Color()},
+				)
+			}
+			table.Render()
+			ui.Muted.Println("\nRun 'cmcp logs <server>' to see why a server failed.")
+		}
+		return nil
+	},
+}
+
+// noteStart records the outcome of a server start for cmcp stats
+func noteStart(name string, ok bool) {
+	startOutcomes = append(startOutcomes, startOutcome{name: name, ok: ok})
+}
+
+// recordUsage counts the command that ran and the server starts it made. Usage is
+// best effort: failing to record it is not reported.
+func recordUsage(cmd *cobra.Command) {
+	if os.Getenv("CMCP_NO_STATS") != "" || cmd == nil || cmd == rootCmd || strings.HasPrefix(cmd.Name(), "__") {
+		return
+	}
+	u, err := usage.Load(config.StatePath("usage.json"))
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	u.RecordCommand(strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" "), now)
+	for _, start := range startOutcomes {
+		u.RecordStart(start.name, start.ok, now)
+	}
+	u.Save()
+}
+
+func init() {
+	statsCmd.Flags().IntVar(&statsDays, "days", 30, "Count the last N days (0 for all recorded days)")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Print the summary as JSON")
+	statsCmd.Flags().BoolVar(&statsClear, "clear", false, "Delete the recorded usage stats")
+	statsCmd.Flags().BoolVarP(&statsYes, "yes", "y", false, "Clear without asking for confirmation")
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/health"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status <server-name>",
+	Short: "Show everything known about one server",
+	Long: `Show a single server's config definition, its registration in Claude, connection
+health and history, the latest debug log, and diagnostics when it is failing.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		server, inConfig := cfg.FindServer(name)
+		claudeName := name
+		if inConfig {
+			claudeName = server.ClaudeName(name)
+		}
+
+		def := builder.GetClaudeDefinition(claudeName)
+		if !inConfig && def == nil {
+			return fmt.Errorf("server '%s' not found in configuration or Claude", name)
+		}
+
+		now := time.Now()
+
+		ui.Emphasis.Print(name)
+		if claudeName != name {
+			ui.Muted.Printf(" (registered in Claude as '%s')", claudeName)
+		}
+		fmt.Println()
+
+		// Config definition, with secrets masked
+		fmt.Println()
+		ui.Emphasis.Println("Config")
+		if inConfig {
+			data, _ := json.MarshalIndent(mcp.MaskServer(*server).ToMap(), "  ", "  ")
+			fmt.Printf("  %s\n", data)
+		} else {
+			ui.Warning.Println("  Not in your cmcp config (orphaned; 'cmcp online --clear' removes it)")
+		}
+
+		// Registration in Claude
+		fmt.Println()
+		ui.Emphasis.Println("Claude")
+		if def == nil {
+			ui.Muted.Printf("  Not registered. Run 'cmcp start %s' to start it.\n", name)
+		} else {
+			printDefinition(def)
+			if inConfig && !def.Matches(name, server) {
+				ui.Warning.Printf("  ⚠ Registered with a different definition than the config. Run 'cmcp stop %s && cmcp start %s' to apply it.\n", name, name)
+			}
+		}
+
+		// Connection health, only meaningful when registered
+		var status string
+		if def != nil {
+			statuses, _ := builder.GetServerStatuses(cfg)
+			for _, s := range statuses {
+				if s.Name == claudeName {
+					status = s.Status
+					recordHealth([]mcp.ServerStatus{s})
+				}
+			}
+		}
+
+		fmt.Println()
+		ui.Emphasis.Println("Health")
+		switch status {
+		case "connected":
+			ui.Success.Println("  ✓ Connected")
+		case "failed":
+			ui.Failure.Println("  ✗ Failed to connect")
+		case "":
+			ui.Muted.Println("  Not running")
+		default:
+			ui.Warning.Println("  • Unknown")
+		}
+		if history, err := health.Load(config.StatePath("health.json")); err == nil {
+			if h, ok := history.Get(claudeName); ok {
+				notes := []string{"last connected " + health.FormatAgo(h.LastConnected, now)}
+				if h.Failures > 0 {
+					notes = append(notes, fmt.Sprintf("%d failure(s)", h.Failures))
+				}
+				if uptime, ok := h.Uptime(now); ok {
+					notes = append(notes, fmt.Sprintf("%.0f%% uptime over 24h", uptime*100))
+				}
+				if h.IsFlapping(now) {
+					notes = append(notes, "flapping")
+				}
+				ui.Muted.Printf("  %s\n", strings.Join(notes, ", "))
+			}
+		}
+
+		// Latest debug log from start/stop/verify runs
+		fmt.Println()
+		ui.Emphasis.Println("Debug log")
+		if log, ok := mcp.FindLatestDebugLog(name); ok {
+			fmt.Printf("  %s %s\n", log.Path, ui.Muted.Sprintf("(%s)", health.FormatAgo(log.ModTime, now)))
+		} else {
+			ui.Muted.Println("  None")
+		}
+
+		// Diagnostics for failing servers
+		if status == "failed" && inConfig {
+			fmt.Println()
+			ui.Emphasis.Println("Diagnostics")
+			diag, err := builder.Diagnose(name, server)
+			if err != nil || diag == nil {
+				ui.Muted.Println("  Could not gather diagnostics")
+			} else {
+				printDiagnostics(mcp.FormatDiagnostics(diag))
+			}
+		}
+
+		return nil
+	},
+}
+
+// printDiagnostics prints formatted diagnostics indented, coloring their headings
+func printDiagnostics(text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		switch {
+		case line == "Connection failed" || line == "Server error:" || strings.HasPrefix(line, "Error:"):
+			fmt.Printf("  %s\n", ui.Failure.Sprint(line))
+		case line == "Health check output:":
+			fmt.Printf("  %s\n", ui.Warning.Sprint(line))
+		case line == "Possible solutions:":
+			fmt.Printf("  %s\n", ui.Emphasis.Sprint(line))
+		default:
+			fmt.Printf("  %s\n", line)
+		}
+	}
+}
+
+// printDefinition prints the details 'claude mcp get' reports for a server
+func printDefinition(def *mcp.ClaudeDefinition) {
+	row := func(label, value string) {
+		if value != "" {
+			ui.Muted.Printf("  %-8s %s\n", label+":", value)
+		}
+	}
+
+	row("scope", def.Scope)
+	row("type", def.Type)
+	if def.URL != "" {
+		row("url", def.URL)
+	} else {
+		row("command", maskCommandLine(def.Describe()))
+	}
+	row("env", strings.Join(def.EnvKeys, ", "))
+}
@@ -2,44 +2,96 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
-	"cmcp/internal/config"
 	"github.com/AlecAivazis/survey/v2"
-	"github.com/fatih/color"
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
-	stopVerbose bool
-	stopDryRun  bool
+	stopVerbose    bool
+	stopDryRun     bool
+	stopDryRunJSON bool
+	stopTags       []string
+	stopGroups     []string
+	stopFailed     bool
+	stopScope      string
 )
 
 var stopCmd = &cobra.Command{
 	Use:          "stop [server-name...]",
 	Short:        "Stop running MCP servers in Claude for this project",
 	Long:         `Stop one or more running MCP servers in Claude for the current project.
-You can specify server names as arguments or run without arguments for interactive selection.
-Only servers that are currently running will be stopped.`,
+You can specify server names or shell-style patterns (e.g. 'gh-*') as arguments,
+select groups with --group, or run without arguments for interactive selection.
+Only servers that are currently running will be stopped.
+Use --failed to stop only servers that are failing to connect.`,
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if stopDryRunJSON && !stopDryRun {
+			return fmt.Errorf("--json can only be used with --dry-run")
+		}
+		if stopFailed && (len(args) > 0 || len(stopGroups) > 0) {
+			return fmt.Errorf("--failed cannot be combined with server names or groups")
+		}
+		if err := mcp.ValidateScope(stopScope); err != nil {
+			return err
+		}
+		builder.Scope = stopScope
+
+		if !stopVerbose {
+			builder.Progress = ui.NewSpinner()
+		}
+
 		// Load config to get our registered servers
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
+		// Group members are selected the same way as server names given as arguments
+		members, err := cfg.GroupMembers(stopGroups)
+		if err != nil {
+			return err
+		}
+		args = append(args, members...)
+
 		var selectedServers []string
 
-		// If server names are provided as arguments, use those
-		if len(args) > 0 {
-			for _, serverName := range args {
-				// Check if server exists in config
-				if _, exists := cfg.MCPServers[serverName]; !exists {
-					return fmt.Errorf("server '%s' not found in configuration", serverName)
+		if stopFailed {
+			// Only target servers from our config that Claude reports as failed
+			servers, err := builder.GetServerStatuses(cfg)
+			if err != nil && !strings.Contains(err.Error(), "No MCP servers configured") {
+				return fmt.Errorf("failed to get server statuses: %w", err)
+			}
+			var failedServers []string
+			for _, server := range servers {
+				if server.Status == "failed" && server.InConfig {
+					failedServers = append(failedServers, server.ConfigName)
 				}
+			}
+			selectedServers = cfg.FilterByTags(failedServers, stopTags)
+
+			if len(selectedServers) == 0 {
+				if stopDryRunJSON {
+					return printPlan(nil)
+				}
+				ui.Success.Println("✓ No failed servers to stop.")
+				return nil
+			}
+		} else if len(args) > 0 {
+			// If server names or patterns are provided as arguments, use those
+			names, err := resolveServerArgs(cfg, args, stopTags, !stopDryRun)
+			if err != nil {
+				return err
+			}
+			for _, serverName := range names {
 				// Check if server is actually running
-				if !builder.IsRunning(serverName) {
-					color.Yellow("Server '%s' is not running.", serverName)
+				if !builder.IsRunning(builder.ClaudeName(serverName)) {
+					ui.Warning.Printf("Server '%s' is not running.\n", serverName)
 					continue
 				}
 				selectedServers = append(selectedServers, serverName)
@@ -47,14 +99,14 @@ Only servers that are currently running will be stopped.`,
 		} else {
 			// Interactive mode - find which servers from our config are in Claude
 			var runningServers []string
-			for name := range cfg.MCPServers {
-				if builder.IsRunning(name) {
+			for _, name := range cfg.FilterByTags(cfg.GetServerNames(), stopTags) {
+				if builder.IsRunning(builder.ClaudeName(name)) {
 					runningServers = append(runningServers, name)
 				}
 			}
 
 			if len(runningServers) == 0 {
-				color.Yellow("No servers from your config are currently in Claude.")
+				ui.Warning.Println("No servers from your config are currently in Claude.")
 				return nil
 			}
 
@@ -63,6 +115,9 @@ Only servers that are currently running will be stopped.`,
 				serverLabels = append(serverLabels, name)
 			}
 
+			if !ui.IsInteractive() {
+				return errNamesRequired
+			}
 			prompt := &survey.MultiSelect{
 				Message: "Select servers to stop (use space to select, enter to confirm):",
 				Options: serverLabels,
@@ -75,39 +130,64 @@ Only servers that are currently running will be stopped.`,
 		}
 
 		if len(selectedServers) == 0 {
-			color.Yellow("No servers selected.")
+			ui.Warning.Println("No servers selected.")
 			return nil
 		}
 
 		// Handle dry-run mode
+		if stopDryRun && stopDryRunJSON {
+			var steps []mcp.PlanStep
+			for _, serverName := range selectedServers {
+				steps = append(steps, builder.PlanStop(builder.ClaudeName(serverName)))
+			}
+			return printPlan(steps)
+		}
+
 		if stopDryRun {
-			yellow := color.New(color.FgYellow)
-			yellow.Println("Would execute the following commands:")
+			ui.Warning.Println("Would execute the following commands:")
 			fmt.Println()
 
 			for _, serverName := range selectedServers {
-				command := builder.BuildStopCommand(serverName)
+				command := builder.BuildStopCommand(builder.ClaudeName(serverName))
 				fmt.Printf("$ %s\n", command)
 			}
 			return nil
 		}
 
+		ctx, stop := withInterrupt()
+		defer stop()
+
 		// Stop each selected server
 		var errors []error
 		var stopped []string
-		cyan := color.New(color.FgCyan)
-		green := color.New(color.FgGreen)
-		red := color.New(color.FgRed)
+		var skipped []string
+		var failures []mcp.Failure
+
+		for i, serverName := range selectedServers {
+			if ctx.Err() != nil {
+				skipped = selectedServers[i:]
+				break
+			}
 
-		for _, serverName := range selectedServers {
-			cyan.Printf("Stopping server '%s' in Claude for this project...\n", serverName)
+			ui.Info.Printf("Stopping server '%s' in Claude for this project...\n", serverName)
 
-			if err := builder.StopServer(serverName, stopVerbose); err != nil {
-				red.Printf("✗ Failed to stop server '%s': %v\n", serverName, err)
+			if err := builder.StopServer(builder.ClaudeName(serverName), stopVerbose); err != nil {
+				if ctx.Err() != nil {
+					ui.Failure.Printf("✗ Interrupted while stopping server '%s'\n", serverName)
+					skipped = selectedServers[i:]
+					break
+				}
 				errors = append(errors, fmt.Errorf("%s", serverName))
+				if len(selectedServers) == 1 {
+					ui.Failure.Printf("✗ Failed to stop server '%s': %v\n", serverName, err)
+					continue
+				}
+				failure := mcp.NewFailure(serverName, err)
+				failures = append(failures, failure)
+				ui.Failure.Printf("✗ Failed to stop server '%s': %s\n", serverName, failure.Summary)
 			} else {
 				stopped = append(stopped, serverName)
-				green.Printf("✓ Successfully stopped server '%s'\n", serverName)
+				ui.Success.Printf("✓ Successfully stopped server '%s'\n", serverName)
 			}
 		}
 
@@ -116,12 +196,14 @@ Only servers that are currently running will be stopped.`,
 		}
 
 		if len(errors) > 0 {
-			red.Printf("\nFailed to stop %d server(s):\n", len(errors))
+			ui.Failure.Printf("\nFailed to stop %d server(s):\n", len(errors))
 			for _, err := range errors {
-				red.Printf("  • %v\n", err)
+				ui.Failure.Printf("  • %v\n", err)
 			}
 		}
+		writeFailureReport("stop", len(selectedServers), failures)
 
+		printSkipped(skipped)
 		return nil
 	},
 }
@@ -129,4 +211,9 @@ Only servers that are currently running will be stopped.`,
 func init() {
 	stopCmd.Flags().BoolVarP(&stopVerbose, "verbose", "v", false, "Show verbose output including command details")
 	stopCmd.Flags().BoolVarP(&stopDryRun, "dry-run", "n", false, "Show commands that would be executed without running them")
+	stopCmd.Flags().BoolVar(&stopDryRunJSON, "json", false, "With --dry-run, print the plan as JSON")
+	stopCmd.Flags().StringSliceVar(&stopTags, "tag", nil, "Only stop servers with this tag (repeatable)")
+	stopCmd.Flags().StringSliceVarP(&stopGroups, "group", "g", nil, "Stop the servers in this group (repeatable)")
+	stopCmd.Flags().StringVarP(&stopScope, "scope", "s", "", "Claude scope to remove servers from (local, project or user; default: Claude's default)")
+	stopCmd.Flags().BoolVar(&stopFailed, "failed", false, "Stop only servers that are failing to connect")
 }
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/clients"
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncTo     []string
+	syncTags   []string
+	syncUser   bool
+	syncDryRun bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [server-name...]",
+	Short: "Copy server definitions to other MCP clients",
+	Long: `Write your cmcp servers into the project config files of other MCP clients
+(.cursor/mcp.json for Cursor, .vscode/mcp.json for VS Code, .zed/settings.json for Zed),
+translating the format each client expects. Comments in Zed's settings are preserved.
+Use --user to write the clients' user-level configs instead of this project's;
+Windsurf and Cline only have user-level configs; Roo Code has both (.roo/mcp.json).
+Cline and Roo Code read "disabled" and "autoApprove" from the server's config.
+For VS Code, secret env values become password prompts declared under "inputs". Servers already in those files that cmcp does not manage are kept.
+You can specify server names or shell-style patterns (e.g. 'gh-*') to sync a subset.`,
+	Example:      `  cmcp sync --to cursor,vscode`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(syncTo) == 0 {
+			return fmt.Errorf("--to is required (supported: %s)", strings.Join(clients.Names(), ", "))
+		}
+		var targets []clients.Client
+		for _, name := range syncTo {
+			client, err := clients.Lookup(name)
+			if err != nil {
+				return err
+			}
+			targets = append(targets, client)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		names := cfg.GetServerNames()
+		if len(args) > 0 {
+			names, err = cfg.MatchServers(args)
+			if err != nil {
+				return err
+			}
+		}
+		names = cfg.FilterByTags(names, syncTags)
+		if len(names) == 0 {
+			ui.Warning.Println("No servers to sync.")
+			return nil
+		}
+
+		servers := make(map[string]config.MCPServer, len(names))
+		for _, name := range names {
+			servers[name] = cfg.MCPServers[name]
+		}
+
+		projectDir, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+
+		for _, client := range targets {
+			var path string
+			if syncUser {
+				path, err = client.UserPath()
+			} else {
+				path, err = client.Path(projectDir)
+			}
+			if err != nil {
+				return err
+			}
+			existing, err := os.ReadFile(path)
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to read %s: %w", path, err)
+			}
+
+			data, err := client.Merge(existing, servers)
+			if err != nil {
+				return err
+			}
+
+			if syncDryRun {
+				ui.Muted.Printf("# %s\n", path)
+				fmt.Print(string(data))
+				continue
+			}
+
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+			}
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", path, err)
+			}
+			ui.Success.Printf("✓ Synced %d server(s) to %s (%s)\n", len(servers), client.DisplayName, path)
+		}
+		return nil
+	},
+}
+
+func init() {
+	syncCmd.Flags().StringSliceVar(&syncTo, "to", nil, "Clients to sync to, comma separated ("+strings.Join(clients.Names(), ", ")+")")
+	syncCmd.Flags().StringSliceVar(&syncTags, "tag", nil, "Only sync servers with this tag (repeatable)")
+	syncCmd.Flags().BoolVar(&syncUser, "user", false, "Write the clients' user-level configs instead of the project's")
+	syncCmd.Flags().BoolVarP(&syncDryRun, "dry-run", "n", false, "Print the resulting config files without writing them")
+}
@@ -0,0 +1,453 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// defaultPollInterval is used for the device flow when the server doesn't set one
+var defaultPollInterval = 5 * time.Second
+
+// Metadata holds the authorization server endpoints used by the login flows
+type Metadata struct {
+	Issuer                      string   `json:"issuer"`
+	AuthorizationEndpoint       string   `json:"authorization_endpoint"`
+	TokenEndpoint               string   `json:"token_endpoint"`
+	RegistrationEndpoint        string   `json:"registration_endpoint"`
+	DeviceAuthorizationEndpoint string   `json:"device_authorization_endpoint"`
+	Scopes                      []string `json:"-"` // Scopes the MCP server asks for
+}
+
+// protectedResource is the OAuth protected resource metadata of an MCP server
+type protectedResource struct {
+	AuthorizationServers []string `json:"authorization_servers"`
+	ScopesSupported      []string `json:"scopes_supported"`
+}
+
+// LoginOptions configure a login
+type LoginOptions struct {
+	ClientID string   // Pre-registered client; dynamic registration is used when empty
+	Scopes   []string // Defaults to the scopes the server advertises
+	Device   bool     // Use the device authorization flow instead of the browser
+
+	// OpenURL shows the authorization page to the user in the browser flow
+	OpenURL func(authURL string) error
+	// ShowCode tells the user where to enter the code in the device flow
+	ShowCode func(userCode, verificationURI string)
+}
+
+// oauthError is an error response from the authorization server
+type oauthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *oauthError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Description)
+	}
+	return e.Code
+}
+
+type tokenResponse struct {
+	oauthError
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// Discover finds the authorization server of a remote MCP server through its
+// protected resource metadata. Servers without metadata are assumed to be their
+// own authorization server, with the default /authorize, /token, and /register endpoints.
+func Discover(ctx context.Context, serverURL string) (*Metadata, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid server URL '%s'", serverURL)
+	}
+	origin := u.Scheme + "://" + u.Host
+	path := strings.TrimSuffix(u.Path, "/")
+
+	var resource protectedResource
+	issuer := origin
+	for _, candidate := range wellKnown(origin, path, "oauth-protected-resource") {
+		if err := getJSON(ctx, candidate, &resource); err == nil && len(resource.AuthorizationServers) > 0 {
+			issuer = strings.TrimSuffix(resource.AuthorizationServers[0], "/")
+			break
+		}
+	}
+
+	issuerURL, err := url.Parse(issuer)
+	if err != nil || issuerURL.Host == "" {
+		return nil, fmt.Errorf("invalid authorization server '%s'", issuer)
+	}
+	issuerOrigin := issuerURL.Scheme + "://" + issuerURL.Host
+	candidates := append(wellKnown(issuerOrigin, issuerURL.Path, "oauth-authorization-server"),
+		wellKnown(issuerOrigin, issuerURL.Path, "openid-configuration")...)
+	for _, candidate := range candidates {
+		var meta Metadata
+		if err := getJSON(ctx, candidate, &meta); err == nil && meta.TokenEndpoint != "" {
+			meta.Scopes = resource.ScopesSupported
+			return &meta, nil
+		}
+	}
+
+	return &Metadata{
+		Issuer:                issuer,
+		AuthorizationEndpoint: issuerOrigin + "/authorize",
+		TokenEndpoint:         issuerOrigin + "/token",
+		RegistrationEndpoint:  issuerOrigin + "/register",
+		Scopes:                resource.ScopesSupported,
+	}, nil
+}
+
+// wellKnown returns the well-known URLs to try for a document, path-specific first
+func wellKnown(origin, path, document string) []string {
+	urls := []string{}
+	if path != "" && path != "/" {
+		urls = append(urls, origin+"/.well-known/"+document+path)
+	}
+	return append(urls, origin+"/.well-known/"+document)
+}
+
+// Login runs the OAuth flow for a remote MCP server and returns the new token
+func Login(ctx context.Context, serverURL string, opts LoginOptions) (*Token, error) {
+	resource, err := Resource(serverURL)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := Discover(ctx, serverURL)
+	if err != nil {
+		return nil, err
+	}
+	scopes := opts.Scopes
+	if len(scopes) == 0 {
+		scopes = meta.Scopes
+	}
+
+	token := &Token{Resource: resource, TokenEndpoint: meta.TokenEndpoint, ClientID: opts.ClientID}
+	var resp *tokenResponse
+	if opts.Device {
+		resp, err = deviceFlow(ctx, meta, token, scopes, opts.ShowCode)
+	} else {
+		resp, err = browserFlow(ctx, meta, token, scopes, opts.OpenURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	token.update(resp)
+	return token, nil
+}
+
+// Refresh exchanges the token's refresh token for a new access token
+func Refresh(ctx context.Context, token *Token) error {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {token.RefreshToken},
+		"resource":      {token.Resource},
+	}
+	resp, err := requestToken(ctx, token, form)
+	if err != nil {
+		return err
+	}
+	token.update(resp)
+	return nil
+}
+
+// update stores a token response, keeping the refresh token if no new one was issued
+func (t *Token) update(resp *tokenResponse) {
+	t.AccessToken = resp.AccessToken
+	if resp.RefreshToken != "" {
+		t.RefreshToken = resp.RefreshToken
+	}
+	t.ExpiresAt = time.Time{}
+	if resp.ExpiresIn > 0 {
+		t.ExpiresAt = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+}
+
+// browserFlow runs the authorization code flow with PKCE, receiving the code on a loopback redirect
+func browserFlow(ctx context.Context, meta *Metadata, token *Token, scopes []string, openURL func(string) error) (*tokenResponse, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start callback listener: %w", err)
+	}
+	defer listener.Close()
+	redirectURI := fmt.Sprintf("http://%s/callback", listener.Addr())
+
+	if err := register(ctx, meta, token, []string{"authorization_code", "refresh_token"}, redirectURI); err != nil {
+		return nil, err
+	}
+
+	verifier, state := randomString(), randomString()
+	challenge := sha256.Sum256([]byte(verifier))
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {token.ClientID},
+		"redirect_uri":          {redirectURI},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+		"state":                 {state},
+		"resource":              {token.Resource},
+	}
+	if len(scopes) > 0 {
+		params.Set("scope", strings.Join(scopes, " "))
+	}
+	separator := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	authURL := meta.AuthorizationEndpoint + separator + params.Encode()
+
+	type callback struct {
+		code string
+		err  error
+	}
+	results := make(chan callback, 1)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/callback" {
+			http.NotFound(w, r)
+			return
+		}
+		query := r.URL.Query()
+		result := callback{code: query.Get("code")}
+		switch {
+		case query.Get("error") != "":
+			result.err = &oauthError{Code: query.Get("error"), Description: query.Get("error_description")}
+		case query.Get("state") != state:
+			result.err = fmt.Errorf("authorization response has an unexpected state")
+		case result.code == "":
+			result.err = fmt.Errorf("authorization response has no code")
+		}
+		if result.err != nil {
+			http.Error(w, "Login failed: "+result.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Login complete. You can close this window and return to the terminal.")
+		}
+		select {
+		case results <- result:
+		default:
+		}
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	if err := openURL(authURL); err != nil {
+		return nil, err
+	}
+
+	var result callback
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out waiting for the browser login: %w", ctx.Err())
+	}
+	if result.err != nil {
+		return nil, fmt.Errorf("authorization failed: %w", result.err)
+	}
+
+	return requestToken(ctx, token, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {result.code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+		"resource":      {token.Resource},
+	})
+}
+
+// deviceFlow runs the device authorization flow, polling until the user approves the code
+func deviceFlow(ctx context.Context, meta *Metadata, token *Token, scopes []string, showCode func(string, string)) (*tokenResponse, error) {
+	if meta.DeviceAuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("the authorization server does not support the device flow; log in with the browser instead")
+	}
+	const deviceGrant = "urn:ietf:params:oauth:grant-type:device_code"
+	if err := register(ctx, meta, token, []string{deviceGrant, "refresh_token"}, ""); err != nil {
+		return nil, err
+	}
+
+	form := url.Values{"client_id": {token.ClientID}, "resource": {token.Resource}}
+	if len(scopes) > 0 {
+		form.Set("scope", strings.Join(scopes, " "))
+	}
+	var device struct {
+		oauthError
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		Interval                int    `json:"interval"`
+	}
+	if err := postForm(ctx, meta.DeviceAuthorizationEndpoint, form, &device); err != nil {
+		return nil, fmt.Errorf("device authorization failed: %w", err)
+	}
+	if device.Code != "" {
+		return nil, fmt.Errorf("device authorization failed: %w", &device.oauthError)
+	}
+
+	verificationURI := device.VerificationURI
+	if device.VerificationURIComplete != "" {
+		verificationURI = device.VerificationURIComplete
+	}
+	showCode(device.UserCode, verificationURI)
+
+	interval := defaultPollInterval
+	if device.Interval > 0 {
+		interval = time.Duration(device.Interval) * time.Second
+	}
+	for {
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for the device to be approved: %w", ctx.Err())
+		}
+
+		resp, err := requestToken(ctx, token, url.Values{
+			"grant_type":  {deviceGrant},
+			"device_code": {device.DeviceCode},
+			"resource":    {token.Resource},
+		})
+		var oauthErr *oauthError
+		if err == nil {
+			return resp, nil
+		}
+		if !errors.As(err, &oauthErr) {
+			return nil, err
+		}
+		switch oauthErr.Code {
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			return nil, fmt.Errorf("device login failed: %w", err)
+		}
+	}
+}
+
+// register obtains a client ID through dynamic client registration unless one was given
+func register(ctx context.Context, meta *Metadata, token *Token, grantTypes []string, redirectURI string) error {
+	if token.ClientID != "" {
+		return nil
+	}
+	if meta.RegistrationEndpoint == "" {
+		return fmt.Errorf("the authorization server does not support dynamic client registration; pass --client-id")
+	}
+
+	request := map[string]interface{}{
+		"client_name":                "cmcp",
+		"grant_types":                grantTypes,
+		"token_endpoint_auth_method": "none",
+	}
+	if redirectURI != "" {
+		request["redirect_uris"] = []string{redirectURI}
+		request["response_types"] = []string{"code"}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.RegistrationEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var client struct {
+		oauthError
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+	}
+	if err := do(req, &client); err != nil {
+		return fmt.Errorf("client registration failed: %w", err)
+	}
+	if client.Code != "" || client.ClientID == "" {
+		return fmt.Errorf("client registration failed: %w", &client.oauthError)
+	}
+	token.ClientID, token.ClientSecret = client.ClientID, client.ClientSecret
+	return nil
+}
+
+// requestToken posts a grant to the token endpoint with the token's client credentials
+func requestToken(ctx context.Context, token *Token, form url.Values) (*tokenResponse, error) {
+	form.Set("client_id", token.ClientID)
+	if token.ClientSecret != "" {
+		form.Set("client_secret", token.ClientSecret)
+	}
+	var resp tokenResponse
+	if err := postForm(ctx, token.TokenEndpoint, form, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Code != "" {
+		return nil, &resp.oauthError
+	}
+	if resp.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access token")
+	}
+	return &resp, nil
+}
+
+func postForm(ctx context.Context, endpoint string, form url.Values, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return do(req, v)
+}
+
+func getJSON(ctx context.Context, rawURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	return do(req, v)
+}
+
+// do sends a request and decodes the JSON response. OAuth error responses
+// (HTTP 400/401 with an error body) are decoded too, for the caller to inspect.
+func do(req *http.Request, v interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var oauthErr oauthError
+		if (resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized) &&
+			json.Unmarshal(data, &oauthErr) == nil && oauthErr.Code != "" {
+			return json.Unmarshal(data, v)
+		}
+		return fmt.Errorf("%s returned HTTP %d", req.URL.Redacted(), resp.StatusCode)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", req.URL.Redacted(), err)
+	}
+	return nil
+}
+
+// randomString returns a random URL-safe string for PKCE verifiers and state
+func randomString() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to read random bytes: %v", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,252 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAuthServer is an MCP server that is also its own OAuth authorization server
+type fakeAuthServer struct {
+	*httptest.Server
+	t          *testing.T
+	mu         sync.Mutex
+	challenge  string
+	pending    int // Device polls answered with authorization_pending
+	refreshed  bool
+	registered bool
+}
+
+func newFakeAuthServer(t *testing.T) *fakeAuthServer {
+	f := &fakeAuthServer{t: t}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/oauth-protected-resource/mcp", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"authorization_servers": []string{f.URL},
+			"scopes_supported":      []string{"read", "write"},
+		})
+	})
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                        f.URL,
+			"authorization_endpoint":        f.URL + "/authorize",
+			"token_endpoint":                f.URL + "/token",
+			"registration_endpoint":         f.URL + "/register",
+			"device_authorization_endpoint": f.URL + "/device",
+		})
+	})
+	mux.HandleFunc("/register", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.registered = true
+		f.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]string{"client_id": "registered-client"})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("scope") != "read write" || q.Get("resource") != f.URL+"/mcp" {
+			t.Errorf("unexpected authorization request: %s", r.URL.RawQuery)
+		}
+		f.mu.Lock()
+		f.challenge = q.Get("code_challenge")
+		f.mu.Unlock()
+		redirect := q.Get("redirect_uri") + "?code=auth-code&state=" + url.QueryEscape(q.Get("state"))
+		http.Redirect(w, r, redirect, http.StatusFound)
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"device_code":      "device-code",
+			"user_code":        "ABCD-1234",
+			"verification_uri": f.URL + "/activate",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("client_id") != "registered-client" {
+			t.Errorf("token request client_id = %q", r.Form.Get("client_id"))
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		switch r.Form.Get("grant_type") {
+		case "authorization_code":
+			sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+			if r.Form.Get("code") != "auth-code" || base64.RawURLEncoding.EncodeToString(sum[:]) != f.challenge {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+				return
+			}
+		case "urn:ietf:params:oauth:grant-type:device_code":
+			if f.pending > 0 {
+				f.pending--
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "authorization_pending"})
+				return
+			}
+		case "refresh_token":
+			if r.Form.Get("refresh_token") != "refresh-1" {
+				t.Errorf("refresh_token = %q", r.Form.Get("refresh_token"))
+			}
+			f.refreshed = true
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access-2", "expires_in": 3600})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "access-1",
+			"refresh_token": "refresh-1",
+			"expires_in":    3600,
+		})
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func TestDiscover(t *testing.T) {
+	f := newFakeAuthServer(t)
+
+	meta, err := Discover(context.Background(), f.URL+"/mcp")
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if meta.TokenEndpoint != f.URL+"/token" || meta.DeviceAuthorizationEndpoint != f.URL+"/device" {
+		t.Errorf("Discover() = %+v", meta)
+	}
+	if strings.Join(meta.Scopes, " ") != "read write" {
+		t.Errorf("Scopes = %v, want the protected resource scopes", meta.Scopes)
+	}
+
+	// Without metadata the server's origin is used with the default endpoints
+	bare := httptest.NewServer(http.NotFoundHandler())
+	defer bare.Close()
+	meta, err = Discover(context.Background(), bare.URL+"/mcp")
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if meta.AuthorizationEndpoint != bare.URL+"/authorize" || meta.RegistrationEndpoint != bare.URL+"/register" {
+		t.Errorf("Discover() fallback = %+v", meta)
+	}
+}
+
+func TestLoginBrowser(t *testing.T) {
+	f := newFakeAuthServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	token, err := Login(ctx, f.URL+"/mcp", LoginOptions{
+		OpenURL: func(authURL string) error {
+			// Follows the redirect back to the loopback callback, like a browser would
+			resp, err := http.Get(authURL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			return err
+		},
+	})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if !f.registered || token.ClientID != "registered-client" {
+		t.Errorf("expected dynamic client registration, got client %q", token.ClientID)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" || token.Resource != f.URL+"/mcp" {
+		t.Errorf("Login() token = %+v", token)
+	}
+	if token.Expired() {
+		t.Error("new token should not be expired")
+	}
+}
+
+func TestLoginDevice(t *testing.T) {
+	f := newFakeAuthServer(t)
+	f.pending = 2
+
+	orig := defaultPollInterval
+	defaultPollInterval = 10 * time.Millisecond
+	defer func() { defaultPollInterval = orig }()
+
+	var shownCode string
+	token, err := Login(context.Background(), f.URL+"/mcp", LoginOptions{
+		Device:   true,
+		ShowCode: func(userCode, verificationURI string) { shownCode = userCode },
+	})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if shownCode != "ABCD-1234" || token.AccessToken != "access-1" {
+		t.Errorf("device login showed %q and got token %+v", shownCode, token)
+	}
+}
+
+func TestStoreAccessTokenRefreshes(t *testing.T) {
+	f := newFakeAuthServer(t)
+	store := NewStore(t.TempDir())
+
+	if token, err := store.AccessToken(context.Background(), f.URL+"/mcp"); err != nil || token != "" {
+		t.Fatalf("AccessToken() before login = %q, %v; want no token", token, err)
+	}
+
+	expired := &Token{
+		Resource:      f.URL + "/mcp",
+		AccessToken:   "access-1",
+		RefreshToken:  "refresh-1",
+		ExpiresAt:     time.Now().Add(-time.Hour),
+		TokenEndpoint: f.URL + "/token",
+		ClientID:      "registered-client",
+	}
+	if err := store.Save(expired); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// The query string doesn't change which token is used
+	token, err := store.AccessToken(context.Background(), f.URL+"/mcp?session=1")
+	if err != nil {
+		t.Fatalf("AccessToken() error = %v", err)
+	}
+	if token != "access-2" || !f.refreshed {
+		t.Errorf("AccessToken() = %q, want the refreshed token", token)
+	}
+
+	saved, err := store.Load(f.URL + "/mcp")
+	if err != nil || saved.AccessToken != "access-2" || saved.RefreshToken != "refresh-1" {
+		t.Errorf("saved token = %+v, %v; want the refreshed token with the old refresh token", saved, err)
+	}
+
+	if removed, err := store.Delete(f.URL + "/mcp"); err != nil || !removed {
+		t.Errorf("Delete() = %v, %v", removed, err)
+	}
+	if removed, _ := store.Delete(f.URL + "/mcp"); removed {
+		t.Error("Delete() of a missing token should report false")
+	}
+}
+
+func TestResource(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+		wantErr  bool
+	}{
+		{url: "https://MCP.example.com/mcp/", expected: "https://mcp.example.com/mcp"},
+		{url: "https://mcp.example.com/mcp?api_key=x#frag", expected: "https://mcp.example.com/mcp"},
+		{url: "https://mcp.example.com", expected: "https://mcp.example.com"},
+		{url: "npx server", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			result, err := Resource(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("Resource() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
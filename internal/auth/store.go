@@ -0,0 +1,136 @@
+// Package auth implements the MCP OAuth login flow for remote servers and caches the tokens
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// expiryLeeway refreshes tokens slightly before they expire
+const expiryLeeway = time.Minute
+
+// Token is a cached OAuth token for one remote server, with what is needed to refresh it
+type Token struct {
+	Resource      string    `json:"resource"`
+	AccessToken   string    `json:"accessToken"`
+	RefreshToken  string    `json:"refreshToken,omitempty"`
+	ExpiresAt     time.Time `json:"expiresAt,omitempty"`
+	TokenEndpoint string    `json:"tokenEndpoint"`
+	ClientID      string    `json:"clientId"`
+	ClientSecret  string    `json:"clientSecret,omitempty"`
+}
+
+// Expired reports whether the access token has expired or is about to
+func (t *Token) Expired() bool {
+	return !t.ExpiresAt.IsZero() && time.Now().Add(expiryLeeway).After(t.ExpiresAt)
+}
+
+// Store keeps one token file per remote server in a directory
+type Store struct {
+	dir string
+}
+
+// NewStore returns a store for the token files in dir
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Resource returns the canonical resource identifier of a server URL: the URL
+// without query or fragment. Tokens are issued for, and cached by, this identifier.
+func Resource(serverURL string) (string, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("invalid server URL '%s'", serverURL)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.RawQuery, u.Fragment = "", ""
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+func (s *Store) path(resource string) string {
+	name := resource[strings.Index(resource, "://")+3:]
+	return filepath.Join(s.dir, strings.Trim(unsafeFileChars.ReplaceAllString(name, "_"), "_")+".json")
+}
+
+// Load returns the cached token for a server URL, or nil if there is none
+func (s *Store) Load(serverURL string) (*Token, error) {
+	resource, err := Resource(serverURL)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(s.path(resource))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read token: %w", err)
+	}
+	var token Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("invalid token file: %w", err)
+	}
+	return &token, nil
+}
+
+// Save writes a token, readable only by the current user
+func (s *Store) Save(token *Token) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create token directory: %w", err)
+	}
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.path(token.Resource), data, 0600); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the cached token for a server URL. It reports whether there was one.
+func (s *Store) Delete(serverURL string) (bool, error) {
+	resource, err := Resource(serverURL)
+	if err != nil {
+		return false, err
+	}
+	if err := os.Remove(s.path(resource)); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to remove token: %w", err)
+	}
+	return true, nil
+}
+
+// AccessToken returns a valid access token for a server URL, refreshing and
+// saving it when it has expired. It returns "" when the user never logged in.
+func (s *Store) AccessToken(ctx context.Context, serverURL string) (string, error) {
+	token, err := s.Load(serverURL)
+	if err != nil || token == nil {
+		return "", err
+	}
+	if !token.Expired() {
+		return token.AccessToken, nil
+	}
+
+	if token.RefreshToken == "" {
+		return "", fmt.Errorf("OAuth token for %s has expired; run 'cmcp auth login' again", token.Resource)
+	}
+	if err := Refresh(ctx, token); err != nil {
+		return "", fmt.Errorf("failed to refresh OAuth token for %s (run 'cmcp auth login' again): %w", token.Resource, err)
+	}
+	if err := s.Save(token); err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,408 @@
+// Package backup bundles the cmcp config, its fragments, overlays and includes,
+// profiles and, encrypted, cached OAuth tokens into one file for moving them to
+// another machine
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// bundleVersion is the format version written to the manifest
+const bundleVersion = 1
+
+// maxEntrySize bounds how much of a bundle entry is read
+const maxEntrySize = 16 << 20
+
+// Bundle entry names
+const (
+	manifestEntry      = "manifest.json"
+	configEntry        = "config.json"
+	activeProfileEntry = "profile"
+	profilesPrefix     = "profiles/"
+	fragmentsPrefix    = "config.d/"
+	filesPrefix        = "files/" // Overlays and included files, by their path under the config directory
+	secretsEntry       = "secrets.json"
+)
+
+// Layout locates the files a bundle holds
+type Layout struct {
+	Config        string // Config file of the default profile
+	ProfilesDir   string // Configs of the other profiles, as <name>.json
+	FragmentsDir  string // Fragments merged into the default profile's config
+	ActiveProfile string // File naming the selected profile
+	TokensDir     string // Cached OAuth tokens, one file per remote server
+}
+
+// DefaultLayout returns where this cmcp keeps its files
+func DefaultLayout() Layout {
+	return Layout{
+		Config:        config.ProfilePath(config.DefaultProfile),
+		ProfilesDir:   config.ProfilesDir(),
+		FragmentsDir:  config.FragmentsDir(),
+		ActiveProfile: config.StatePath(config.ActiveProfileFile),
+		TokensDir:     config.StatePath("tokens"),
+	}
+}
+
+// Manifest describes what a bundle holds
+type Manifest struct {
+	Version       int       `json:"version"`
+	Created       time.Time `json:"created"`
+	Profiles      []string  `json:"profiles"`                // Profiles with a config in the bundle
+	ActiveProfile string    `json:"activeProfile,omitempty"` // Profile selected when the bundle was created
+	Secrets       int       `json:"secrets"`                 // Number of encrypted OAuth tokens
+	Unbundled     []string  `json:"unbundled,omitempty"`     // Included files outside the config directory, left out
+}
+
+// Bundle is a read backup bundle
+type Bundle struct {
+	Manifest Manifest
+
+	files   map[string][]byte // Entry name to content, besides the manifest and secrets
+	secrets *sealed
+}
+
+// Create writes a bundle of the files in layout to w. Cached OAuth tokens are
+// included, encrypted with the passphrase, unless the passphrase is empty.
+func Create(w io.Writer, layout Layout, passphrase string) (*Manifest, error) {
+	manifest := &Manifest{Version: bundleVersion, Created: time.Now().UTC()}
+	files := make(map[string][]byte)
+
+	add := func(entry, file string) (bool, error) {
+		data, err := os.ReadFile(file)
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		files[entry] = data
+		return true, nil
+	}
+
+	// The default profile has no file until something is saved
+	configs := []string{layout.Config}
+	if ok, err := add(configEntry, layout.Config); err != nil {
+		return nil, err
+	} else if ok {
+		manifest.Profiles = append(manifest.Profiles, config.DefaultProfile)
+	}
+	profiles, err := listFiles(layout.ProfilesDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range profiles {
+		configs = append(configs, filepath.Join(layout.ProfilesDir, name))
+		if ok, err := add(profilesPrefix+name, filepath.Join(layout.ProfilesDir, name)); err != nil {
+			return nil, err
+		} else if ok {
+			manifest.Profiles = append(manifest.Profiles, strings.TrimSuffix(name, ".json"))
+		}
+	}
+	fragments, err := listFiles(layout.FragmentsDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range fragments {
+		if _, err := add(fragmentsPrefix+name, filepath.Join(layout.FragmentsDir, name)); err != nil {
+			return nil, err
+		}
+	}
+	// Overlays of the default profile's config and the files any config includes are
+	// kept by their path under the config directory; an included file elsewhere is
+	// left out and reported, since restoring it could write anywhere
+	configDir, err := filepath.Abs(filepath.Dir(layout.Config))
+	if err != nil {
+		return nil, err
+	}
+	extras, err := filepath.Glob(config.OverlayPath(filepath.Join(configDir, filepath.Base(layout.Config)), "*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range configs {
+		included, err := config.IncludedFiles(file)
+		if err != nil {
+			return nil, err
+		}
+		extras = append(extras, included...)
+	}
+	for _, file := range extras {
+		rel, err := filepath.Rel(configDir, file)
+		if err != nil || !safeRelPath(filepath.ToSlash(rel)) {
+			if !slices.Contains(manifest.Unbundled, file) {
+				manifest.Unbundled = append(manifest.Unbundled, file)
+			}
+			continue
+		}
+		if _, err := add(filesPrefix+filepath.ToSlash(rel), file); err != nil {
+			return nil, err
+		}
+	}
+
+	if ok, err := add(activeProfileEntry, layout.ActiveProfile); err != nil {
+		return nil, err
+	} else if ok {
+		manifest.ActiveProfile = strings.TrimSpace(string(files[activeProfileEntry]))
+	}
+
+	var secrets *sealed
+	if passphrase != "" {
+		tokens, err := readTokens(layout.TokensDir)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(tokens)
+		if err != nil {
+			return nil, err
+		}
+		if secrets, err = seal(data, passphrase); err != nil {
+			return nil, fmt.Errorf("failed to encrypt secrets: %w", err)
+		}
+		manifest.Secrets = len(tokens)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	write := func(entry string, data []byte, mode int64) error {
+		header := &tar.Header{Name: entry, Mode: mode, Size: int64(len(data)), ModTime: manifest.Created}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	data, _ := json.MarshalIndent(manifest, "", "  ")
+	if err := write(manifestEntry, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	for _, entry := range sortedKeys(files) {
+		if err := write(entry, files[entry], 0644); err != nil {
+			return nil, fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+	if secrets != nil {
+		data, _ := json.Marshal(secrets)
+		if err := write(secretsEntry, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return manifest, nil
+}
+
+// Read reads a bundle written by Create
+func Read(r io.Reader) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a cmcp backup bundle: %w", err)
+	}
+	defer gz.Close()
+
+	bundle := &Bundle{files: make(map[string][]byte)}
+	hasManifest := false
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxEntrySize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+
+		switch entry := header.Name; {
+		case entry == manifestEntry:
+			if err := json.Unmarshal(data, &bundle.Manifest); err != nil {
+				return nil, fmt.Errorf("invalid bundle manifest: %w", err)
+			}
+			hasManifest = true
+		case entry == secretsEntry:
+			bundle.secrets = &sealed{}
+			if err := json.Unmarshal(data, bundle.secrets); err != nil {
+				return nil, fmt.Errorf("invalid bundle secrets: %w", err)
+			}
+		case entry == configEntry, entry == activeProfileEntry:
+			bundle.files[entry] = data
+		case strings.HasPrefix(entry, profilesPrefix) && safeFileName(strings.TrimPrefix(entry, profilesPrefix)):
+			bundle.files[entry] = data
+		case strings.HasPrefix(entry, fragmentsPrefix) && safeFileName(strings.TrimPrefix(entry, fragmentsPrefix)):
+			bundle.files[entry] = data
+		case strings.HasPrefix(entry, filesPrefix) && safeRelPath(strings.TrimPrefix(entry, filesPrefix)):
+			bundle.files[entry] = data
+		}
+	}
+
+	if !hasManifest {
+		return nil, errors.New("not a cmcp backup bundle: no manifest")
+	}
+	if bundle.Manifest.Version > bundleVersion {
+		return nil, fmt.Errorf("bundle version %d is newer than this cmcp supports (%d); upgrade cmcp", bundle.Manifest.Version, bundleVersion)
+	}
+	if bundle.secrets == nil {
+		bundle.Manifest.Secrets = 0
+	}
+	return bundle, nil
+}
+
+// Destinations returns the files restoring the bundle writes, besides OAuth tokens
+func (b *Bundle) Destinations(layout Layout) []string {
+	var paths []string
+	for _, entry := range sortedKeys(b.files) {
+		paths = append(paths, destination(layout, entry))
+	}
+	return paths
+}
+
+// Restore writes the bundle's files to layout, replacing existing ones. OAuth
+// tokens are decrypted with the passphrase and restored too, unless it is empty.
+// A wrong passphrase fails before anything is written.
+func (b *Bundle) Restore(layout Layout, passphrase string) ([]string, error) {
+	var tokens map[string][]byte
+	if passphrase != "" && b.secrets != nil {
+		data, err := b.secrets.open(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &tokens); err != nil {
+			return nil, fmt.Errorf("invalid bundle secrets: %w", err)
+		}
+	}
+
+	var written []string
+	for _, entry := range sortedKeys(b.files) {
+		file := destination(layout, entry)
+		if err := writeFile(file, b.files[entry], 0755, 0644); err != nil {
+			return written, err
+		}
+		written = append(written, file)
+	}
+	for _, name := range sortedKeys(tokens) {
+		if !safeFileName(name) {
+			continue
+		}
+		file := filepath.Join(layout.TokensDir, name)
+		if err := writeFile(file, tokens[name], 0700, 0600); err != nil {
+			return written, err
+		}
+		written = append(written, file)
+	}
+	return written, nil
+}
+
+// destination returns where a bundle entry is restored
+func destination(layout Layout, entry string) string {
+	switch entry {
+	case configEntry:
+		return layout.Config
+	case activeProfileEntry:
+		return layout.ActiveProfile
+	}
+	if strings.HasPrefix(entry, fragmentsPrefix) {
+		return filepath.Join(layout.FragmentsDir, strings.TrimPrefix(entry, fragmentsPrefix))
+	}
+	if strings.HasPrefix(entry, filesPrefix) {
+		return filepath.Join(filepath.Dir(layout.Config), filepath.FromSlash(strings.TrimPrefix(entry, filesPrefix)))
+	}
+	return filepath.Join(layout.ProfilesDir, strings.TrimPrefix(entry, profilesPrefix))
+}
+
+func writeFile(file string, data []byte, dirMode, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(file), dirMode); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(file), err)
+	}
+	if err := os.WriteFile(file, data, mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return nil
+}
+
+// readTokens returns the token files of a token directory by name
+func readTokens(dir string) (map[string][]byte, error) {
+	names, err := listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make(map[string][]byte, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token %s: %w", name, err)
+		}
+		tokens[name] = data
+	}
+	return tokens, nil
+}
+
+// listFiles returns the .json files of a directory, which may not exist
+func listFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && safeFileName(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// safeFileName reports whether name is a plain .json file name, so restoring it
+// cannot write outside its directory
+func safeFileName(name string) bool {
+	return strings.HasSuffix(name, ".json") && path.Base(name) == name && !strings.HasPrefix(name, ".") && !strings.ContainsAny(name, `/\`)
+}
+
+// safeRelPath reports whether rel is a slash-separated path to a .json file below a
+// directory, so restoring it cannot write outside that directory
+func safeRelPath(rel string) bool {
+	if !strings.HasSuffix(rel, ".json") || path.IsAbs(rel) || path.Clean(rel) != rel || strings.Contains(rel, `\`) {
+		return false
+	}
+	for _, part := range strings.Split(rel, "/") {
+		if strings.HasPrefix(part, ".") {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
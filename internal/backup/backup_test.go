@@ -0,0 +1,191 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testLayout returns a layout under a temporary directory
+func testLayout(t *testing.T) Layout {
+	dir := t.TempDir()
+	return Layout{
+		Config:        filepath.Join(dir, "config.json"),
+		ProfilesDir:   filepath.Join(dir, "profiles"),
+		FragmentsDir:  filepath.Join(dir, "config.d"),
+		ActiveProfile: filepath.Join(dir, "profile"),
+		TokensDir:     filepath.Join(dir, "tokens"),
+	}
+}
+
+func writeTestFile(t *testing.T, file, content string) {
+	t.Helper()
+	os.MkdirAll(filepath.Dir(file), 0755)
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	source := testLayout(t)
+	writeTestFile(t, source.Config, `{"mcpServers":{"github":{"command":"gh"}},"groups":{"work":["github"]}}`)
+	writeTestFile(t, filepath.Join(source.ProfilesDir, "work.json"), `{"mcpServers":{}}`)
+	writeTestFile(t, filepath.Join(source.FragmentsDir, "fetch.json"), `{"mcpServers":{"fetch":{"command":"uvx"}}}`)
+	writeTestFile(t, source.ActiveProfile, "work\n")
+	writeTestFile(t, filepath.Join(source.TokensDir, "mcp.example.com.json"), `{"accessToken":"secret"}`)
+
+	tests := []struct {
+		name          string
+		passphrase    string
+		restoreWith   string
+		wantSecrets   int
+		wantToken     bool
+		wantErr       string
+		wantUntouched bool
+	}{
+		{name: "without secrets", wantSecrets: 0},
+		{name: "with secrets", passphrase: "correct horse", restoreWith: "correct horse", wantSecrets: 1, wantToken: true},
+		{name: "secrets skipped", passphrase: "correct horse", wantSecrets: 1},
+		{name: "wrong passphrase", passphrase: "correct horse", restoreWith: "wrong", wantSecrets: 1, wantErr: "wrong passphrase", wantUntouched: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			manifest, err := Create(&buf, source, tt.passphrase)
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if strings.Join(manifest.Profiles, ",") != "default,work" || manifest.ActiveProfile != "work" || manifest.Secrets != tt.wantSecrets {
+				t.Errorf("manifest = %+v", manifest)
+			}
+			if bytes.Contains(buf.Bytes(), []byte("secret\"")) {
+				t.Error("the bundle must not hold tokens in plain text")
+			}
+
+			bundle, err := Read(&buf)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			target := testLayout(t)
+			if got := len(bundle.Destinations(target)); got != 4 {
+				t.Errorf("Destinations() has %d files, want 4", got)
+			}
+
+			written, err := bundle.Restore(target, tt.restoreWith)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Restore() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Restore() error = %v", err)
+			}
+			if tt.wantUntouched {
+				if len(written) != 0 {
+					t.Errorf("Restore() wrote %v despite failing", written)
+				}
+				return
+			}
+
+			data, _ := os.ReadFile(target.Config)
+			if !strings.Contains(string(data), `"work":["github"]`) {
+				t.Errorf("restored config = %s", data)
+			}
+			if _, err := os.Stat(filepath.Join(target.ProfilesDir, "work.json")); err != nil {
+				t.Errorf("profile not restored: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(target.FragmentsDir, "fetch.json")); err != nil {
+				t.Errorf("fragment not restored: %v", err)
+			}
+			info, err := os.Stat(filepath.Join(target.TokensDir, "mcp.example.com.json"))
+			if tt.wantToken != (err == nil) {
+				t.Fatalf("token restored = %v, want %v", err == nil, tt.wantToken)
+			}
+			if tt.wantToken && info.Mode().Perm() != 0600 {
+				t.Errorf("token mode = %v, want 0600", info.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestCreateBundlesOverlaysAndIncludes(t *testing.T) {
+	source := testLayout(t)
+	dir := filepath.Dir(source.Config)
+	outside := filepath.Join(t.TempDir(), "team.json")
+	writeTestFile(t, source.Config, `{"include": ["shared/base.json", "`+filepath.ToSlash(outside)+`"], "mcpServers": {}}`)
+	writeTestFile(t, filepath.Join(dir, "config.staging.json"), `{"mcpServers":{"api":{"url":"https://staging.example.com"}}}`)
+	writeTestFile(t, filepath.Join(dir, "shared", "base.json"), `{"include": ["more.json"], "mcpServers":{"fetch":{"command":"uvx"}}}`)
+	writeTestFile(t, filepath.Join(dir, "shared", "more.json"), `{"mcpServers":{}}`)
+	writeTestFile(t, outside, `{"mcpServers":{}}`)
+
+	var buf bytes.Buffer
+	manifest, err := Create(&buf, source, "")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if len(manifest.Unbundled) != 1 || manifest.Unbundled[0] != outside {
+		t.Errorf("Unbundled = %v, want %s", manifest.Unbundled, outside)
+	}
+
+	bundle, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	target := testLayout(t)
+	if _, err := bundle.Restore(target, ""); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	targetDir := filepath.Dir(target.Config)
+	for _, file := range []string{"config.staging.json", "shared/base.json", "shared/more.json"} {
+		if _, err := os.Stat(filepath.Join(targetDir, filepath.FromSlash(file))); err != nil {
+			t.Errorf("%s not restored: %v", file, err)
+		}
+	}
+	if len(bundle.Manifest.Unbundled) != 1 {
+		t.Errorf("restored manifest Unbundled = %v", bundle.Manifest.Unbundled)
+	}
+}
+
+func TestReadIgnoresUnsafeEntries(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{
+		"manifest.json":         `{"version":1}`,
+		"profiles/../evil.json": "{}",
+		"/etc/passwd":           "root",
+		"profiles/ok.json":      "{}",
+		"files/../../evil.json": "{}",
+		"files/.hidden/x.json":  "{}",
+	} {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+
+	bundle, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	layout := testLayout(t)
+	if got := bundle.Destinations(layout); len(got) != 1 || got[0] != filepath.Join(layout.ProfilesDir, "ok.json") {
+		t.Errorf("Destinations() = %v, want only the safe profile", got)
+	}
+
+	if _, err := Read(strings.NewReader("not a bundle")); err == nil {
+		t.Error("Read() should reject data that is not a bundle")
+	}
+}
+
+func TestPBKDF2SHA256(t *testing.T) {
+	// RFC 7914, section 11
+	want := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+	if got := hex.EncodeToString(pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, 64)); got != want {
+		t.Errorf("pbkdf2SHA256() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// kdfIterations is the PBKDF2-HMAC-SHA256 work factor for deriving the secrets key
+const kdfIterations = 600000
+
+// sealed is an encrypted payload with what is needed to decrypt it given the passphrase
+type sealed struct {
+	KDF        string `json:"kdf"` // "pbkdf2-sha256"
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"` // AES-256-GCM
+}
+
+// errWrongPassphrase is returned when secrets cannot be decrypted
+var errWrongPassphrase = errors.New("wrong passphrase, or the secrets in the bundle are damaged")
+
+// seal encrypts data with a key derived from the passphrase
+func seal(data []byte, passphrase string) (*sealed, error) {
+	s := &sealed{KDF: "pbkdf2-sha256", Iterations: kdfIterations, Salt: make([]byte, 16)}
+	if _, err := rand.Read(s.Salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, s)
+	if err != nil {
+		return nil, err
+	}
+	s.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(s.Nonce); err != nil {
+		return nil, err
+	}
+	s.Ciphertext = gcm.Seal(nil, s.Nonce, data, nil)
+	return s, nil
+}
+
+// open decrypts a sealed payload
+func (s *sealed) open(passphrase string) ([]byte, error) {
+	if s.KDF != "pbkdf2-sha256" || s.Iterations <= 0 {
+		return nil, fmt.Errorf("unsupported secrets encryption '%s'", s.KDF)
+	}
+	gcm, err := newGCM(passphrase, s)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Nonce) != gcm.NonceSize() {
+		return nil, errWrongPassphrase
+	}
+	data, err := gcm.Open(nil, s.Nonce, s.Ciphertext, nil)
+	if err != nil {
+		return nil, errWrongPassphrase
+	}
+	return data, nil
+}
+
+func newGCM(passphrase string, s *sealed) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2SHA256([]byte(passphrase), s.Salt, s.Iterations, 32))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2SHA256 derives a key as in RFC 8018, section 5.2
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	var key []byte
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.Write(prf, binary.BigEndian, block)
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
@@ -0,0 +1,154 @@
+package catalog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// Input is a value the user must provide when installing a catalog entry
+type Input struct {
+	Name    string `json:"name"` // Referenced as ${input:Name} in the server definition
+	Prompt  string `json:"prompt"`
+	Secret  bool   `json:"secret,omitempty"`  // Hide the value while typing
+	Default string `json:"default,omitempty"` // Used when the user leaves the prompt empty
+}
+
+// Entry is a ready-made server definition for a well-known MCP server
+type Entry struct {
+	ID          string
+	Description string
+	Server      config.MCPServer
+	Inputs      []Input
+}
+
+var entries = map[string]Entry{
+	"github": {
+		ID:          "github",
+		Description: "GitHub repositories, issues, and pull requests (official Docker image)",
+		Server: config.MCPServer{
+			Command: "docker",
+			Args:    []string{"run", "-i", "--rm", "-e", "GITHUB_PERSONAL_ACCESS_TOKEN", "ghcr.io/github/github-mcp-server"},
+			Env:     map[string]string{"GITHUB_PERSONAL_ACCESS_TOKEN": "${input:token}"},
+		},
+		Inputs: []Input{{Name: "token", Prompt: "GitHub personal access token", Secret: true}},
+	},
+	"filesystem": {
+		ID:          "filesystem",
+		Description: "Read and write files under an allowed directory",
+		Server: config.MCPServer{
+			Command: "npx",
+			Args:    []string{"-y", "@modelcontextprotocol/server-filesystem", "${input:path}"},
+		},
+		Inputs: []Input{{Name: "path", Prompt: "Directory the server may access", Default: "."}},
+	},
+	"context7": {
+		ID:          "context7",
+		Description: "Up-to-date library documentation",
+		Server: config.MCPServer{
+			Command: "npx",
+			Args:    []string{"-y", "@upstash/context7-mcp"},
+		},
+	},
+	"memory": {
+		ID:          "memory",
+		Description: "Knowledge-graph based persistent memory",
+		Server: config.MCPServer{
+			Command: "npx",
+			Args:    []string{"-y", "@modelcontextprotocol/server-memory"},
+		},
+	},
+	"sequential-thinking": {
+		ID:          "sequential-thinking",
+		Description: "Structured step-by-step problem solving",
+		Server: config.MCPServer{
+			Command: "npx",
+			Args:    []string{"-y", "@modelcontextprotocol/server-sequential-thinking"},
+		},
+	},
+	"fetch": {
+		ID:          "fetch",
+		Description: "Fetch web pages and convert them to markdown",
+		Server: config.MCPServer{
+			Command: "uvx",
+			Args:    []string{"mcp-server-fetch"},
+		},
+	},
+	"git": {
+		ID:          "git",
+		Description: "Read, search, and manipulate a Git repository",
+		Server: config.MCPServer{
+			Command: "uvx",
+			Args:    []string{"mcp-server-git", "--repository", "${input:repository}"},
+		},
+		Inputs: []Input{{Name: "repository", Prompt: "Path to the Git repository", Default: "."}},
+	},
+	"brave-search": {
+		ID:          "brave-search",
+		Description: "Web and local search using the Brave Search API",
+		Server: config.MCPServer{
+			Command: "npx",
+			Args:    []string{"-y", "@modelcontextprotocol/server-brave-search"},
+			Env:     map[string]string{"BRAVE_API_KEY": "${input:apiKey}"},
+		},
+		Inputs: []Input{{Name: "apiKey", Prompt: "Brave Search API key", Secret: true}},
+	},
+}
+
+// Get returns the catalog entry with the given ID
+func Get(id string) (Entry, bool) {
+	entry, ok := entries[id]
+	return entry, ok
+}
+
+// List returns all catalog entries sorted by ID
+func List() []Entry {
+	list := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, entry)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+var inputPattern = regexp.MustCompile(`\$\{input:([A-Za-z0-9_]+)\}`)
+
+// Render substitutes ${input:name} placeholders with the given values
+func Render(server config.MCPServer, values map[string]string) (config.MCPServer, error) {
+	var missing string
+	replace := func(s string) string {
+		return inputPattern.ReplaceAllStringFunc(s, func(match string) string {
+			name := inputPattern.FindStringSubmatch(match)[1]
+			value, ok := values[name]
+			if !ok && missing == "" {
+				missing = name
+			}
+			return value
+		})
+	}
+
+	rendered := server
+	rendered.Command = replace(server.Command)
+	rendered.Cwd = replace(server.Cwd)
+	rendered.URL = replace(server.URL)
+
+	if server.Args != nil {
+		rendered.Args = make([]string, len(server.Args))
+		for i, arg := range server.Args {
+			rendered.Args[i] = replace(arg)
+		}
+	}
+	if server.Env != nil {
+		rendered.Env = make(map[string]string, len(server.Env))
+		for k, v := range server.Env {
+			rendered.Env[replace(k)] = replace(v)
+		}
+	}
+
+	if missing != "" {
+		return config.MCPServer{}, fmt.Errorf("no value provided for input '%s'", missing)
+	}
+	return rendered, nil
+}
@@ -0,0 +1,97 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestRender(t *testing.T) {
+	server := config.MCPServer{
+		Command: "docker",
+		Args:    []string{"run", "-v", "${input:path}:/data", "image"},
+		Env:     map[string]string{"TOKEN": "${input:token}", "PORT": "8080"},
+	}
+
+	rendered, err := Render(server, map[string]string{"path": "/home/me", "token": "abc"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if rendered.Args[2] != "/home/me:/data" {
+		t.Errorf("Args[2] = %q, want %q", rendered.Args[2], "/home/me:/data")
+	}
+	if rendered.Env["TOKEN"] != "abc" || rendered.Env["PORT"] != "8080" {
+		t.Errorf("Env = %v", rendered.Env)
+	}
+
+	// The original definition must be left untouched
+	if server.Env["TOKEN"] != "${input:token}" || server.Args[2] != "${input:path}:/data" {
+		t.Error("Render() should not modify its input")
+	}
+}
+
+func TestRenderMissingInput(t *testing.T) {
+	server := config.MCPServer{Command: "npx", Args: []string{"${input:path}"}}
+	if _, err := Render(server, map[string]string{}); err == nil {
+		t.Error("expected error for missing input")
+	}
+}
+
+func TestCatalogEntriesDeclareTheirInputs(t *testing.T) {
+	for _, entry := range List() {
+		values := make(map[string]string)
+		for _, input := range entry.Inputs {
+			values[input.Name] = "value"
+		}
+		if _, err := Render(entry.Server, values); err != nil {
+			t.Errorf("entry '%s': %v", entry.ID, err)
+		}
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	names := TemplateNames()
+	if len(names) == 0 {
+		t.Fatal("expected embedded templates")
+	}
+
+	for _, name := range names {
+		tmpl, err := GetTemplate(name)
+		if err != nil {
+			t.Fatalf("GetTemplate(%q) error = %v", name, err)
+		}
+
+		values := make(map[string]string)
+		for _, input := range tmpl.Inputs {
+			values[input.Name] = "VALUE"
+		}
+		server, err := Render(tmpl.Server, values)
+		if err != nil {
+			t.Errorf("template '%s': %v", name, err)
+		}
+		if server.Command == "" && server.URL == "" {
+			t.Errorf("template '%s' has neither command nor url", name)
+		}
+	}
+}
+
+func TestTemplateEnvKeyPlaceholder(t *testing.T) {
+	tmpl, err := GetTemplate("docker-token")
+	if err != nil {
+		t.Fatalf("GetTemplate() error = %v", err)
+	}
+
+	server, err := Render(tmpl.Server, map[string]string{"image": "img", "tokenVar": "MY_TOKEN", "token": "s3cret"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if server.Env["MY_TOKEN"] != "s3cret" {
+		t.Errorf("Env = %v, want MY_TOKEN=s3cret", server.Env)
+	}
+}
+
+func TestUnknownTemplate(t *testing.T) {
+	if _, err := GetTemplate("nope"); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
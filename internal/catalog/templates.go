@@ -0,0 +1,49 @@
+package catalog
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+//go:embed templates/*.json
+var templateFiles embed.FS
+
+// Template is a generic server shape (docker, npx, uvx, ...) with placeholders to fill in
+type Template struct {
+	Name        string           `json:"-"`
+	Description string           `json:"description"`
+	Inputs      []Input          `json:"inputs"`
+	Server      config.MCPServer `json:"server"`
+}
+
+// GetTemplate loads the embedded template with the given name
+func GetTemplate(name string) (*Template, error) {
+	data, err := templateFiles.ReadFile(path.Join("templates", name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown template '%s' (available: %s)", name, strings.Join(TemplateNames(), ", "))
+	}
+
+	var tmpl Template
+	if err := json.Unmarshal(data, &tmpl); err != nil {
+		return nil, fmt.Errorf("invalid template '%s': %w", name, err)
+	}
+	tmpl.Name = name
+	return &tmpl, nil
+}
+
+// TemplateNames returns the names of all embedded templates, sorted
+func TemplateNames() []string {
+	files, _ := templateFiles.ReadDir("templates")
+	var names []string
+	for _, f := range files {
+		names = append(names, strings.TrimSuffix(f.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,345 @@
+// Package clients writes cmcp server definitions into other MCP clients' config files
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+)
+
+// Client describes where another MCP client keeps its servers and how it expects them
+type Client struct {
+	Name         string // Identifier used with --to
+	DisplayName  string
+	File         string // Config file relative to the project directory, empty if only user-level
+	ServersKey   string // Top-level key holding the server map
+	JSONC        bool   // File may contain comments; edits only touch the keys cmcp writes
+	SecretInputs bool   // Sensitive env values become prompted "inputs" instead of plain text
+	translate    func(server config.MCPServer) map[string]interface{}
+	userFile     func() (string, error) // User-level config file, shared by all projects
+}
+
+var supported = []Client{
+	{
+		Name:        "cursor",
+		DisplayName: "Cursor",
+		File:        filepath.Join(".cursor", "mcp.json"),
+		ServersKey:  "mcpServers",
+		translate:   cursorServer,
+		userFile:    homeFile(".cursor", "mcp.json"),
+	},
+	{
+		Name:         "vscode",
+		DisplayName:  "VS Code",
+		File:         filepath.Join(".vscode", "mcp.json"),
+		ServersKey:   "servers",
+		JSONC:        true,
+		SecretInputs: true,
+		translate:    vscodeServer,
+		userFile: func() (string, error) {
+			dir, err := os.UserConfigDir()
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(dir, "Code", "User", "mcp.json"), nil
+		},
+	},
+	{
+		Name:        "zed",
+		DisplayName: "Zed",
+		File:        filepath.Join(".zed", "settings.json"),
+		ServersKey:  "context_servers",
+		JSONC:       true,
+		translate:   zedServer,
+		userFile:    homeFile(".config", "zed", "settings.json"),
+	},
+	{
+		Name:        "windsurf",
+		DisplayName: "Windsurf",
+		ServersKey:  "mcpServers",
+		translate:   windsurfServer,
+		userFile:    homeFile(".codeium", "windsurf", "mcp_config.json"),
+	},
+	{
+		Name:        "cline",
+		DisplayName: "Cline",
+		ServersKey:  "mcpServers",
+		translate:   clineServer,
+		userFile:    vscodeExtensionFile("saoudrizwan.claude-dev", "cline_mcp_settings.json"),
+	},
+	{
+		Name:        "roo",
+		DisplayName: "Roo Code",
+		File:        filepath.Join(".roo", "mcp.json"),
+		ServersKey:  "mcpServers",
+		translate:   clineServer,
+		userFile:    vscodeExtensionFile("rooveterinaryinc.roo-cline", "mcp_settings.json"),
+	},
+}
+
+// clineOnlyFields are config extras understood by Cline and Roo Code only
+var clineOnlyFields = []string{"disabled", "autoApprove"}
+
+// vscodeExtensionFile returns a userFile func for a VS Code extension's settings file
+func vscodeExtensionFile(extension, file string) func() (string, error) {
+	return func() (string, error) {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "Code", "User", "globalStorage", extension, "settings", file), nil
+	}
+}
+
+// homeFile returns a userFile func for a path under the home directory
+func homeFile(elem ...string) func() (string, error) {
+	return func() (string, error) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(append([]string{home}, elem...)...), nil
+	}
+}
+
+// Names returns the identifiers of the supported clients
+func Names() []string {
+	names := make([]string, len(supported))
+	for i, client := range supported {
+		names[i] = client.Name
+	}
+	return names
+}
+
+// Lookup returns the supported client with the given identifier
+func Lookup(name string) (Client, error) {
+	for _, client := range supported {
+		if client.Name == strings.ToLower(name) {
+			return client, nil
+		}
+	}
+	return Client{}, fmt.Errorf("unknown client '%s' (supported: %s)", name, strings.Join(Names(), ", "))
+}
+
+// Path returns the client's config file for a project directory
+func (c Client) Path(projectDir string) (string, error) {
+	if c.File == "" {
+		return "", fmt.Errorf("%s has no project-level config; use its user-level config instead", c.DisplayName)
+	}
+	return filepath.Join(projectDir, c.File), nil
+}
+
+// UserPath returns the client's user-level config file
+func (c Client) UserPath() (string, error) {
+	if c.userFile == nil {
+		return "", fmt.Errorf("%s has no user-level config", c.DisplayName)
+	}
+	path, err := c.userFile()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate %s user config: %w", c.DisplayName, err)
+	}
+	return path, nil
+}
+
+// Merge adds or replaces the given servers in the client's existing config data,
+// keeping other servers and top-level settings. Empty data starts a new config.
+func (c Client) Merge(existing []byte, servers map[string]config.MCPServer) ([]byte, error) {
+	doc := make(map[string]interface{})
+	hasContent := len(strings.TrimSpace(string(existing))) > 0
+	if hasContent {
+		source := existing
+		if c.JSONC {
+			source = stripJSONC(existing)
+		}
+		if err := json.Unmarshal(source, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s config: %w", c.DisplayName, err)
+		}
+	}
+
+	entries, ok := doc[c.ServersKey].(map[string]interface{})
+	if !ok {
+		if _, exists := doc[c.ServersKey]; exists {
+			return nil, fmt.Errorf("%s config has an unexpected %q value", c.DisplayName, c.ServersKey)
+		}
+		entries = make(map[string]interface{})
+	}
+
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	inputs, _ := doc["inputs"].([]interface{})
+	declaredInputs := len(inputs)
+	for _, name := range names {
+		server := servers[name]
+		entry := c.translate(server)
+		if c.SecretInputs {
+			inputs = secretInputs(name, &server, entry, inputs)
+		}
+		entries[name] = entry
+	}
+
+	// Top-level keys written by cmcp, in order
+	keys := []string{c.ServersKey}
+	updates := map[string]interface{}{c.ServersKey: entries}
+	if len(inputs) > declaredInputs {
+		keys = append(keys, "inputs")
+		updates["inputs"] = inputs
+	}
+
+	if c.JSONC && hasContent {
+		data := existing
+		for _, key := range keys {
+			var err error
+			if data, err = setTopLevelKey(data, key, updates[key]); err != nil {
+				return nil, err
+			}
+		}
+		return data, nil
+	}
+	for _, key := range keys {
+		doc[key] = updates[key]
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// portableMap returns the server definition without settings specific to one client
+func portableMap(server config.MCPServer) map[string]interface{} {
+	result := server.ClaudeMap()
+	for _, key := range clineOnlyFields {
+		delete(result, key)
+	}
+	return result
+}
+
+// cursorServer matches Cursor's mcp.json, which infers the transport from command or url
+func cursorServer(server config.MCPServer) map[string]interface{} {
+	result := portableMap(server)
+	delete(result, "type")
+	return result
+}
+
+// vscodeServer matches VS Code's mcp.json, which requires an explicit transport type
+func vscodeServer(server config.MCPServer) map[string]interface{} {
+	result := portableMap(server)
+	if server.IsRemote() {
+		if server.Type == "" {
+			result["type"] = "http"
+		}
+	} else {
+		result["type"] = "stdio"
+	}
+	return result
+}
+
+// zedServer matches the context_servers entries in Zed's settings.json
+func zedServer(server config.MCPServer) map[string]interface{} {
+	result := portableMap(server)
+	delete(result, "type")
+	if !server.IsRemote() {
+		result["source"] = "custom"
+	}
+	return result
+}
+
+// windsurfServer matches Windsurf's mcp_config.json, which takes remote endpoints as serverUrl
+func windsurfServer(server config.MCPServer) map[string]interface{} {
+	result := portableMap(server)
+	delete(result, "type")
+	if url, ok := result["url"]; ok {
+		delete(result, "url")
+		result["serverUrl"] = url
+	}
+	return result
+}
+
+// clineServer matches Cline's and Roo Code's settings, which carry a disabled flag and
+// an autoApprove tool list taken from the server's config extras
+func clineServer(server config.MCPServer) map[string]interface{} {
+	result := server.ClaudeMap()
+	if server.IsRemote() {
+		// Both call plain HTTP "streamableHttp"
+		if server.Type == "" || server.Type == "http" {
+			result["type"] = "streamableHttp"
+		}
+	} else {
+		delete(result, "type")
+	}
+
+	result["disabled"] = server.IsDisabled()
+
+	autoApprove := []string{}
+	if tools, ok := server.Extra["autoApprove"].([]interface{}); ok {
+		for _, tool := range tools {
+			if name, ok := tool.(string); ok {
+				autoApprove = append(autoApprove, name)
+			}
+		}
+	}
+	result["autoApprove"] = autoApprove
+	return result
+}
+
+// secretInputs replaces sensitive env values in a translated server with ${input:id}
+// references and declares a password prompt for each one that is not declared yet.
+// Values that are already variable references are left alone.
+func secretInputs(name string, server *config.MCPServer, entry map[string]interface{}, inputs []interface{}) []interface{} {
+	env, ok := entry["env"].(map[string]string)
+	if !ok {
+		return inputs
+	}
+
+	declared := make(map[string]bool)
+	for _, input := range inputs {
+		if m, ok := input.(map[string]interface{}); ok {
+			if id, ok := m["id"].(string); ok {
+				declared[id] = true
+			}
+		}
+	}
+
+	mapped := make(map[string]string, len(env))
+	for _, key := range getSortedKeys(env) {
+		value := env[key]
+		if value == "" || strings.Contains(value, "${") || !mcp.IsSensitiveServerKey(server, key) {
+			mapped[key] = value
+			continue
+		}
+
+		id := name + "-" + strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+		mapped[key] = "${input:" + id + "}"
+		if !declared[id] {
+			declared[id] = true
+			inputs = append(inputs, map[string]interface{}{
+				"type":        "promptString",
+				"id":          id,
+				"description": fmt.Sprintf("%s for %s", key, name),
+				"password":    true,
+			})
+		}
+	}
+	entry["env"] = mapped
+	return inputs
+}
+
+func getSortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,285 @@
+package clients
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestLookup(t *testing.T) {
+	if client, err := Lookup("VSCode"); err != nil || client.Name != "vscode" {
+		t.Errorf("Lookup(VSCode) = %v, %v, want vscode", client.Name, err)
+	}
+	if _, err := Lookup("emacs"); err == nil {
+		t.Error("expected error for an unsupported client")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	servers := map[string]config.MCPServer{
+		"github": {Command: "npx", Args: []string{"-y", "gh"}, Tags: []string{"work"}, Retries: 2},
+		"remote": {URL: "https://example.com/mcp", Type: "sse"},
+	}
+
+	tests := []struct {
+		name     string
+		client   string
+		existing string
+		check    func(t *testing.T, doc map[string]interface{})
+	}{
+		{
+			name:   "cursor drops transport type and cmcp-only fields",
+			client: "cursor",
+			check: func(t *testing.T, doc map[string]interface{}) {
+				entries := doc["mcpServers"].(map[string]interface{})
+				github := entries["github"].(map[string]interface{})
+				if github["command"] != "npx" || github["tags"] != nil || github["retries"] != nil {
+					t.Errorf("github = %v", github)
+				}
+				remote := entries["remote"].(map[string]interface{})
+				if remote["url"] != "https://example.com/mcp" || remote["type"] != nil {
+					t.Errorf("remote = %v", remote)
+				}
+			},
+		},
+		{
+			name:   "vscode requires an explicit type",
+			client: "vscode",
+			check: func(t *testing.T, doc map[string]interface{}) {
+				entries := doc["servers"].(map[string]interface{})
+				if got := entries["github"].(map[string]interface{})["type"]; got != "stdio" {
+					t.Errorf("github type = %v, want stdio", got)
+				}
+				if got := entries["remote"].(map[string]interface{})["type"]; got != "sse" {
+					t.Errorf("remote type = %v, want sse", got)
+				}
+			},
+		},
+		{
+			name:   "windsurf uses serverUrl for remote servers",
+			client: "windsurf",
+			check: func(t *testing.T, doc map[string]interface{}) {
+				entries := doc["mcpServers"].(map[string]interface{})
+				remote := entries["remote"].(map[string]interface{})
+				if remote["serverUrl"] != "https://example.com/mcp" || remote["url"] != nil || remote["type"] != nil {
+					t.Errorf("remote = %v", remote)
+				}
+				if got := entries["github"].(map[string]interface{})["command"]; got != "npx" {
+					t.Errorf("github command = %v, want npx", got)
+				}
+			},
+		},
+		{
+			name:   "cline defaults disabled and autoApprove",
+			client: "cline",
+			check: func(t *testing.T, doc map[string]interface{}) {
+				entries := doc["mcpServers"].(map[string]interface{})
+				github := entries["github"].(map[string]interface{})
+				if github["disabled"] != false || len(github["autoApprove"].([]interface{})) != 0 {
+					t.Errorf("github = %v", github)
+				}
+				if got := entries["remote"].(map[string]interface{})["type"]; got != "sse" {
+					t.Errorf("remote type = %v, want sse", got)
+				}
+			},
+		},
+		{
+			name:     "existing servers and settings are kept",
+			client:   "vscode",
+			existing: `{"inputs": [{"id": "token"}], "servers": {"local": {"type": "stdio", "command": "x"}, "github": {"command": "old"}}}`,
+			check: func(t *testing.T, doc map[string]interface{}) {
+				if doc["inputs"] == nil {
+					t.Error("inputs were dropped")
+				}
+				entries := doc["servers"].(map[string]interface{})
+				if entries["local"] == nil {
+					t.Error("unmanaged server was dropped")
+				}
+				if got := entries["github"].(map[string]interface{})["command"]; got != "npx" {
+					t.Errorf("github command = %v, want npx", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := Lookup(tt.client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := client.Merge([]byte(tt.existing), servers)
+			if err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+			var doc map[string]interface{}
+			if err := json.Unmarshal(data, &doc); err != nil {
+				t.Fatalf("Merge() produced invalid JSON: %v", err)
+			}
+			tt.check(t, doc)
+		})
+	}
+}
+
+func TestMergeInvalid(t *testing.T) {
+	client, _ := Lookup("cursor")
+	for _, existing := range []string{`{not json`, `{"mcpServers": []}`} {
+		if _, err := client.Merge([]byte(existing), nil); err == nil {
+			t.Errorf("Merge(%s) expected error", existing)
+		}
+	}
+}
+
+func TestMergeZedKeepsComments(t *testing.T) {
+	client, err := Lookup("zed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	servers := map[string]config.MCPServer{"github": {Command: "npx", Args: []string{"gh"}}}
+
+	tests := []struct {
+		name     string
+		existing string
+	}{
+		{name: "replace existing key", existing: "// Zed settings\n{\n  \"theme\": \"One Dark\", // dark\n  \"context_servers\": {\n    \"local\": {\"command\": \"x\"}, /* keep */\n  },\n}\n"},
+		{name: "append after trailing comma", existing: "{\n  // font\n  \"buffer_font_size\": 15,\n}\n"},
+		{name: "append without trailing comma", existing: "{\n  \"vim_mode\": true\n}\n"},
+		{name: "empty object", existing: "{}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := client.Merge([]byte(tt.existing), servers)
+			if err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+			var doc map[string]interface{}
+			if err := json.Unmarshal(stripJSONC(data), &doc); err != nil {
+				t.Fatalf("Merge() produced invalid JSONC: %v\n%s", err, data)
+			}
+			github := doc["context_servers"].(map[string]interface{})["github"].(map[string]interface{})
+			if github["source"] != "custom" || github["command"] != "npx" {
+				t.Errorf("github = %v", github)
+			}
+			if strings.Contains(tt.existing, "// Zed settings") && !strings.Contains(string(data), "// Zed settings") {
+				t.Errorf("comments outside context_servers were not kept:\n%s", data)
+			}
+			if strings.Contains(tt.existing, "\"local\"") && doc["context_servers"].(map[string]interface{})["local"] == nil {
+				t.Errorf("unmanaged server was dropped:\n%s", data)
+			}
+		})
+	}
+}
+
+func TestStripJSONC(t *testing.T) {
+	input := `{"a": "http://x//y", /* c */ "b": [1, 2,], // tail
+}`
+	var doc map[string]interface{}
+	if err := json.Unmarshal(stripJSONC([]byte(input)), &doc); err != nil {
+		t.Fatalf("stripJSONC() output invalid: %v", err)
+	}
+	if doc["a"] != "http://x//y" {
+		t.Errorf("a = %v, want URL untouched", doc["a"])
+	}
+}
+
+func TestMergeVSCodeInputs(t *testing.T) {
+	client, err := Lookup("vscode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	servers := map[string]config.MCPServer{
+		"github": {Command: "npx", Env: map[string]string{
+			"GITHUB_TOKEN": "ghp_secret",
+			"LOG_LEVEL":    "debug",
+			"API_KEY":      "${env:API_KEY}",
+		}},
+	}
+	existing := `{
+  // Declared by hand
+  "inputs": [{"type": "promptString", "id": "github-github-token", "password": true}],
+  "servers": {}
+}`
+
+	data, err := client.Merge([]byte(existing), servers)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if strings.Contains(string(data), "ghp_secret") {
+		t.Errorf("secret value was written:\n%s", data)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(stripJSONC(data), &doc); err != nil {
+		t.Fatalf("Merge() produced invalid JSONC: %v", err)
+	}
+	env := doc["servers"].(map[string]interface{})["github"].(map[string]interface{})["env"].(map[string]interface{})
+	if env["GITHUB_TOKEN"] != "${input:github-github-token}" {
+		t.Errorf("GITHUB_TOKEN = %v, want input reference", env["GITHUB_TOKEN"])
+	}
+	if env["LOG_LEVEL"] != "debug" || env["API_KEY"] != "${env:API_KEY}" {
+		t.Errorf("env = %v, want non-secrets and references unchanged", env)
+	}
+	if inputs := doc["inputs"].([]interface{}); len(inputs) != 1 {
+		t.Errorf("inputs = %v, want the existing declaration reused", inputs)
+	}
+	if !strings.Contains(string(data), "// Declared by hand") {
+		t.Errorf("comment was dropped:\n%s", data)
+	}
+
+	// The server's own env map must not be modified
+	if servers["github"].Env["GITHUB_TOKEN"] != "ghp_secret" {
+		t.Error("Merge() modified the cmcp server definition")
+	}
+}
+
+func TestProjectPath(t *testing.T) {
+	cursor, _ := Lookup("cursor")
+	if path, err := cursor.Path("/work"); err != nil || path != filepath.Join("/work", ".cursor", "mcp.json") {
+		t.Errorf("cursor Path() = %q, %v", path, err)
+	}
+	windsurf, _ := Lookup("windsurf")
+	if _, err := windsurf.Path("/work"); err == nil {
+		t.Error("expected error for a client without project-level config")
+	}
+}
+
+func TestUserPath(t *testing.T) {
+	for _, name := range Names() {
+		client, _ := Lookup(name)
+		if path, err := client.UserPath(); err != nil || path == "" {
+			t.Errorf("%s UserPath() = %q, %v", name, path, err)
+		}
+	}
+}
+
+func TestClineExtras(t *testing.T) {
+	server := config.MCPServer{
+		URL: "https://example.com/mcp",
+		Extra: map[string]interface{}{
+			"disabled":    true,
+			"autoApprove": []interface{}{"search", "read"},
+		},
+	}
+
+	cline := clineServer(server)
+	if cline["disabled"] != true || !reflect.DeepEqual(cline["autoApprove"], []string{"search", "read"}) {
+		t.Errorf("clineServer() = %v, want extras mapped", cline)
+	}
+	if cline["type"] != "streamableHttp" {
+		t.Errorf("type = %v, want streamableHttp", cline["type"])
+	}
+
+	// Other clients don't understand these fields
+	cursor := cursorServer(server)
+	if _, ok := cursor["disabled"]; ok {
+		t.Errorf("cursorServer() = %v, want no disabled field", cursor)
+	}
+	if _, ok := cursor["autoApprove"]; ok {
+		t.Errorf("cursorServer() = %v, want no autoApprove field", cursor)
+	}
+}
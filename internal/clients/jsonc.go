@@ -0,0 +1,190 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// stripJSONC removes comments and trailing commas so JSON with comments
+// (as used by editor settings files) can be decoded with encoding/json
+func stripJSONC(data []byte) []byte {
+	var out bytes.Buffer
+	for i := 0; i < len(data); {
+		switch {
+		case data[i] == '"':
+			end := scanString(data, i)
+			out.Write(data[i:end])
+			i = end
+		case isCommentStart(data, i):
+			i = skipComment(data, i)
+			out.WriteByte(' ')
+		case data[i] == ',':
+			next := skipSpace(data, i+1)
+			if next < len(data) && (data[next] == '}' || data[next] == ']') {
+				i++
+				continue
+			}
+			out.WriteByte(',')
+			i++
+		default:
+			out.WriteByte(data[i])
+			i++
+		}
+	}
+	return out.Bytes()
+}
+
+// setTopLevelKey replaces the value of a top-level key in a JSONC object, or adds
+// the key when missing, leaving the rest of the text (comments, order, formatting) untouched
+func setTopLevelKey(data []byte, key string, value interface{}) ([]byte, error) {
+	encoded, err := json.MarshalIndent(value, "  ", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return nil, fmt.Errorf("settings file is not a JSON object")
+	}
+	open := i
+	i = skipSpace(data, i+1)
+
+	lastValueEnd := -1
+	trailingComma := -1
+	for i < len(data) && data[i] != '}' {
+		if data[i] != '"' {
+			return nil, fmt.Errorf("unexpected %q at offset %d", data[i], i)
+		}
+		keyEnd := scanString(data, i)
+		var name string
+		if err := json.Unmarshal(data[i:keyEnd], &name); err != nil {
+			return nil, fmt.Errorf("invalid key at offset %d: %w", i, err)
+		}
+
+		i = skipSpace(data, keyEnd)
+		if i >= len(data) || data[i] != ':' {
+			return nil, fmt.Errorf("expected ':' after key %q", name)
+		}
+		valueStart := skipSpace(data, i+1)
+		valueEnd := scanValue(data, valueStart)
+		if name == key {
+			return splice(data, valueStart, valueEnd, encoded), nil
+		}
+
+		lastValueEnd, trailingComma = valueEnd, -1
+		i = skipSpace(data, valueEnd)
+		if i < len(data) && data[i] == ',' {
+			trailingComma = i
+			i = skipSpace(data, i+1)
+		}
+	}
+	if i >= len(data) {
+		return nil, fmt.Errorf("settings file is missing its closing brace")
+	}
+
+	entry := append([]byte(fmt.Sprintf("\n  %q: ", key)), encoded...)
+	switch {
+	case lastValueEnd < 0:
+		return splice(data, open+1, open+1, append(entry, '\n')), nil
+	case trailingComma >= 0:
+		return splice(data, trailingComma+1, trailingComma+1, append(entry, ',')), nil
+	default:
+		return splice(data, lastValueEnd, lastValueEnd, append([]byte(","), entry...)), nil
+	}
+}
+
+// splice replaces data[start:end] with insert
+func splice(data []byte, start, end int, insert []byte) []byte {
+	result := make([]byte, 0, len(data)-(end-start)+len(insert))
+	result = append(result, data[:start]...)
+	result = append(result, insert...)
+	return append(result, data[end:]...)
+}
+
+func isCommentStart(data []byte, i int) bool {
+	return data[i] == '/' && i+1 < len(data) && (data[i+1] == '/' || data[i+1] == '*')
+}
+
+// skipComment returns the index just past the comment starting at i
+func skipComment(data []byte, i int) int {
+	if data[i+1] == '/' {
+		end := bytes.IndexByte(data[i:], '\n')
+		if end < 0 {
+			return len(data)
+		}
+		return i + end
+	}
+	end := bytes.Index(data[i+2:], []byte("*/"))
+	if end < 0 {
+		return len(data)
+	}
+	return i + 2 + end + 2
+}
+
+// skipSpace returns the index of the next character that is not whitespace or a comment
+func skipSpace(data []byte, i int) int {
+	for i < len(data) {
+		switch {
+		case data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r':
+			i++
+		case isCommentStart(data, i):
+			i = skipComment(data, i)
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// scanString returns the index just past the string literal starting at i
+func scanString(data []byte, i int) int {
+	for j := i + 1; j < len(data); j++ {
+		switch data[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1
+		}
+	}
+	return len(data)
+}
+
+// scanValue returns the index just past the value starting at i
+func scanValue(data []byte, i int) int {
+	if i >= len(data) {
+		return i
+	}
+	switch data[i] {
+	case '"':
+		return scanString(data, i)
+	case '{', '[':
+		depth := 0
+		for j := i; j < len(data); {
+			switch {
+			case data[j] == '"':
+				j = scanString(data, j)
+				continue
+			case isCommentStart(data, j):
+				j = skipComment(data, j)
+				continue
+			case data[j] == '{' || data[j] == '[':
+				depth++
+			case data[j] == '}' || data[j] == ']':
+				depth--
+				if depth == 0 {
+					return j + 1
+				}
+			}
+			j++
+		}
+		return len(data)
+	}
+
+	// Literal (number, true, false, null)
+	j := i
+	for j < len(data) && !bytes.ContainsAny(data[j:j+1], ",}] \t\r\n/") {
+		j++
+	}
+	return j
+}
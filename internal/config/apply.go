@@ -0,0 +1,109 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Actions an apply takes on a server
+const (
+	ApplyCreated    = "created"
+	ApplyConfigured = "configured"
+	ApplyUnchanged  = "unchanged"
+	ApplyPruned     = "pruned"
+)
+
+// ApplyChange is what applying a desired state does to one server
+type ApplyChange struct {
+	Name   string
+	Action string
+}
+
+// ParseDesiredState decodes a file of server definitions under "mcpServers", in the
+// same shape as the config file. YAML and JSON are both accepted.
+func ParseDesiredState(data []byte) (map[string]MCPServer, error) {
+	var raw struct {
+		MCPServers map[string]interface{} `yaml:"mcpServers"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid desired state: %w", err)
+	}
+	if raw.MCPServers == nil {
+		return nil, fmt.Errorf("desired state has no \"mcpServers\"")
+	}
+
+	servers := make(map[string]MCPServer, len(raw.MCPServers))
+	for name, definition := range raw.MCPServers {
+		if name == "" || IsPattern(name) {
+			return nil, fmt.Errorf("invalid server name '%s'", name)
+		}
+		// Round-trip through JSON so YAML definitions keep unknown fields like config files do
+		data, err := json.Marshal(definition)
+		if err != nil {
+			return nil, fmt.Errorf("server '%s': %w", name, err)
+		}
+		server, err := ParseServerJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("server '%s': %w", name, err)
+		}
+		servers[name] = server
+	}
+	return servers, nil
+}
+
+// PlanApply compares the configured servers with a desired state and returns the
+// change for each server, sorted by name. Servers missing from the desired state are
+// pruned only when prune is set; otherwise they are left out of the plan. Servers
+// merged in from fragments, includes or an overlay are never pruned, since they live
+// in their own files and would come back on the next load.
+func (c *Config) PlanApply(desired map[string]MCPServer, prune bool) []ApplyChange {
+	var changes []ApplyChange
+	for name, server := range desired {
+		current, exists := c.MCPServers[name]
+		switch {
+		case !exists:
+			changes = append(changes, ApplyChange{name, ApplyCreated})
+		case sameDefinition(current, server):
+			changes = append(changes, ApplyChange{name, ApplyUnchanged})
+		default:
+			changes = append(changes, ApplyChange{name, ApplyConfigured})
+		}
+	}
+	if prune {
+		for name := range c.MCPServers {
+			if _, merged := c.MergedFrom(name); merged {
+				continue
+			}
+			if _, wanted := desired[name]; !wanted {
+				changes = append(changes, ApplyChange{name, ApplyPruned})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+// Apply makes the changes of a plan from PlanApply and saves the config once
+func (c *Config) Apply(desired map[string]MCPServer, changes []ApplyChange) error {
+	for _, change := range changes {
+		switch change.Action {
+		case ApplyCreated, ApplyConfigured:
+			c.MCPServers[change.Name] = desired[change.Name]
+		case ApplyPruned:
+			delete(c.MCPServers, change.Name)
+			c.removeFromAllGroups(change.Name)
+		}
+	}
+	return Save(c)
+}
+
+// sameDefinition reports whether two servers would be saved identically
+func sameDefinition(a, b MCPServer) bool {
+	aData, errA := json.Marshal(a)
+	bData, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aData, bData)
+}
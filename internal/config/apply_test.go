@@ -0,0 +1,122 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDesiredState(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]MCPServer
+		wantErr bool
+	}{
+		{
+			name: "yaml with inherited env and extra fields",
+			data: `mcpServers:
+  github:
+    command: npx
+    args: ["-y", "@modelcontextprotocol/server-github"]
+    env:
+      GITHUB_TOKEN: null
+    tags: [work]
+    disabled: true
+`,
+			want: map[string]MCPServer{
+				"github": {
+					Command: "npx",
+					Args:    []string{"-y", "@modelcontextprotocol/server-github"},
+					Env:     map[string]string{"GITHUB_TOKEN": InheritEnv},
+					Tags:    []string{"work"},
+					Extra:   map[string]interface{}{"disabled": true},
+				},
+			},
+		},
+		{
+			name: "json",
+			data: `{"mcpServers": {"docs": {"type": "http", "url": "https://example.com/mcp"}}}`,
+			want: map[string]MCPServer{
+				"docs": {Type: "http", URL: "https://example.com/mcp"},
+			},
+		},
+		{name: "no mcpServers", data: "servers: {}\n", wantErr: true},
+		{name: "server without command or url", data: "mcpServers:\n  empty: {}\n", wantErr: true},
+		{name: "pattern as name", data: "mcpServers:\n  'gh-*': {command: gh}\n", wantErr: true},
+		{name: "invalid yaml", data: "mcpServers: [", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDesiredState([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDesiredState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDesiredState() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanApply(t *testing.T) {
+	cfg := &Config{
+		MCPServers: map[string]MCPServer{
+			"fetch":  {Command: "uvx", Args: []string{"mcp-server-fetch"}},
+			"github": {Command: "npx", Args: []string{"server-github"}},
+			"old":    {Command: "old-server"},
+		},
+	}
+	desired := map[string]MCPServer{
+		"fetch":  {Command: "uvx", Args: []string{"mcp-server-fetch"}},
+		"github": {Command: "npx", Args: []string{"server-github"}, Tags: []string{"work"}},
+		"linear": {Type: "sse", URL: "https://mcp.linear.app/sse"},
+	}
+
+	want := []ApplyChange{
+		{"fetch", ApplyUnchanged},
+		{"github", ApplyConfigured},
+		{"linear", ApplyCreated},
+	}
+	if got := cfg.PlanApply(desired, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("PlanApply(prune=false) = %v, want %v", got, want)
+	}
+
+	want = []ApplyChange{
+		{"fetch", ApplyUnchanged},
+		{"github", ApplyConfigured},
+		{"linear", ApplyCreated},
+		{"old", ApplyPruned},
+	}
+	if got := cfg.PlanApply(desired, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("PlanApply(prune=true) = %v, want %v", got, want)
+	}
+}
+
+func TestPlanApplyKeepsMergedServers(t *testing.T) {
+	useTempConfig(t)
+	if err := Save(&Config{MCPServers: map[string]MCPServer{"old": {Command: "old-server"}}}); err != nil {
+		t.Fatal(err)
+	}
+	writeFragment(t, "frag.json", `{"mcpServers": {"frag": {"command": "frag-server"}}}`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	desired := map[string]MCPServer{"fetch": {Command: "uvx"}}
+	want := []ApplyChange{{"fetch", ApplyCreated}, {"old", ApplyPruned}}
+	changes := cfg.PlanApply(desired, true)
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("PlanApply(prune=true) = %v, want %v", changes, want)
+	}
+	if err := cfg.Apply(desired, changes); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if cfg, err = Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.GetServerNames(); !reflect.DeepEqual(got, []string{"fetch", "frag"}) {
+		t.Errorf("servers after apply = %v, want fetch and frag", got)
+	}
+}
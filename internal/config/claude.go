@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultClaudeBinary names the claude found on PATH. It can be picked with
+// --claude-bin without being configured.
+const DefaultClaudeBinary = "claude"
+
+// ClaudeSettings names the claude installations cmcp can drive, such as a stable and
+// a canary build or a wrapper script
+type ClaudeSettings struct {
+	Binaries map[string]string `json:"binaries,omitempty"` // Name to a command on PATH or a path; ~ is expanded
+	Default  string            `json:"default,omitempty"`  // Binary used without --claude-bin; claude on PATH when empty
+}
+
+// ClaudeBinaries returns the claude installations by name, including claude on PATH
+// under DefaultClaudeBinary unless the config names another binary that way
+func (c *Config) ClaudeBinaries() map[string]string {
+	binaries := map[string]string{DefaultClaudeBinary: DefaultClaudeBinary}
+	if c.Claude != nil {
+		for name, command := range c.Claude.Binaries {
+			binaries[name] = expandHome(command)
+		}
+	}
+	return binaries
+}
+
+// DefaultClaude returns the name of the claude installation used without --claude-bin
+func (c *Config) DefaultClaude() string {
+	if c.Claude != nil && c.Claude.Default != "" {
+		return c.Claude.Default
+	}
+	return DefaultClaudeBinary
+}
+
+// ClaudeBinary returns the command of the named claude installation, or of the
+// default one when name is empty. A name holding a path separator is used as a path.
+func (c *Config) ClaudeBinary(name string) (string, error) {
+	if name == "" {
+		name = c.DefaultClaude()
+	}
+	binaries := c.ClaudeBinaries()
+	if command, ok := binaries[name]; ok {
+		return command, nil
+	}
+	if strings.ContainsRune(name, '/') || strings.ContainsRune(name, filepath.Separator) {
+		return expandHome(name), nil
+	}
+	names := make([]string, 0, len(binaries))
+	for known := range binaries {
+		names = append(names, known)
+	}
+	sort.Strings(names)
+	return "", fmt.Errorf("unknown claude binary '%s' (configured: %s)", name, strings.Join(names, ", "))
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
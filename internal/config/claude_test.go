@@ -0,0 +1,64 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClaudeBinary(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	cfg := &Config{Claude: &ClaudeSettings{
+		Binaries: map[string]string{"canary": "claude-canary", "wrapper": "~/bin/claude-wrap"},
+		Default:  "canary",
+	}}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr string
+	}{
+		{"", "claude-canary", ""},
+		{"claude", "claude", ""},
+		{"wrapper", filepath.Join(home, "bin/claude-wrap"), ""},
+		{"/opt/claude/bin/claude", "/opt/claude/bin/claude", ""},
+		{"beta", "", "unknown claude binary 'beta' (configured: canary, claude, wrapper)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cfg.ClaudeBinary(tt.name)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ClaudeBinary(%q) error = %v, want %q", tt.name, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ClaudeBinary(%q) = %q, %v; want %q", tt.name, got, err, tt.want)
+			}
+		})
+	}
+
+	// Without settings, claude on PATH is the default
+	if got, err := (&Config{}).ClaudeBinary(""); err != nil || got != DefaultClaudeBinary {
+		t.Errorf("ClaudeBinary() without settings = %q, %v", got, err)
+	}
+}
+
+func TestClaudeSettingsRoundTrip(t *testing.T) {
+	var cfg Config
+	data := `{"mcpServers":{},"claude":{"binaries":{"canary":"claude-canary"},"default":"canary"}}`
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if cfg.Claude == nil || cfg.Claude.Default != "canary" || cfg.Extra != nil {
+		t.Fatalf("Claude = %+v, Extra = %v", cfg.Claude, cfg.Extra)
+	}
+	out, err := json.Marshal(cfg)
+	if err != nil || !strings.Contains(string(out), `"claude":{"binaries":{"canary":"claude-canary"},"default":"canary"}`) {
+		t.Errorf("Marshal() = %s, %v", out, err)
+	}
+}
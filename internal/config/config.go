@@ -4,7 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 )
 
 type MCPServer struct {
@@ -12,32 +16,103 @@ type MCPServer struct {
 	Args    []string               `json:"args,omitempty"`
 	Env     map[string]string      `json:"env,omitempty"`
 	Cwd     string                 `json:"cwd,omitempty"`
-	Extra   map[string]interface{} `json:"-"` // Stores any additional fields
+	Type    string                 `json:"type,omitempty"`    // Transport for remote servers ("http" or "sse")
+	URL     string                 `json:"url,omitempty"`     // Endpoint for remote servers
+	Headers map[string]string      `json:"headers,omitempty"` // HTTP headers for remote servers; values may use ${env:NAME} and ${token}
+	Extra   map[string]interface{} `json:"-"`                 // Stores any additional fields
+	BaseDir string                 `json:"-"`                 // Directory of the config file that defined the server; relative paths resolve against it
+
+	// cmcp-only settings, never passed to Claude
+	Alias          string     `json:"alias,omitempty"`          // Name the server is registered under in Claude, instead of its cmcp name
+	AutoRestart    bool       `json:"autoRestart,omitempty"`    // Re-add the server when cmcp monitor sees it fail
+	Tags           []string   `json:"tags,omitempty"`           // Labels used to filter bulk operations with --tag
+	StartupTimeout int        `json:"startupTimeout,omitempty"` // Seconds to wait for the server to connect after starting
+	Retries        int        `json:"retries,omitempty"`        // Extra start attempts when the server fails to connect
+	TokenCommand   string     `json:"tokenCommand,omitempty"`   // Shell command printing a fresh bearer token, run at start time
+	HealthCheck    string     `json:"healthCheck,omitempty"`    // Shell command or http(s) URL confirming the server is usable once connected
+	TLS            *TLSConfig `json:"tls,omitempty"`            // Certificates for cmcp's own connections to a remote server
+	Proxy          string     `json:"proxy,omitempty"`          // Proxy URL for cmcp's own connections, or "none"; default from HTTPS_PROXY
+	MaskKeys       []string   `json:"maskKeys,omitempty"`       // Env and header keys always masked in output
+	NoMaskKeys     []string   `json:"noMaskKeys,omitempty"`     // Env and header keys never masked, even if they look secret
 }
 
+// TLSConfig customizes how cmcp connects to a remote server over HTTPS
+type TLSConfig struct {
+	CAFile             string `json:"caFile,omitempty"`   // PEM bundle of extra CAs to trust
+	CertFile           string `json:"certFile,omitempty"` // Client certificate for mutual TLS
+	KeyFile            string `json:"keyFile,omitempty"`  // Key for certFile, when not in the same file
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
+}
+
+// InheritEnv is the env value that passes the variable from the shell running cmcp at
+// start time, so a config can declare what a server needs without storing the value.
+// A null env value in the config file means the same and is saved back as InheritEnv.
+const InheritEnv = "$inherit"
+
+// cmcpOnlyFields are config keys that only cmcp understands and must not be sent to Claude
+var cmcpOnlyFields = []string{"alias", "autoRestart", "tags", "startupTimeout", "retries", "tokenCommand", "healthCheck", "tls", "proxy", "maskKeys", "noMaskKeys", "disabled"}
+
 type Config struct {
-	MCPServers map[string]MCPServer `json:"mcpServers"`
+	Version    int                    `json:"version,omitempty"` // Config format version, see CurrentVersion
+	Include    []string               `json:"include,omitempty"` // Files whose servers are merged in, see mergeIncludes
+	MCPServers map[string]MCPServer   `json:"mcpServers"`
+	Groups     map[string][]string    `json:"groups,omitempty"` // Named server lists used with --group
+	DebugLogs  *DebugLogSettings      `json:"debugLogs,omitempty"`
+	Theme      *ThemeSettings         `json:"theme,omitempty"`
+	Claude     *ClaudeSettings        `json:"claude,omitempty"` // Claude installations to pick from with --claude-bin
+	Extra      map[string]interface{} `json:"-"`                // Top-level keys this cmcp does not know, kept as is
+
+	merged map[string]mergedServer // Servers from other files than the config file, by name
+}
+
+// DebugLogSettings limits the debug logs cmcp keeps of its claude commands
+type DebugLogSettings struct {
+	MaxFileKB  int `json:"maxFileKB,omitempty"`  // Larger logs keep only their beginning and end
+	MaxTotalKB int `json:"maxTotalKB,omitempty"` // The oldest logs are removed once all logs exceed this
 }
 
-var configPath string
+// ThemeSettings picks the colors of cmcp's output. Colors maps roles such as
+// "success" or "secret" to color specs and overrides the preset's choice.
+type ThemeSettings struct {
+	Preset string            `json:"preset,omitempty"`
+	Colors map[string]string `json:"colors,omitempty"`
+}
+
+var (
+	configPath  string
+	defaultPath string // Config file of the default profile; state files and profiles live next to it
+)
 
 func init() {
 	// Allow override via environment variable for testing
 	if envPath := os.Getenv("CMCP_CONFIG_PATH"); envPath != "" {
-		configPath = envPath
+		defaultPath = envPath
 	} else {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
 			panic(fmt.Sprintf("failed to get user home directory: %v", err))
 		}
-		configPath = filepath.Join(homeDir, ".cmcp", "config.json")
+		defaultPath = filepath.Join(homeDir, ".cmcp", "config.json")
 	}
+	configPath = ProfilePath(ActiveProfile())
 }
 
 func GetConfigPath() (string, error) {
 	return configPath, nil
 }
 
+// SetConfigPath overrides the config file location for the rest of the process.
+// The path is used as is, without profile selection.
+func SetConfigPath(path string) {
+	configPath = path
+	defaultPath = path
+}
+
+// StatePath returns the path of a cmcp state file stored next to the config
+func StatePath(name string) string {
+	return filepath.Join(filepath.Dir(defaultPath), name)
+}
+
 // UnmarshalJSON implements custom JSON unmarshaling to preserve unknown fields
 func (s *MCPServer) UnmarshalJSON(data []byte) error {
 	// First unmarshal into a map to capture all fields
@@ -67,6 +142,8 @@ func (s *MCPServer) UnmarshalJSON(data []byte) error {
 		for k, v := range env {
 			if str, ok := v.(string); ok {
 				s.Env[k] = str
+			} else if v == nil {
+				s.Env[k] = InheritEnv
 			}
 		}
 		delete(raw, "env")
@@ -77,6 +154,85 @@ func (s *MCPServer) UnmarshalJSON(data []byte) error {
 		delete(raw, "cwd")
 	}
 
+	if typ, ok := raw["type"].(string); ok {
+		s.Type = typ
+		delete(raw, "type")
+	}
+
+	if url, ok := raw["url"].(string); ok {
+		s.URL = url
+		delete(raw, "url")
+	}
+
+	if headers, ok := raw["headers"].(map[string]interface{}); ok {
+		s.Headers = make(map[string]string)
+		for k, v := range headers {
+			if str, ok := v.(string); ok {
+				s.Headers[k] = str
+			}
+		}
+		delete(raw, "headers")
+	}
+
+	if autoRestart, ok := raw["autoRestart"].(bool); ok {
+		s.AutoRestart = autoRestart
+		delete(raw, "autoRestart")
+	}
+
+	if tags, ok := raw["tags"].([]interface{}); ok {
+		s.Tags = stringList(tags)
+		delete(raw, "tags")
+	}
+
+	if timeout, ok := raw["startupTimeout"].(float64); ok {
+		s.StartupTimeout = int(timeout)
+		delete(raw, "startupTimeout")
+	}
+
+	if retries, ok := raw["retries"].(float64); ok {
+		s.Retries = int(retries)
+		delete(raw, "retries")
+	}
+
+	if alias, ok := raw["alias"].(string); ok {
+		s.Alias = alias
+		delete(raw, "alias")
+	}
+
+	if tokenCommand, ok := raw["tokenCommand"].(string); ok {
+		s.TokenCommand = tokenCommand
+		delete(raw, "tokenCommand")
+	}
+
+	if healthCheck, ok := raw["healthCheck"].(string); ok {
+		s.HealthCheck = healthCheck
+		delete(raw, "healthCheck")
+	}
+
+	if tlsRaw, ok := raw["tls"].(map[string]interface{}); ok {
+		s.TLS = &TLSConfig{}
+		s.TLS.CAFile, _ = tlsRaw["caFile"].(string)
+		s.TLS.CertFile, _ = tlsRaw["certFile"].(string)
+		s.TLS.KeyFile, _ = tlsRaw["keyFile"].(string)
+		s.TLS.InsecureSkipVerify, _ = tlsRaw["insecureSkipVerify"].(bool)
+		delete(raw, "tls")
+	}
+
+	if proxy, ok := raw["proxy"].(string); ok {
+		s.Proxy = proxy
+		delete(raw, "proxy")
+	}
+
+	if keys, ok := raw["maskKeys"].([]interface{}); ok {
+		s.MaskKeys = stringList(keys)
+		delete(raw, "maskKeys")
+	}
+
+	if keys, ok := raw["noMaskKeys"].([]interface{}); ok {
+		s.NoMaskKeys = stringList(keys)
+		delete(raw, "noMaskKeys")
+	}
+
 	// Store any remaining fields in Extra
 	if len(raw) > 0 {
 		s.Extra = raw
@@ -87,6 +243,11 @@ func (s *MCPServer) UnmarshalJSON(data []byte) error {
 
 // MarshalJSON implements custom JSON marshaling to include extra fields
 func (s MCPServer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToMap())
+}
+
+// ToMap returns the server definition as a generic map, including extra fields
+func (s MCPServer) ToMap() map[string]interface{} {
 	// Start with extra fields if any
 	result := make(map[string]interface{})
 	for k, v := range s.Extra {
@@ -94,7 +255,9 @@ func (s MCPServer) MarshalJSON() ([]byte, error) {
 	}
 
 	// Add known fields (these will override any duplicates in Extra)
-	result["command"] = s.Command
+	if s.Command != "" || s.URL == "" {
+		result["command"] = s.Command
+	}
 	if len(s.Args) > 0 {
 		result["args"] = s.Args
 	}
@@ -104,8 +267,134 @@ func (s MCPServer) MarshalJSON() ([]byte, error) {
 	if s.Cwd != "" {
 		result["cwd"] = s.Cwd
 	}
+	if s.Type != "" {
+		result["type"] = s.Type
+	}
+	if s.URL != "" {
+		result["url"] = s.URL
+	}
+	if len(s.Headers) > 0 {
+		result["headers"] = s.Headers
+	}
+	if s.AutoRestart {
+		result["autoRestart"] = true
+	}
+	if len(s.Tags) > 0 {
+		result["tags"] = s.Tags
+	}
+	if s.StartupTimeout > 0 {
+		result["startupTimeout"] = s.StartupTimeout
+	}
+	if s.Retries > 0 {
+		result["retries"] = s.Retries
+	}
+	if s.Alias != "" {
+		result["alias"] = s.Alias
+	}
+	if s.TokenCommand != "" {
+		result["tokenCommand"] = s.TokenCommand
+	}
+	if s.HealthCheck != "" {
+		result["healthCheck"] = s.HealthCheck
+	}
+	if s.TLS != nil {
+		tls := make(map[string]interface{})
+		if s.TLS.CAFile != "" {
+			tls["caFile"] = s.TLS.CAFile
+		}
+		if s.TLS.CertFile != "" {
+			tls["certFile"] = s.TLS.CertFile
+		}
+		if s.TLS.KeyFile != "" {
+			tls["keyFile"] = s.TLS.KeyFile
+		}
+		if s.TLS.InsecureSkipVerify {
+			tls["insecureSkipVerify"] = true
+		}
+		result["tls"] = tls
+	}
+	if s.Proxy != "" {
+		result["proxy"] = s.Proxy
+	}
+	if len(s.MaskKeys) > 0 {
+		result["maskKeys"] = s.MaskKeys
+	}
+	if len(s.NoMaskKeys) > 0 {
+		result["noMaskKeys"] = s.NoMaskKeys
+	}
 
-	return json.Marshal(result)
+	return result
+}
+
+// stringList keeps the strings of a decoded JSON array
+func stringList(values []interface{}) []string {
+	list := make([]string, 0, len(values))
+	for _, value := range values {
+		if str, ok := value.(string); ok {
+			list = append(list, str)
+		}
+	}
+	return list
+}
+
+// ClaudeMap returns the server definition as Claude expects it, without cmcp-only settings
+func (s MCPServer) ClaudeMap() map[string]interface{} {
+	result := s.ToMap()
+	for _, key := range cmcpOnlyFields {
+		delete(result, key)
+	}
+	return result
+}
+
+// HasAnyTag reports whether the server carries at least one of the given tags
+func (s *MCPServer) HasAnyTag(tags []string) bool {
+	for _, want := range tags {
+		for _, tag := range s.Tags {
+			if tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// StartupTimeoutDuration returns the configured startup timeout, or 0 for the default schedule
+func (s *MCPServer) StartupTimeoutDuration() time.Duration {
+	return time.Duration(s.StartupTimeout) * time.Second
+}
+
+// IsDisabled reports whether the server is kept in the config but not started. The
+// flag is stored as "disabled", the key Cline and Roo Code read when syncing.
+func (s *MCPServer) IsDisabled() bool {
+	disabled, _ := s.Extra["disabled"].(bool)
+	return disabled
+}
+
+// SetDisabled marks the server as disabled or enabled
+func (s *MCPServer) SetDisabled(disabled bool) {
+	if !disabled {
+		delete(s.Extra, "disabled")
+		return
+	}
+	if s.Extra == nil {
+		s.Extra = make(map[string]interface{})
+	}
+	s.Extra["disabled"] = true
+}
+
+// ClaudeName returns the name the server is registered under in Claude: its alias,
+// or its cmcp name. Aliases let variants such as github-work and github-personal
+// both show up to the model as github, one at a time.
+func (s *MCPServer) ClaudeName(name string) string {
+	if s.Alias != "" {
+		return s.Alias
+	}
+	return name
+}
+
+// IsRemote reports whether the server is reached over a URL rather than launched locally
+func (s *MCPServer) IsRemote() bool {
+	return s.URL != ""
 }
 
 func Load() (*Config, error) {
@@ -113,18 +402,14 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	var cfg *Config
 	data, err := os.ReadFile(configPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// Return empty config without saving - let caller decide what to do
-			cfg := &Config{MCPServers: make(map[string]MCPServer)}
-			return cfg, nil
-		}
+	if os.IsNotExist(err) {
+		// Start from an empty config without saving - let caller decide what to do
+		cfg = &Config{Version: CurrentVersion}
+	} else if err != nil {
 		return nil, err
-	}
-
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	} else if cfg, err = decodeConfig(configPath, data); err != nil {
 		return nil, err
 	}
 
@@ -132,21 +417,56 @@ func Load() (*Config, error) {
 	if cfg.MCPServers == nil {
 		cfg.MCPServers = make(map[string]MCPServer)
 	}
+	for name, server := range cfg.MCPServers {
+		server.BaseDir = filepath.Dir(configPath)
+		cfg.MCPServers[name] = server
+	}
 
-	return &cfg, nil
+	if err := cfg.mergeIncludes(configPath); err != nil {
+		return nil, err
+	}
+	// Fragments belong to the default profile; other profiles stay independent
+	if configPath == defaultPath {
+		if err := cfg.mergeFragments(FragmentsDir()); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.mergeOverlay(configPath); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
 }
 
+// TrackSave, when set, is called as Save starts and returns the function called as
+// it ends, so --timings can report config writes wherever they happen
+var TrackSave func() func()
+
+// Save writes the config file. Servers merged in from other files are only written
+// when they were changed.
 func Save(cfg *Config) error {
+	if TrackSave != nil {
+		defer TrackSave()()
+	}
 	if err := ensureConfigDir(); err != nil {
 		return err
 	}
+	own, err := cfg.ownServers()
+	if err != nil {
+		return err
+	}
+	return writeConfig(configPath, own)
+}
 
+// writeConfig writes a config file in the current format
+func writeConfig(path string, cfg *Config) error {
+	cfg.Version = CurrentVersion
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(configPath, data, 0644)
+	return os.WriteFile(path, data, 0644)
 }
 
 func ensureConfigDir() error {
@@ -170,19 +490,146 @@ func (c *Config) AddServer(name string, server MCPServer) error {
 	return Save(c)
 }
 
-func (c *Config) RemoveServer(name string) error {
+// ParseServerJSON decodes a single server definition in the shape accepted by
+// 'claude mcp add-json' and checks that it has a command or URL
+func ParseServerJSON(data []byte) (MCPServer, error) {
+	var server MCPServer
+	if err := json.Unmarshal(data, &server); err != nil {
+		return MCPServer{}, fmt.Errorf("invalid server JSON: %w", err)
+	}
+	if server.Command == "" && server.URL == "" {
+		return MCPServer{}, fmt.Errorf("server JSON must include a \"command\" or \"url\"")
+	}
+	return server, nil
+}
+
+// CanRemove reports why RemoveServer would refuse the named server: it is not
+// configured, or it comes from config.d, an include or an overlay rather than
+// the config file itself
+func (c *Config) CanRemove(name string) error {
 	if _, exists := c.MCPServers[name]; !exists {
 		return fmt.Errorf("server '%s' not found", name)
 	}
+	if merged, ok := c.merged[name]; ok && merged.overlay {
+		return fmt.Errorf("server '%s' is changed by the %s overlay; unset the environment to remove it", name, filepath.Base(merged.file))
+	} else if ok {
+		return fmt.Errorf("server '%s' is defined in %s; remove it there", name, merged.file)
+	}
+	return nil
+}
+
+func (c *Config) RemoveServer(name string) error {
+	if err := c.CanRemove(name); err != nil {
+		return err
+	}
 	delete(c.MCPServers, name)
+	c.removeFromAllGroups(name)
 	return Save(c)
 }
 
+// IsPattern reports whether a server argument contains shell-style glob characters
+func IsPattern(arg string) bool {
+	return strings.ContainsAny(arg, "*?[")
+}
+
+// MatchServers expands server names and shell-style patterns (e.g. "gh-*") against
+// the configured servers. Plain names must exist; patterns must match at least one server.
+func (c *Config) MatchServers(args []string) ([]string, error) {
+	names := c.GetServerNames()
+
+	seen := make(map[string]bool)
+	var matched []string
+	for _, arg := range args {
+		if !IsPattern(arg) {
+			if _, exists := c.MCPServers[arg]; !exists {
+				return nil, &ServerNotFoundError{Name: arg}
+			}
+			if !seen[arg] {
+				seen[arg] = true
+				matched = append(matched, arg)
+			}
+			continue
+		}
+
+		found := false
+		for _, name := range names {
+			ok, err := path.Match(arg, name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern '%s': %w", arg, err)
+			}
+			if ok {
+				found = true
+				if !seen[name] {
+					seen[name] = true
+					matched = append(matched, name)
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no servers in configuration match '%s'", arg)
+		}
+	}
+	return matched, nil
+}
+
+// FindDuplicates returns groups of server names (sorted) that share an identical
+// definition, ignoring cmcp-only settings such as tags
+func (c *Config) FindDuplicates() [][]string {
+	groups := make(map[string][]string)
+	for _, name := range c.GetServerNames() {
+		// json.Marshal sorts map keys, so equal definitions encode identically
+		data, err := json.Marshal(c.MCPServers[name].ClaudeMap())
+		if err != nil {
+			continue
+		}
+		groups[string(data)] = append(groups[string(data)], name)
+	}
+
+	var duplicates [][]string
+	for _, names := range groups {
+		if len(names) > 1 {
+			sort.Strings(names)
+			duplicates = append(duplicates, names)
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool { return duplicates[i][0] < duplicates[j][0] })
+	return duplicates
+}
+
+// FilterByTags returns the names whose servers carry at least one of the given tags.
+// With no tags, names is returned unchanged.
+func (c *Config) FilterByTags(names []string, tags []string) []string {
+	if len(tags) == 0 {
+		return names
+	}
+
+	var filtered []string
+	for _, name := range names {
+		if server, exists := c.MCPServers[name]; exists && server.HasAnyTag(tags) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered
+}
+
+// Aliases maps the names of servers with an alias to the name they use in Claude
+func (c *Config) Aliases() map[string]string {
+	aliases := make(map[string]string)
+	for name, server := range c.MCPServers {
+		if server.Alias != "" && server.Alias != name {
+			aliases[name] = server.Alias
+		}
+	}
+	return aliases
+}
+
+// GetServerNames returns the configured server names in alphabetical order
 func (c *Config) GetServerNames() []string {
 	names := make([]string, 0, len(c.MCPServers))
 	for name := range c.MCPServers {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 	return names
 }
 
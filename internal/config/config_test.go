@@ -0,0 +1,301 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testConfig(names ...string) *Config {
+	cfg := &Config{MCPServers: make(map[string]MCPServer)}
+	for _, name := range names {
+		cfg.MCPServers[name] = MCPServer{Command: "npx"}
+	}
+	return cfg
+}
+
+func TestMatchServers(t *testing.T) {
+	cfg := testConfig("gh-work", "gh-personal", "filesystem", "test-a", "test-b")
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "plain names keep order", args: []string{"filesystem", "gh-work"}, expected: []string{"filesystem", "gh-work"}},
+		{name: "glob expands sorted", args: []string{"gh-*"}, expected: []string{"gh-personal", "gh-work"}},
+		{name: "duplicates removed", args: []string{"test-a", "test-*"}, expected: []string{"test-a", "test-b"}},
+		{name: "character class", args: []string{"test-[b]"}, expected: []string{"test-b"}},
+		{name: "unknown name", args: []string{"nope"}, wantErr: true},
+		{name: "pattern without matches", args: []string{"prod-*"}, wantErr: true},
+		{name: "invalid pattern", args: []string{"[a"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cfg.MatchServers(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("MatchServers(%v) expected error", tt.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MatchServers(%v) error = %v", tt.args, err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("MatchServers(%v) = %v, want %v", tt.args, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilterByTags(t *testing.T) {
+	cfg := &Config{MCPServers: map[string]MCPServer{
+		"github":     {Command: "npx", Tags: []string{"work", "git"}},
+		"filesystem": {Command: "npx", Tags: []string{"local"}},
+		"memory":     {Command: "npx"},
+	}}
+	names := []string{"filesystem", "github", "memory"}
+
+	tests := []struct {
+		name     string
+		tags     []string
+		expected []string
+	}{
+		{name: "no tags keeps everything", tags: nil, expected: names},
+		{name: "single tag", tags: []string{"work"}, expected: []string{"github"}},
+		{name: "any of several tags", tags: []string{"local", "git"}, expected: []string{"filesystem", "github"}},
+		{name: "unknown tag", tags: []string{"prod"}, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cfg.FilterByTags(names, tt.tags)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("FilterByTags(%v) = %v, want %v", tt.tags, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTagsRoundTrip(t *testing.T) {
+	var server MCPServer
+	if err := json.Unmarshal([]byte(`{"command":"npx","tags":["work"]}`), &server); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(server.Tags, []string{"work"}) || server.Extra != nil {
+		t.Errorf("Tags = %v, Extra = %v", server.Tags, server.Extra)
+	}
+	if _, ok := server.ClaudeMap()["tags"]; ok {
+		t.Error("ClaudeMap() should not include tags")
+	}
+}
+
+func TestAlias(t *testing.T) {
+	var server MCPServer
+	if err := json.Unmarshal([]byte(`{"command":"npx","alias":"github"}`), &server); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if server.Alias != "github" || server.Extra != nil || server.ToMap()["alias"] != "github" {
+		t.Errorf("Alias = %q, Extra = %v, ToMap() = %v", server.Alias, server.Extra, server.ToMap())
+	}
+	if _, ok := server.ClaudeMap()["alias"]; ok {
+		t.Error("ClaudeMap() should not include alias")
+	}
+	if got := server.ClaudeName("github-work"); got != "github" {
+		t.Errorf("ClaudeName() = %q, want github", got)
+	}
+
+	cfg := &Config{MCPServers: map[string]MCPServer{
+		"github-work": server,
+		"fetch":       {Command: "uvx"},
+	}}
+	if got := cfg.Aliases(); !reflect.DeepEqual(got, map[string]string{"github-work": "github"}) {
+		t.Errorf("Aliases() = %v", got)
+	}
+}
+
+func TestStartupTimeoutRoundTrip(t *testing.T) {
+	var server MCPServer
+	if err := json.Unmarshal([]byte(`{"command":"docker","startupTimeout":45}`), &server); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if server.StartupTimeoutDuration() != 45*time.Second || server.Extra != nil {
+		t.Errorf("StartupTimeout = %d, Extra = %v", server.StartupTimeout, server.Extra)
+	}
+	if server.ToMap()["startupTimeout"] != 45 {
+		t.Errorf("ToMap() should keep startupTimeout, got %v", server.ToMap())
+	}
+	if _, ok := server.ClaudeMap()["startupTimeout"]; ok {
+		t.Error("ClaudeMap() should not include startupTimeout")
+	}
+
+	if err := json.Unmarshal([]byte(`{"command":"docker","retries":2}`), &server); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if server.Retries != 2 || server.Extra != nil {
+		t.Errorf("Retries = %d, Extra = %v", server.Retries, server.Extra)
+	}
+	if _, ok := server.ClaudeMap()["retries"]; ok {
+		t.Error("ClaudeMap() should not include retries")
+	}
+}
+
+func TestHeadersRoundTrip(t *testing.T) {
+	var server MCPServer
+	data := `{"type":"http","url":"https://example.com/mcp","headers":{"X-Team":"core"},"tokenCommand":"gh auth token","healthCheck":"https://example.com/healthz"}`
+	if err := json.Unmarshal([]byte(data), &server); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if server.Headers["X-Team"] != "core" || server.TokenCommand != "gh auth token" || server.HealthCheck != "https://example.com/healthz" || server.Extra != nil {
+		t.Errorf("Headers = %v, TokenCommand = %q, HealthCheck = %q, Extra = %v", server.Headers, server.TokenCommand, server.HealthCheck, server.Extra)
+	}
+	claude := server.ClaudeMap()
+	if _, ok := claude["headers"]; !ok {
+		t.Error("ClaudeMap() should include headers")
+	}
+	if _, ok := claude["tokenCommand"]; ok {
+		t.Error("ClaudeMap() should not include tokenCommand")
+	}
+	if _, ok := claude["healthCheck"]; ok {
+		t.Error("ClaudeMap() should not include healthCheck")
+	}
+}
+
+func TestTLSRoundTrip(t *testing.T) {
+	var server MCPServer
+	data := `{"url":"https://mcp.corp.example/mcp","tls":{"caFile":"/etc/corp-ca.pem","insecureSkipVerify":true}}`
+	if err := json.Unmarshal([]byte(data), &server); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	expected := &TLSConfig{CAFile: "/etc/corp-ca.pem", InsecureSkipVerify: true}
+	if !reflect.DeepEqual(server.TLS, expected) || server.Extra != nil {
+		t.Errorf("TLS = %+v, Extra = %v", server.TLS, server.Extra)
+	}
+
+	marshaled, err := json.Marshal(server)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var again MCPServer
+	if err := json.Unmarshal(marshaled, &again); err != nil || !reflect.DeepEqual(again.TLS, expected) {
+		t.Errorf("round trip TLS = %+v, %v", again.TLS, err)
+	}
+	if _, ok := server.ClaudeMap()["tls"]; ok {
+		t.Error("ClaudeMap() should not include tls")
+	}
+}
+
+func TestMaskKeysRoundTrip(t *testing.T) {
+	var server MCPServer
+	data := `{"command":"app","env":{"SESSION":"s","PORT_KEY":"8080"},"maskKeys":["SESSION"],"noMaskKeys":["PORT_KEY"]}`
+	if err := json.Unmarshal([]byte(data), &server); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(server.MaskKeys, []string{"SESSION"}) || !reflect.DeepEqual(server.NoMaskKeys, []string{"PORT_KEY"}) || server.Extra != nil {
+		t.Errorf("MaskKeys = %v, NoMaskKeys = %v, Extra = %v", server.MaskKeys, server.NoMaskKeys, server.Extra)
+	}
+
+	m := server.ToMap()
+	if _, ok := m["maskKeys"]; !ok {
+		t.Error("ToMap() should keep maskKeys")
+	}
+	claude := server.ClaudeMap()
+	if _, ok := claude["maskKeys"]; ok {
+		t.Error("ClaudeMap() should not include maskKeys")
+	}
+	if _, ok := claude["noMaskKeys"]; ok {
+		t.Error("ClaudeMap() should not include noMaskKeys")
+	}
+}
+
+func TestInheritEnvRoundTrip(t *testing.T) {
+	var server MCPServer
+	if err := json.Unmarshal([]byte(`{"command":"npx","env":{"GITHUB_TOKEN":null,"MODE":"$inherit","PORT":"80"}}`), &server); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	want := map[string]string{"GITHUB_TOKEN": InheritEnv, "MODE": InheritEnv, "PORT": "80"}
+	if !reflect.DeepEqual(server.Env, want) {
+		t.Errorf("Env = %v, want %v", server.Env, want)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	var server MCPServer
+	if err := json.Unmarshal([]byte(`{"command":"npx","disabled":true}`), &server); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !server.IsDisabled() {
+		t.Error("IsDisabled() = false, want true")
+	}
+	if _, ok := server.ClaudeMap()["disabled"]; ok {
+		t.Error("ClaudeMap() should not include disabled")
+	}
+
+	server.SetDisabled(false)
+	if server.IsDisabled() || server.ToMap()["disabled"] != nil {
+		t.Errorf("after SetDisabled(false) ToMap() = %v", server.ToMap())
+	}
+	server.SetDisabled(true)
+	if server.ToMap()["disabled"] != true {
+		t.Errorf("after SetDisabled(true) ToMap() = %v", server.ToMap())
+	}
+}
+
+func TestParseServerJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    MCPServer
+		wantErr bool
+	}{
+		{
+			name:  "stdio server",
+			input: `{"command": "npx", "args": ["-y", "server-github"], "env": {"TOKEN": "x"}}`,
+			want:  MCPServer{Command: "npx", Args: []string{"-y", "server-github"}, Env: map[string]string{"TOKEN": "x"}},
+		},
+		{
+			name:  "remote server",
+			input: `{"type": "http", "url": "https://example.com/mcp"}`,
+			want:  MCPServer{Type: "http", URL: "https://example.com/mcp"},
+		},
+		{name: "missing command and url", input: `{"args": ["x"]}`, wantErr: true},
+		{name: "not JSON", input: `command: npx`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseServerJSON([]byte(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseServerJSON() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseServerJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseServerJSON() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	cfg := &Config{MCPServers: map[string]MCPServer{
+		"github":      {Command: "npx", Args: []string{"-y", "server-github"}, Env: map[string]string{"TOKEN": "x"}},
+		"gh":          {Command: "npx", Args: []string{"-y", "server-github"}, Env: map[string]string{"TOKEN": "x"}, Tags: []string{"work"}},
+		"gh-personal": {Command: "npx", Args: []string{"-y", "server-github"}, Env: map[string]string{"TOKEN": "y"}},
+		"fetch":       {Command: "uvx", Args: []string{"mcp-server-fetch"}},
+	}}
+
+	got := cfg.FindDuplicates()
+	want := [][]string{{"gh", "github"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDuplicates() = %v, want %v", got, want)
+	}
+}
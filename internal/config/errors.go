@@ -0,0 +1,27 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrServerNotFound matches errors for servers missing from the configuration or from Claude
+var ErrServerNotFound = errors.New("server not found")
+
+// ServerNotFoundError names a server that is not configured, or not registered in
+// Claude when InClaude is set. It matches ErrServerNotFound with errors.Is.
+type ServerNotFoundError struct {
+	Name     string
+	InClaude bool
+}
+
+func (e *ServerNotFoundError) Error() string {
+	if e.InClaude {
+		return fmt.Sprintf("server '%s' is not registered in Claude", e.Name)
+	}
+	return fmt.Sprintf("server '%s' not found in configuration", e.Name)
+}
+
+func (e *ServerNotFoundError) Is(target error) bool {
+	return target == ErrServerNotFound
+}
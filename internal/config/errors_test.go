@@ -0,0 +1,23 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestServerNotFoundError(t *testing.T) {
+	cfg := &Config{MCPServers: map[string]MCPServer{"github": {Command: "npx"}}}
+	_, err := cfg.MatchServers([]string{"missing"})
+	if !errors.Is(err, ErrServerNotFound) {
+		t.Fatalf("MatchServers() error = %v, want ErrServerNotFound", err)
+	}
+
+	var notFound *ServerNotFoundError
+	if !errors.As(fmt.Errorf("wrapped: %w", err), &notFound) || notFound.Name != "missing" || notFound.InClaude {
+		t.Errorf("errors.As() = %+v, want server 'missing' not found in configuration", notFound)
+	}
+	if got, want := err.Error(), "server 'missing' not found in configuration"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
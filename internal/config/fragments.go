@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FragmentsDir returns the directory whose *.json files are merged into the default
+// profile's config, so tools can drop in server definitions without editing it
+func FragmentsDir() string {
+	return StatePath("config.d")
+}
+
+// mergedServer is a server Load took from another file than the config file
+type mergedServer struct {
+	file    string
+	server  MCPServer
+	overlay bool       // Set for servers changed or added by an environment overlay
+	base    *MCPServer // The config file's own definition under an overlay, if any
+}
+
+// MergedFrom returns the file a server was merged in from, if it is not defined in
+// the config file itself
+func (c *Config) MergedFrom(name string) (string, bool) {
+	merged, ok := c.merged[name]
+	return merged.file, ok
+}
+
+// mergeFragments adds the servers of each *.json file in dir, in file name order, to
+// the servers not defined in the config file or its includes. A server defined in
+// several fragments is taken from the last one.
+func (c *Config) mergeFragments(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") && !strings.HasPrefix(entry.Name(), ".") {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+
+	defined := make(map[string]bool, len(c.MCPServers))
+	for name := range c.MCPServers {
+		defined[name] = true
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		servers, err := ParseDesiredState(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		for name, server := range servers {
+			if defined[name] {
+				continue
+			}
+			server.BaseDir = dir
+			c.MCPServers[name] = server
+			c.addMerged(name, mergedServer{file: file, server: server})
+		}
+	}
+	return nil
+}
+
+// addMerged records where a server came from
+func (c *Config) addMerged(name string, merged mergedServer) {
+	if c.merged == nil {
+		c.merged = make(map[string]mergedServer)
+	}
+	c.merged[name] = merged
+}
+
+// ownServers returns the config as written to the config file: merged servers are left
+// out unless they were changed, in which case the config file's definition overrides
+// theirs from then on. Servers under an overlay keep the config file's definition;
+// changing them is refused so the overlay's values are not saved as the base ones.
+func (c *Config) ownServers() (*Config, error) {
+	if len(c.merged) == 0 {
+		return c, nil
+	}
+	own := *c
+	own.MCPServers = make(map[string]MCPServer, len(c.MCPServers))
+	for name, server := range c.MCPServers {
+		merged, ok := c.merged[name]
+		switch {
+		case !ok:
+			own.MCPServers[name] = server
+		case !sameDefinition(server, merged.server) && merged.overlay:
+			return nil, fmt.Errorf("server '%s' is changed by the %s overlay; unset the environment to edit it", name, filepath.Base(merged.file))
+		case !sameDefinition(server, merged.server):
+			own.MCPServers[name] = server
+		case merged.base != nil:
+			own.MCPServers[name] = *merged.base
+		}
+	}
+	return &own, nil
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFragment(t *testing.T, name, content string) string {
+	t.Helper()
+	file := filepath.Join(FragmentsDir(), name)
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoadMergesFragments(t *testing.T) {
+	useTempConfig(t)
+
+	if err := Save(&Config{MCPServers: map[string]MCPServer{"github": {Command: "gh"}}}); err != nil {
+		t.Fatal(err)
+	}
+	writeFragment(t, "10-fetch.json", `{"mcpServers": {"fetch": {"command": "uvx", "args": ["mcp-server-fetch"]}, "github": {"command": "ignored"}}}`)
+	later := writeFragment(t, "20-fetch.json", `{"mcpServers": {"fetch": {"command": "uvx", "args": ["./fetch"]}}}`)
+	writeFragment(t, "notes.txt", `not a fragment`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.MCPServers["github"].Command != "gh" {
+		t.Errorf("github = %+v, want the config file's definition", cfg.MCPServers["github"])
+	}
+	fetch := cfg.MCPServers["fetch"]
+	if len(fetch.Args) != 1 || fetch.Args[0] != "./fetch" || fetch.BaseDir != FragmentsDir() {
+		t.Errorf("fetch = %+v, want the later fragment's definition", fetch)
+	}
+	if file, ok := cfg.MergedFrom("fetch"); !ok || file != later {
+		t.Errorf("MergedFrom(fetch) = %q, %v", file, ok)
+	}
+	if err := cfg.RemoveServer("fetch"); err == nil || !strings.Contains(err.Error(), "20-fetch.json") {
+		t.Errorf("RemoveServer(fetch) error = %v, want pointing at the fragment", err)
+	}
+	if cfg.CanRemove("fetch") == nil || cfg.CanRemove("github") != nil {
+		t.Errorf("CanRemove() = %v, %v; want only the fragment server refused", cfg.CanRemove("fetch"), cfg.CanRemove("github"))
+	}
+
+	// Unchanged fragment servers are not copied into the config file
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(configPath)
+	if strings.Contains(string(data), "fetch") {
+		t.Errorf("config file holds the fragment's server:\n%s", data)
+	}
+
+	// A changed one is, and overrides the fragment from then on
+	fetch.Env = map[string]string{"LOG": "debug"}
+	cfg.MCPServers["fetch"] = fetch
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, merged := cfg.MergedFrom("fetch"); merged || cfg.MCPServers["fetch"].Env["LOG"] != "debug" {
+		t.Errorf("fetch = %+v, want the config file's changed definition", cfg.MCPServers["fetch"])
+	}
+}
+
+func TestLoadFragmentErrors(t *testing.T) {
+	useTempConfig(t)
+	file := writeFragment(t, "broken.json", `{"mcpServers": {"x": {}}}`)
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), file) {
+		t.Errorf("Load() error = %v, want one naming %s", err, file)
+	}
+}
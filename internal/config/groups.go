@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// GetGroupNames returns the defined group names in alphabetical order
+func (c *Config) GetGroupNames() []string {
+	names := make([]string, 0, len(c.Groups))
+	for name := range c.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CreateGroup defines a new group with the given members (names or patterns)
+func (c *Config) CreateGroup(name string, members []string) error {
+	if name == "" || IsPattern(name) {
+		return fmt.Errorf("invalid group name '%s'", name)
+	}
+	if _, exists := c.Groups[name]; exists {
+		return fmt.Errorf("group '%s' already exists", name)
+	}
+	if c.Groups == nil {
+		c.Groups = make(map[string][]string)
+	}
+	c.Groups[name] = []string{}
+	return c.AddToGroup(name, members)
+}
+
+// AddToGroup adds servers (names or patterns) to an existing group, skipping current members
+func (c *Config) AddToGroup(name string, members []string) error {
+	current, exists := c.Groups[name]
+	if !exists {
+		return fmt.Errorf("group '%s' not found", name)
+	}
+	names, err := c.MatchServers(members)
+	if err != nil {
+		return err
+	}
+
+	for _, server := range names {
+		if !containsString(current, server) {
+			current = append(current, server)
+		}
+	}
+	sort.Strings(current)
+	c.Groups[name] = current
+	return nil
+}
+
+// RemoveFromGroup removes servers from a group. Servers that are no longer
+// configured can still be removed by name.
+func (c *Config) RemoveFromGroup(name string, members []string) error {
+	current, exists := c.Groups[name]
+	if !exists {
+		return fmt.Errorf("group '%s' not found", name)
+	}
+
+	for _, server := range members {
+		if !containsString(current, server) {
+			return fmt.Errorf("server '%s' is not in group '%s'", server, name)
+		}
+	}
+
+	kept := []string{}
+	for _, server := range current {
+		if !containsString(members, server) {
+			kept = append(kept, server)
+		}
+	}
+	c.Groups[name] = kept
+	return nil
+}
+
+// DeleteGroup removes a group definition; its servers are left untouched
+func (c *Config) DeleteGroup(name string) error {
+	if _, exists := c.Groups[name]; !exists {
+		return fmt.Errorf("group '%s' not found", name)
+	}
+	delete(c.Groups, name)
+	return nil
+}
+
+// GroupMembers expands group names into the configured servers they contain,
+// in order and without duplicates
+func (c *Config) GroupMembers(groups []string) ([]string, error) {
+	var members []string
+	for _, group := range groups {
+		names, exists := c.Groups[group]
+		if !exists {
+			return nil, fmt.Errorf("group '%s' not found", group)
+		}
+		members = append(members, names...)
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	return c.MatchServers(members)
+}
+
+// removeFromAllGroups drops a server from every group it belongs to
+func (c *Config) removeFromAllGroups(server string) {
+	for name, members := range c.Groups {
+		kept := []string{}
+		for _, member := range members {
+			if member != server {
+				kept = append(kept, member)
+			}
+		}
+		c.Groups[name] = kept
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
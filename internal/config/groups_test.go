@@ -0,0 +1,96 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func groupTestConfig() *Config {
+	return &Config{
+		MCPServers: map[string]MCPServer{
+			"gh-personal": {Command: "gh"},
+			"gh-work":     {Command: "gh"},
+			"postgres":    {Command: "pg"},
+		},
+	}
+}
+
+func TestGroups(t *testing.T) {
+	cfg := groupTestConfig()
+
+	if err := cfg.CreateGroup("dev", []string{"postgres", "gh-*"}); err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+	if want := []string{"gh-personal", "gh-work", "postgres"}; !reflect.DeepEqual(cfg.Groups["dev"], want) {
+		t.Errorf("Groups[dev] = %v, want %v", cfg.Groups["dev"], want)
+	}
+	if err := cfg.CreateGroup("dev", nil); err == nil {
+		t.Error("expected error creating an existing group")
+	}
+	if err := cfg.CreateGroup("dev-*", nil); err == nil {
+		t.Error("expected error for a pattern as group name")
+	}
+
+	// Adding an existing member is a no-op; unknown servers are rejected
+	if err := cfg.AddToGroup("dev", []string{"postgres"}); err != nil {
+		t.Fatalf("AddToGroup() error = %v", err)
+	}
+	if len(cfg.Groups["dev"]) != 3 {
+		t.Errorf("Groups[dev] = %v, want 3 members", cfg.Groups["dev"])
+	}
+	if err := cfg.AddToGroup("dev", []string{"missing"}); err == nil {
+		t.Error("expected error adding an unknown server")
+	}
+	if err := cfg.AddToGroup("nope", []string{"postgres"}); err == nil {
+		t.Error("expected error adding to an unknown group")
+	}
+
+	if err := cfg.RemoveFromGroup("dev", []string{"gh-work"}); err != nil {
+		t.Fatalf("RemoveFromGroup() error = %v", err)
+	}
+	if want := []string{"gh-personal", "postgres"}; !reflect.DeepEqual(cfg.Groups["dev"], want) {
+		t.Errorf("Groups[dev] = %v, want %v", cfg.Groups["dev"], want)
+	}
+	if err := cfg.RemoveFromGroup("dev", []string{"gh-work"}); err == nil {
+		t.Error("expected error removing a non-member")
+	}
+
+	if err := cfg.DeleteGroup("dev"); err != nil {
+		t.Fatalf("DeleteGroup() error = %v", err)
+	}
+	if len(cfg.GetGroupNames()) != 0 {
+		t.Errorf("GetGroupNames() = %v, want none", cfg.GetGroupNames())
+	}
+}
+
+func TestGroupMembers(t *testing.T) {
+	cfg := groupTestConfig()
+	cfg.Groups = map[string][]string{
+		"github": {"gh-personal", "gh-work"},
+		"db":     {"postgres", "gh-work"},
+		"stale":  {"removed"},
+	}
+
+	got, err := cfg.GroupMembers([]string{"github", "db"})
+	if err != nil {
+		t.Fatalf("GroupMembers() error = %v", err)
+	}
+	if want := []string{"gh-personal", "gh-work", "postgres"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupMembers() = %v, want %v", got, want)
+	}
+
+	if got, err := cfg.GroupMembers(nil); err != nil || got != nil {
+		t.Errorf("GroupMembers(nil) = %v, %v, want nil, nil", got, err)
+	}
+	if _, err := cfg.GroupMembers([]string{"unknown"}); err == nil {
+		t.Error("expected error for an unknown group")
+	}
+	if _, err := cfg.GroupMembers([]string{"stale"}); err == nil {
+		t.Error("expected error for a group with an unconfigured server")
+	}
+
+	cfg.removeFromAllGroups("gh-work")
+	if want := []string{"postgres"}; !reflect.DeepEqual(cfg.Groups["db"], want) {
+		t.Errorf("Groups[db] = %v, want %v", cfg.Groups["db"], want)
+	}
+}
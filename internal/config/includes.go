@@ -0,0 +1,119 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxIncludeDepth bounds how deeply included files may include others
+const maxIncludeDepth = 16
+
+// includedFile is the part of an included file cmcp reads
+type includedFile struct {
+	Include    []string             `json:"include"`
+	MCPServers map[string]MCPServer `json:"mcpServers"`
+}
+
+// mergeIncludes adds the servers of the files the config file at path includes to
+// the servers it does not define itself. A file's own servers win over those of its
+// includes, and a later include wins over an earlier one, so team fragments can be
+// listed first and personal overrides last. Relative paths resolve against the
+// including file's directory; an include cycle is an error.
+func (c *Config) mergeIncludes(path string) error {
+	if len(c.Include) == 0 {
+		return nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	collector := includeCollector{servers: make(map[string]mergedServer)}
+	if err := collector.collect(c.Include, filepath.Dir(abs), []string{abs}); err != nil {
+		return err
+	}
+	for name, included := range collector.servers {
+		if _, own := c.MCPServers[name]; own {
+			continue
+		}
+		c.MCPServers[name] = included.server
+		c.addMerged(name, included)
+	}
+	return nil
+}
+
+// IncludedFiles returns every file the config file at path includes, directly or
+// through other included files, in the order they are read
+func IncludedFiles(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file includedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	collector := includeCollector{servers: make(map[string]mergedServer)}
+	if err := collector.collect(file.Include, filepath.Dir(abs), []string{abs}); err != nil {
+		return nil, err
+	}
+	return collector.files, nil
+}
+
+// includeCollector gathers the servers and files of a config file's includes
+type includeCollector struct {
+	servers map[string]mergedServer
+	files   []string // Each included file once, in the order read
+}
+
+// collect reads the included files in order, each after the files it includes
+// itself. chain holds the files being included, outermost first.
+func (ic *includeCollector) collect(includes []string, dir string, chain []string) error {
+	if len(chain) > maxIncludeDepth {
+		return fmt.Errorf("includes nested more than %d levels deep: %s", maxIncludeDepth, strings.Join(chain, " -> "))
+	}
+	for _, include := range includes {
+		file := expandHome(include)
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		file = filepath.Clean(file)
+		for _, including := range chain {
+			if including == file {
+				return fmt.Errorf("include cycle: %s", strings.Join(append(chain, file), " -> "))
+			}
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("%s: failed to read include: %w", chain[len(chain)-1], err)
+		}
+		var included includedFile
+		if err := json.Unmarshal(data, &included); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		if err := ic.collect(included.Include, filepath.Dir(file), append(chain[:len(chain):len(chain)], file)); err != nil {
+			return err
+		}
+		for name, server := range included.MCPServers {
+			server.BaseDir = filepath.Dir(file)
+			ic.servers[name] = mergedServer{file: file, server: server}
+		}
+		if !containsString(ic.files, file) {
+			ic.files = append(ic.files, file)
+		}
+	}
+	return nil
+}
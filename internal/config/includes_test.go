@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadMergesIncludes(t *testing.T) {
+	useTempConfig(t)
+	dir := filepath.Dir(configPath)
+
+	writeFile := func(name, content string) string {
+		t.Helper()
+		file := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(file), 0755)
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+	writeFile("config.json", `{"include": ["team/servers.json", "personal.json"], "mcpServers": {"github": {"command": "gh"}}}`)
+	team := writeFile("team/servers.json", `{"include": ["base.json"], "mcpServers": {"linear": {"command": "team-linear"}, "github": {"command": "team-gh"}}}`)
+	writeFile("team/base.json", `{"mcpServers": {"linear": {"command": "base-linear"}, "sentry": {"command": "sentry"}}}`)
+	personal := writeFile("personal.json", `{"mcpServers": {"sentry": {"command": "my-sentry"}}}`)
+	writeFragment(t, "linear.json", `{"mcpServers": {"linear": {"command": "fragment-linear"}}}`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]string{"github": "gh", "linear": "team-linear", "sentry": "my-sentry"}
+	for name, command := range want {
+		if got := cfg.MCPServers[name].Command; got != command {
+			t.Errorf("%s command = %q, want %q", name, got, command)
+		}
+	}
+	if file, _ := cfg.MergedFrom("linear"); file != team {
+		t.Errorf("MergedFrom(linear) = %q, want %q", file, team)
+	}
+	if file, _ := cfg.MergedFrom("sentry"); file != personal {
+		t.Errorf("MergedFrom(sentry) = %q, want %q", file, personal)
+	}
+	if cfg.MCPServers["linear"].BaseDir != filepath.Dir(team) {
+		t.Errorf("linear BaseDir = %q, want the including file's directory", cfg.MCPServers["linear"].BaseDir)
+	}
+
+	// Saving keeps the include list and leaves included servers where they are
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(configPath)
+	if !strings.Contains(string(data), `"include"`) || strings.Contains(string(data), "sentry") {
+		t.Errorf("saved config:\n%s", data)
+	}
+}
+
+func TestLoadIncludeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				"config.json": `{"include": ["a.json"], "mcpServers": {}}`,
+				"a.json":      `{"include": ["b.json"]}`,
+				"b.json":      `{"include": ["./a.json"]}`,
+			},
+			wantErr: "include cycle",
+		},
+		{
+			name:    "self",
+			files:   map[string]string{"config.json": `{"include": ["config.json"], "mcpServers": {}}`},
+			wantErr: "include cycle",
+		},
+		{
+			name:    "missing",
+			files:   map[string]string{"config.json": `{"include": ["gone.json"], "mcpServers": {}}`},
+			wantErr: "failed to read include",
+		},
+		{
+			name: "invalid",
+			files: map[string]string{
+				"config.json": `{"include": ["bad.json"], "mcpServers": {}}`,
+				"bad.json":    `{`,
+			},
+			wantErr: "bad.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempConfig(t)
+			for name, content := range tt.files {
+				os.WriteFile(filepath.Join(filepath.Dir(configPath), name), []byte(content), 0644)
+			}
+			if _, err := Load(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LintIssue is a problem found in a server definition
+type LintIssue struct {
+	Server  string
+	Error   bool // Claude will reject or fail to launch the server; otherwise a warning
+	Message string
+	Fixes   []string // Suggested commands that fix the problem
+}
+
+var (
+	// validServerName is what claude mcp add accepts as a server name
+	validServerName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	// invalidNameChars matches the characters replaced when suggesting a valid name
+	invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+	// shellReference matches ${VAR} and ${VAR:-default}, which Claude expands
+	shellReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-[^}]*)?\}`)
+	// headerEnvReference matches cmcp's ${env:VAR} header references
+	headerEnvReference = regexp.MustCompile(`\$\{env:([A-Za-z_][A-Za-z0-9_]*)\}`)
+	// bareReference matches $VAR, which Claude passes on literally
+	bareReference = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// Lint checks the named servers, or every server when names is empty, for common
+// configuration mistakes. Issues are sorted by server name.
+func (c *Config) Lint(names []string) []LintIssue {
+	if len(names) == 0 {
+		names = c.GetServerNames()
+	}
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+
+	var issues []LintIssue
+	for _, name := range sorted {
+		server, exists := c.FindServer(name)
+		if !exists {
+			continue
+		}
+		issues = append(issues, lintServer(name, server, os.LookupEnv)...)
+	}
+	return issues
+}
+
+func lintServer(name string, s *MCPServer, lookupEnv func(string) (string, bool)) []LintIssue {
+	var issues []LintIssue
+	add := func(isError bool, message string, fixes ...string) {
+		issues = append(issues, LintIssue{Server: name, Error: isError, Message: message, Fixes: fixes})
+	}
+
+	if !validServerName.MatchString(name) {
+		suggested := strings.Trim(invalidNameChars.ReplaceAllString(name, "-"), "-")
+		if suggested == "" {
+			suggested = "server"
+		}
+		add(true, "Claude only accepts server names made of letters, numbers, '-' and '_'",
+			fmt.Sprintf("cmcp config get '%s' --reveal | cmcp config add %s --json - && cmcp config rm '%s'", name, suggested, name))
+	}
+
+	if s.Alias != "" && !validServerName.MatchString(s.Alias) {
+		add(true, fmt.Sprintf("alias '%s' is not a name Claude accepts (letters, numbers, '-' and '_')", s.Alias),
+			fmt.Sprintf("cmcp config set %s.alias %s", name, strings.Trim(invalidNameChars.ReplaceAllString(s.Alias, "-"), "-")))
+	}
+
+	if !s.IsRemote() {
+		command := strings.TrimSpace(s.Command)
+		switch {
+		case command == "":
+			add(true, "no command or url is configured",
+				fmt.Sprintf("cmcp config set %s.command <command>", name))
+		case strings.ContainsAny(command, " \t") && !fileExists(command):
+			// "npx -y server" in the command field makes Claude look for a program with that name
+			fields := strings.Fields(command)
+			args, _ := json.Marshal(append(fields[1:], s.Args...))
+			add(true, fmt.Sprintf("command '%s' includes arguments; Claude runs it as a single program name", command),
+				fmt.Sprintf("cmcp config set %s.command %s", name, fields[0]),
+				fmt.Sprintf("cmcp config set %s.args '%s'", name, args))
+		}
+	}
+
+	// A cwd with {{projectDir}} style placeholders only exists once cmcp expands it at start
+	if s.Cwd != "" && !strings.Contains(s.Cwd, "{{") {
+		cwd := s.Cwd
+		if !filepath.IsAbs(cwd) && s.BaseDir != "" {
+			cwd = filepath.Join(s.BaseDir, cwd)
+		}
+		if info, err := os.Stat(cwd); err != nil {
+			add(true, fmt.Sprintf("cwd '%s' does not exist", s.Cwd),
+				fmt.Sprintf("cmcp config set %s.cwd <existing directory>", name))
+		} else if !info.IsDir() {
+			add(true, fmt.Sprintf("cwd '%s' is not a directory", s.Cwd),
+				fmt.Sprintf("cmcp config set %s.cwd <existing directory>", name))
+		}
+	}
+
+	for _, key := range sortedKeys(s.Env) {
+		value := s.Env[key]
+		if value == InheritEnv {
+			if _, set := lookupEnv(key); !set {
+				add(false, fmt.Sprintf("env %s is inherited from the shell, but it is not set in this shell", key),
+					fmt.Sprintf("export %s=... before starting", key))
+			}
+			continue
+		}
+		for _, variable := range undefinedReferences(shellReference, value, lookupEnv) {
+			add(false, fmt.Sprintf("env %s references ${%s}, which is not set in this shell", key, variable),
+				fmt.Sprintf("export %s=... before starting, or use ${%s:-default}", variable, variable))
+		}
+		// Drop ${...} references before looking for bare $VAR ones
+		for _, match := range bareReference.FindAllStringSubmatch(shellReference.ReplaceAllString(value, ""), -1) {
+			add(false, fmt.Sprintf("env %s contains $%s, which Claude passes on literally", key, match[1]),
+				fmt.Sprintf("cmcp config set %s.env.%s '%s'", name, key, strings.ReplaceAll(value, "$"+match[1], "${"+match[1]+"}")))
+		}
+	}
+
+	for _, arg := range s.Args {
+		for _, variable := range undefinedReferences(shellReference, arg, lookupEnv) {
+			add(false, fmt.Sprintf("argument '%s' references ${%s}, which is not set in this shell", arg, variable),
+				fmt.Sprintf("export %s=... before starting", variable))
+		}
+	}
+
+	for _, key := range sortedKeys(s.Headers) {
+		for _, variable := range undefinedReferences(headerEnvReference, s.Headers[key], lookupEnv) {
+			add(false, fmt.Sprintf("header %s references ${env:%s}, which is not set in this shell", key, variable),
+				fmt.Sprintf("export %s=... before starting", variable))
+		}
+	}
+
+	return issues
+}
+
+// undefinedReferences returns the variables referenced in value that are not set and
+// have no default
+func undefinedReferences(pattern *regexp.Regexp, value string, lookupEnv func(string) (string, bool)) []string {
+	var undefined []string
+	for _, match := range pattern.FindAllStringSubmatch(value, -1) {
+		hasDefault := len(match) > 2 && match[2] != ""
+		if _, set := lookupEnv(match[1]); !set && !hasDefault {
+			undefined = append(undefined, match[1])
+		}
+	}
+	return undefined
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLintServer(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"HOME": "/home/me", "GITHUB_TOKEN": "x"}
+	lookupEnv := func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+
+	tests := []struct {
+		name     string
+		server   MCPServer
+		errors   []string // Substrings of the expected error messages, in order
+		warnings []string
+	}{
+		{
+			name:   "valid",
+			server: MCPServer{Command: "npx", Args: []string{"-y", "server"}, Cwd: dir, Env: map[string]string{"TOKEN": "${GITHUB_TOKEN}", "MODE": "${MODE:-fast}"}},
+		},
+		{
+			name:   "remote server needs no command",
+			server: MCPServer{Type: "http", URL: "https://example.com/mcp", Headers: map[string]string{"Authorization": "Bearer ${token}"}},
+		},
+		{name: "empty command", server: MCPServer{}, errors: []string{"no command"}},
+		{name: "invalid alias", server: MCPServer{Command: "npx", Alias: "git hub"}, errors: []string{"alias 'git hub'"}},
+		{name: "args in command", server: MCPServer{Command: "npx -y server"}, errors: []string{"includes arguments"}},
+		{name: "missing cwd", server: MCPServer{Command: "npx", Cwd: filepath.Join(dir, "missing")}, errors: []string{"does not exist"}},
+		{name: "cwd is a file", server: MCPServer{Command: "npx", Cwd: file}, errors: []string{"not a directory"}},
+		{name: "cwd placeholder", server: MCPServer{Command: "npx", Cwd: "{{gitRoot}}/web"}},
+		{
+			name:     "undefined references",
+			server:   MCPServer{Command: "npx", Args: []string{"--db=${DB_URL}"}, Env: map[string]string{"KEY": "${API_KEY}"}},
+			warnings: []string{"${API_KEY}", "${DB_URL}"},
+		},
+		{
+			name:     "inherited env",
+			server:   MCPServer{Command: "npx", Env: map[string]string{"GITHUB_TOKEN": InheritEnv, "ACME_KEY": InheritEnv}},
+			warnings: []string{"ACME_KEY is inherited"},
+		},
+		{name: "bare variable", server: MCPServer{Command: "npx", Env: map[string]string{"DATA": "$HOME/data"}}, warnings: []string{"$HOME"}},
+		{
+			name:     "header env reference",
+			server:   MCPServer{URL: "https://example.com/mcp", Headers: map[string]string{"X-Key": "${env:ACME_KEY}", "X-Token": "${env:GITHUB_TOKEN}"}},
+			warnings: []string{"${env:ACME_KEY}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errors, warnings []string
+			for _, issue := range lintServer("github", &tt.server, lookupEnv) {
+				if len(issue.Fixes) == 0 {
+					t.Errorf("issue %q has no suggested fix", issue.Message)
+				}
+				if issue.Error {
+					errors = append(errors, issue.Message)
+				} else {
+					warnings = append(warnings, issue.Message)
+				}
+			}
+			checkMessages(t, "errors", errors, tt.errors)
+			checkMessages(t, "warnings", warnings, tt.warnings)
+		})
+	}
+}
+
+func checkMessages(t *testing.T, kind string, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s = %v, want %d matching %v", kind, got, len(want), want)
+		return
+	}
+	for i := range want {
+		if !strings.Contains(got[i], want[i]) {
+			t.Errorf("%s[%d] = %q, want it to mention %q", kind, i, got[i], want[i])
+		}
+	}
+}
+
+func TestLintFixes(t *testing.T) {
+	issues := lintServer("my.server", &MCPServer{Command: "uvx  mcp-server-fetch", Args: []string{"--port", "80"}}, func(string) (string, bool) { return "", false })
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want a name and a command issue: %+v", len(issues), issues)
+	}
+	if !strings.Contains(issues[0].Fixes[0], "cmcp config add my-server") {
+		t.Errorf("name fix = %q, want a valid name suggested", issues[0].Fixes[0])
+	}
+	want := []string{
+		"cmcp config set my.server.command uvx",
+		`cmcp config set my.server.args '["mcp-server-fetch","--port","80"]'`,
+	}
+	if !reflect.DeepEqual(issues[1].Fixes, want) {
+		t.Errorf("command fixes = %v, want %v", issues[1].Fixes, want)
+	}
+}
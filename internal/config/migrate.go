@@ -0,0 +1,153 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CurrentVersion is the config format version this cmcp reads and writes
+const CurrentVersion = 1
+
+// migration upgrades a decoded config file by one version
+type migration struct {
+	description string
+	apply       func(raw map[string]interface{}) error
+}
+
+// migrations[i] upgrades a version i file to version i+1. Add new entries at the
+// end and bump CurrentVersion; never change a released migration.
+var migrations = []migration{
+	{"store comma-separated tags as lists", migrateTagStrings},
+}
+
+// knownConfigKeys are the top-level keys Config decodes; anything else is kept in Extra
+var knownConfigKeys = map[string]bool{"version": true, "include": true, "mcpServers": true, "groups": true, "debugLogs": true, "theme": true, "claude": true}
+
+// configAlias has Config's fields without its JSON methods
+type configAlias Config
+
+// UnmarshalJSON decodes the known fields and keeps unknown top-level keys, so a
+// structure cmcp does not understand survives being loaded and saved again
+func (c *Config) UnmarshalJSON(data []byte) error {
+	var alias configAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	alias.Extra = nil
+	for key, value := range raw {
+		if !knownConfigKeys[key] {
+			if alias.Extra == nil {
+				alias.Extra = make(map[string]interface{})
+			}
+			alias.Extra[key] = value
+		}
+	}
+	*c = Config(alias)
+	return nil
+}
+
+// MarshalJSON writes the known fields followed by any unknown top-level keys
+func (c Config) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(configAlias(c))
+	if err != nil || len(c.Extra) == 0 {
+		return data, err
+	}
+	var merged map[string]interface{}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	for key, value := range c.Extra {
+		if _, exists := merged[key]; !exists {
+			merged[key] = value
+		}
+	}
+	return json.Marshal(merged)
+}
+
+// migrate upgrades a decoded config file to CurrentVersion in place and returns the
+// version it started at. Files from a newer cmcp are refused rather than loaded with
+// their new structures dropped.
+func migrate(raw map[string]interface{}) (int, error) {
+	version := 0
+	if v, ok := raw["version"]; ok {
+		number, ok := v.(float64)
+		if !ok || number < 0 || number != float64(int(number)) {
+			return 0, fmt.Errorf("invalid config version %v", v)
+		}
+		version = int(number)
+	}
+	if version > CurrentVersion {
+		return version, fmt.Errorf("config format version %d is newer than this cmcp supports (%d); upgrade cmcp to use this file", version, CurrentVersion)
+	}
+
+	for v := version; v < CurrentVersion; v++ {
+		if err := migrations[v].apply(raw); err != nil {
+			return version, fmt.Errorf("failed to migrate config from version %d (%s): %w", v, migrations[v].description, err)
+		}
+	}
+	raw["version"] = CurrentVersion
+	return version, nil
+}
+
+// decodeConfig decodes a config file, upgrading it to CurrentVersion first. When the
+// file was upgraded, the original is backed up next to it and the new one saved.
+func decodeConfig(path string, data []byte) (*Config, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	from, err := migrate(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	upgraded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(upgraded, &cfg); err != nil {
+		return nil, err
+	}
+	if from == CurrentVersion {
+		return &cfg, nil
+	}
+
+	if err := os.WriteFile(fmt.Sprintf("%s.v%d.bak", path, from), data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to back up config before migrating it: %w", err)
+	}
+	if err := writeConfig(path, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to write migrated config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// migrateTagStrings turns "tags": "work, git" into "tags": ["work", "git"]; a string
+// would otherwise be ignored and the tags lost on the next save
+func migrateTagStrings(raw map[string]interface{}) error {
+	servers, _ := raw["mcpServers"].(map[string]interface{})
+	for _, value := range servers {
+		server, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		tags, ok := server["tags"].(string)
+		if !ok {
+			continue
+		}
+		var list []interface{}
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				list = append(list, tag)
+			}
+		}
+		server["tags"] = list
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantFrom int
+		wantErr  string
+		wantTags []string
+	}{
+		{name: "unversioned file", input: `{"mcpServers": {"gh": {"command": "npx", "tags": "work, git,"}}}`, wantFrom: 0, wantTags: []string{"work", "git"}},
+		{name: "current version", input: `{"version": 1, "mcpServers": {"gh": {"command": "npx", "tags": ["work"]}}}`, wantFrom: 1, wantTags: []string{"work"}},
+		{name: "newer version", input: `{"version": 99, "mcpServers": {}}`, wantErr: "newer than this cmcp supports"},
+		{name: "invalid version", input: `{"version": "two", "mcpServers": {}}`, wantErr: "invalid config version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.input), &raw); err != nil {
+				t.Fatal(err)
+			}
+			from, err := migrate(raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("migrate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || from != tt.wantFrom {
+				t.Fatalf("migrate() = %d, %v, want %d", from, err, tt.wantFrom)
+			}
+			if raw["version"] != CurrentVersion {
+				t.Errorf("version = %v, want %d", raw["version"], CurrentVersion)
+			}
+
+			data, _ := json.Marshal(raw)
+			var cfg Config
+			if err := json.Unmarshal(data, &cfg); err != nil {
+				t.Fatal(err)
+			}
+			if tags := cfg.MCPServers["gh"].Tags; !reflect.DeepEqual(tags, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", tags, tt.wantTags)
+			}
+		})
+	}
+
+	if len(migrations) != CurrentVersion {
+		t.Errorf("%d migrations for version %d; every version needs one", len(migrations), CurrentVersion)
+	}
+}
+
+func TestUnknownTopLevelKeysSurvive(t *testing.T) {
+	input := `{"version": 1, "mcpServers": {"gh": {"command": "npx"}}, "transports": {"gh": "stdio"}}`
+	var cfg Config
+	if err := json.Unmarshal([]byte(input), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Extra["transports"]; !ok || len(cfg.Extra) != 1 {
+		t.Fatalf("Extra = %v, want only transports", cfg.Extra)
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again map[string]interface{}
+	json.Unmarshal(data, &again)
+	if !reflect.DeepEqual(again["transports"], map[string]interface{}{"gh": "stdio"}) || again["mcpServers"] == nil {
+		t.Errorf("round trip = %s", data)
+	}
+}
+
+func TestDecodeConfigBacksUpMigratedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	old := []byte(`{"mcpServers": {"gh": {"command": "npx", "tags": "work"}}}`)
+	if err := os.WriteFile(path, old, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := decodeConfig(path, old)
+	if err != nil {
+		t.Fatalf("decodeConfig() error = %v", err)
+	}
+	if cfg.Version != CurrentVersion || !reflect.DeepEqual(cfg.MCPServers["gh"].Tags, []string{"work"}) {
+		t.Errorf("decoded config = %+v", cfg)
+	}
+	if backup, err := os.ReadFile(path + ".v0.bak"); err != nil || string(backup) != string(old) {
+		t.Errorf("backup = %q, %v", backup, err)
+	}
+	saved, _ := os.ReadFile(path)
+	if !strings.Contains(string(saved), `"version": 1`) {
+		t.Errorf("migrated file was not saved: %s", saved)
+	}
+
+	// Loading the upgraded file again changes nothing
+	os.Remove(path + ".v0.bak")
+	if _, err := decodeConfig(path, saved); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".v0.bak"); err == nil {
+		t.Error("a current file should not be backed up again")
+	}
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// environment selects the config.<environment>.json overlay, from CMCP_ENV by default
+var environment = os.Getenv("CMCP_ENV")
+
+// SetEnvironment selects the overlay merged over the config for the rest of the
+// process; an empty name turns overlays off
+func SetEnvironment(name string) error {
+	if name != "" && !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid environment name '%s': use letters, digits, '.', '_' and '-'", name)
+	}
+	environment = name
+	return nil
+}
+
+// Environment returns the selected environment, or "" when no overlay is used
+func Environment() string {
+	return environment
+}
+
+// OverlayPath returns the overlay of a config file for an environment, e.g.
+// config.staging.json next to config.json
+func OverlayPath(path, env string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + env + ext
+}
+
+// mergeOverlay merges the overlay of the config file at path for the selected
+// environment over the loaded servers. Overlay entries may be partial: env, headers
+// and other objects are merged key by key and any other field replaces the base
+// value. Servers only in the overlay are added. A selected environment without an
+// overlay file is an error, so a mistyped name does not go unnoticed.
+func (c *Config) mergeOverlay(path string) error {
+	if environment == "" {
+		return nil
+	}
+	file := OverlayPath(path, environment)
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("environment '%s' has no overlay; create %s or unset CMCP_ENV", environment, file)
+	}
+	if err != nil {
+		return err
+	}
+
+	var overlay struct {
+		MCPServers map[string]map[string]interface{} `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &overlay); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	for name, fields := range overlay.MCPServers {
+		base, exists := c.MCPServers[name]
+		merged := fields
+		if exists {
+			// Round-trip through JSON so nested settings like tls are generic maps too
+			var baseFields map[string]interface{}
+			baseData, err := json.Marshal(base)
+			if err == nil {
+				err = json.Unmarshal(baseData, &baseFields)
+			}
+			if err != nil {
+				return fmt.Errorf("server '%s': %w", name, err)
+			}
+			merged = mergeMaps(baseFields, fields)
+		}
+		encoded, err := json.Marshal(merged)
+		if err != nil {
+			return fmt.Errorf("%s: server '%s': %w", file, name, err)
+		}
+		server, err := ParseServerJSON(encoded)
+		if err != nil {
+			return fmt.Errorf("%s: server '%s': %w", file, name, err)
+		}
+		server.BaseDir = filepath.Dir(file)
+		if exists {
+			server.BaseDir = base.BaseDir
+		}
+
+		overlaid := mergedServer{file: file, server: server, overlay: true}
+		if _, mergedIn := c.merged[name]; exists && !mergedIn {
+			overlaid.base = &base
+		}
+		c.MCPServers[name] = server
+		c.addMerged(name, overlaid)
+	}
+	return nil
+}
+
+// mergeMaps returns base with the values of overlay set over it, merging nested objects
+func mergeMaps(base, overlay map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overlay))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overlay {
+		nested, isMap := value.(map[string]interface{})
+		if current, ok := merged[key].(map[string]interface{}); ok && isMap {
+			merged[key] = mergeMaps(current, nested)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
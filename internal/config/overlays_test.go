@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// useEnvironment selects an overlay for the test
+func useEnvironment(t *testing.T, name string) {
+	t.Helper()
+	old := environment
+	if err := SetEnvironment(name); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { environment = old })
+}
+
+func TestOverlayPath(t *testing.T) {
+	if got := OverlayPath("/home/me/.cmcp/config.json", "staging"); got != "/home/me/.cmcp/config.staging.json" {
+		t.Errorf("OverlayPath() = %q", got)
+	}
+	if err := SetEnvironment("../prod"); err == nil {
+		t.Error("SetEnvironment() should reject names with path separators")
+	}
+}
+
+func TestLoadMergesOverlay(t *testing.T) {
+	useTempConfig(t)
+	base := `{"mcpServers": {
+		"github": {"command": "gh", "env": {"GITHUB_TOKEN": "base-token", "LOG": "info"}},
+		"remote": {"type": "http", "url": "https://prod.example.com/mcp", "headers": {"Authorization": "Bearer ${env:PROD}"}},
+		"fetch": {"command": "uvx"}
+	}}`
+	if err := os.WriteFile(configPath, []byte(base), 0644); err != nil {
+		t.Fatal(err)
+	}
+	overlay := `{"mcpServers": {
+		"github": {"env": {"GITHUB_TOKEN": "staging-token"}},
+		"remote": {"url": "https://staging.example.com/mcp"},
+		"debug": {"command": "debug-server"}
+	}}`
+	if err := os.WriteFile(OverlayPath(configPath, "staging"), []byte(overlay), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without an environment the overlay is ignored
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MCPServers["github"].Env["GITHUB_TOKEN"] != "base-token" || len(cfg.MCPServers) != 3 {
+		t.Fatalf("servers without an environment = %+v", cfg.MCPServers)
+	}
+
+	useEnvironment(t, "staging")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := map[string]string{"GITHUB_TOKEN": "staging-token", "LOG": "info"}; !reflect.DeepEqual(cfg.MCPServers["github"].Env, want) {
+		t.Errorf("github env = %v, want %v", cfg.MCPServers["github"].Env, want)
+	}
+	remote := cfg.MCPServers["remote"]
+	if remote.URL != "https://staging.example.com/mcp" || remote.Type != "http" || remote.Headers["Authorization"] == "" {
+		t.Errorf("remote = %+v", remote)
+	}
+	if cfg.MCPServers["debug"].Command != "debug-server" {
+		t.Errorf("debug = %+v, want the overlay-only server", cfg.MCPServers["debug"])
+	}
+
+	// Saving writes the base definitions back, never the overlay's values
+	fetch := cfg.MCPServers["fetch"]
+	fetch.Args = []string{"mcp-server-fetch"}
+	cfg.MCPServers["fetch"] = fetch
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	data, _ := os.ReadFile(configPath)
+	if strings.Contains(string(data), "staging") || !strings.Contains(string(data), "base-token") || !strings.Contains(string(data), "mcp-server-fetch") {
+		t.Errorf("saved config:\n%s", data)
+	}
+
+	github := cfg.MCPServers["github"]
+	github.Args = []string{"--verbose"}
+	cfg.MCPServers["github"] = github
+	if err := Save(cfg); err == nil || !strings.Contains(err.Error(), "config.staging.json") {
+		t.Errorf("Save() error = %v, want changing an overlaid server refused", err)
+	}
+	if err := cfg.RemoveServer("debug"); err == nil {
+		t.Error("RemoveServer() should refuse servers from the overlay")
+	}
+}
+
+func TestLoadMissingOverlay(t *testing.T) {
+	useTempConfig(t)
+	useEnvironment(t, "prod")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), filepath.Base(OverlayPath(configPath, "prod"))) {
+		t.Errorf("Load() error = %v, want the missing overlay named", err)
+	}
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile is the profile stored in the main config file
+const DefaultProfile = "default"
+
+// ActiveProfileFile is the state file holding the selected profile name
+const ActiveProfileFile = "profile"
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ProfilesDir returns the directory holding non-default profile configs
+func ProfilesDir() string {
+	return filepath.Join(filepath.Dir(defaultPath), "profiles")
+}
+
+// ProfilePath returns the config file of a profile
+func ProfilePath(name string) string {
+	if name == DefaultProfile {
+		return defaultPath
+	}
+	return filepath.Join(ProfilesDir(), name+".json")
+}
+
+// ActiveProfile returns the selected profile, falling back to the default
+// profile when none is selected or the selected one no longer exists
+func ActiveProfile() string {
+	data, err := os.ReadFile(StatePath(ActiveProfileFile))
+	if err != nil {
+		return DefaultProfile
+	}
+	name := strings.TrimSpace(string(data))
+	if !ProfileExists(name) {
+		return DefaultProfile
+	}
+	return name
+}
+
+// ProfileExists reports whether a profile has been created
+func ProfileExists(name string) bool {
+	if name == DefaultProfile {
+		return true
+	}
+	if !profileNamePattern.MatchString(name) {
+		return false
+	}
+	_, err := os.Stat(ProfilePath(name))
+	return err == nil
+}
+
+// ListProfiles returns the default profile followed by the other profiles in
+// alphabetical order
+func ListProfiles() ([]string, error) {
+	entries, err := os.ReadDir(ProfilesDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if entry.IsDir() || name == entry.Name() || name == DefaultProfile || !profileNamePattern.MatchString(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...), nil
+}
+
+// CreateProfile creates a profile with an empty server list
+func CreateProfile(name string) error {
+	return writeProfile(name, []byte("{\n  \"mcpServers\": {}\n}"))
+}
+
+// CopyProfile creates a profile holding a copy of another profile's config
+func CopyProfile(source, name string) error {
+	if !ProfileExists(source) {
+		return fmt.Errorf("profile '%s' not found", source)
+	}
+	data, err := os.ReadFile(ProfilePath(source))
+	if os.IsNotExist(err) {
+		// The default profile has no file until something is saved
+		return CreateProfile(name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read profile '%s': %w", source, err)
+	}
+	return writeProfile(name, data)
+}
+
+// writeProfile stores the config data for a new profile
+func writeProfile(name string, data []byte) error {
+	if name == DefaultProfile || !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name '%s'", name)
+	}
+	if ProfileExists(name) {
+		return fmt.Errorf("profile '%s' already exists", name)
+	}
+	if err := os.MkdirAll(ProfilesDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create profiles directory: %w", err)
+	}
+	return os.WriteFile(ProfilePath(name), data, 0644)
+}
+
+// SwitchProfile selects the profile used by later commands and by the rest of this process
+func SwitchProfile(name string) error {
+	if !ProfileExists(name) {
+		return fmt.Errorf("profile '%s' not found", name)
+	}
+	if err := os.MkdirAll(filepath.Dir(defaultPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(StatePath(ActiveProfileFile), []byte(name+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to save active profile: %w", err)
+	}
+	configPath = ProfilePath(name)
+	return nil
+}
+
+// DeleteProfile removes a profile's config. The default and active profiles cannot be deleted.
+func DeleteProfile(name string) error {
+	if name == DefaultProfile {
+		return fmt.Errorf("the default profile cannot be deleted")
+	}
+	if !ProfileExists(name) {
+		return fmt.Errorf("profile '%s' not found", name)
+	}
+	if name == ActiveProfile() {
+		return fmt.Errorf("profile '%s' is active; switch to another profile first", name)
+	}
+	return os.Remove(ProfilePath(name))
+}
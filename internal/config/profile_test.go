@@ -0,0 +1,88 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// useTempConfig points the package at a config file in a temporary directory
+func useTempConfig(t *testing.T) {
+	t.Helper()
+	oldConfig, oldDefault := configPath, defaultPath
+	SetConfigPath(filepath.Join(t.TempDir(), "config.json"))
+	t.Cleanup(func() {
+		configPath, defaultPath = oldConfig, oldDefault
+	})
+}
+
+func TestProfiles(t *testing.T) {
+	useTempConfig(t)
+
+	cfg := &Config{MCPServers: map[string]MCPServer{"github": {Command: "gh"}}}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if err := CreateProfile("work"); err != nil {
+		t.Fatalf("CreateProfile() error = %v", err)
+	}
+	if err := CopyProfile(DefaultProfile, "home"); err != nil {
+		t.Fatalf("CopyProfile() error = %v", err)
+	}
+	for _, name := range []string{"work", DefaultProfile, "../escape", ""} {
+		if err := CreateProfile(name); err == nil {
+			t.Errorf("CreateProfile(%q) expected error", name)
+		}
+	}
+
+	profiles, err := ListProfiles()
+	if err != nil {
+		t.Fatalf("ListProfiles() error = %v", err)
+	}
+	if want := []string{DefaultProfile, "home", "work"}; !reflect.DeepEqual(profiles, want) {
+		t.Errorf("ListProfiles() = %v, want %v", profiles, want)
+	}
+
+	// Switching changes which config Load reads
+	if err := SwitchProfile("work"); err != nil {
+		t.Fatalf("SwitchProfile() error = %v", err)
+	}
+	if ActiveProfile() != "work" {
+		t.Errorf("ActiveProfile() = %q, want work", ActiveProfile())
+	}
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded.MCPServers) != 0 {
+		t.Errorf("work profile servers = %v, want none", loaded.GetServerNames())
+	}
+
+	if err := SwitchProfile("home"); err != nil {
+		t.Fatalf("SwitchProfile() error = %v", err)
+	}
+	loaded, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if _, ok := loaded.MCPServers["github"]; !ok {
+		t.Errorf("copied profile servers = %v, want github", loaded.GetServerNames())
+	}
+
+	if err := DeleteProfile("home"); err == nil {
+		t.Error("expected error deleting the active profile")
+	}
+	if err := DeleteProfile(DefaultProfile); err == nil {
+		t.Error("expected error deleting the default profile")
+	}
+	if err := DeleteProfile("work"); err != nil {
+		t.Fatalf("DeleteProfile() error = %v", err)
+	}
+	if ProfileExists("work") {
+		t.Error("work profile still exists after delete")
+	}
+	if err := SwitchProfile("work"); err == nil {
+		t.Error("expected error switching to a deleted profile")
+	}
+}
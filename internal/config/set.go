@@ -0,0 +1,241 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is one step of a dotted config path: a map key or a list index
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parsePath splits "github.env.GITHUB_TOKEN" or "fs.args[1]" into the server
+// name and the segments below it
+func parsePath(path string) (string, []pathSegment, error) {
+	var segments []pathSegment
+	var name string
+
+	rest := path
+	for i := 0; rest != ""; i++ {
+		if strings.HasPrefix(rest, "[") {
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return "", nil, fmt.Errorf("invalid path '%s': missing ']'", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return "", nil, fmt.Errorf("invalid path '%s': bad index '%s'", path, rest[1:end])
+			}
+			segments = append(segments, pathSegment{index: index, isIndex: true})
+			rest = strings.TrimPrefix(rest[end+1:], ".")
+			continue
+		}
+
+		end := strings.IndexAny(rest, ".[")
+		key := rest
+		if end == -1 {
+			rest = ""
+		} else {
+			key = rest[:end]
+			rest = strings.TrimPrefix(rest[end:], ".")
+		}
+		if key == "" {
+			return "", nil, fmt.Errorf("invalid path '%s': empty segment", path)
+		}
+		if i == 0 {
+			name = key
+		} else {
+			segments = append(segments, pathSegment{key: key})
+		}
+	}
+
+	if name == "" || len(segments) == 0 {
+		return "", nil, fmt.Errorf("invalid path '%s': expected <server>.<field>", path)
+	}
+	return name, segments, nil
+}
+
+// fieldKind is the type a value at a path should be parsed as
+type fieldKind int
+
+const (
+	kindAuto fieldKind = iota // Infer from the existing value, else JSON or string
+	kindString
+	kindBool
+	kindInt
+	kindStringList
+)
+
+// schemaKind returns the expected type for well-known server fields
+func schemaKind(segments []pathSegment) fieldKind {
+	top := segments[0].key
+	switch {
+	case len(segments) == 1 && (top == "command" || top == "cwd" || top == "url" || top == "type" || top == "tokenCommand" || top == "healthCheck" || top == "alias" || top == "proxy"):
+		return kindString
+	case len(segments) == 1 && (top == "autoRestart" || top == "disabled"):
+		return kindBool
+	case len(segments) == 1 && (top == "startupTimeout" || top == "retries"):
+		return kindInt
+	case len(segments) == 1 && (top == "args" || top == "tags" || top == "maskKeys" || top == "noMaskKeys"):
+		return kindStringList
+	case len(segments) == 2 && (top == "env" || top == "headers") && !segments[1].isIndex:
+		return kindString
+	case len(segments) == 2 && top == "tls" && segments[1].key == "insecureSkipVerify":
+		return kindBool
+	case len(segments) == 2 && top == "tls" && (segments[1].key == "caFile" || segments[1].key == "certFile" || segments[1].key == "keyFile"):
+		return kindString
+	case len(segments) == 2 && (top == "args" || top == "tags" || top == "maskKeys" || top == "noMaskKeys") && segments[1].isIndex:
+		return kindString
+	}
+	return kindAuto
+}
+
+// parseValue converts the raw command-line value to the type expected at the path
+func parseValue(raw string, kind fieldKind, existing interface{}) (interface{}, error) {
+	if kind == kindAuto {
+		switch existing.(type) {
+		case string:
+			kind = kindString
+		case bool:
+			kind = kindBool
+		case float64:
+			n, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("expected a number, got '%s'", raw)
+			}
+			return n, nil
+		}
+	}
+
+	switch kind {
+	case kindString:
+		return raw, nil
+	case kindBool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false, got '%s'", raw)
+		}
+		return b, nil
+	case kindInt:
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("expected a non-negative whole number, got '%s'", raw)
+		}
+		return n, nil
+	case kindStringList:
+		var list []string
+		if strings.HasPrefix(strings.TrimSpace(raw), "[") {
+			if err := json.Unmarshal([]byte(raw), &list); err != nil {
+				return nil, fmt.Errorf("expected a JSON array of strings: %w", err)
+			}
+		} else {
+			list = []string{raw}
+		}
+		result := make([]interface{}, len(list))
+		for i, item := range list {
+			result[i] = item
+		}
+		return result, nil
+	}
+
+	// Unknown fields take JSON values (numbers, objects, ...) and fall back to strings
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err == nil {
+		return value, nil
+	}
+	return raw, nil
+}
+
+// setAt assigns value at segments below node, creating maps as needed
+func setAt(node interface{}, segments []pathSegment, assign func(existing interface{}) (interface{}, error)) (interface{}, error) {
+	if len(segments) == 0 {
+		return assign(node)
+	}
+	seg := segments[0]
+
+	if seg.isIndex {
+		list, ok := node.([]interface{})
+		if node != nil && !ok {
+			return nil, fmt.Errorf("cannot index into a non-list value")
+		}
+		if seg.index > len(list) {
+			return nil, fmt.Errorf("index %d out of range (length %d)", seg.index, len(list))
+		}
+		var existing interface{}
+		if seg.index < len(list) {
+			existing = list[seg.index]
+		}
+		value, err := setAt(existing, segments[1:], assign)
+		if err != nil {
+			return nil, err
+		}
+		if seg.index == len(list) {
+			return append(list, value), nil
+		}
+		list[seg.index] = value
+		return list, nil
+	}
+
+	m, ok := node.(map[string]interface{})
+	if node != nil && !ok {
+		return nil, fmt.Errorf("cannot set field '%s' on a non-object value", seg.key)
+	}
+	if m == nil {
+		m = make(map[string]interface{})
+	}
+	value, err := setAt(m[seg.key], segments[1:], assign)
+	if err != nil {
+		return nil, err
+	}
+	m[seg.key] = value
+	return m, nil
+}
+
+// Set assigns a value at a dotted path such as "github.env.GITHUB_TOKEN" or
+// "fs.args[1]". Well-known fields are parsed by type (env values and args stay
+// strings, autoRestart is a bool, startupTimeout and retries are whole numbers);
+// other fields accept JSON. The config is not saved.
+func (c *Config) Set(path, raw string) error {
+	name, segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	server, exists := c.MCPServers[name]
+	if !exists {
+		return &ServerNotFoundError{Name: name}
+	}
+
+	// Work on the generic JSON form so extra fields can be set too
+	data, err := json.Marshal(server)
+	if err != nil {
+		return err
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+
+	kind := schemaKind(segments)
+	updated, err := setAt(generic, segments, func(existing interface{}) (interface{}, error) {
+		return parseValue(raw, kind, existing)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set '%s': %w", path, err)
+	}
+
+	data, err = json.Marshal(updated)
+	if err != nil {
+		return err
+	}
+	var result MCPServer
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("failed to set '%s': %w", path, err)
+	}
+	c.MCPServers[name] = result
+	return nil
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		value   string
+		check   func(s MCPServer) bool
+		wantErr bool
+	}{
+		{
+			name:  "env value stays a string",
+			path:  "github.env.GITHUB_TOKEN",
+			value: "${env:GH_TOKEN}",
+			check: func(s MCPServer) bool { return s.Env["GITHUB_TOKEN"] == "${env:GH_TOKEN}" },
+		},
+		{
+			name:  "numeric env value stays a string",
+			path:  "github.env.PORT",
+			value: "8080",
+			check: func(s MCPServer) bool { return s.Env["PORT"] == "8080" },
+		},
+		{
+			name:  "replace arg by index",
+			path:  "github.args[1]",
+			value: "/new/path",
+			check: func(s MCPServer) bool { return reflect.DeepEqual(s.Args, []string{"-y", "/new/path"}) },
+		},
+		{
+			name:  "append arg at the end",
+			path:  "github.args[2]",
+			value: "--verbose",
+			check: func(s MCPServer) bool { return len(s.Args) == 3 && s.Args[2] == "--verbose" },
+		},
+		{
+			name:  "whole args as JSON array",
+			path:  "github.args",
+			value: `["a", "b"]`,
+			check: func(s MCPServer) bool { return reflect.DeepEqual(s.Args, []string{"a", "b"}) },
+		},
+		{
+			name:  "bool field",
+			path:  "github.autoRestart",
+			value: "true",
+			check: func(s MCPServer) bool { return s.AutoRestart },
+		},
+		{
+			name:  "tls setting creates the tls object",
+			path:  "github.tls.insecureSkipVerify",
+			value: "true",
+			check: func(s MCPServer) bool { return s.TLS != nil && s.TLS.InsecureSkipVerify },
+		},
+		{
+			name:  "unknown field takes JSON",
+			path:  "github.timeout",
+			value: "30",
+			check: func(s MCPServer) bool { return s.Extra["timeout"] == float64(30) },
+		},
+		{
+			name:  "startup timeout in seconds",
+			path:  "github.startupTimeout",
+			value: "60",
+			check: func(s MCPServer) bool { return s.StartupTimeout == 60 && s.Extra == nil },
+		},
+		{name: "startup timeout with unit", path: "github.startupTimeout", value: "60s", wantErr: true},
+		{name: "index out of range", path: "github.args[5]", value: "x", wantErr: true},
+		{name: "bad bool", path: "github.autoRestart", value: "maybe", wantErr: true},
+		{name: "unknown server", path: "nope.command", value: "x", wantErr: true},
+		{name: "missing field", path: "github", value: "x", wantErr: true},
+		{name: "index into string", path: "github.command[0]", value: "x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{MCPServers: map[string]MCPServer{
+				"github": {Command: "npx", Args: []string{"-y", "server-github"}, Env: map[string]string{"LOG": "1"}},
+			}}
+
+			err := cfg.Set(tt.path, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Set(%q, %q) expected error", tt.path, tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Set(%q, %q) error = %v", tt.path, tt.value, err)
+			}
+			if server := cfg.MCPServers["github"]; !tt.check(server) {
+				t.Errorf("Set(%q, %q) gave %+v", tt.path, tt.value, server)
+			}
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	name, segments, err := parsePath("fs.args[1]")
+	if err != nil {
+		t.Fatalf("parsePath() error = %v", err)
+	}
+	expected := []pathSegment{{key: "args"}, {index: 1, isIndex: true}}
+	if name != "fs" || !reflect.DeepEqual(segments, expected) {
+		t.Errorf("parsePath() = %q, %+v", name, segments)
+	}
+}
@@ -0,0 +1,176 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// maxObservations caps how many observations are kept per server
+const maxObservations = 200
+
+// flapWindow is the period over which status changes count towards flappiness
+const flapWindow = 24 * time.Hour
+
+// FlapThreshold is the number of status changes within the window that marks a server as flapping
+const FlapThreshold = 3
+
+// Observation is a single recorded server status
+type Observation struct {
+	Time   time.Time `json:"time"`
+	Status string    `json:"status"` // "connected", "failed", "unknown"
+}
+
+// ServerHistory is the recorded health of one server
+type ServerHistory struct {
+	Observations  []Observation `json:"observations"`
+	LastConnected time.Time     `json:"lastConnected,omitempty"`
+	Failures      int           `json:"failures"` // Times the server went from any other state into failed
+}
+
+// History is the persisted health history of all servers
+type History struct {
+	Servers map[string]*ServerHistory `json:"servers"`
+
+	path string
+}
+
+// New returns an empty history that will be saved to path
+func New(path string) *History {
+	return &History{Servers: make(map[string]*ServerHistory), path: path}
+}
+
+// Load reads the history file, returning an empty history if it doesn't exist
+func Load(path string) (*History, error) {
+	h := New(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, err
+	}
+	if h.Servers == nil {
+		h.Servers = make(map[string]*ServerHistory)
+	}
+	return h, nil
+}
+
+// Save writes the history back to the file it was loaded from
+func (h *History) Save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(h.path, data, 0644)
+}
+
+// Record adds an observation for a server
+func (h *History) Record(name, status string, at time.Time) {
+	server, ok := h.Servers[name]
+	if !ok {
+		server = &ServerHistory{}
+		h.Servers[name] = server
+	}
+
+	if status == "failed" {
+		if last, ok := server.last(); !ok || last.Status != "failed" {
+			server.Failures++
+		}
+	}
+	if status == "connected" {
+		server.LastConnected = at
+	}
+
+	server.Observations = append(server.Observations, Observation{Time: at, Status: status})
+	if len(server.Observations) > maxObservations {
+		server.Observations = server.Observations[len(server.Observations)-maxObservations:]
+	}
+}
+
+// Get returns the history of a server, if any has been recorded
+func (h *History) Get(name string) (*ServerHistory, bool) {
+	server, ok := h.Servers[name]
+	return server, ok
+}
+
+// Forget drops the recorded history of a server. It reports whether there was any.
+func (h *History) Forget(name string) bool {
+	_, ok := h.Servers[name]
+	delete(h.Servers, name)
+	return ok
+}
+
+func (s *ServerHistory) last() (Observation, bool) {
+	if len(s.Observations) == 0 {
+		return Observation{}, false
+	}
+	return s.Observations[len(s.Observations)-1], true
+}
+
+// Flaps counts status changes within the flap window ending at now
+func (s *ServerHistory) Flaps(now time.Time) int {
+	flaps := 0
+	previous := ""
+	for _, obs := range s.Observations {
+		if now.Sub(obs.Time) > flapWindow {
+			previous = obs.Status
+			continue
+		}
+		if previous != "" && obs.Status != previous {
+			flaps++
+		}
+		previous = obs.Status
+	}
+	return flaps
+}
+
+// IsFlapping reports whether the server changed state often within the flap window
+func (s *ServerHistory) IsFlapping(now time.Time) bool {
+	return s.Flaps(now) >= FlapThreshold
+}
+
+// Uptime returns the fraction of observations within the flap window that were connected
+func (s *ServerHistory) Uptime(now time.Time) (float64, bool) {
+	total, connected := 0, 0
+	for _, obs := range s.Observations {
+		if now.Sub(obs.Time) > flapWindow {
+			continue
+		}
+		total++
+		if obs.Status == "connected" {
+			connected++
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return float64(connected) / float64(total), true
+}
+
+// FormatAgo renders a time relative to now, e.g. "5m ago"
+func FormatAgo(t, now time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,123 @@
+package health
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordCountsFailureTransitions(t *testing.T) {
+	h, err := Load(filepath.Join(t.TempDir(), "health.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	now := time.Now()
+	h.Record("github", "connected", now.Add(-5*time.Minute))
+	h.Record("github", "failed", now.Add(-4*time.Minute))
+	h.Record("github", "failed", now.Add(-3*time.Minute))
+	h.Record("github", "connected", now.Add(-2*time.Minute))
+	h.Record("github", "failed", now.Add(-1*time.Minute))
+
+	server, ok := h.Get("github")
+	if !ok {
+		t.Fatal("expected history for github")
+	}
+	if server.Failures != 2 {
+		t.Errorf("Failures = %d, want 2", server.Failures)
+	}
+	if !server.LastConnected.Equal(now.Add(-2 * time.Minute)) {
+		t.Errorf("LastConnected = %v", server.LastConnected)
+	}
+	if flaps := server.Flaps(now); flaps != 3 {
+		t.Errorf("Flaps() = %d, want 3", flaps)
+	}
+	if !server.IsFlapping(now) {
+		t.Error("expected server to be flapping")
+	}
+	if uptime, ok := server.Uptime(now); !ok || uptime != 0.4 {
+		t.Errorf("Uptime() = %v, %v, want 0.4", uptime, ok)
+	}
+}
+
+func TestFlapsIgnoresOldObservations(t *testing.T) {
+	h, _ := Load(filepath.Join(t.TempDir(), "health.json"))
+
+	now := time.Now()
+	h.Record("fs", "connected", now.Add(-72*time.Hour))
+	h.Record("fs", "failed", now.Add(-71*time.Hour))
+	h.Record("fs", "connected", now.Add(-70*time.Hour))
+	h.Record("fs", "connected", now.Add(-1*time.Hour))
+
+	server, _ := h.Get("fs")
+	if flaps := server.Flaps(now); flaps != 0 {
+		t.Errorf("Flaps() = %d, want 0", flaps)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "health.json")
+	h, _ := Load(path)
+
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	h.Record("github", "connected", at)
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	server, ok := loaded.Get("github")
+	if !ok || !server.LastConnected.Equal(at) || len(server.Observations) != 1 {
+		t.Errorf("loaded history = %+v", server)
+	}
+}
+
+func TestObservationsAreCapped(t *testing.T) {
+	h, _ := Load(filepath.Join(t.TempDir(), "health.json"))
+
+	now := time.Now()
+	for i := 0; i < maxObservations+10; i++ {
+		h.Record("github", "connected", now)
+	}
+	server, _ := h.Get("github")
+	if len(server.Observations) != maxObservations {
+		t.Errorf("len(Observations) = %d, want %d", len(server.Observations), maxObservations)
+	}
+}
+
+func TestForget(t *testing.T) {
+	h, _ := Load(filepath.Join(t.TempDir(), "health.json"))
+	h.Record("github", "connected", time.Now())
+
+	if !h.Forget("github") {
+		t.Error("Forget() = false, want true for a recorded server")
+	}
+	if _, ok := h.Get("github"); ok {
+		t.Error("expected no history for github after Forget()")
+	}
+	if h.Forget("github") {
+		t.Error("Forget() = true, want false once forgotten")
+	}
+}
+
+func TestFormatAgo(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		t        time.Time
+		expected string
+	}{
+		{time.Time{}, "never"},
+		{now.Add(-10 * time.Second), "just now"},
+		{now.Add(-5 * time.Minute), "5m ago"},
+		{now.Add(-3 * time.Hour), "3h ago"},
+		{now.Add(-50 * time.Hour), "2d ago"},
+	}
+	for _, tt := range tests {
+		if got := FormatAgo(tt.t, now); got != tt.expected {
+			t.Errorf("FormatAgo() = %q, want %q", got, tt.expected)
+		}
+	}
+}
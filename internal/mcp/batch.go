@@ -0,0 +1,143 @@
+package mcp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// StartRequest is one server to start with StartServers
+type StartRequest struct {
+	Name    string
+	Server  *config.MCPServer
+	Retries int
+}
+
+// StartServers adds all servers to Claude first and then verifies them together,
+// running a single claude mcp list per poll instead of one polling loop per
+// server. Servers that fail are diagnosed and retried individually. The returned
+// errors line up with requests; nil means the server connected.
+func (b *ClaudeCmdBuilder) StartServers(requests []StartRequest, verbose bool) []error {
+	errs := make([]error, len(requests))
+	debugLogs := make([]string, len(requests))
+	resolved := make([]*config.MCPServer, len(requests))
+
+	var added []int
+	for i, req := range requests {
+		if ctxErr := b.context().Err(); ctxErr != nil {
+			errs[i] = fmt.Errorf("interrupted before adding server '%s': %w", req.Name, ctxErr)
+			continue
+		}
+		debugLogs[i], resolved[i], errs[i] = b.addServer(req.Name, req.Server, verbose)
+		if errs[i] == nil {
+			added = append(added, i)
+		}
+	}
+
+	for i, err := range b.verifyServers(requests, added) {
+		if err != nil {
+			err = b.diagnoseFailure(requests[i].Name, requests[i].Server, verbose, debugLogs[i], err)
+		} else {
+			err = b.deepVerify(requests[i].Name, resolved[i], verbose)
+		}
+		errs[i] = err
+	}
+
+	for i, req := range requests {
+		if errs[i] != nil && req.Retries > 0 && b.context().Err() == nil {
+			errs[i] = b.retryStart(req.Name, req.Server, verbose, req.Retries, errs[i])
+		}
+	}
+	return errs
+}
+
+// verifyServers polls claude mcp list until every added server has connected,
+// failed, or run out of time. Each server follows the same schedule as
+// verifyServer: 3 checks by default, or polling until its startup timeout.
+func (b *ClaudeCmdBuilder) verifyServers(requests []StartRequest, indices []int) map[int]error {
+	results := make(map[int]error)
+	if len(indices) == 0 {
+		return results
+	}
+
+	b.step("Waiting for %d server(s) to start...", len(indices))
+	defer b.clearProgress()
+
+	// The servers are verified together, so the time is not split between them
+	checks := 0
+	defer func(start time.Time) {
+		b.Timings.Record("", fmt.Sprintf("verification of %d server(s) (%d check(s))", len(indices), checks), TimingServer, time.Since(start))
+	}(time.Now())
+
+	pending := indices
+	failAll := func(err error) map[int]error {
+		for _, i := range pending {
+			results[i] = err
+		}
+		return results
+	}
+
+	// Give the servers a moment to start
+	if err := b.wait(500 * time.Millisecond); err != nil {
+		return failAll(err)
+	}
+
+	start := time.Now()
+	for attempt := 0; len(pending) > 0; attempt++ {
+		b.step("Verifying %d server(s) (check %d)...", len(pending), attempt+1)
+		checks = attempt + 1
+		statuses, err := b.GetServerStatuses(nil)
+		if ctxErr := b.context().Err(); ctxErr != nil {
+			return failAll(ctxErr)
+		}
+		if err != nil {
+			return failAll(err)
+		}
+
+		status := make(map[string]string, len(statuses))
+		for _, s := range statuses {
+			status[s.Name] = s.Status
+		}
+
+		var still []int
+		nextDeadline := time.Duration(-1)
+		for _, i := range pending {
+			req := requests[i]
+			claudeName := req.Server.ClaudeName(req.Name)
+			timeout := req.Server.StartupTimeoutDuration()
+			expired := (timeout == 0 && attempt >= 2) || (timeout > 0 && time.Since(start) >= timeout)
+
+			switch {
+			case status[claudeName] == "connected":
+				results[i] = nil
+			case status[claudeName] == "failed" && (timeout == 0 || expired):
+				results[i] = &VerificationError{Server: req.Name, Timeout: timeout, Attempts: attempt + 1, Failed: true}
+			case expired:
+				results[i] = &VerificationError{Server: req.Name, Timeout: timeout, Attempts: attempt + 1}
+			default:
+				// Slow servers may still be starting, so keep polling
+				still = append(still, i)
+				if remaining := timeout - time.Since(start); timeout > 0 && (nextDeadline < 0 || remaining < nextDeadline) {
+					nextDeadline = remaining
+				}
+			}
+		}
+		pending = still
+		if len(pending) == 0 {
+			break
+		}
+
+		delay := time.Duration(attempt+1) * verifyPollInterval
+		if delay > maxVerifyPollInterval {
+			delay = maxVerifyPollInterval
+		}
+		if nextDeadline >= 0 && delay > nextDeadline {
+			delay = nextDeadline
+		}
+		if err := b.wait(delay); err != nil {
+			return failAll(err)
+		}
+	}
+	return results
+}
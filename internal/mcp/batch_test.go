@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestStartServersSharesVerification(t *testing.T) {
+	defer func(interval time.Duration) { verifyPollInterval = interval }(verifyPollInterval)
+	verifyPollInterval = 10 * time.Millisecond
+
+	// 'slow' only shows up as connected on the second list
+	lists := 0
+	runner := &fakeRunner{respond: func(args []string) (string, error) {
+		if subcommand(args) == "list" {
+			lists++
+			output := "fast: npx fast - ✓ Connected\nbroken: nonexistent-command - ✗ Failed to connect\n"
+			if lists > 1 {
+				output += "slow: npx slow - ✓ Connected\n"
+			}
+			return output, nil
+		}
+		return listResponder("")(args)
+	}}
+	b := NewClaudeCmdBuilder()
+	b.Runner = runner
+
+	requests := []StartRequest{
+		{Name: "fast", Server: &config.MCPServer{Command: "npx", Args: []string{"fast"}}},
+		{Name: "broken", Server: &config.MCPServer{Command: "nonexistent-command"}},
+		{Name: "slow", Server: &config.MCPServer{Command: "npx", Args: []string{"slow"}}},
+	}
+	errs := b.StartServers(requests, false)
+
+	if errs[0] != nil || errs[2] != nil {
+		t.Errorf("StartServers() errors = %v, want fast and slow to connect", errs)
+	}
+	if errs[1] == nil {
+		t.Error("StartServers() should report the broken server")
+	}
+
+	// Two shared checks for all three servers, plus one from diagnosing 'broken'
+	if lists != 3 {
+		t.Errorf("StartServers() ran claude mcp list %d times, want 3", lists)
+	}
+}
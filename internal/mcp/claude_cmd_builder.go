@@ -1,39 +1,160 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
-	"cmcp/internal/config"
-	"github.com/fatih/color"
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/ui"
 )
 
 type ClaudeCmdBuilder struct {
 	// Builder for Claude CLI commands
+
+	// PullImages makes StartServer pull missing docker images before registering
+	PullImages bool
+
+	// Progress, if set, is updated during silent steps in non-verbose mode
+	Progress ProgressReporter
+
+	// Context, if set, cancels in-flight claude subprocesses and retry waits
+	Context context.Context
+
+	// Runner executes claude commands; nil runs ClaudeBin
+	Runner CommandRunner
+
+	// ClaudeBin is the claude binary to run, as a command on PATH or a path; empty
+	// finds claude on PATH
+	ClaudeBin string
+
+	// DeepVerify makes starts also connect to each server directly once Claude
+	// reports it connected, failing servers whose tools/list is empty or errors
+	DeepVerify bool
+
+	// Aliases maps configured server names to the alias they are registered under
+	// in Claude (see config.Config.Aliases). Methods given a server apply its alias
+	// themselves; name-only methods such as IsRunning and StopServer take the name
+	// in Claude, which ClaudeName looks up.
+	Aliases map[string]string
+
+	// Scope is the Claude scope servers are added to, removed from, and looked up in
+	// ("local", "project" or "user"); empty leaves it to Claude's default
+	Scope string
+
+	// Timings, if set, records how long each phase of starting and stopping takes
+	Timings *Timings
+
+	// Stdout and Stderr receive the builder's messages and, in verbose mode, claude's
+	// output; nil writes to os.Stdout and os.Stderr
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// ProgressReporter shows feedback while the builder waits on claude
+type ProgressReporter interface {
+	Step(message string)
+	Clear()
 }
 
 // ServerStatus represents the status of a server in Claude
 type ServerStatus struct {
-	Name      string
-	Command   string
-	Status    string // "connected", "failed", "unknown"
-	InConfig  bool
+	Name       string // The name in Claude
+	Command    string
+	Status     string // "connected", "failed", "unknown"
+	InConfig   bool
+	ConfigName string // The configured server registered under Name, which differs from Name for aliases
 }
 
 func NewClaudeCmdBuilder() *ClaudeCmdBuilder {
 	return &ClaudeCmdBuilder{}
 }
 
+// ClaudeName returns the name a configured server is registered under in Claude
+func (b *ClaudeCmdBuilder) ClaudeName(name string) string {
+	if alias, ok := b.Aliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// context returns the builder's context, defaulting to context.Background()
+func (b *ClaudeCmdBuilder) context() context.Context {
+	if b.Context != nil {
+		return b.Context
+	}
+	return context.Background()
+}
+
+// runner returns the builder's command runner, defaulting to the claude binary
+func (b *ClaudeCmdBuilder) runner() CommandRunner {
+	if b.Runner != nil {
+		return b.Runner
+	}
+	return execRunner{path: b.ClaudeBin}
+}
+
+// claudeBin returns the claude binary as shown in commands printed for the user
+func (b *ClaudeCmdBuilder) claudeBin() string {
+	if b.ClaudeBin != "" {
+		return b.ClaudeBin
+	}
+	return "claude"
+}
+
+// wait sleeps for d, returning early with the context's error if it is cancelled
+func (b *ClaudeCmdBuilder) wait(d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-b.context().Done():
+		return b.context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// stdout returns where the builder's messages go
+func (b *ClaudeCmdBuilder) stdout() io.Writer {
+	if b.Stdout != nil {
+		return b.Stdout
+	}
+	return os.Stdout
+}
+
+// stderr returns where claude's error output goes
+func (b *ClaudeCmdBuilder) stderr() io.Writer {
+	if b.Stderr != nil {
+		return b.Stderr
+	}
+	return os.Stderr
+}
+
+// step reports the current step to the progress reporter, if any
+func (b *ClaudeCmdBuilder) step(format string, args ...interface{}) {
+	if b.Progress != nil {
+		b.Progress.Step(fmt.Sprintf(format, args...))
+	}
+}
+
+// clearProgress removes the progress indicator before regular output is printed
+func (b *ClaudeCmdBuilder) clearProgress() {
+	if b.Progress != nil {
+		b.Progress.Clear()
+	}
+}
+
 // createDebugLogFile creates a temp file for debug output and returns the path
 func (b *ClaudeCmdBuilder) createDebugLogFile(operation string) (string, error) {
 	// Create temp directory for cmcp debug logs
-	tempDir := filepath.Join(os.TempDir(), "cmcp-debug")
+	tempDir := debugLogDir()
 	if err := os.MkdirAll(tempDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create debug temp dir: %w", err)
 	}
@@ -50,6 +171,10 @@ func (b *ClaudeCmdBuilder) createDebugLogFile(operation string) (string, error)
 	}
 	file.Close()
 
+	// Make room for the new log by dropping the oldest ones
+	_, maxTotal := debugLogLimits()
+	rotateDebugLogs(tempDir, maxTotal, filename)
+
 	return logPath, nil
 }
 
@@ -63,9 +188,49 @@ func findClaude() string {
 }
 
 func (b *ClaudeCmdBuilder) StartServer(name string, server *config.MCPServer, verbose bool) error {
-	var args []string
+	debugLogPath, resolved, err := b.addServer(name, server, verbose)
+	if err != nil {
+		return err
+	}
+
+	// Verify server started successfully with diagnostics
+	if err := b.VerifyServerStartedWithDiagnosticsVerbose(name, server, verbose, debugLogPath); err != nil {
+		return err
+	}
+	return b.deepVerify(name, resolved, verbose)
+}
+
+// addServer registers a server with Claude without verifying it, returning the
+// debug log path to mention if verification fails later and the definition as
+// resolved for Claude, which is what a deep verification must launch
+func (b *ClaudeCmdBuilder) addServer(name string, server *config.MCPServer, verbose bool) (string, *config.MCPServer, error) {
 	var commandStr string
 
+	// Mint a fresh token and fill in header references for remote servers
+	done := b.Timings.Track(name, "resolve definition", TimingCmcp)
+	server, err := ResolveHeaders(b.context(), server)
+	if err == nil {
+		server, err = ResolveEnv(server)
+	}
+	if err == nil {
+		server, err = ExpandPlaceholders(name, server)
+	}
+	done()
+	if err != nil {
+		return "", nil, err
+	}
+	server = ResolvePaths(server)
+
+	// Catch docker problems now rather than diagnosing a failed start afterwards
+	if server.Command == "docker" {
+		done := b.Timings.Track(name, "docker pre-flight", TimingCmcp)
+		err := b.dockerPreflight(name, server, verbose)
+		done()
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
 	// Create debug log file only if not verbose
 	var debugLogPath string
 	var debugLogErr error
@@ -73,56 +238,55 @@ func (b *ClaudeCmdBuilder) StartServer(name string, server *config.MCPServer, ve
 		debugLogPath, debugLogErr = b.createDebugLogFile("start-" + name)
 	}
 
-	// Decide whether to use add-json or regular add
-	useAddJSON := len(server.Env) > 0
+	// Decide whether to use add-json or regular add; --debug is always
+	// included for better error diagnostics
+	args, method := b.startArgs(name, server)
+	useAddJSON := method == "add-json"
 
-	if useAddJSON {
-		// Use add-json for servers with environment variables
-		args = b.buildStartArgsJSON(name, server)
-
-		// Show command if verbose with pretty-printed JSON
-		if verbose {
+	// Show command if verbose
+	if verbose {
+		if useAddJSON {
 			// Print the command prefix and JSON separately to avoid color code issues
-			fmt.Printf("  Command: claude mcp add-json %s ", name)
+			fmt.Fprintf(b.stdout(), "  Command: %s %s ", b.ClaudeCommand("add-json"), ShellQuote(server.ClaudeName(name)))
 			b.printPrettyJSON(server)
-		}
-	} else {
-		// Use regular add for simple servers
-		args = b.buildStartArgs(name, server)
-
-		// Show command if verbose
-		if verbose {
+		} else {
 			commandStr = b.BuildStartCommand(name, server)
-			fmt.Printf("  Command: %s\n", commandStr)
+			fmt.Fprintf(b.stdout(), "  Command: %s\n", commandStr)
 		}
 	}
 
-	// Always add --debug flag for better error diagnostics
-	args = append([]string{args[0], args[1], "--debug"}, args[2:]...)
-
 	// Execute claude mcp add/add-json
-	cmd := exec.Command(findClaude(), args...)
+	if !verbose {
+		b.step("Adding '%s' to Claude...", name)
+	}
 
 	// Capture output or show directly based on verbose flag
 	var stdout, stderr strings.Builder
+	var cmdOut, cmdErr io.Writer
 	if verbose {
-		// In verbose mode, show output directly
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		fmt.Println()  // Add newline before debug output
+		// In verbose mode, show output directly with secrets masked
+		cmdOut, cmdErr = newMaskingWriter(b.stdout()), newMaskingWriter(b.stderr())
+		fmt.Fprintln(b.stdout())  // Add newline before debug output
 	} else {
 		// In normal mode, capture output for logging
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
+		cmdOut, cmdErr = &stdout, &stderr
 	}
 
-	err := cmd.Run()
+	done = b.Timings.Track(name, "claude add", TimingClaude)
+	err = runClaude(b.context(), b.runner(), cmdOut, cmdErr, args...)
+	done()
+	flushMasked(cmdOut, cmdErr)
+	b.clearProgress()
 
 	// Write debug output to log file only if not verbose
 	if !verbose && debugLogErr == nil {
 		debugContent := fmt.Sprintf("Command: %s\nExit Code: %v\n\nSTDOUT:\n%s\n\nSTDERR:\n%s\n", 
-			strings.Join(args, " "), err, stdout.String(), stderr.String())
-		ioutil.WriteFile(debugLogPath, []byte(debugContent), 0644)
+			ShellJoin(args), err, stdout.String(), stderr.String())
+		writeDebugLog(debugLogPath, debugContent)
+	}
+
+	if ctxErr := b.context().Err(); ctxErr != nil {
+		return "", nil, fmt.Errorf("interrupted while adding server '%s': %w", name, ctxErr)
 	}
 
 	// Handle output based on verbose flag and error state
@@ -134,21 +298,21 @@ func (b *ClaudeCmdBuilder) StartServer(name string, server *config.MCPServer, ve
 			} else {
 				commandStr = b.BuildStartCommand(name, server)
 			}
-			fmt.Printf("  Command failed: %s\n", commandStr)
+			fmt.Fprintf(b.stdout(), "  Command failed: %s\n", commandStr)
 			
 			if stderr.Len() > 0 {
-				fmt.Fprintf(os.Stderr, "%s", stderr.String())
+				fmt.Fprintf(b.stderr(), "%s", stderr.String())
 			}
 
 			// Include debug log path in error message if available
-			errorMsg := fmt.Sprintf("failed to add server '%s' to Claude", name)
+			addErr := &CommandError{Server: name, Operation: "add", Err: err}
 			if debugLogErr == nil {
-				errorMsg += fmt.Sprintf("\n\n\033[0;36mℹ Debug log saved to:\033[0m\n  %s\n\033[0;90m  View this file for detailed error information\033[0m", debugLogPath)
+				addErr.DebugLog = debugLogPath
 			}
-			return fmt.Errorf(errorMsg)
+			return "", nil, addErr
 		} else {
 			// In verbose mode, error was already shown, just return simple error
-			return fmt.Errorf("failed to add server '%s' to Claude", name)
+			return "", nil, &CommandError{Server: name, Operation: "add", Err: err}
 		}
 	}
 
@@ -163,21 +327,50 @@ func (b *ClaudeCmdBuilder) StartServer(name string, server *config.MCPServer, ve
 			}
 			// Show file modifications with indentation
 			if strings.Contains(line, "File modified:") {
-				fmt.Printf("  %s\n", line)
+				fmt.Fprintf(b.stdout(), "  %s\n", line)
 			}
 		}
 	}
 
-	// Verify server started successfully with diagnostics
-	var verifyDebugPath string
-	if !verbose && debugLogErr == nil {
-		verifyDebugPath = debugLogPath
-	}
-	if err := b.VerifyServerStartedWithDiagnosticsVerbose(name, server, verbose, verifyDebugPath); err != nil {
-		return err
+	if verbose || debugLogErr != nil {
+		return "", server, nil
 	}
+	return debugLogPath, server, nil
+}
 
-	return nil
+// retryBackoff is the delay before the first start retry; it doubles after each attempt
+var retryBackoff = 2 * time.Second
+
+// StartServerWithRetry starts a server, repeating the add and verify cycle up to
+// retries more times with exponential backoff when it fails to connect
+func (b *ClaudeCmdBuilder) StartServerWithRetry(name string, server *config.MCPServer, verbose bool, retries int) error {
+	return b.retryStart(name, server, verbose, retries, b.StartServer(name, server, verbose))
+}
+
+// retryStart retries a server whose first start attempt returned err
+func (b *ClaudeCmdBuilder) retryStart(name string, server *config.MCPServer, verbose bool, retries int, err error) error {
+	delay := retryBackoff
+	for attempt := 0; err != nil && attempt < retries && b.context().Err() == nil; attempt++ {
+		fmt.Fprintf(b.stdout(), "  Attempt %d/%d for '%s' failed, retrying in %v...\n", attempt+1, retries+1, name, delay)
+
+		// Remove the half-registered server so it can be added again
+		if claudeName := server.ClaudeName(name); b.IsRunning(claudeName) {
+			if err := b.StopServer(claudeName, verbose); err != nil {
+				return fmt.Errorf("failed to remove server '%s' before retrying: %w", name, err)
+			}
+		}
+
+		done := b.Timings.Track(name, "retry wait", TimingCmcp)
+		waitErr := b.wait(delay)
+		done()
+		if waitErr != nil {
+			return fmt.Errorf("interrupted while retrying server '%s': %w", name, waitErr)
+		}
+		delay *= 2
+
+		err = b.StartServer(name, server, verbose)
+	}
+	return err
 }
 
 // VerifyServerStarted checks if a server is actually running after being added
@@ -187,8 +380,35 @@ func (b *ClaudeCmdBuilder) VerifyServerStarted(name string) error {
 
 // VerifyServerStartedVerbose checks if a server is running with optional verbose output
 func (b *ClaudeCmdBuilder) VerifyServerStartedVerbose(name string, verbose bool) error {
+	return b.verifyServer(name, 0, verbose)
+}
+
+// verifyPollInterval is the base delay between verification attempts; it grows
+// with each attempt up to maxVerifyPollInterval
+var (
+	verifyPollInterval    = time.Second
+	maxVerifyPollInterval = 5 * time.Second
+)
+
+// verifyServer polls claude mcp list until the server connects. With a zero
+// timeout it makes 3 attempts; otherwise it keeps polling, also through failed
+// health checks, until the timeout elapses.
+func (b *ClaudeCmdBuilder) verifyServer(name string, timeout time.Duration, verbose bool) error {
+	if !verbose {
+		b.step("Waiting for '%s' to start...", name)
+		defer b.clearProgress()
+	}
+
+	attempts := 0
+	start := time.Now()
+	defer func() {
+		b.Timings.Record(name, fmt.Sprintf("verification (%d check(s))", attempts), TimingServer, time.Since(start))
+	}()
+
 	// Give the server a moment to start
-	time.Sleep(500 * time.Millisecond)
+	if err := b.wait(500 * time.Millisecond); err != nil {
+		return err
+	}
 
 	// Create debug log file only if not verbose
 	var debugLogPath string
@@ -197,10 +417,19 @@ func (b *ClaudeCmdBuilder) VerifyServerStartedVerbose(name string, verbose bool)
 		debugLogPath, debugLogErr = b.createDebugLogFile("verify-" + name)
 	}
 
-	// Try up to 3 times with increasing delays
-	for attempt := 0; attempt < 3; attempt++ {
+	// The debug log is only mentioned when output was captured to it
+	shownLog := ""
+	if !verbose && debugLogErr == nil {
+		shownLog = debugLogPath
+	}
+
+	deadline := time.Now().Add(timeout)
+	lastFailed := false
+
+	// Try up to 3 times, or until the startup timeout, with increasing delays
+	for attempt := 0; ; attempt++ {
 		// Run claude mcp list with debug and check if server is connected
-		cmd := exec.Command(findClaude(), "mcp", "list", "--debug")
+		listArgs := []string{"mcp", "list", "--debug"}
 		
 		var output []byte
 		var err error
@@ -208,53 +437,56 @@ func (b *ClaudeCmdBuilder) VerifyServerStartedVerbose(name string, verbose bool)
 		if verbose {
 			// In verbose mode, show output directly
 			if attempt == 0 {
-				fmt.Println("\nVerifying server connection...")
+				fmt.Fprintln(b.stdout(), "\nVerifying server connection...")
 			}
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-			err = cmd.Run()
+			maskedOut, maskedErr := newMaskingWriter(b.stdout()), newMaskingWriter(b.stderr())
+			err = runClaude(b.context(), b.runner(), maskedOut, maskedErr, listArgs...)
+			flushMasked(maskedOut, maskedErr)
 		} else {
 			// In normal mode, capture output for logging
-			output, err = cmd.CombinedOutput()
+			if timeout > 0 {
+				b.step("Verifying connection to '%s' (%v left)...", name, time.Until(deadline).Round(time.Second))
+			} else {
+				b.step("Verifying connection to '%s' (attempt %d/3)...", name, attempt+1)
+			}
+			output, err = claudeCombinedOutput(b.context(), b.runner(), listArgs...)
 			
 			// Log the verification attempt if we have a debug log
 			if debugLogErr == nil {
 				debugContent := fmt.Sprintf("Verification attempt %d:\nCommand: claude mcp list --debug\nOutput:\n%s\nError: %v\n\n", 
 					attempt+1, string(output), err)
 				// Append to existing log file
-				file, appendErr := os.OpenFile(debugLogPath, os.O_APPEND|os.O_WRONLY, 0644)
-				if appendErr == nil {
-					file.WriteString(debugContent)
-					file.Close()
-				}
+				appendDebugLog(debugLogPath, debugContent)
 			}
 		}
 
+		if ctxErr := b.context().Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if err != nil {
-			if !verbose && debugLogErr == nil {
-				return fmt.Errorf("failed to list servers: %w\n\n\033[0;36mℹ Debug log saved to:\033[0m\n  %s\n\033[0;90m  View this file for detailed error information\033[0m", err, debugLogPath)
-			}
-			return fmt.Errorf("failed to list servers: %w", err)
+			return &CommandError{Server: name, Operation: "list", DebugLog: shownLog, Err: err}
 		}
+		attempts = attempt + 1
 
 		// Parse the output to check server status
 		// For verbose mode, we need to re-run to capture output for parsing
 		if verbose {
-			cmd := exec.Command(findClaude(), "mcp", "list")
-			output, _ = cmd.Output()
+			output, _ = claudeOutput(b.context(), b.runner(), "mcp", "list")
 		}
 		
+		lastFailed = false
 		lines := strings.Split(string(output), "\n")
 		for _, line := range lines {
 			// Look for the server in the output
 			if strings.Contains(line, name+":") {
 				// Check if it shows as connected (✓) or failed (✗)
 				if strings.Contains(line, "✗") || strings.Contains(line, "Failed") {
-					errorMsg := "failed to connect"
-					if !verbose && debugLogErr == nil {
-						errorMsg += fmt.Sprintf("\n\n\033[0;36mℹ Debug log saved to:\033[0m\n  %s\n\033[0;90m  View this file for detailed connection diagnostics\033[0m", debugLogPath)
+					// Slow servers may still be starting, so keep polling until the timeout
+					if timeout > 0 && time.Now().Before(deadline) {
+						lastFailed = true
+						break
 					}
-					return fmt.Errorf(errorMsg)
+					return &VerificationError{Server: name, Timeout: timeout, Attempts: attempts, Failed: true, DebugLog: shownLog}
 				}
 				if strings.Contains(line, "✓") || strings.Contains(line, "Connected") {
 					return nil // Server is connected
@@ -263,17 +495,26 @@ func (b *ClaudeCmdBuilder) VerifyServerStartedVerbose(name string, verbose bool)
 		}
 
 		// If not found or not connected yet, wait before retrying
-		if attempt < 2 {
-			time.Sleep(time.Duration(attempt+1) * time.Second)
+		delay := time.Duration(attempt+1) * verifyPollInterval
+		if delay > maxVerifyPollInterval {
+			delay = maxVerifyPollInterval
+		}
+		if timeout > 0 {
+			if remaining := time.Until(deadline); remaining <= 0 {
+				break
+			} else if delay > remaining {
+				delay = remaining
+			}
+		} else if attempt >= 2 {
+			break
+		}
+		if err := b.wait(delay); err != nil {
+			return err
 		}
 	}
 
 	// After retries, assume failure
-	errorMsg := "failed to connect after 3 attempts"
-	if !verbose && debugLogErr == nil {
-		errorMsg += fmt.Sprintf("\n\n\033[0;36mℹ Debug log saved to:\033[0m\n  %s\n\033[0;90m  View this file for detailed connection diagnostics\033[0m", debugLogPath)
-	}
-	return fmt.Errorf(errorMsg)
+	return &VerificationError{Server: name, Timeout: timeout, Attempts: attempts, Failed: timeout > 0 && lastFailed, DebugLog: shownLog}
 }
 
 // VerifyServerStartedWithDiagnostics checks if a server is running and provides diagnostics on failure
@@ -283,25 +524,35 @@ func (b *ClaudeCmdBuilder) VerifyServerStartedWithDiagnostics(name string, serve
 
 // VerifyServerStartedWithDiagnosticsVerbose checks if a server is running and provides diagnostics on failure
 func (b *ClaudeCmdBuilder) VerifyServerStartedWithDiagnosticsVerbose(name string, server *config.MCPServer, verbose bool, debugLogPath string) error {
-	err := b.VerifyServerStartedVerbose(name, verbose)
+	err := b.verifyServer(server.ClaudeName(name), server.StartupTimeoutDuration(), verbose)
 	if err == nil {
 		return nil // Server started successfully
 	}
+	return b.diagnoseFailure(name, server, verbose, debugLogPath, err)
+}
+
+// diagnoseFailure replaces a verification error with diagnostics for the server when available
+func (b *ClaudeCmdBuilder) diagnoseFailure(name string, server *config.MCPServer, verbose bool, debugLogPath string, err error) error {
+	if ctxErr := b.context().Err(); ctxErr != nil {
+		return fmt.Errorf("interrupted while verifying server '%s': %w", name, ctxErr)
+	}
 
 	// Get diagnostic information
-	diag, _ := GetServerDiagnostics(name, server.Command, server.Args)
+	if !verbose {
+		b.step("Running diagnostics for '%s'...", name)
+	}
+	done := b.Timings.Track(name, "diagnostics", TimingClaude)
+	diag, _ := b.Diagnose(name, server)
+	done()
+	b.clearProgress()
 	if diag != nil {
-		var diagInfo string
-		if !verbose && debugLogPath != "" {
+		// Report the diagnostics instead of the original error, which stays wrapped
+		diagnosed := &DiagnosticError{Server: name, Diagnostics: diag, Err: err}
+		if !verbose {
 			// Include debug log path in the diagnostic output
-			diagInfo = FormatDiagnosticsWithDebugLog(diag, debugLogPath)
-		} else {
-			diagInfo = FormatDiagnostics(diag)
-		}
-		if diagInfo != "" {
-			// Return just the diagnostic info, not the original error
-			return fmt.Errorf("%s", strings.TrimSpace(diagInfo))
+			diagnosed.DebugLog = debugLogPath
 		}
+		return diagnosed
 	}
 
 	return err
@@ -310,7 +561,7 @@ func (b *ClaudeCmdBuilder) VerifyServerStartedWithDiagnosticsVerbose(name string
 func (b *ClaudeCmdBuilder) StopServer(name string, verbose bool) error {
 	// First check if server exists in Claude
 	if !b.IsRunning(name) {
-		return fmt.Errorf("server '%s' is not registered in Claude", name)
+		return &config.ServerNotFoundError{Name: name, InClaude: true}
 	}
 
 	// Create debug log file only if not verbose
@@ -322,57 +573,66 @@ func (b *ClaudeCmdBuilder) StopServer(name string, verbose bool) error {
 
 	// Build the command
 	commandStr := b.BuildStopCommand(name)
-	args := []string{"mcp", "remove", "--debug", name}
+	args := append(append([]string{"mcp", "remove", "--debug"}, b.scopeArgs()...), name)
 
 	// Show command if verbose
 	if verbose {
-		fmt.Printf("  Command: %s\n", commandStr)
-		fmt.Println()  // Add newline before debug output
+		fmt.Fprintf(b.stdout(), "  Command: %s\n", commandStr)
+		fmt.Fprintln(b.stdout())  // Add newline before debug output
 	}
 
 	// Execute claude mcp remove
-	cmd := exec.Command(findClaude(), args...)
+	if !verbose {
+		b.step("Removing '%s' from Claude...", name)
+	}
 
 	// Capture output or show directly based on verbose flag
 	var stdout, stderr strings.Builder
+	var cmdOut, cmdErr io.Writer
 	if verbose {
-		// In verbose mode, show output directly
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+		// In verbose mode, show output directly with secrets masked
+		cmdOut, cmdErr = newMaskingWriter(b.stdout()), newMaskingWriter(b.stderr())
 	} else {
 		// In normal mode, capture output for logging
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
+		cmdOut, cmdErr = &stdout, &stderr
 	}
 
-	err := cmd.Run()
+	done := b.Timings.Track(name, "claude remove", TimingClaude)
+	err := runClaude(b.context(), b.runner(), cmdOut, cmdErr, args...)
+	done()
+	flushMasked(cmdOut, cmdErr)
+	b.clearProgress()
 
 	// Write debug output to log file only if not verbose
 	if !verbose && debugLogErr == nil {
 		debugContent := fmt.Sprintf("Command: %s\nExit Code: %v\n\nSTDOUT:\n%s\n\nSTDERR:\n%s\n", 
-			strings.Join(args, " "), err, stdout.String(), stderr.String())
-		ioutil.WriteFile(debugLogPath, []byte(debugContent), 0644)
+			ShellJoin(args), err, stdout.String(), stderr.String())
+		writeDebugLog(debugLogPath, debugContent)
+	}
+
+	if ctxErr := b.context().Err(); ctxErr != nil {
+		return fmt.Errorf("interrupted while removing server '%s': %w", name, ctxErr)
 	}
 
 	// Handle output based on verbose flag and error state
 	if err != nil {
 		if !verbose {
 			// On error, show the full command and stderr
-			fmt.Printf("  Command failed: %s\n", commandStr)
+			fmt.Fprintf(b.stdout(), "  Command failed: %s\n", commandStr)
 			
 			if stderr.Len() > 0 {
-				fmt.Fprintf(os.Stderr, "%s", stderr.String())
+				fmt.Fprintf(b.stderr(), "%s", stderr.String())
 			}
 
 			// Include debug log path in error message if available
-			errorMsg := fmt.Sprintf("failed to remove server '%s' from Claude", name)
+			removeErr := &CommandError{Server: name, Operation: "remove", Err: err}
 			if debugLogErr == nil {
-				errorMsg += fmt.Sprintf("\n\n\033[0;36mℹ Debug log saved to:\033[0m\n  %s\n\033[0;90m  View this file for detailed error information\033[0m", debugLogPath)
+				removeErr.DebugLog = debugLogPath
 			}
-			return fmt.Errorf(errorMsg)
+			return removeErr
 		} else {
 			// In verbose mode, error was already shown
-			return fmt.Errorf("failed to remove server '%s' from Claude", name)
+			return &CommandError{Server: name, Operation: "remove", Err: err}
 		}
 	}
 
@@ -387,7 +647,7 @@ func (b *ClaudeCmdBuilder) StopServer(name string, verbose bool) error {
 			}
 			// Show file modifications with indentation
 			if strings.Contains(line, "File modified:") {
-				fmt.Printf("  %s\n", line)
+				fmt.Fprintf(b.stdout(), "  %s\n", line)
 			}
 		}
 	}
@@ -408,17 +668,48 @@ func (b *ClaudeCmdBuilder) StopAllServers() error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	var names []string
+	for _, name := range cfg.GetServerNames() {
+		server := cfg.MCPServers[name]
+		if claudeName := server.ClaudeName(name); !slices.Contains(names, claudeName) {
+			names = append(names, claudeName)
+		}
+	}
+	return b.StopServers(names)
+}
+
+// StopServers removes the given servers from Claude, skipping any that are not running
+func (b *ClaudeCmdBuilder) StopServers(names []string) error {
 	var errors []error
-	for name := range cfg.MCPServers {
+	var stopped []string
+	interrupted := func(i int) error {
+		b.clearProgress()
+		return fmt.Errorf("interrupted: stopped %v, skipped %v: %w", stopped, names[i:], b.context().Err())
+	}
+	for i, name := range names {
+		if b.context().Err() != nil {
+			return interrupted(i)
+		}
+
 		// Check if this server is in Claude before trying to remove
-		if b.IsRunning(name) {
+		b.step("Checking '%s' (%d/%d)...", name, i+1, len(names))
+		running := b.IsRunning(name)
+		// An interrupted check reads as not running, so this server is skipped too
+		if b.context().Err() != nil {
+			return interrupted(i)
+		}
+		if running {
 			// Use StopServer with verbose=false for reset command
 			if err := b.StopServer(name, false); err != nil {
 				errors = append(errors, err)
+			} else {
+				stopped = append(stopped, name)
 			}
 		}
 	}
 
+	b.clearProgress()
+
 	if len(errors) > 0 {
 		return fmt.Errorf("errors stopping servers: %v", errors)
 	}
@@ -426,12 +717,13 @@ func (b *ClaudeCmdBuilder) StopAllServers() error {
 }
 
 func (b *ClaudeCmdBuilder) IsRunning(name string) bool {
+	if b.Scope != "" {
+		return b.isRegisteredInScope(name)
+	}
 	// Check if server is registered in Claude by running claude mcp get
-	cmd := exec.Command(findClaude(), "mcp", "get", name)
 	// Suppress output
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-	err := cmd.Run()
+	defer b.Timings.Track(name, "claude get", TimingClaude)()
+	err := runClaude(b.context(), b.runner(), nil, nil, "mcp", "get", name)
 	// If the command succeeds, the server exists in Claude
 	return err == nil
 }
@@ -446,14 +738,13 @@ func (b *ClaudeCmdBuilder) IsRunningWithDebugLog(name string, logDebug bool) (bo
 	debugLogPath, debugLogErr := b.createDebugLogFile("check-" + name)
 
 	// Check if server is registered in Claude by running claude mcp get with debug
-	cmd := exec.Command(findClaude(), "mcp", "get", "--debug", name)
-	output, err := cmd.CombinedOutput()
+	output, err := claudeCombinedOutput(b.context(), b.runner(), "mcp", "get", "--debug", name)
 
 	// Log the check if we have a debug log
 	if debugLogErr == nil {
 		debugContent := fmt.Sprintf("Command: claude mcp get --debug %s\nOutput:\n%s\nError: %v\n", 
 			name, string(output), err)
-		ioutil.WriteFile(debugLogPath, []byte(debugContent), 0644)
+		writeDebugLog(debugLogPath, debugContent)
 	}
 
 	logPath := ""
@@ -468,8 +759,7 @@ func (b *ClaudeCmdBuilder) IsRunningWithDebugLog(name string, logDebug bool) (bo
 // GetServerStatuses parses claude mcp list output and returns server statuses
 func (b *ClaudeCmdBuilder) GetServerStatuses(cfg *config.Config) ([]ServerStatus, error) {
 	// Execute claude mcp list and capture output
-	cmd := exec.Command(findClaude(), "mcp", "list")
-	output, err := cmd.CombinedOutput()
+	output, err := claudeCombinedOutput(b.context(), b.runner(), "mcp", "list")
 	if err != nil && len(output) == 0 {
 		return nil, fmt.Errorf("failed to list servers: %w", err)
 	}
@@ -525,16 +815,17 @@ func (b *ClaudeCmdBuilder) GetServerStatuses(cfg *config.Config) ([]ServerStatus
 		}
 		
 		// Check if server is in config
-		inConfig := false
+		configName := ""
 		if cfg != nil {
-			_, inConfig = cfg.MCPServers[serverName]
+			configName = ConfigNameFor(cfg, serverName, command)
 		}
 		
 		servers = append(servers, ServerStatus{
-			Name:     serverName,
-			Command:  command,
-			Status:   status,
-			InConfig: inConfig,
+			Name:       serverName,
+			Command:    command,
+			Status:     status,
+			InConfig:   configName != "",
+			ConfigName: configName,
 		})
 	}
 	
@@ -544,7 +835,7 @@ func (b *ClaudeCmdBuilder) GetServerStatuses(cfg *config.Config) ([]ServerStatus
 // buildStartArgs constructs the arguments for starting a server
 func (b *ClaudeCmdBuilder) buildStartArgs(name string, server *config.MCPServer) []string {
 	// Build the claude mcp add command
-	args := []string{"mcp", "add", name}
+	args := append(append([]string{"mcp", "add"}, b.scopeArgs()...), server.ClaudeName(name))
 
 	// Add environment variables as options
 	if server.Env != nil {
@@ -565,18 +856,18 @@ func (b *ClaudeCmdBuilder) buildStartArgs(name string, server *config.MCPServer)
 func (b *ClaudeCmdBuilder) BuildStartCommand(name string, server *config.MCPServer) string {
 	args := b.buildStartArgs(name, server)
 	// Mask sensitive values in args
-	maskedArgs := MaskSensitiveArgs(args)
-	return fmt.Sprintf("claude %s", strings.Join(maskedArgs, " "))
+	maskedArgs := maskArgs(args, sensitiveKeysOf(server))
+	return "claude " + ShellJoin(maskedArgs)
 }
 
 // BuildStopCommand constructs the command to stop a server without executing it
 func (b *ClaudeCmdBuilder) BuildStopCommand(name string) string {
-	return fmt.Sprintf("claude mcp remove %s", name)
+	return b.ClaudeCommand("remove") + " " + ShellQuote(name)
 }
 
 // BuildListCommand constructs the command to list servers without executing it
 func (b *ClaudeCmdBuilder) BuildListCommand() string {
-	return "claude mcp list"
+	return ShellJoin([]string{b.claudeBin(), "mcp", "list"})
 }
 
 // BuildResetCommands constructs the commands to remove multiple servers without executing them
@@ -590,28 +881,11 @@ func (b *ClaudeCmdBuilder) BuildResetCommands(serverNames []string) []string {
 
 // buildStartArgsJSON constructs the arguments for starting a server using add-json
 func (b *ClaudeCmdBuilder) buildStartArgsJSON(name string, server *config.MCPServer) []string {
-	// Create the JSON structure starting with any extra fields
-	serverJSON := make(map[string]interface{})
-	for k, v := range server.Extra {
-		serverJSON[k] = v
-	}
-
-	// Add known fields (these will override any duplicates in Extra)
-	serverJSON["command"] = server.Command
-	if len(server.Args) > 0 {
-		serverJSON["args"] = server.Args
-	}
-	if len(server.Env) > 0 {
-		serverJSON["env"] = server.Env
-	}
-	if server.Cwd != "" {
-		serverJSON["cwd"] = server.Cwd
-	}
-
-	// Marshal to JSON
-	jsonData, _ := json.Marshal(serverJSON)
+	// Marshal the full definition, including any extra fields
+	jsonData, _ := json.Marshal(server.ClaudeMap())
 
-	return []string{"mcp", "add-json", name, string(jsonData)}
+	args := append([]string{"mcp", "add-json"}, b.scopeArgs()...)
+	return append(args, server.ClaudeName(name), string(jsonData))
 }
 
 // BuildStartCommandJSON constructs the add-json command for display
@@ -621,75 +895,128 @@ func (b *ClaudeCmdBuilder) BuildStartCommandJSON(name string, server *config.MCP
 		return b.buildPrettyJSONCommand(name, server)
 	}
 
-	// Regular JSON with masked values
-	serverJSON := map[string]interface{}{
-		"command": server.Command,
-		"args":    server.Args,
+	// Regular JSON with masked values, matching the payload StartServer sends
+	jsonData, _ := json.Marshal(server.ClaudeMap())
+	maskedJSON, _ := maskJSON(jsonData, sensitiveKeysOf(server))
+
+	return fmt.Sprintf("%s %s %s", b.ClaudeCommand("add-json"), ShellQuote(name), ShellQuote(string(maskedJSON)))
+}
+
+// BuildStartCommandPlain constructs the start command as one uncolored line that can be
+// pasted into a shell and run as is. Placeholders and relative paths are resolved as
+// StartServer does. Instead of being masked, secrets and inherited env values reference
+// shell variables, such as "$GITHUB_TOKEN", which must be set when the command runs.
+func (b *ClaudeCmdBuilder) BuildStartCommandPlain(name string, server *config.MCPServer) string {
+	// A placeholder that cannot be expanded here is left for the reader to fill in
+	if expanded, err := ExpandPlaceholders(name, server); err == nil {
+		server = expanded
+	}
+	server = ResolvePaths(server)
+
+	// Secrets are swapped for markers that survive JSON encoding, then each marker is
+	// replaced by a double-quoted variable outside the single quotes of its word
+	isSensitive := sensitiveKeysOf(server)
+	var variables []string
+	reference := func(variable string) string {
+		variables = append(variables, variable)
+		return fmt.Sprintf("@@cmcp-variable-%d@@", len(variables)-1)
+	}
+	shellReference := func(name string) string {
+		return reference("$" + envName(name))
+	}
+	quote := func(word string) string {
+		if !strings.Contains(word, "@@cmcp-variable-") {
+			return ShellQuote(word)
+		}
+		quoted := "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+		for i, variable := range variables {
+			quoted = strings.Replace(quoted, fmt.Sprintf("@@cmcp-variable-%d@@", i), `'"`+variable+`"'`, 1)
+		}
+		return quoted
 	}
 
-	if len(server.Env) > 0 {
-		serverJSON["env"] = server.Env
+	if !b.UsesAddJSON(server) {
+		args := shareArgs(b.buildStartArgs(name, server), isSensitive, shellReference)
+		words := make([]string, len(args))
+		for i, arg := range args {
+			words[i] = quote(arg)
+		}
+		return "claude " + strings.Join(words, " ")
 	}
 
-	jsonData, _ := json.Marshal(serverJSON)
-	maskedJSON, _ := MaskSensitiveJSON(jsonData)
+	payload := server.ClaudeMap()
+	if len(server.Env) > 0 {
+		env := make(map[string]string, len(server.Env))
+		for key, value := range server.Env {
+			switch {
+			case value == config.InheritEnv:
+				value = reference("$" + key)
+			case isSensitive(key) && value != "":
+				value = reference(getBashVariable(key))
+			}
+			env[key] = value
+		}
+		payload["env"] = env
+	}
+	if len(server.Headers) > 0 {
+		headers := make(map[string]string, len(server.Headers))
+		for key, value := range server.Headers {
+			if isSensitive(key) && value != "" {
+				value = reference("$" + strings.ToUpper(shellVariableUnsafe.ReplaceAllString(key, "_")))
+			}
+			headers[key] = value
+		}
+		payload["headers"] = headers
+	}
+	if args, ok := payload["args"].([]string); ok {
+		payload["args"] = shareArgs(args, isSensitive, shellReference)
+	}
+	if url, ok := payload["url"].(string); ok {
+		payload["url"] = shareInline(url, shellReference)
+	}
 
-	return fmt.Sprintf("claude mcp add-json %s '%s'", name, string(maskedJSON))
+	jsonData, _ := json.Marshal(payload)
+	return fmt.Sprintf("%s %s %s", b.ClaudeCommand("add-json"), ShellQuote(name), quote(string(jsonData)))
 }
 
 // buildPrettyJSONCommand creates a colored, pretty-printed JSON command
 func (b *ClaudeCmdBuilder) buildPrettyJSONCommand(name string, server *config.MCPServer) string {
-	// Create the JSON structure
-	serverJSON := map[string]interface{}{
-		"command": server.Command,
-		"args":    server.Args,
-	}
-
-	if len(server.Env) > 0 {
-		serverJSON["env"] = server.Env
-	}
-
-	// Marshal to JSON for pretty printing
-	jsonData, _ := json.Marshal(serverJSON)
-	prettyJSON, _ := MaskSensitiveJSONPretty(jsonData, "  ")
+	// Marshal the same payload StartServer sends for pretty printing
+	jsonData, _ := json.Marshal(server.ClaudeMap())
+	prettyJSON, _ := maskJSONPretty(jsonData, "  ", sensitiveKeysOf(server))
 
 	// Apply colors
 	lines := strings.Split(prettyJSON, "\n")
 	coloredLines := make([]string, len(lines))
 
-	blue := color.New(color.FgBlue).SprintFunc()
-	// green := color.New(color.FgGreen).SprintFunc() // Reserved for future use
-	yellow := color.New(color.FgYellow).SprintFunc()
-	gray := color.New(color.FgHiBlack).SprintFunc()
-
 	for i, line := range lines {
 		// Color the JSON structure
 		colored := line
 
 		// Color property names (e.g., "command":)
-		colored = strings.ReplaceAll(colored, `"command":`, blue(`"command"`)+gray(":"))
-		colored = strings.ReplaceAll(colored, `"args":`, blue(`"args"`)+gray(":"))
-		colored = strings.ReplaceAll(colored, `"env":`, blue(`"env"`)+gray(":"))
-		colored = strings.ReplaceAll(colored, `"cwd":`, blue(`"cwd"`)+gray(":"))
+		colored = strings.ReplaceAll(colored, `"command":`, ui.Command.Sprint(`"command"`)+ui.Muted.Sprint(":"))
+		colored = strings.ReplaceAll(colored, `"args":`, ui.Command.Sprint(`"args"`)+ui.Muted.Sprint(":"))
+		colored = strings.ReplaceAll(colored, `"env":`, ui.Command.Sprint(`"env"`)+ui.Muted.Sprint(":"))
+		colored = strings.ReplaceAll(colored, `"cwd":`, ui.Command.Sprint(`"cwd"`)+ui.Muted.Sprint(":"))
 
 		// Color environment variable keys
 		for key := range server.Env {
-			colored = strings.ReplaceAll(colored, `"`+key+`":`, blue(`"`+key+`"`)+gray(":"))
+			colored = strings.ReplaceAll(colored, `"`+key+`":`, ui.Command.Sprint(`"`+key+`"`)+ui.Muted.Sprint(":"))
 		}
 
 		// Color bash variables (e.g., $GITHUB_TOKEN)
 		// bashVarRegex := `\$[A-Z_]+[A-Z0-9_]*` // Reserved for regex-based coloring
-		colored = strings.ReplaceAll(colored, "$", yellow("$"))
+		colored = strings.ReplaceAll(colored, "$", ui.Secret.Sprint("$"))
 
 		// Color string values (but not bash variables)
 		// This is a bit tricky, so we'll keep it simple for now
 
 		// Color structural elements
-		colored = strings.ReplaceAll(colored, "{", gray("{"))
-		colored = strings.ReplaceAll(colored, "}", gray("}"))
-		colored = strings.ReplaceAll(colored, "[", gray("["))
-		colored = strings.ReplaceAll(colored, "]", gray("]"))
-		colored = strings.ReplaceAll(colored, ",", gray(","))
+		colored = strings.ReplaceAll(colored, "{", ui.Muted.Sprint("{"))
+		colored = strings.ReplaceAll(colored, "}", ui.Muted.Sprint("}"))
+		colored = strings.ReplaceAll(colored, "[", ui.Muted.Sprint("["))
+		colored = strings.ReplaceAll(colored, "]", ui.Muted.Sprint("]"))
+		colored = strings.ReplaceAll(colored, ",", ui.Muted.Sprint(","))
 
 		coloredLines[i] = colored
 	}
@@ -697,7 +1024,7 @@ func (b *ClaudeCmdBuilder) buildPrettyJSONCommand(name string, server *config.MC
 	// Join the colored lines
 	coloredJSON := strings.Join(coloredLines, "\n")
 
-	return fmt.Sprintf("claude mcp add-json %s '%s'", name, coloredJSON)
+	return fmt.Sprintf("%s %s '%s'", b.ClaudeCommand("add-json"), name, coloredJSON)
 }
 
 // PrintPrettyJSONPublic prints the colored JSON configuration to stdout (public version)
@@ -707,61 +1034,41 @@ func (b *ClaudeCmdBuilder) PrintPrettyJSONPublic(server *config.MCPServer) {
 
 // printPrettyJSON prints the colored JSON configuration to stdout
 func (b *ClaudeCmdBuilder) printPrettyJSON(server *config.MCPServer) {
-	// Create the JSON structure starting with any extra fields
-	serverJSON := make(map[string]interface{})
-	for k, v := range server.Extra {
-		serverJSON[k] = v
-	}
-
-	// Add known fields
-	serverJSON["command"] = server.Command
-	if len(server.Args) > 0 {
-		serverJSON["args"] = server.Args
-	}
-	if len(server.Env) > 0 {
-		serverJSON["env"] = server.Env
-	}
-	if server.Cwd != "" {
-		serverJSON["cwd"] = server.Cwd
-	}
-
-	// Marshal to JSON for pretty printing
-	jsonData, _ := json.Marshal(serverJSON)
-	prettyJSON, _ := MaskSensitiveJSONPretty(jsonData, "  ")
+	// Marshal the full definition, including any extra fields, for pretty printing
+	jsonData, _ := json.Marshal(server.ClaudeMap())
+	prettyJSON, _ := maskJSONPretty(jsonData, "  ", sensitiveKeysOf(server))
 
 	// Apply colors
 	lines := strings.Split(prettyJSON, "\n")
 
-	blue := color.New(color.FgBlue).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
-	gray := color.New(color.FgHiBlack).SprintFunc()
-
 	for _, line := range lines {
 		// Color the JSON structure
 		colored := line
 
 		// Color property names (e.g., "command":)
-		colored = strings.ReplaceAll(colored, `"command":`, blue(`"command"`)+gray(":"))
-		colored = strings.ReplaceAll(colored, `"args":`, blue(`"args"`)+gray(":"))
-		colored = strings.ReplaceAll(colored, `"env":`, blue(`"env"`)+gray(":"))
-		colored = strings.ReplaceAll(colored, `"cwd":`, blue(`"cwd"`)+gray(":"))
+		colored = strings.ReplaceAll(colored, `"command":`, ui.Command.Sprint(`"command"`)+ui.Muted.Sprint(":"))
+		colored = strings.ReplaceAll(colored, `"args":`, ui.Command.Sprint(`"args"`)+ui.Muted.Sprint(":"))
+		colored = strings.ReplaceAll(colored, `"env":`, ui.Command.Sprint(`"env"`)+ui.Muted.Sprint(":"))
+		colored = strings.ReplaceAll(colored, `"cwd":`, ui.Command.Sprint(`"cwd"`)+ui.Muted.Sprint(":"))
+		colored = strings.ReplaceAll(colored, `"type":`, ui.Command.Sprint(`"type"`)+ui.Muted.Sprint(":"))
+		colored = strings.ReplaceAll(colored, `"url":`, ui.Command.Sprint(`"url"`)+ui.Muted.Sprint(":"))
 
 		// Color environment variable keys
 		for key := range server.Env {
-			colored = strings.ReplaceAll(colored, `"`+key+`":`, blue(`"`+key+`"`)+gray(":"))
+			colored = strings.ReplaceAll(colored, `"`+key+`":`, ui.Command.Sprint(`"`+key+`"`)+ui.Muted.Sprint(":"))
 		}
 
 		// Color bash variables (e.g., $GITHUB_TOKEN)
-		colored = strings.ReplaceAll(colored, "$", yellow("$"))
+		colored = strings.ReplaceAll(colored, "$", ui.Secret.Sprint("$"))
 
 		// Color structural elements
-		colored = strings.ReplaceAll(colored, "{", gray("{"))
-		colored = strings.ReplaceAll(colored, "}", gray("}"))
-		colored = strings.ReplaceAll(colored, "[", gray("["))
-		colored = strings.ReplaceAll(colored, "]", gray("]"))
-		colored = strings.ReplaceAll(colored, ",", gray(","))
+		colored = strings.ReplaceAll(colored, "{", ui.Muted.Sprint("{"))
+		colored = strings.ReplaceAll(colored, "}", ui.Muted.Sprint("}"))
+		colored = strings.ReplaceAll(colored, "[", ui.Muted.Sprint("["))
+		colored = strings.ReplaceAll(colored, "]", ui.Muted.Sprint("]"))
+		colored = strings.ReplaceAll(colored, ",", ui.Muted.Sprint(","))
 
-		fmt.Println(colored)
+		fmt.Fprintln(b.stdout(), colored)
 	}
 }
 
@@ -1,9 +1,12 @@
 package mcp
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
-	"cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/config"
 )
 
 func TestBuildStartCommand(t *testing.T) {
@@ -40,7 +43,7 @@ func TestBuildStartCommand(t *testing.T) {
 					"PORT":    "8080",
 				},
 			},
-			expected: "claude mcp add test-server --env API_KEY=*** --env PORT=8080 -- python server.py",
+			expected: "claude mcp add test-server --env 'API_KEY=***' --env PORT=8080 -- python server.py",
 		},
 		{
 			name: "complex command with args and env",
@@ -70,10 +73,10 @@ func TestBuildStartCommand(t *testing.T) {
 				// Check that all env vars are present (with masking for sensitive ones)
 				for k, v := range tt.server.Env {
 					expectedVal := v
-					if isSensitiveKey(k) {
+					if IsSensitiveKey(k) {
 						expectedVal = "***"
 					}
-					envStr := "--env " + k + "=" + expectedVal
+					envStr := "--env " + ShellQuote(k+"="+expectedVal)
 					if !contains(result, envStr) {
 						t.Errorf("Missing environment variable: %s", envStr)
 					}
@@ -301,3 +304,89 @@ func containsAt(s, substr string) bool {
 	}
 	return false
 }
+
+func TestBuildStartArgsJSONOmitsCmcpSettings(t *testing.T) {
+	b := NewClaudeCmdBuilder()
+
+	server := &config.MCPServer{
+		Command:     "npx",
+		Args:        []string{"server"},
+		Env:         map[string]string{"PORT": "8080"},
+		AutoRestart: true,
+		Extra:       map[string]interface{}{"timeout": float64(30)},
+	}
+
+	args := b.buildStartArgsJSON("test-server", server)
+	payload := args[len(args)-1]
+
+	if contains(payload, "autoRestart") {
+		t.Errorf("add-json payload should not include cmcp-only settings, got %v", payload)
+	}
+	if !contains(payload, `"timeout":30`) {
+		t.Errorf("add-json payload should keep unknown fields, got %v", payload)
+	}
+}
+
+func TestVerifyServerStartedCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	b := NewClaudeCmdBuilder()
+	b.Context = ctx
+
+	// A cancelled context must stop before any claude subprocess or retry wait
+	start := time.Now()
+	err := b.VerifyServerStartedVerbose("test", true)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("VerifyServerStartedVerbose() error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("VerifyServerStartedVerbose() took %v after cancellation", elapsed)
+	}
+}
+
+func TestStopServersInterruptedDuringCheck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Ctrl-C lands while checking the first server
+	b := NewClaudeCmdBuilder()
+	b.Context = ctx
+	b.Runner = &fakeRunner{respond: func(args []string) (string, error) {
+		cancel()
+		return "", context.Canceled
+	}}
+
+	err := b.StopServers([]string{"first", "second"})
+	if !errors.Is(err, context.Canceled) || !contains(err.Error(), "skipped [first second]") {
+		t.Errorf("StopServers() error = %v, want both servers reported as skipped", err)
+	}
+}
+
+func TestVerifyServerStartupTimeout(t *testing.T) {
+	defer func(interval time.Duration) { verifyPollInterval = interval }(verifyPollInterval)
+	verifyPollInterval = 10 * time.Millisecond
+
+	// The server reports failed while it warms up, then connects
+	lists := 0
+	runner := &fakeRunner{respond: func(args []string) (string, error) {
+		lists++
+		if lists < 4 {
+			return "slow: docker run img - ✗ Failed to connect\n", nil
+		}
+		return "slow: docker run img - ✓ Connected\n", nil
+	}}
+	b := &ClaudeCmdBuilder{Runner: runner}
+
+	if err := b.verifyServer("slow", 0, false); err == nil {
+		t.Error("verifyServer() without a timeout should fail on the first failed check")
+	}
+
+	lists = 0
+	if err := b.verifyServer("slow", 5*time.Second, false); err != nil {
+		t.Errorf("verifyServer() with a timeout error = %v", err)
+	}
+	if lists != 4 {
+		t.Errorf("verifyServer() listed %d times, want 4", lists)
+	}
+}
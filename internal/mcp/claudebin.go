@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+)
+
+// claudeVersionTimeout bounds how long claude --version may take
+var claudeVersionTimeout = 10 * time.Second
+
+// ClaudeInstall describes a claude installation cmcp can run
+type ClaudeInstall struct {
+	Name    string // Name in the config, or "claude" for the one on PATH
+	Command string // Configured command or path
+	Path    string // Resolved executable; empty when it cannot be found
+	Version string // First line of claude --version
+	Default bool   // Used when --claude-bin is not given
+	Err     error  // Why the installation cannot be run
+}
+
+// InspectClaudeInstalls resolves each named claude command and asks it for its
+// version, in parallel. The result is sorted by name.
+func InspectClaudeInstalls(ctx context.Context, binaries map[string]string, defaultName string) []ClaudeInstall {
+	installs := make([]ClaudeInstall, 0, len(binaries))
+	for name, command := range binaries {
+		installs = append(installs, ClaudeInstall{Name: name, Command: command, Default: name == defaultName})
+	}
+	sort.Slice(installs, func(i, j int) bool { return installs[i].Name < installs[j].Name })
+
+	var wg sync.WaitGroup
+	for i := range installs {
+		wg.Add(1)
+		go func(install *ClaudeInstall) {
+			defer wg.Done()
+			install.Path, install.Version, install.Err = claudeVersion(ctx, install.Command)
+		}(&installs[i])
+	}
+	wg.Wait()
+	return installs
+}
+
+// claudeVersion finds a claude command and returns its path and version
+func claudeVersion(ctx context.Context, command string) (string, string, error) {
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return "", "", claudeUnavailable(command, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, claudeVersionTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path, "--version")
+	cmd.WaitDelay = time.Second
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return path, "", &ClaudeTimeoutError{Command: "--version", Timeout: claudeVersionTimeout}
+		}
+		if line := firstLine(string(output)); line != "" {
+			return path, "", fmt.Errorf("--version failed: %s", line)
+		}
+		return path, "", fmt.Errorf("--version failed: %w", err)
+	}
+	return path, firstLine(string(output)), nil
+}
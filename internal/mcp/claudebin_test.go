@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestInspectClaudeInstalls(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+	dir := t.TempDir()
+	script := func(name, body string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	installs := InspectClaudeInstalls(context.Background(), map[string]string{
+		"stable":  script("claude", `echo "1.0.90 (Claude Code)"`),
+		"canary":  script("claude-canary", `echo "1.1.0-canary.3 (Claude Code)"`),
+		"broken":  script("claude-broken", `echo "node: not found" >&2; exit 127`),
+		"missing": filepath.Join(dir, "nowhere"),
+	}, "canary")
+
+	want := []struct {
+		name, version string
+		isDefault     bool
+		errText       string
+	}{
+		{"broken", "", false, "--version failed: node: not found"},
+		{"canary", "1.1.0-canary.3 (Claude Code)", true, ""},
+		{"missing", "", false, ""},
+		{"stable", "1.0.90 (Claude Code)", false, ""},
+	}
+	if len(installs) != len(want) {
+		t.Fatalf("got %d installs, want %d", len(installs), len(want))
+	}
+	for i, w := range want {
+		got := installs[i]
+		if got.Name != w.name || got.Version != w.version || got.Default != w.isDefault {
+			t.Errorf("installs[%d] = %+v, want %+v", i, got, w)
+		}
+		if w.errText != "" && (got.Err == nil || got.Err.Error() != w.errText) {
+			t.Errorf("installs[%d].Err = %v, want %q", i, got.Err, w.errText)
+		}
+	}
+	if !errors.Is(installs[2].Err, ErrClaudeUnavailable) {
+		t.Errorf("missing binary error = %v, want ErrClaudeUnavailable", installs[2].Err)
+	}
+}
+
+func TestClaudeBinInCommands(t *testing.T) {
+	b := NewClaudeCmdBuilder()
+	b.ClaudeBin = "claude-canary"
+	if got := b.BuildStopCommand("github"); got != "claude-canary mcp remove github" {
+		t.Errorf("BuildStopCommand() = %q", got)
+	}
+	if got := b.PlanStop("github").Argv[0]; got != "claude-canary" {
+		t.Errorf("PlanStop() argv[0] = %q", got)
+	}
+}
@@ -0,0 +1,162 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// claudeLogUnsafe matches the characters Claude replaces in log directory names
+var claudeLogUnsafe = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// claudeCacheDir returns the directory Claude Code keeps its per-project MCP logs in
+func claudeCacheDir() string {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(home, "Library", "Caches", "claude-cli-nodejs")
+	case "windows":
+		if local := os.Getenv("LOCALAPPDATA"); local != "" {
+			return filepath.Join(local, "claude-cli-nodejs", "Cache")
+		}
+		return filepath.Join(home, "AppData", "Local", "claude-cli-nodejs", "Cache")
+	default:
+		if cache := os.Getenv("XDG_CACHE_HOME"); cache != "" {
+			return filepath.Join(cache, "claude-cli-nodejs")
+		}
+		return filepath.Join(home, ".cache", "claude-cli-nodejs")
+	}
+}
+
+// claudeLogDirName returns the name of the directory Claude writes a server's logs to
+func claudeLogDirName(name string) string {
+	return "mcp-logs-" + claudeLogUnsafe.ReplaceAllString(name, "-")
+}
+
+// FindClaudeLogs returns Claude's log files for a server, newest first. Logs of the
+// current project come first; other projects are searched when it has none.
+func FindClaudeLogs(name string) []*DebugLog {
+	cwd, _ := os.Getwd()
+	return findClaudeLogs(claudeCacheDir(), cwd, name)
+}
+
+// FindLatestClaudeLog returns Claude's most recent log file for a server
+func FindLatestClaudeLog(name string) (*DebugLog, bool) {
+	logs := FindClaudeLogs(name)
+	if len(logs) == 0 {
+		return nil, false
+	}
+	return logs[0], true
+}
+
+func findClaudeLogs(cacheDir, projectDir, name string) []*DebugLog {
+	if projectDir != "" {
+		project := claudeLogUnsafe.ReplaceAllString(projectDir, "-")
+		if logs := listLogs(filepath.Join(cacheDir, project, claudeLogDirName(name))); len(logs) > 0 {
+			return logs
+		}
+	}
+
+	projects, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return nil
+	}
+	var logs []*DebugLog
+	for _, project := range projects {
+		if project.IsDir() {
+			logs = append(logs, listLogs(filepath.Join(cacheDir, project.Name(), claudeLogDirName(name)))...)
+		}
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].ModTime.After(logs[j].ModTime) })
+	return logs
+}
+
+// listLogs returns the files in a log directory, newest first
+func listLogs(dir string) []*DebugLog {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var logs []*DebugLog
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		logs = append(logs, &DebugLog{Path: filepath.Join(dir, entry.Name()), ModTime: info.ModTime()})
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].ModTime.After(logs[j].ModTime) })
+	return logs
+}
+
+// ClaudeLogEntry is one message Claude logged for a server
+type ClaudeLogEntry struct {
+	Time    time.Time
+	Level   string // "debug" or "error"
+	Message string
+}
+
+// ReadClaudeLog reads a Claude MCP log file. Claude writes a JSON array of entries
+// ({"debug": ...} or {"error": ...} with a timestamp); JSON lines and plain text are
+// read too, so newer formats still show up.
+func ReadClaudeLog(path string) ([]ClaudeLogEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseClaudeLog(data), nil
+}
+
+func parseClaudeLog(data []byte) []ClaudeLogEntry {
+	var entries []ClaudeLogEntry
+	var array []json.RawMessage
+	if err := json.Unmarshal(data, &array); err == nil {
+		for _, raw := range array {
+			entries = append(entries, parseClaudeLogEntry(raw))
+		}
+		return entries
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+			entries = append(entries, parseClaudeLogEntry(line))
+		}
+	}
+	return entries
+}
+
+func parseClaudeLogEntry(raw []byte) ClaudeLogEntry {
+	var fields struct {
+		Debug     interface{} `json:"debug"`
+		Error     interface{} `json:"error"`
+		Timestamp time.Time   `json:"timestamp"`
+	}
+	if json.Unmarshal(raw, &fields) != nil || (fields.Debug == nil && fields.Error == nil) {
+		return ClaudeLogEntry{Message: strings.TrimSpace(string(raw))}
+	}
+
+	entry := ClaudeLogEntry{Time: fields.Timestamp, Level: "debug"}
+	message := fields.Debug
+	if fields.Error != nil {
+		entry.Level, message = "error", fields.Error
+	}
+	if text, ok := message.(string); ok {
+		entry.Message = text
+	} else {
+		data, _ := json.Marshal(message)
+		entry.Message = string(data)
+	}
+	return entry
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFindClaudeLogs(t *testing.T) {
+	cache := t.TempDir()
+	now := time.Now()
+	write := func(project, server, file string, modTime time.Time) {
+		dir := filepath.Join(cache, project, claudeLogDirName(server))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, file)
+		if err := os.WriteFile(path, []byte("[]"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, modTime, modTime)
+	}
+	write("-home-me-app", "github", "old.txt", now.Add(-time.Hour))
+	write("-home-me-app", "github", "new.txt", now.Add(-time.Minute))
+	write("-home-me-other", "github", "other.txt", now)
+	write("-home-me-other", "my.server", "dotted.txt", now)
+
+	logs := findClaudeLogs(cache, "/home/me/app", "github")
+	if len(logs) != 2 || filepath.Base(logs[0].Path) != "new.txt" {
+		t.Errorf("current project logs = %v, want new.txt then old.txt", logs)
+	}
+
+	logs = findClaudeLogs(cache, "/somewhere/else", "github")
+	if len(logs) != 3 || filepath.Base(logs[0].Path) != "other.txt" {
+		t.Errorf("logs across projects = %v, want the newest first", logs)
+	}
+
+	if logs := findClaudeLogs(cache, "", "my.server"); len(logs) != 1 {
+		t.Errorf("server names should be sanitized like Claude does, got %v", logs)
+	}
+	if logs := findClaudeLogs(cache, "/home/me/app", "missing"); len(logs) != 0 {
+		t.Errorf("unknown server should have no logs, got %v", logs)
+	}
+}
+
+func TestParseClaudeLog(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		levels []string
+		msgs   []string
+	}{
+		{
+			name:   "json array",
+			data:   `[{"debug":"Starting connection","timestamp":"2025-08-07T15:06:25.000Z"},{"error":"Connection failed: spawn npx ENOENT","timestamp":"2025-08-07T15:06:26.000Z"}]`,
+			levels: []string{"debug", "error"},
+			msgs:   []string{"Starting connection", "Connection failed: spawn npx ENOENT"},
+		},
+		{
+			name:   "json lines with structured message",
+			data:   "{\"debug\":{\"code\":1},\"timestamp\":\"2025-08-07T15:06:25.000Z\"}\n\n",
+			levels: []string{"debug"},
+			msgs:   []string{`{"code":1}`},
+		},
+		{
+			name:   "plain text",
+			data:   "server said hi\n",
+			levels: []string{""},
+			msgs:   []string{"server said hi"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := parseClaudeLog([]byte(tt.data))
+			if len(entries) != len(tt.msgs) {
+				t.Fatalf("got %d entries, want %d", len(entries), len(tt.msgs))
+			}
+			for i, entry := range entries {
+				if entry.Level != tt.levels[i] || entry.Message != tt.msgs[i] {
+					t.Errorf("entry %d = %q %q, want %q %q", i, entry.Level, entry.Message, tt.levels[i], tt.msgs[i])
+				}
+				if tt.levels[i] != "" && entry.Time.IsZero() {
+					t.Errorf("entry %d has no time", i)
+				}
+			}
+		})
+	}
+}
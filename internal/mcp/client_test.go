@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+)
+
+// fakeServer runs an in-process MCP server over pipes and returns a client connected to it
+func fakeServer(t *testing.T, handle func(method string, params json.RawMessage) (interface{}, *RPCError)) *Client {
+	t.Helper()
+
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+
+	go func() {
+		scanner := bufio.NewScanner(serverR)
+		for scanner.Scan() {
+			var req struct {
+				ID     *int64          `json:"id"`
+				Method string          `json:"method"`
+				Params json.RawMessage `json:"params"`
+			}
+			if err := json.Unmarshal(scanner.Bytes(), &req); err != nil || req.ID == nil {
+				continue
+			}
+
+			// Interleave a notification to make sure the client skips it
+			serverW.Write([]byte(`{"jsonrpc":"2.0","method":"notifications/message","params":{}}` + "\n"))
+
+			result, rpcErr := handle(req.Method, req.Params)
+			resp := map[string]interface{}{"jsonrpc": "2.0", "id": *req.ID}
+			if rpcErr != nil {
+				resp["error"] = rpcErr
+			} else {
+				resp["result"] = result
+			}
+			data, _ := json.Marshal(resp)
+			serverW.Write(append(data, '\n'))
+		}
+	}()
+
+	t.Cleanup(func() {
+		clientW.Close()
+		serverW.Close()
+	})
+
+	client := newClient(clientR, clientW)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.initialize(ctx); err != nil {
+		t.Fatalf("initialize() error = %v", err)
+	}
+	return client
+}
+
+func basicHandler(method string, params json.RawMessage) (interface{}, *RPCError) {
+	switch method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]interface{}{"name": "fake", "version": "0.0.1"},
+		}, nil
+	case "tools/list":
+		var p struct {
+			Cursor string `json:"cursor"`
+		}
+		json.Unmarshal(params, &p)
+		if p.Cursor == "" {
+			return map[string]interface{}{
+				"tools":      []map[string]interface{}{{"name": "echo", "description": "Echo input"}},
+				"nextCursor": "page2",
+			}, nil
+		}
+		return map[string]interface{}{
+			"tools": []map[string]interface{}{{"name": "add"}},
+		}, nil
+	}
+	return nil, &RPCError{Code: methodNotFound, Message: "method not found"}
+}
+
+func TestClientInitialize(t *testing.T) {
+	client := fakeServer(t, basicHandler)
+
+	if client.Initialize.ServerInfo.Name != "fake" {
+		t.Errorf("ServerInfo.Name = %q, want %q", client.Initialize.ServerInfo.Name, "fake")
+	}
+	if !client.HasCapability("tools") {
+		t.Error("expected tools capability")
+	}
+	if client.HasCapability("prompts") {
+		t.Error("did not expect prompts capability")
+	}
+}
+
+func TestClientListTools(t *testing.T) {
+	client := fakeServer(t, basicHandler)
+
+	tools, err := client.ListTools(context.Background())
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+
+	// Both pages should be returned
+	if len(tools) != 2 || tools[0].Name != "echo" || tools[1].Name != "add" {
+		t.Errorf("ListTools() = %+v, want echo and add", tools)
+	}
+}
+
+func TestClientListUnsupported(t *testing.T) {
+	client := fakeServer(t, basicHandler)
+
+	// Method not found is treated as an empty list
+	prompts, err := client.ListPrompts(context.Background())
+	if err != nil {
+		t.Fatalf("ListPrompts() error = %v", err)
+	}
+	if len(prompts) != 0 {
+		t.Errorf("ListPrompts() = %+v, want empty", prompts)
+	}
+}
+
+func TestClientCallError(t *testing.T) {
+	client := fakeServer(t, basicHandler)
+
+	_, err := client.Call(context.Background(), "bogus/method", nil)
+	rpcErr, ok := err.(*RPCError)
+	if !ok {
+		t.Fatalf("Call() error = %v, want *RPCError", err)
+	}
+	if rpcErr.Code != methodNotFound {
+		t.Errorf("Code = %d, want %d", rpcErr.Code, methodNotFound)
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	client := fakeServer(t, func(method string, params json.RawMessage) (interface{}, *RPCError) {
+		if method == "slow" {
+			time.Sleep(500 * time.Millisecond)
+		}
+		return basicHandler(method, params)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.Call(ctx, "slow", nil); err == nil {
+		t.Error("expected timeout error")
+	}
+}
+
+func TestClientCallTool(t *testing.T) {
+	client := fakeServer(t, func(method string, params json.RawMessage) (interface{}, *RPCError) {
+		if method == "tools/call" {
+			var p struct {
+				Name      string                 `json:"name"`
+				Arguments map[string]interface{} `json:"arguments"`
+			}
+			json.Unmarshal(params, &p)
+			return map[string]interface{}{
+				"content": []map[string]interface{}{{"type": "text", "text": p.Name + ":" + p.Arguments["msg"].(string)}},
+			}, nil
+		}
+		return basicHandler(method, params)
+	})
+
+	result, err := client.CallTool(context.Background(), "echo", map[string]interface{}{"msg": "hi"})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if result.IsError || len(result.Content) != 1 || result.Content[0].Text != "echo:hi" {
+		t.Errorf("CallTool() = %+v, want text echo:hi", result)
+	}
+}
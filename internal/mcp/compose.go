@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// DockerRun holds the parts of a docker run invocation that map onto a compose service
+type DockerRun struct {
+	Image       string
+	Command     []string
+	Env         []string // KEY=VALUE, or KEY to pass the value through
+	EnvFiles    []string
+	Volumes     []string
+	Ports       []string
+	Workdir     string
+	User        string
+	Network     string
+	Entrypoint  string
+	Interactive bool
+	TTY         bool
+}
+
+// ParseDockerRun parses "docker run" arguments. It returns false when the
+// arguments are not a docker run invocation with an image.
+func ParseDockerRun(args []string) (DockerRun, bool) {
+	var run DockerRun
+	i := 0
+	for i < len(args) && args[i] != "run" {
+		i++
+	}
+	if i == len(args) {
+		return run, false
+	}
+
+	for i++; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			run.Image = arg
+			run.Command = args[i+1:]
+			return run, true
+		}
+
+		flag, value, hasValue := strings.Cut(arg, "=")
+		if !hasValue && dockerValueFlags[flag] {
+			if i+1 >= len(args) {
+				break
+			}
+			i++
+			value = args[i]
+		}
+
+		switch flag {
+		case "-e", "--env":
+			run.Env = append(run.Env, value)
+		case "--env-file":
+			run.EnvFiles = append(run.EnvFiles, value)
+		case "-v", "--volume":
+			run.Volumes = append(run.Volumes, value)
+		case "-p", "--publish":
+			run.Ports = append(run.Ports, value)
+		case "-w", "--workdir":
+			run.Workdir = value
+		case "-u", "--user":
+			run.User = value
+		case "--network", "--net":
+			run.Network = value
+		case "--entrypoint":
+			run.Entrypoint = value
+		case "--interactive":
+			run.Interactive = true
+		case "--tty":
+			run.TTY = true
+		default:
+			// Combined short flags such as -it
+			if !strings.HasPrefix(flag, "--") && !dockerValueFlags[flag] {
+				run.Interactive = run.Interactive || strings.Contains(flag, "i")
+				run.TTY = run.TTY || strings.Contains(flag, "t")
+			}
+		}
+	}
+	return run, false
+}
+
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+type composeService struct {
+	Image       string            `yaml:"image"`
+	Entrypoint  string            `yaml:"entrypoint,omitempty"`
+	Command     []string          `yaml:"command,omitempty"`
+	Environment map[string]string `yaml:"environment,omitempty"`
+	EnvFile     []string          `yaml:"env_file,omitempty"`
+	Volumes     []string          `yaml:"volumes,omitempty"`
+	Ports       []string          `yaml:"ports,omitempty"`
+	WorkingDir  string            `yaml:"working_dir,omitempty"`
+	User        string            `yaml:"user,omitempty"`
+	NetworkMode string            `yaml:"network_mode,omitempty"`
+	StdinOpen   bool              `yaml:"stdin_open,omitempty"`
+	TTY         bool              `yaml:"tty,omitempty"`
+}
+
+var invalidServiceChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// ComposeServiceName converts a server name into a valid compose service name
+func ComposeServiceName(name string) string {
+	return strings.Trim(invalidServiceChars.ReplaceAllString(strings.ToLower(name), "-"), "-_")
+}
+
+// ExportCompose builds a docker-compose.yml with one service per docker-launched
+// server. Sensitive env values are written as ${KEY} references so secrets stay in
+// the environment or a .env file. Servers not launched with docker run are returned
+// as skipped.
+func ExportCompose(servers map[string]config.MCPServer) ([]byte, []string, error) {
+	file := composeFile{Services: make(map[string]composeService)}
+	var skipped []string
+
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		server := servers[name]
+		run, ok := ParseDockerRun(server.Args)
+		if server.Command != "docker" || !ok {
+			skipped = append(skipped, name)
+			continue
+		}
+
+		service := composeService{
+			Image:       run.Image,
+			Entrypoint:  run.Entrypoint,
+			Command:     run.Command,
+			EnvFile:     run.EnvFiles,
+			Volumes:     run.Volumes,
+			Ports:       run.Ports,
+			WorkingDir:  run.Workdir,
+			User:        run.User,
+			NetworkMode: run.Network,
+			StdinOpen:   run.Interactive,
+			TTY:         run.TTY,
+		}
+
+		for _, env := range run.Env {
+			key, value, literal := strings.Cut(env, "=")
+			if !literal {
+				// -e KEY takes the value from the server's env
+				value = server.Env[key]
+			}
+			if value == config.InheritEnv || IsSensitiveServerKey(&server, key) && value != "" {
+				value = "${" + key + "}"
+			}
+			if service.Environment == nil {
+				service.Environment = make(map[string]string)
+			}
+			service.Environment[key] = value
+		}
+
+		file.Services[ComposeServiceName(name)] = service
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(file); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), skipped, nil
+}
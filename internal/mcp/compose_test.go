@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseDockerRun(t *testing.T) {
+	args := []string{"run", "-it", "--rm", "-e", "GITHUB_TOKEN", "--env=LOG=debug",
+		"-v", "/data:/data:ro", "-p", "8080:80", "--workdir", "/app",
+		"ghcr.io/github/github-mcp-server", "stdio", "--read-only"}
+
+	run, ok := ParseDockerRun(args)
+	if !ok {
+		t.Fatal("ParseDockerRun() = false, want true")
+	}
+	if run.Image != "ghcr.io/github/github-mcp-server" {
+		t.Errorf("Image = %q", run.Image)
+	}
+	if !reflect.DeepEqual(run.Command, []string{"stdio", "--read-only"}) {
+		t.Errorf("Command = %v", run.Command)
+	}
+	if !reflect.DeepEqual(run.Env, []string{"GITHUB_TOKEN", "LOG=debug"}) {
+		t.Errorf("Env = %v", run.Env)
+	}
+	if !reflect.DeepEqual(run.Volumes, []string{"/data:/data:ro"}) || !reflect.DeepEqual(run.Ports, []string{"8080:80"}) {
+		t.Errorf("Volumes = %v, Ports = %v", run.Volumes, run.Ports)
+	}
+	if run.Workdir != "/app" || !run.Interactive || !run.TTY {
+		t.Errorf("Workdir = %q, Interactive = %v, TTY = %v", run.Workdir, run.Interactive, run.TTY)
+	}
+
+	for _, args := range [][]string{{"-y", "pkg"}, {"run", "--rm", "-i"}} {
+		if _, ok := ParseDockerRun(args); ok {
+			t.Errorf("ParseDockerRun(%v) = true, want false", args)
+		}
+	}
+}
+
+func TestExportCompose(t *testing.T) {
+	servers := map[string]config.MCPServer{
+		"GitHub.MCP": {
+			Command: "docker",
+			Args:    []string{"run", "-i", "--rm", "-e", "GITHUB_TOKEN", "-e", "REGION=eu", "ghcr.io/github/github-mcp-server"},
+			Env:     map[string]string{"GITHUB_TOKEN": "ghp_secret"},
+		},
+		"fs": {Command: "npx", Args: []string{"-y", "server-filesystem"}},
+	}
+
+	data, skipped, err := ExportCompose(servers)
+	if err != nil {
+		t.Fatalf("ExportCompose() error = %v", err)
+	}
+	if !reflect.DeepEqual(skipped, []string{"fs"}) {
+		t.Errorf("skipped = %v, want [fs]", skipped)
+	}
+	if strings.Contains(string(data), "ghp_secret") {
+		t.Errorf("secret value was exported:\n%s", data)
+	}
+
+	var file composeFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		t.Fatalf("invalid YAML: %v", err)
+	}
+	service, ok := file.Services["github-mcp"]
+	if !ok {
+		t.Fatalf("services = %v, want github-mcp", file.Services)
+	}
+	if service.Image != "ghcr.io/github/github-mcp-server" || !service.StdinOpen {
+		t.Errorf("service = %+v", service)
+	}
+	want := map[string]string{"GITHUB_TOKEN": "${GITHUB_TOKEN}", "REGION": "eu"}
+	if !reflect.DeepEqual(service.Environment, want) {
+		t.Errorf("Environment = %v, want %v", service.Environment, want)
+	}
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// Conflict is a configured server whose name is registered in Claude with a different definition
+type Conflict struct {
+	Name       string
+	Scope      string
+	Configured string
+	Registered string
+}
+
+// describeServer returns a configured server's launch command or URL in Claude's format
+func describeServer(server *config.MCPServer) string {
+	if server.IsRemote() {
+		return server.URL
+	}
+	return strings.TrimSpace(server.Command + " " + strings.Join(server.Args, " "))
+}
+
+// Matches reports whether Claude's definition launches the configured server with
+// the same command or URL, once its placeholders and relative paths are resolved as
+// a start resolves them. Env values are not compared.
+func (d *ClaudeDefinition) Matches(name string, server *config.MCPServer) bool {
+	return launchString(name, server) == d.Describe()
+}
+
+// checkConflict compares a configured server with Claude's registered definition
+func checkConflict(name string, server *config.MCPServer, def *ClaudeDefinition) *Conflict {
+	if def.Matches(name, server) {
+		return nil
+	}
+	return &Conflict{
+		Name:       name,
+		Scope:      def.Scope,
+		Configured: launchString(name, server),
+		Registered: def.Describe(),
+	}
+}
+
+// FindConflicts checks the named servers against the active Claude scope and returns
+// those registered there with a different command or URL. Env values are not compared.
+func (b *ClaudeCmdBuilder) FindConflicts(cfg *config.Config, names []string) []Conflict {
+	var conflicts []Conflict
+	for _, name := range names {
+		server, exists := cfg.FindServer(name)
+		if !exists {
+			continue
+		}
+		def := b.GetClaudeDefinition(server.ClaudeName(name))
+		if def == nil {
+			continue
+		}
+		if conflict := checkConflict(name, server, def); conflict != nil {
+			conflicts = append(conflicts, *conflict)
+		}
+	}
+	return conflicts
+}
+
+// Outdated reports whether the server is registered in the builder's scope with a
+// different command, URL or set of env variables than the config gives it, so that
+// starting it again would change what Claude runs. Env values are not compared.
+func (b *ClaudeCmdBuilder) Outdated(name string, server *config.MCPServer) bool {
+	return b.Drift(name, server) != nil
+}
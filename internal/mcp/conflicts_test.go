@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestCheckConflict(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   config.MCPServer
+		def      ClaudeDefinition
+		conflict bool
+	}{
+		{
+			name:   "same command",
+			server: config.MCPServer{Command: "npx", Args: []string{"-y", "pkg"}},
+			def:    ClaudeDefinition{Command: "npx", Args: "-y pkg"},
+		},
+		{
+			name:     "different args",
+			server:   config.MCPServer{Command: "npx", Args: []string{"-y", "pkg@2"}},
+			def:      ClaudeDefinition{Command: "npx", Args: "-y pkg"},
+			conflict: true,
+		},
+		{
+			name:   "same url",
+			server: config.MCPServer{Type: "http", URL: "https://example.com/mcp"},
+			def:    ClaudeDefinition{Type: "http", URL: "https://example.com/mcp"},
+		},
+		{
+			name:   "placeholder and relative path resolved",
+			server: config.MCPServer{Command: "./bin/server", Args: []string{"--name", "{{serverName}}"}, BaseDir: "/work/app"},
+			def:    ClaudeDefinition{Command: "/work/app/bin/server", Args: "--name srv"},
+		},
+		{
+			name:     "remote replaced by local",
+			server:   config.MCPServer{Command: "docker", Args: []string{"run", "img"}},
+			def:      ClaudeDefinition{Type: "http", URL: "https://example.com/mcp"},
+			conflict: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkConflict("srv", &tt.server, &tt.def)
+			if (got != nil) != tt.conflict {
+				t.Errorf("checkConflict() = %+v, want conflict %v", got, tt.conflict)
+			}
+		})
+	}
+}
+
+func TestFindConflicts(t *testing.T) {
+	runner := &fakeRunner{respond: func(args []string) (string, error) {
+		if args[2] == "github" {
+			return "github:\n  Scope: Local config\n  Type: stdio\n  Command: npx\n  Args: -y old\n", nil
+		}
+		return "No MCP server found with name: " + args[2], errors.New("exit status 1")
+	}}
+	b := &ClaudeCmdBuilder{Runner: runner}
+	cfg := &config.Config{MCPServers: map[string]config.MCPServer{
+		"github": {Command: "npx", Args: []string{"-y", "new"}},
+		"fetch":  {Command: "uvx", Args: []string{"mcp-server-fetch"}},
+	}}
+
+	conflicts := b.FindConflicts(cfg, []string{"github", "fetch", "missing"})
+	if len(conflicts) != 1 || conflicts[0].Name != "github" || conflicts[0].Registered != "npx -y old" {
+		t.Errorf("FindConflicts() = %+v, want github conflict", conflicts)
+	}
+}
+
+func TestOutdated(t *testing.T) {
+	runner := &fakeRunner{respond: func(args []string) (string, error) {
+		switch args[2] {
+		case "github":
+			return "github:\n  Scope: Local config\n  Type: stdio\n  Command: npx\n  Args: -y server-github\n  Environment:\n    GITHUB_TOKEN=ghp_x\n", nil
+		case "docs":
+			return "docs:\n  Scope: User config\n  Type: http\n  URL: https://example.com/mcp\n", nil
+		}
+		return "No MCP server found with name: " + args[2], errors.New("exit status 1")
+	}}
+
+	tests := []struct {
+		name     string
+		server   string
+		scope    string
+		def      config.MCPServer
+		outdated bool
+	}{
+		{
+			name:   "same definition",
+			server: "github",
+			def:    config.MCPServer{Command: "npx", Args: []string{"-y", "server-github"}, Env: map[string]string{"GITHUB_TOKEN": "ghp_y"}},
+		},
+		{
+			name:     "args changed",
+			server:   "github",
+			def:      config.MCPServer{Command: "npx", Args: []string{"-y", "server-github@2"}, Env: map[string]string{"GITHUB_TOKEN": "ghp_x"}},
+			outdated: true,
+		},
+		{
+			name:     "env variable added",
+			server:   "github",
+			def:      config.MCPServer{Command: "npx", Args: []string{"-y", "server-github"}, Env: map[string]string{"GITHUB_TOKEN": "ghp_x", "GITHUB_HOST": "ghe"}},
+			outdated: true,
+		},
+		{
+			name:   "placeholder expanded before comparing",
+			server: "github",
+			def:    config.MCPServer{Command: "npx", Args: []string{"-y", "server-{{serverName}}"}, Env: map[string]string{"GITHUB_TOKEN": "ghp_x"}},
+		},
+		{
+			name:     "url changed",
+			server:   "docs",
+			def:      config.MCPServer{Type: "http", URL: "https://example.com/v2/mcp"},
+			outdated: true,
+		},
+		{
+			name:   "registered in another scope",
+			server: "docs",
+			scope:  "local",
+			def:    config.MCPServer{Type: "http", URL: "https://example.com/v2/mcp"},
+		},
+		{
+			name:   "not registered",
+			server: "fetch",
+			def:    config.MCPServer{Command: "uvx"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &ClaudeCmdBuilder{Runner: runner, Scope: tt.scope}
+			if got := b.Outdated(tt.server, &tt.def); got != tt.outdated {
+				t.Errorf("Outdated() = %v, want %v", got, tt.outdated)
+			}
+		})
+	}
+}
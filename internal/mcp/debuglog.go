@@ -0,0 +1,181 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// debugLogOperations are the operation prefixes used in debug log file names
+var debugLogOperations = []string{"start", "verify", "stop", "check"}
+
+// debugLogDir returns the directory debug logs are written to
+func debugLogDir() string {
+	return filepath.Join(os.TempDir(), "cmcp-debug")
+}
+
+// Default debug log limits, overridden by debugLogs in the config file
+const (
+	defaultDebugLogMaxFileKB  = 256
+	defaultDebugLogMaxTotalKB = 10 * 1024
+)
+
+// debugLogLimits returns the maximum size of one debug log and of all of them, in bytes
+func debugLogLimits() (int, int64) {
+	maxFile, maxTotal := defaultDebugLogMaxFileKB, defaultDebugLogMaxTotalKB
+	if cfg, err := config.Load(); err == nil && cfg.DebugLogs != nil {
+		if cfg.DebugLogs.MaxFileKB > 0 {
+			maxFile = cfg.DebugLogs.MaxFileKB
+		}
+		if cfg.DebugLogs.MaxTotalKB > 0 {
+			maxTotal = cfg.DebugLogs.MaxTotalKB
+		}
+	}
+	return maxFile * 1024, int64(maxTotal) * 1024
+}
+
+// writeDebugLog writes a debug log, truncating the middle of content over the size limit
+func writeDebugLog(path, content string) {
+	maxFile, _ := debugLogLimits()
+	os.WriteFile(path, []byte(truncateMiddle(content, maxFile)), 0644)
+}
+
+// appendDebugLog adds to a debug log, keeping the whole file within the size limit
+func appendDebugLog(path, content string) {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	writeDebugLog(path, string(existing)+content)
+}
+
+// truncateMiddle shortens text to about max bytes, keeping its beginning and end since
+// a huge stderr dump usually has the command at the top and the real error at the bottom
+func truncateMiddle(text string, max int) string {
+	if max <= 0 || len(text) <= max {
+		return text
+	}
+	head := text[:max/2]
+	tail := text[len(text)-max/2:]
+	// Cut at line boundaries when there are any
+	if i := strings.LastIndexByte(head, '\n'); i > 0 {
+		head = head[:i+1]
+	}
+	if i := strings.IndexByte(tail, '\n'); i >= 0 && i < len(tail)-1 {
+		tail = tail[i+1:]
+	}
+	return fmt.Sprintf("%s\n... [%d bytes truncated] ...\n\n%s", head, len(text)-len(head)-len(tail), tail)
+}
+
+// rotateDebugLogs removes the oldest debug logs in dir until they fit in maxTotal bytes,
+// always keeping the one named keep
+func rotateDebugLogs(dir string, maxTotal int64, keep string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var logs []os.FileInfo
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "cmcp-") || !strings.HasSuffix(entry.Name(), ".log") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && info.Mode().IsRegular() {
+			logs = append(logs, info)
+		}
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].ModTime().After(logs[j].ModTime()) })
+
+	var total int64
+	for _, info := range logs {
+		total += info.Size()
+		if total > maxTotal && info.Name() != keep {
+			os.Remove(filepath.Join(dir, info.Name()))
+			total -= info.Size()
+		}
+	}
+}
+
+// DebugLog is a debug log file written for a server
+type DebugLog struct {
+	Path    string
+	ModTime time.Time
+}
+
+// FindLatestDebugLog returns the most recent debug log written for a server
+func FindLatestDebugLog(name string) (*DebugLog, bool) {
+	return findLatestDebugLog(debugLogDir(), name)
+}
+
+func findLatestDebugLog(dir, name string) (*DebugLog, bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, false
+	}
+
+	var latest *DebugLog
+	for _, entry := range entries {
+		if !isDebugLogFor(entry.Name(), name) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if latest == nil || info.ModTime().After(latest.ModTime) {
+			latest = &DebugLog{Path: filepath.Join(dir, entry.Name()), ModTime: info.ModTime()}
+		}
+	}
+	return latest, latest != nil
+}
+
+// isDebugLogFor reports whether a file name like "cmcp-start-github-20250807-150625.log"
+// belongs to the server, without matching servers whose names merely end the same way
+func isDebugLogFor(filename, name string) bool {
+	const timestampLen = len("-20060102-150405")
+
+	base := strings.TrimSuffix(filename, ".log")
+	if !strings.HasPrefix(base, "cmcp-") || base == filename || len(base) < len("cmcp-")+timestampLen {
+		return false
+	}
+	rest := strings.TrimPrefix(base[:len(base)-timestampLen], "cmcp-")
+
+	for _, operation := range debugLogOperations {
+		if rest == operation+"-"+name {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveDebugLogs deletes every debug log written for a server and returns how many there were
+func RemoveDebugLogs(name string) (int, error) {
+	return removeDebugLogs(debugLogDir(), name)
+}
+
+func removeDebugLogs(dir, name string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if !isDebugLogFor(entry.Name(), name) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
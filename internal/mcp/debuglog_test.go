@@ -0,0 +1,186 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestIsDebugLogFor(t *testing.T) {
+	tests := []struct {
+		filename string
+		name     string
+		want     bool
+	}{
+		{"cmcp-start-github-20250807-150625.log", "github", true},
+		{"cmcp-verify-github-20250807-150625.log", "github", true},
+		{"cmcp-start-my-github-20250807-150625.log", "github", false},
+		{"cmcp-start-my-github-20250807-150625.log", "my-github", true},
+		{"cmcp-start-github-20250807-150625.txt", "github", false},
+		{"cmcp-reset-github-20250807-150625.log", "github", false},
+	}
+
+	for _, tt := range tests {
+		if got := isDebugLogFor(tt.filename, tt.name); got != tt.want {
+			t.Errorf("isDebugLogFor(%q, %q) = %v, want %v", tt.filename, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFindLatestDebugLog(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	files := map[string]time.Time{
+		"cmcp-start-github-20250807-150625.log":  now.Add(-2 * time.Hour),
+		"cmcp-verify-github-20250807-170625.log": now.Add(-time.Hour),
+		"cmcp-start-other-20250807-180625.log":   now,
+	}
+	for name, modTime := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, modTime, modTime)
+	}
+
+	log, ok := findLatestDebugLog(dir, "github")
+	if !ok || filepath.Base(log.Path) != "cmcp-verify-github-20250807-170625.log" {
+		t.Errorf("findLatestDebugLog() = %+v, %v", log, ok)
+	}
+	if _, ok := findLatestDebugLog(dir, "missing"); ok {
+		t.Error("findLatestDebugLog() should not find logs for unknown servers")
+	}
+}
+
+func TestTruncateMiddle(t *testing.T) {
+	long := strings.Repeat("noise line\n", 100)
+	tests := []struct {
+		name    string
+		text    string
+		max     int
+		want    string   // Exact result, when set
+		keeps   []string // Substrings the result must keep
+		maxSize int
+	}{
+		{name: "short text is unchanged", text: "hello\n", max: 100, want: "hello\n"},
+		{name: "no limit", text: long, max: 0, want: long},
+		{
+			name:    "keeps head and tail lines",
+			text:    "Command: claude mcp add\n" + long + "Error: spawn ENOENT\n",
+			max:     200,
+			keeps:   []string{"Command: claude mcp add\n", "Error: spawn ENOENT\n", "bytes truncated"},
+			maxSize: 260,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateMiddle(tt.text, tt.max)
+			if tt.want != "" && got != tt.want {
+				t.Errorf("truncateMiddle() = %q, want %q", got, tt.want)
+			}
+			for _, keep := range tt.keeps {
+				if !strings.Contains(got, keep) {
+					t.Errorf("truncateMiddle() lost %q: %q", keep, got)
+				}
+			}
+			if tt.maxSize > 0 && len(got) > tt.maxSize {
+				t.Errorf("truncateMiddle() is %d bytes, want at most %d", len(got), tt.maxSize)
+			}
+		})
+	}
+}
+
+func TestRotateDebugLogs(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	files := []struct {
+		name string
+		age  time.Duration
+	}{
+		{"cmcp-start-a-20250807-150625.log", 4 * time.Hour},
+		{"cmcp-start-b-20250807-160625.log", 3 * time.Hour},
+		{"cmcp-start-c-20250807-170625.log", 2 * time.Hour},
+		{"cmcp-start-d-20250807-180625.log", time.Hour},
+		{"unrelated.txt", 5 * time.Hour},
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if err := os.WriteFile(path, make([]byte, 100), 0644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, now.Add(-f.age), now.Add(-f.age))
+	}
+
+	// The oldest log is kept when asked to, even though it doesn't fit
+	rotateDebugLogs(dir, 250, "cmcp-start-a-20250807-150625.log")
+
+	for _, f := range files {
+		_, err := os.Stat(filepath.Join(dir, f.name))
+		kept := err == nil
+		wantKept := f.name != "cmcp-start-b-20250807-160625.log"
+		if kept != wantKept {
+			t.Errorf("%s kept = %v, want %v", f.name, kept, wantKept)
+		}
+	}
+}
+
+func TestDebugLogLimitsFromConfig(t *testing.T) {
+	orig, _ := config.GetConfigPath()
+	path := filepath.Join(t.TempDir(), "config.json")
+	config.SetConfigPath(path)
+	defer config.SetConfigPath(orig)
+
+	if err := os.WriteFile(path, []byte(`{"mcpServers": {}, "debugLogs": {"maxFileKB": 4}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	maxFile, maxTotal := debugLogLimits()
+	if maxFile != 4*1024 || maxTotal != defaultDebugLogMaxTotalKB*1024 {
+		t.Errorf("debugLogLimits() = %d, %d", maxFile, maxTotal)
+	}
+
+	log := filepath.Join(t.TempDir(), "cmcp-start-x.log")
+	writeDebugLog(log, strings.Repeat("x", 3*1024))
+	appendDebugLog(log, strings.Repeat("y", 3*1024))
+	data, _ := os.ReadFile(log)
+	if len(data) > 4*1024+100 || !strings.HasPrefix(string(data), "xxx") || !strings.HasSuffix(string(data), "yyy") {
+		t.Errorf("appended log is %d bytes and should keep its head and tail", len(data))
+	}
+}
+
+func TestRemoveDebugLogs(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{
+		"cmcp-start-github-20250807-150625.log",
+		"cmcp-stop-github-20250807-160625.log",
+		"cmcp-start-my-github-20250807-170625.log",
+		"unrelated.txt",
+	}
+	for _, name := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := removeDebugLogs(dir, "github")
+	if err != nil {
+		t.Fatalf("removeDebugLogs() error = %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("removeDebugLogs() = %d, want 2", removed)
+	}
+	for i, name := range files {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if exists := err == nil; exists != (i >= 2) {
+			t.Errorf("%s exists = %v, want %v", name, exists, i >= 2)
+		}
+	}
+
+	if removed, err := removeDebugLogs(filepath.Join(dir, "missing"), "github"); err != nil || removed != 0 {
+		t.Errorf("removeDebugLogs() on a missing dir = %d, %v, want 0, nil", removed, err)
+	}
+}
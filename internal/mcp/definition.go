@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"strings"
+	"sync"
+)
+
+// ClaudeDefinition is what 'claude mcp get' reports for a registered server
+type ClaudeDefinition struct {
+	Scope   string
+	Type    string
+	Command string
+	Args    string
+	URL     string
+	EnvKeys []string // Names of the environment variables; values are never kept
+}
+
+// Describe returns the launch command or URL in the form Claude displays it
+func (d *ClaudeDefinition) Describe() string {
+	if d.URL != "" {
+		return d.URL
+	}
+	return strings.TrimSpace(d.Command + " " + d.Args)
+}
+
+// parseClaudeGet extracts the definition from 'claude mcp get' output
+func parseClaudeGet(output string) *ClaudeDefinition {
+	def := &ClaudeDefinition{}
+	inEnv := false
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		// Environment entries are indented KEY=value lines below "Environment:"
+		if inEnv && strings.HasPrefix(line, "    ") {
+			if key, _, found := strings.Cut(trimmed, "="); found {
+				def.EnvKeys = append(def.EnvKeys, key)
+				continue
+			}
+		}
+		inEnv = false
+
+		key, value, found := strings.Cut(trimmed, ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Scope":
+			def.Scope = value
+		case "Type":
+			def.Type = value
+		case "Command":
+			def.Command = value
+		case "Args":
+			def.Args = value
+		case "URL":
+			def.URL = value
+		case "Environment":
+			inEnv = true
+		}
+	}
+	return def
+}
+
+// GetClaudeDefinition returns the server registered in Claude under name, or nil if there is none
+func (b *ClaudeCmdBuilder) GetClaudeDefinition(name string) *ClaudeDefinition {
+	output, err := claudeOutput(b.context(), b.runner(), "mcp", "get", name)
+	if err != nil {
+		return nil
+	}
+	return parseClaudeGet(string(output))
+}
+
+// GetClaudeDefinitions looks up several servers concurrently. Servers that are
+// not registered in Claude are missing from the result.
+func (b *ClaudeCmdBuilder) GetClaudeDefinitions(names []string) map[string]*ClaudeDefinition {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	definitions := make(map[string]*ClaudeDefinition, len(names))
+
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if def := b.GetClaudeDefinition(name); def != nil {
+				mu.Lock()
+				definitions[name] = def
+				mu.Unlock()
+			}
+		}(name)
+	}
+	wg.Wait()
+	return definitions
+}
@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseClaudeGet(t *testing.T) {
+	output := `github:
+  Scope: Local config (private to you in this project)
+  Status: ✓ Connected
+  Type: stdio
+  Command: npx
+  Args: -y @modelcontextprotocol/server-github
+  Environment:
+    GITHUB_TOKEN=abc:def
+    LOG_LEVEL=debug
+
+To remove this server, run: claude mcp remove "github" -s local
+`
+	def := parseClaudeGet(output)
+	if def.Scope != "Local config (private to you in this project)" || def.Type != "stdio" {
+		t.Errorf("parseClaudeGet() scope/type = %q/%q", def.Scope, def.Type)
+	}
+	if got := def.Describe(); got != "npx -y @modelcontextprotocol/server-github" {
+		t.Errorf("Describe() = %q", got)
+	}
+	if !reflect.DeepEqual(def.EnvKeys, []string{"GITHUB_TOKEN", "LOG_LEVEL"}) {
+		t.Errorf("EnvKeys = %v", def.EnvKeys)
+	}
+}
+
+func TestGetClaudeDefinitions(t *testing.T) {
+	runner := &fakeRunner{respond: func(args []string) (string, error) {
+		switch args[2] {
+		case "github":
+			return "github:\n  Scope: User config\n  Type: stdio\n  Command: npx\n  Args: pkg\n", nil
+		case "remote":
+			return "remote:\n  Scope: Project config\n  Type: http\n  URL: https://example.com/mcp\n", nil
+		}
+		return "", errors.New("exit status 1")
+	}}
+	b := &ClaudeCmdBuilder{Runner: runner}
+
+	defs := b.GetClaudeDefinitions([]string{"github", "remote", "missing"})
+	if len(defs) != 2 {
+		t.Fatalf("GetClaudeDefinitions() = %v, want 2 entries", defs)
+	}
+	if defs["remote"].Describe() != "https://example.com/mcp" || defs["github"].Scope != "User config" {
+		t.Errorf("GetClaudeDefinitions() = %+v, %+v", defs["github"], defs["remote"])
+	}
+}
@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/registry"
 )
 
 // DiagnosticInfo contains detailed information about a server failure
@@ -23,6 +28,33 @@ type DiagnosticInfo struct {
 
 // GetServerDiagnostics attempts to gather diagnostic information for a failed server
 func GetServerDiagnostics(name string, cmd string, args []string) (*DiagnosticInfo, error) {
+	return getServerDiagnostics(context.Background(), execRunner{}, name, cmd, args, "")
+}
+
+// Diagnose gathers diagnostic information for a server using the builder's runner and context
+func (b *ClaudeCmdBuilder) Diagnose(name string, server *config.MCPServer) (*DiagnosticInfo, error) {
+	// Look at what Claude launched, from the directory it launched it in
+	launched := server
+	if expanded, err := ExpandPlaceholders(name, server); err == nil {
+		launched = expanded
+	}
+	launched = ResolvePaths(launched)
+	diag, err := getServerDiagnostics(b.context(), b.runner(), server.ClaudeName(name), launched.Command, launched.Args, launched.Cwd)
+	if diag != nil && server.IsRemote() {
+		diag.Suggestions = append(diag.Suggestions, getDiagnosticsForProxy(server)...)
+		if server.TLS != nil {
+			diag.Suggestions = append(diag.Suggestions, getDiagnosticsForTLS(server.TLS)...)
+		}
+	}
+	if diag != nil {
+		if log, ok := FindLatestClaudeLog(server.ClaudeName(name)); ok {
+			diag.Suggestions = append(diag.Suggestions, fmt.Sprintf("Check Claude's own log for the server: %s (or run 'cmcp logs --claude %s')", log.Path, name))
+		}
+	}
+	return diag, err
+}
+
+func getServerDiagnostics(ctx context.Context, runner CommandRunner, name string, cmd string, args []string, dir string) (*DiagnosticInfo, error) {
 	diag := &DiagnosticInfo{
 		ServerName:  name,
 		Command:     cmd,
@@ -31,8 +63,7 @@ func GetServerDiagnostics(name string, cmd string, args []string) (*DiagnosticIn
 	}
 
 	// First, check if the server exists in Claude's config
-	getCmd := exec.Command(findClaude(), "mcp", "get", name)
-	_, getErr := getCmd.CombinedOutput()
+	_, getErr := claudeCombinedOutput(ctx, runner, "mcp", "get", name)
 	if getErr != nil {
 		diag.Error = fmt.Errorf("server not found in Claude config: %v", getErr)
 		diag.Suggestions = append(diag.Suggestions, "Server may not be properly registered with Claude")
@@ -40,8 +71,7 @@ func GetServerDiagnostics(name string, cmd string, args []string) (*DiagnosticIn
 	}
 
 	// Parse the health check info from claude mcp list
-	listCmd := exec.Command(findClaude(), "mcp", "list")
-	listOut, listErr := listCmd.CombinedOutput()
+	listOut, listErr := claudeCombinedOutput(ctx, runner, "mcp", "list")
 	if listErr == nil {
 		lines := strings.Split(string(listOut), "\n")
 		for _, line := range lines {
@@ -60,6 +90,18 @@ func GetServerDiagnostics(name string, cmd string, args []string) (*DiagnosticIn
 		diag.Suggestions = append(diag.Suggestions, getDiagnosticsForNode(cmd, args)...)
 	} else if cmd == "python" || cmd == "python3" {
 		diag.Suggestions = append(diag.Suggestions, getDiagnosticsForPython(cmd, args)...)
+	} else if cmd == "uv" || cmd == "uvx" || cmd == "pipx" {
+		diag.Suggestions = append(diag.Suggestions, getDiagnosticsForUV(cmd, args, dir)...)
+	} else if cmd == "deno" {
+		diag.Suggestions = append(diag.Suggestions, getDiagnosticsForDeno(args, dir)...)
+	} else if cmd == "bun" || cmd == "bunx" {
+		diag.Suggestions = append(diag.Suggestions, getDiagnosticsForBun(cmd, args, dir)...)
+	} else if cmd == "go" {
+		diag.Suggestions = append(diag.Suggestions, getDiagnosticsForGo(args, dir)...)
+	} else if cmd == "java" {
+		diag.Suggestions = append(diag.Suggestions, getDiagnosticsForJava(args, dir)...)
+	} else if cmd == "ruby" || cmd == "bundle" {
+		diag.Suggestions = append(diag.Suggestions, getDiagnosticsForRuby(cmd, args, dir)...)
 	}
 
 	// Try running the command with a timeout to capture any startup errors
@@ -67,6 +109,7 @@ func GetServerDiagnostics(name string, cmd string, args []string) (*DiagnosticIn
 	defer cancel()
 
 	testCmd := exec.CommandContext(ctx, cmd, args...)
+	testCmd.Dir = dir
 	var stdout, stderr bytes.Buffer
 	testCmd.Stdout = &stdout
 	testCmd.Stderr = &stderr
@@ -181,6 +224,234 @@ func getDiagnosticsForPython(cmd string, args []string) []string {
 	return suggestions
 }
 
+// getDiagnosticsForUV provides diagnostics for uv, uvx, and pipx launched servers
+// run in dir, the server's working directory, or cmcp's when empty
+func getDiagnosticsForUV(cmd string, args []string, dir string) []string {
+	suggestions := []string{}
+
+	// Check if the launcher is installed
+	if _, err := exec.LookPath(cmd); err != nil {
+		if cmd == "pipx" {
+			suggestions = append(suggestions, "pipx not found. Install it with: python3 -m pip install --user pipx")
+		} else {
+			suggestions = append(suggestions, fmt.Sprintf("%s not found. Install uv with: curl -LsSf https://astral.sh/uv/install.sh | sh", cmd))
+		}
+		return suggestions
+	}
+
+	// uv run executes a local project or script rather than a published package
+	if cmd == "uv" && len(args) > 0 && args[0] == "run" {
+		project := ""
+		for i := 1; i < len(args); i++ {
+			if (args[i] == "--directory" || args[i] == "--project") && i+1 < len(args) {
+				project = args[i+1]
+				if info, err := os.Stat(inDir(dir, project)); err != nil || !info.IsDir() {
+					suggestions = append(suggestions, fmt.Sprintf("Project directory '%s' not found", project))
+				}
+			}
+			if strings.HasSuffix(args[i], ".py") {
+				script := inDir(project, args[i])
+				if _, err := os.Stat(inDir(dir, script)); err != nil {
+					suggestions = append(suggestions, fmt.Sprintf("Python script '%s' not found", script))
+				}
+				break
+			}
+		}
+		return suggestions
+	}
+
+	// Check that the package can be resolved on PyPI
+	if ref, ok := uvPackage(cmd, args); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := registry.LatestVersion(ctx, ref); err != nil && strings.Contains(err.Error(), "HTTP 404") {
+			suggestions = append(suggestions, fmt.Sprintf("Package '%s' was not found on PyPI. Check the package name.", ref.Name))
+		}
+	}
+
+	return suggestions
+}
+
+// getDiagnosticsForDeno provides Deno-specific diagnostics for a server run in dir,
+// the server's working directory, or cmcp's when empty
+func getDiagnosticsForDeno(args []string, dir string) []string {
+	suggestions := []string{}
+
+	if _, err := exec.LookPath("deno"); err != nil {
+		suggestions = append(suggestions, "deno not found. Install it with: curl -fsSL https://deno.land/install.sh | sh")
+		return suggestions
+	}
+
+	if len(args) > 0 && args[0] == "run" {
+		hasPermissions := false
+		for _, arg := range args[1:] {
+			if strings.HasPrefix(arg, "--allow-") || arg == "-A" {
+				hasPermissions = true
+				continue
+			}
+			if strings.HasPrefix(arg, "-") {
+				continue
+			}
+			// The first positional argument is the script or module specifier
+			if !strings.Contains(arg, "://") && !strings.HasPrefix(arg, "npm:") && !strings.HasPrefix(arg, "jsr:") {
+				if _, err := os.Stat(inDir(dir, arg)); err != nil {
+					suggestions = append(suggestions, fmt.Sprintf("Script file '%s' not found", arg))
+				}
+			}
+			break
+		}
+		if !hasPermissions {
+			suggestions = append(suggestions, "No --allow-* permissions given. Most MCP servers need at least --allow-net or --allow-read.")
+		}
+	}
+
+	return suggestions
+}
+
+// getDiagnosticsForBun provides Bun-specific diagnostics for a server run in dir,
+// the server's working directory, or cmcp's when empty
+func getDiagnosticsForBun(cmd string, args []string, dir string) []string {
+	suggestions := []string{}
+
+	if _, err := exec.LookPath(cmd); err != nil {
+		suggestions = append(suggestions, fmt.Sprintf("%s not found. Install Bun with: curl -fsSL https://bun.sh/install | bash", cmd))
+		return suggestions
+	}
+
+	if cmd == "bun" {
+		for _, arg := range args {
+			if strings.HasSuffix(arg, ".ts") || strings.HasSuffix(arg, ".js") {
+				if _, err := os.Stat(inDir(dir, arg)); err != nil {
+					suggestions = append(suggestions, fmt.Sprintf("Script file '%s' not found", arg))
+				} else if _, err := os.Stat(inDir(dir, "package.json")); err == nil {
+					if _, err := os.Stat(inDir(dir, "node_modules")); err != nil {
+						suggestions = append(suggestions, "Run 'bun install' to install dependencies")
+					}
+				}
+				break
+			}
+		}
+	}
+
+	return suggestions
+}
+
+// getDiagnosticsForGo provides diagnostics for servers launched with go run in dir,
+// the server's working directory, or cmcp's when empty
+func getDiagnosticsForGo(args []string, dir string) []string {
+	suggestions := []string{}
+
+	if _, err := exec.LookPath("go"); err != nil {
+		suggestions = append(suggestions, "go not found. Please install Go from https://go.dev/dl/")
+		return suggestions
+	}
+
+	if len(args) > 1 && args[0] == "run" {
+		target := args[len(args)-1]
+		for _, arg := range args[1:] {
+			if !strings.HasPrefix(arg, "-") {
+				target = arg
+				break
+			}
+		}
+		// Remote packages (module@version) are fetched by go itself
+		if !strings.Contains(target, "@") {
+			if _, err := os.Stat(inDir(dir, target)); err != nil {
+				suggestions = append(suggestions, fmt.Sprintf("Go package or file '%s' not found", target))
+			} else if _, err := os.Stat(inDir(dir, "go.mod")); err != nil && !strings.HasSuffix(target, ".go") {
+				suggestions = append(suggestions, "No go.mod in the working directory. Set 'cwd' to the module root.")
+			}
+		}
+	}
+
+	return suggestions
+}
+
+// getDiagnosticsForJava provides Java-specific diagnostics for a server run in dir,
+// the server's working directory, or cmcp's when empty
+func getDiagnosticsForJava(args []string, dir string) []string {
+	suggestions := []string{}
+
+	if _, err := exec.LookPath("java"); err != nil {
+		suggestions = append(suggestions, "java not found. Please install a Java runtime (JDK 17 or newer).")
+		return suggestions
+	}
+
+	for i, arg := range args {
+		if arg == "-jar" && i+1 < len(args) {
+			if _, err := os.Stat(inDir(dir, args[i+1])); err != nil {
+				suggestions = append(suggestions, fmt.Sprintf("JAR file '%s' not found", args[i+1]))
+			}
+			break
+		}
+	}
+
+	return suggestions
+}
+
+// getDiagnosticsForRuby provides Ruby-specific diagnostics for a server run in dir,
+// the server's working directory, or cmcp's when empty
+func getDiagnosticsForRuby(cmd string, args []string, dir string) []string {
+	suggestions := []string{}
+
+	if _, err := exec.LookPath(cmd); err != nil {
+		if cmd == "bundle" {
+			suggestions = append(suggestions, "bundle not found. Install it with: gem install bundler")
+		} else {
+			suggestions = append(suggestions, "ruby not found. Please install Ruby.")
+		}
+		return suggestions
+	}
+
+	for _, arg := range args {
+		if strings.HasSuffix(arg, ".rb") {
+			if _, err := os.Stat(inDir(dir, arg)); err != nil {
+				suggestions = append(suggestions, fmt.Sprintf("Ruby script '%s' not found", arg))
+			}
+			break
+		}
+	}
+
+	// Gemfile-based servers need their gems installed
+	if _, err := os.Stat(inDir(dir, "Gemfile")); err == nil {
+		if _, err := os.Stat(inDir(dir, "Gemfile.lock")); err != nil {
+			suggestions = append(suggestions, "Run 'bundle install' to install dependencies")
+		}
+	}
+
+	return suggestions
+}
+
+// inDir returns path as seen from dir, a server's working directory; relative paths
+// stay relative to cmcp's when dir is empty
+func inDir(dir, path string) string {
+	if dir == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// uvPackage finds the PyPI package launched by uvx, uv tool run, or pipx run
+func uvPackage(cmd string, args []string) (*registry.PackageRef, bool) {
+	switch {
+	case cmd == "pipx" && len(args) > 0 && args[0] == "run":
+		args = args[1:]
+	case cmd == "uv" && len(args) > 1 && args[0] == "tool" && args[1] == "run":
+		args = args[2:]
+	case cmd != "uvx":
+		return nil, false
+	}
+	// pipx uses --spec where uvx uses --from
+	normalized := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "--spec" {
+			arg = "--from"
+		}
+		normalized[i] = arg
+	}
+	return registry.FindPackage(&config.MCPServer{Command: "uvx", Args: normalized})
+}
+
 // analyzeDiagnosticErrors analyzes common error patterns and provides suggestions
 func analyzeDiagnosticErrors(diag *DiagnosticInfo) {
 	errStr := diag.StdErr + diag.StdOut
@@ -209,76 +480,80 @@ func analyzeDiagnosticErrors(diag *DiagnosticInfo) {
 	if strings.Contains(errStr, "environment variable") || strings.Contains(errStr, "env var") {
 		diag.Suggestions = append(diag.Suggestions, "Missing or invalid environment variables. Check your configuration.")
 	}
-}
 
-// FormatDiagnostics formats diagnostic information for display
-func FormatDiagnostics(diag *DiagnosticInfo) string {
-	var sb strings.Builder
-	
-	// Start with a clear indication this is a connection failure
-	sb.WriteString("Connection failed\n")
-	
-	if diag.HealthCheck != "" {
-		sb.WriteString(fmt.Sprintf("\n\033[1;33mHealth check output:\033[0m\n  %s\n", diag.HealthCheck))
+	// Python version constraints (uv/pipx resolution)
+	if strings.Contains(errStr, "requires-python") || strings.Contains(errStr, "No interpreter found") || strings.Contains(errStr, "requires Python") {
+		diag.Suggestions = append(diag.Suggestions, "Python version mismatch. Pin a compatible interpreter, e.g. 'uvx --python 3.12 <package>'.")
+	} else if strings.Contains(errStr, "No solution found when resolving") {
+		diag.Suggestions = append(diag.Suggestions, "Package dependencies could not be resolved. Check the package name and version pin.")
 	}
-	
-	if diag.StdErr != "" {
-		// Mask sensitive information before displaying
-		maskedErr := maskSensitiveOutput(diag.StdErr)
-		sb.WriteString(fmt.Sprintf("\n\033[1;31mServer error:\033[0m\n%s\n", maskedErr))
-	} else if diag.Error != nil {
-		sb.WriteString(fmt.Sprintf("\n\033[1;31mError:\033[0m %v\n", diag.Error))
+
+	// Deno permission errors
+	if strings.Contains(errStr, "NotCapable") || strings.Contains(errStr, "PermissionDenied: Requires") || strings.Contains(errStr, "run again with the --allow-") {
+		diag.Suggestions = append(diag.Suggestions, "Deno permission denied. Add the required --allow-* flag to the server args.")
 	}
-	
-	if len(diag.Suggestions) > 0 {
-		sb.WriteString("\n\033[1;35mPossible solutions:\033[0m\n")
-		for i, suggestion := range diag.Suggestions {
-			sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, suggestion))
-		}
+
+	// Java runtime version errors
+	if strings.Contains(errStr, "UnsupportedClassVersionError") {
+		diag.Suggestions = append(diag.Suggestions, "Java runtime is too old for this server. Install a newer JDK.")
 	}
-	
-	return sb.String()
+
+	// Ruby load errors
+	if strings.Contains(errStr, "cannot load such file") || strings.Contains(errStr, "Could not find gem") {
+		diag.Suggestions = append(diag.Suggestions, "Missing Ruby gems. Run 'bundle install' in the server directory.")
+	}
+
+	// Go module errors
+	if strings.Contains(errStr, "cannot find module providing package") || strings.Contains(errStr, "go: cannot find main module") {
+		diag.Suggestions = append(diag.Suggestions, "Go module not found. Set 'cwd' to the module root or use a module@version path.")
+	}
+}
+
+// FormatDiagnostics formats diagnostic information for display as plain text;
+// callers color it
+func FormatDiagnostics(diag *DiagnosticInfo) string {
+	return FormatDiagnosticsWithDebugLog(diag, "")
 }
 
 // FormatDiagnosticsWithDebugLog formats diagnostic information with optional debug log path
 func FormatDiagnosticsWithDebugLog(diag *DiagnosticInfo, debugLogPath string) string {
 	var sb strings.Builder
-	
+
 	// Start with a clear indication this is a connection failure
 	sb.WriteString("Connection failed\n")
-	
+
 	if diag.HealthCheck != "" {
-		sb.WriteString(fmt.Sprintf("\n\033[1;33mHealth check output:\033[0m\n  %s\n", diag.HealthCheck))
+		sb.WriteString(fmt.Sprintf("\nHealth check output:\n  %s\n", diag.HealthCheck))
 	}
-	
+
 	if diag.StdErr != "" {
 		// Mask sensitive information before displaying
 		maskedErr := maskSensitiveOutput(diag.StdErr)
-		sb.WriteString(fmt.Sprintf("\n\033[1;31mServer error:\033[0m\n%s\n", maskedErr))
+		sb.WriteString(fmt.Sprintf("\nServer error:\n%s\n", maskedErr))
 	} else if diag.Error != nil {
-		sb.WriteString(fmt.Sprintf("\n\033[1;31mError:\033[0m %v\n", diag.Error))
+		sb.WriteString(fmt.Sprintf("\nError: %v\n", diag.Error))
 	}
-	
+
 	if len(diag.Suggestions) > 0 {
-		sb.WriteString("\n\033[1;35mPossible solutions:\033[0m\n")
+		sb.WriteString("\nPossible solutions:\n")
 		for i, suggestion := range diag.Suggestions {
 			sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, suggestion))
 		}
 	}
-	
+
 	// Add debug log path as a separate, clearly marked section
 	if debugLogPath != "" {
-		sb.WriteString(fmt.Sprintf("\n\033[0;36mℹ Debug log saved to:\033[0m\n  %s\n", debugLogPath))
-		sb.WriteString("\033[0;90m  View this file for detailed connection diagnostics and Claude CLI debug output\033[0m\n")
+		sb.WriteString(fmt.Sprintf("\nℹ Debug log saved to:\n  %s\n", debugLogPath))
+		sb.WriteString("  View this file for detailed connection diagnostics and Claude CLI debug output\n")
 	}
-	
+
 	return sb.String()
 }
 
 // maskSensitiveOutput masks sensitive information in output
 func maskSensitiveOutput(output string) string {
 	// This is a simple implementation - in practice, you'd want to be more sophisticated
-	lines := strings.Split(output, "\n")
+	lines := strings.Split(maskInline(output), "\n")
 	for i, line := range lines {
 		upperLine := strings.ToUpper(line)
 		for _, pattern := range sensitivePatterns {
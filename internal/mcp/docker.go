@@ -0,0 +1,306 @@
+package mcp
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/ui"
+)
+
+// staleImageAge is how old a local image may be before cmcp suggests pulling it again
+const staleImageAge = 30 * 24 * time.Hour
+
+// dockerValueFlags are docker run flags that consume the following argument
+var dockerValueFlags = map[string]bool{
+	"-e": true, "--env": true, "--env-file": true,
+	"-v": true, "--volume": true, "--mount": true,
+	"-p": true, "--publish": true, "--name": true,
+	"-w": true, "--workdir": true, "-u": true, "--user": true,
+	"--network": true, "--net": true, "--entrypoint": true,
+	"-l": true, "--label": true, "--platform": true,
+	"-m": true, "--memory": true, "--cpus": true,
+	"--add-host": true, "--pull": true, "-h": true, "--hostname": true,
+}
+
+// DockerImage returns the image referenced by docker run arguments
+func DockerImage(args []string) (string, bool) {
+	inRun := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !inRun {
+			if arg == "run" {
+				inRun = true
+			}
+			continue
+		}
+		if strings.HasPrefix(arg, "-") {
+			// --flag=value carries its own value
+			if dockerValueFlags[arg] && !strings.Contains(arg, "=") {
+				i++
+			}
+			continue
+		}
+		return arg, true
+	}
+	return "", false
+}
+
+// ImagePresent reports whether the image exists locally
+func ImagePresent(image string) bool {
+	return exec.Command("docker", "image", "inspect", image).Run() == nil
+}
+
+// DockerAvailable reports whether the docker daemon answers, so a missing image can be
+// told apart from a daemon that is not running
+func DockerAvailable() bool {
+	return exec.Command("docker", "info", "--format", "{{.ServerVersion}}").Run() == nil
+}
+
+// dockerInstalled reports whether the docker CLI is on PATH
+func dockerInstalled() bool {
+	_, err := exec.LookPath("docker")
+	return err == nil
+}
+
+// PullImage pulls an image, streaming docker's progress output when verbose
+func PullImage(image string, verbose bool) error {
+	return pullImage(image, verbose, os.Stdout, os.Stderr)
+}
+
+// pullImage pulls an image, streaming docker's progress output to stdout and stderr
+// when verbose
+func pullImage(image string, verbose bool, stdout, stderr io.Writer) error {
+	cmd := exec.Command("docker", "pull", image)
+	if verbose {
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		return cmd.Run()
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("docker pull %s failed: %v\n%s", image, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// ImageAge returns how long ago the local image was built
+func ImageAge(image string) (time.Duration, error) {
+	output, err := exec.Command("docker", "image", "inspect", "--format", "{{.Created}}", image).Output()
+	if err != nil {
+		return 0, fmt.Errorf("image '%s' not found locally", image)
+	}
+	created, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(output)))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected creation time for '%s': %w", image, err)
+	}
+	return time.Since(created), nil
+}
+
+// ImageOutdated reports whether the registry has a different digest for the image's tag
+func ImageOutdated(image string) (bool, error) {
+	local, err := exec.Command("docker", "image", "inspect", "--format", "{{join .RepoDigests \" \"}}", image).Output()
+	if err != nil {
+		return false, fmt.Errorf("image '%s' not found locally", image)
+	}
+	remote, err := exec.Command("docker", "buildx", "imagetools", "inspect", "--format", "{{.Manifest.Digest}}", image).Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to query registry for '%s'", image)
+	}
+
+	remoteDigest := strings.TrimSpace(string(remote))
+	return remoteDigest != "" && !strings.Contains(string(local), remoteDigest), nil
+}
+
+// prepareDockerImage pulls a missing image and warns when the local copy looks stale
+func (b *ClaudeCmdBuilder) prepareDockerImage(server *config.MCPServer, verbose bool) error {
+	image, ok := DockerImage(server.Args)
+	if !ok {
+		return nil
+	}
+
+	if !ImagePresent(image) {
+		fmt.Fprintf(b.stdout(), "  Pulling missing image %s...\n", image)
+		if err := pullImage(image, verbose, b.stdout(), b.stderr()); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	age, err := ImageAge(image)
+	if err != nil || age < staleImageAge {
+		return nil
+	}
+	if outdated, err := ImageOutdated(image); err == nil && outdated {
+		ui.Warning.Fprintf(b.stdout(), "  ⚠ Local image %s is %d days old and the registry has a newer build. Run 'cmcp pull' to update it.\n",
+			image, int(age.Hours()/24))
+	}
+	return nil
+}
+
+// ImagePullable reports whether the registry has the image, without pulling it
+func ImagePullable(image string) error {
+	output, err := exec.Command("docker", "manifest", "inspect", image).CombinedOutput()
+	if err != nil {
+		if line := firstLine(string(output)); line != "" {
+			return fmt.Errorf("%s", line)
+		}
+		return err
+	}
+	return nil
+}
+
+// dockerInputs are what a docker run command takes from the host: variables passed
+// through with -e NAME, and bind-mount sources and env files that must exist
+type dockerInputs struct {
+	EnvNames []string
+	Paths    []string
+}
+
+// dockerRunInputs collects the host inputs of docker run arguments, up to the image
+func dockerRunInputs(args []string) dockerInputs {
+	var inputs dockerInputs
+	inRun := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !inRun {
+			inRun = arg == "run"
+			continue
+		}
+		if !strings.HasPrefix(arg, "-") {
+			break // The image; what follows belongs to the container
+		}
+
+		flag, value, inline := strings.Cut(arg, "=")
+		if !dockerValueFlags[flag] {
+			continue
+		}
+		if !inline {
+			if i+1 >= len(args) {
+				break
+			}
+			i++
+			value = args[i]
+		}
+
+		switch flag {
+		case "-e", "--env":
+			if !strings.Contains(value, "=") {
+				inputs.EnvNames = append(inputs.EnvNames, value)
+			}
+		case "--env-file":
+			inputs.Paths = append(inputs.Paths, value)
+		case "-v", "--volume":
+			if source, ok := bindSource(value); ok {
+				inputs.Paths = append(inputs.Paths, source)
+			}
+		case "--mount":
+			if source, ok := mountSource(value); ok {
+				inputs.Paths = append(inputs.Paths, source)
+			}
+		}
+	}
+	return inputs
+}
+
+// bindSource returns the host path of a -v source:target[:options] value; named
+// volumes have no host path
+func bindSource(value string) (string, bool) {
+	drive := ""
+	// Keep a Windows drive letter (C:\ or C:/) out of the split
+	if len(value) > 2 && value[1] == ':' && (value[2] == '\\' || value[2] == '/') {
+		drive, value = value[:2], value[2:]
+	}
+	source, _, found := strings.Cut(value, ":")
+	if !found {
+		return "", false // An anonymous volume
+	}
+	source = drive + source
+	if drive == "" && !filepath.IsAbs(source) && !strings.HasPrefix(source, ".") && !strings.HasPrefix(source, "~") {
+		return "", false
+	}
+	return source, true
+}
+
+// mountSource returns the host path of a --mount type=bind,source=...,target=... value
+func mountSource(value string) (string, bool) {
+	var source string
+	bind := false
+	for _, field := range strings.Split(value, ",") {
+		key, val, _ := strings.Cut(field, "=")
+		switch key {
+		case "type":
+			bind = val == "bind"
+		case "source", "src":
+			source = val
+		}
+	}
+	return source, bind && source != ""
+}
+
+// missingDockerInputs lists the -e variables that are set neither in the server's env
+// nor in the environment, and the bind-mount sources and env files that do not exist.
+// Relative paths are taken from the server's cwd, or the current directory.
+func missingDockerInputs(server *config.MCPServer) []string {
+	inputs := dockerRunInputs(server.Args)
+	var problems []string
+	for _, name := range inputs.EnvNames {
+		if _, ok := server.Env[name]; ok {
+			continue
+		}
+		if _, ok := os.LookupEnv(name); !ok {
+			problems = append(problems, fmt.Sprintf("%s is passed with -e but is set neither in the server's env nor in your shell", name))
+		}
+	}
+	for _, path := range inputs.Paths {
+		resolved := path
+		if strings.HasPrefix(resolved, "~") {
+			if home, err := os.UserHomeDir(); err == nil {
+				resolved = filepath.Join(home, strings.TrimPrefix(resolved, "~"))
+			}
+		} else if !filepath.IsAbs(resolved) && server.Cwd != "" {
+			resolved = filepath.Join(server.Cwd, resolved)
+		}
+		if _, err := os.Stat(resolved); err != nil {
+			problems = append(problems, fmt.Sprintf("%s does not exist on this machine", path))
+		}
+	}
+	return problems
+}
+
+// dockerPreflight checks a docker server before it is added to Claude: the daemon is
+// up, the image is present or can be pulled (pulled now with PullImages), and its -e
+// variables, bind mounts and env files are available
+func (b *ClaudeCmdBuilder) dockerPreflight(name string, server *config.MCPServer, verbose bool) error {
+	if !verbose {
+		b.step("Checking docker for '%s'...", name)
+		defer b.clearProgress()
+	}
+
+	problems := missingDockerInputs(server)
+	image, hasImage := DockerImage(server.Args)
+	switch {
+	case !dockerInstalled():
+		problems = append([]string{"docker is not installed or not on PATH"}, problems...)
+	case !DockerAvailable():
+		problems = append([]string{"the docker daemon is not running; start Docker Desktop or the docker service"}, problems...)
+	case hasImage && !b.PullImages && !ImagePresent(image):
+		if err := ImagePullable(image); err != nil {
+			problems = append(problems, fmt.Sprintf("image %s is not present locally and cannot be pulled: %v", image, err))
+		}
+	}
+	if len(problems) > 0 {
+		return &PreflightError{Server: name, Problems: problems}
+	}
+
+	// Make sure docker images are available instead of failing verification later
+	if b.PullImages {
+		b.clearProgress()
+		return b.prepareDockerImage(server, verbose)
+	}
+	return nil
+}
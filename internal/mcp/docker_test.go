@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestDockerImage(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		found    bool
+	}{
+		{
+			name:     "github server",
+			args:     []string{"run", "-i", "--rm", "-e", "GITHUB_PERSONAL_ACCESS_TOKEN", "ghcr.io/github/github-mcp-server"},
+			expected: "ghcr.io/github/github-mcp-server",
+			found:    true,
+		},
+		{
+			name:     "volume and name flags",
+			args:     []string{"run", "-i", "--rm", "-v", "/tmp:/data", "--name", "fs", "mcp/filesystem:latest", "/data"},
+			expected: "mcp/filesystem:latest",
+			found:    true,
+		},
+		{
+			name:     "flag with inline value",
+			args:     []string{"run", "--env=TOKEN", "--network=host", "mcp/fetch"},
+			expected: "mcp/fetch",
+			found:    true,
+		},
+		{
+			name:  "not a run command",
+			args:  []string{"compose", "up"},
+			found: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image, found := DockerImage(tt.args)
+			if found != tt.found || image != tt.expected {
+				t.Errorf("DockerImage() = %q, %v, want %q, %v", image, found, tt.expected, tt.found)
+			}
+		})
+	}
+}
+
+func TestDockerRunInputs(t *testing.T) {
+	args := []string{
+		"run", "-i", "--rm",
+		"-e", "GITHUB_TOKEN", "-e", "LOG_LEVEL=debug", "--env=REGION",
+		"--env-file", ".env",
+		"-v", "/data:/data:ro", "-v", "cache:/cache", "--volume=./src:/src", "-v", "/anon",
+		"--mount", "type=bind,source=/etc/certs,target=/certs", "--mount", "type=volume,src=vol,dst=/v",
+		"ghcr.io/acme/server", "-e", "AFTER_IMAGE",
+	}
+
+	got := dockerRunInputs(args)
+	want := dockerInputs{
+		EnvNames: []string{"GITHUB_TOKEN", "REGION"},
+		Paths:    []string{".env", "/data", "./src", "/etc/certs"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dockerRunInputs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBindSource(t *testing.T) {
+	tests := []struct {
+		value  string
+		source string
+		ok     bool
+	}{
+		{"/host:/container", "/host", true},
+		{"~/notes:/notes:ro", "~/notes", true},
+		{`C:\data:/data`, `C:\data`, true},
+		{"named:/data", "", false},
+		{"/anonymous", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			source, ok := bindSource(tt.value)
+			if source != tt.source || ok != tt.ok {
+				t.Errorf("bindSource(%q) = %q, %v, want %q, %v", tt.value, source, ok, tt.source, tt.ok)
+			}
+		})
+	}
+}
+
+func TestMissingDockerInputs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CMCP_TEST_SHELL_VAR", "")
+
+	server := &config.MCPServer{
+		Command: "docker",
+		Args: []string{"run", "-i",
+			"-e", "FROM_ENV", "-e", "CMCP_TEST_SHELL_VAR", "-e", "CMCP_TEST_UNSET_VAR",
+			"--env-file", ".env", "-v", filepath.Join(dir, "missing") + ":/data",
+			"mcp/server"},
+		Env: map[string]string{"FROM_ENV": "x"},
+		Cwd: dir,
+	}
+
+	want := []string{
+		"CMCP_TEST_UNSET_VAR is passed with -e but is set neither in the server's env nor in your shell",
+		filepath.Join(dir, "missing") + " does not exist on this machine",
+	}
+	if got := missingDockerInputs(server); !reflect.DeepEqual(got, want) {
+		t.Errorf("missingDockerInputs() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// remoteSuffix is the transport 'claude mcp list' appends to a remote server's URL
+var remoteSuffix = regexp.MustCompile(`\s+\((?i:http|sse)\)$`)
+
+// launchString returns what Claude reports for the server once cmcp has started it,
+// with placeholders and relative paths resolved the way a start resolves them
+func launchString(name string, server *config.MCPServer) string {
+	if expanded, err := ExpandPlaceholders(name, server); err == nil {
+		server = expanded
+	}
+	return describeServer(ResolvePaths(server))
+}
+
+// ConfigNameFor returns the configured server registered in Claude as claudeName,
+// given the command 'claude mcp list' shows for it, or "" when none is. When several
+// servers share the alias, the one whose definition matches the command wins.
+func ConfigNameFor(cfg *config.Config, claudeName, command string) string {
+	var candidates []string
+	for _, name := range cfg.GetServerNames() {
+		server := cfg.MCPServers[name]
+		if server.ClaudeName(name) == claudeName {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) > 1 {
+		registered := remoteSuffix.ReplaceAllString(command, "")
+		for _, name := range candidates {
+			server := cfg.MCPServers[name]
+			if launchString(name, &server) == registered {
+				return name
+			}
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0]
+	}
+	return ""
+}
+
+// Drift compares a running server's definition in the builder's scope with the config
+// and returns the difference, or nil if starting it again would change nothing. The
+// command, URL and names of env variables are compared; env values are not.
+func (b *ClaudeCmdBuilder) Drift(name string, server *config.MCPServer) *Conflict {
+	def := b.GetClaudeDefinition(server.ClaudeName(name))
+	if def == nil || (b.Scope != "" && !strings.HasPrefix(def.Scope, claudeScopes[b.Scope])) {
+		return nil
+	}
+	drift := &Conflict{
+		Name:       name,
+		Scope:      def.Scope,
+		Configured: launchString(name, server),
+		Registered: def.Describe(),
+	}
+
+	keys := make([]string, 0, len(server.Env))
+	for key := range server.Env {
+		keys = append(keys, key)
+	}
+	registered := append([]string{}, def.EnvKeys...)
+	sort.Strings(keys)
+	sort.Strings(registered)
+	if len(keys)+len(registered) > 0 && !reflect.DeepEqual(keys, registered) {
+		drift.Configured += " (env " + strings.Join(keys, ", ") + ")"
+		drift.Registered += " (env " + strings.Join(registered, ", ") + ")"
+	}
+
+	if drift.Configured == drift.Registered {
+		return nil
+	}
+	return drift
+}
+
+// FindDrift compares the command string 'claude mcp list' reported for each running
+// server in the config with what starting it now would register, so servers whose
+// config changed after they were started can be flagged without further lookups.
+func FindDrift(cfg *config.Config, servers []ServerStatus) []Conflict {
+	var drifted []Conflict
+	for _, status := range servers {
+		server, exists := cfg.FindServer(status.ConfigName)
+		if !exists || status.Command == "" {
+			continue
+		}
+		configured := launchString(status.ConfigName, server)
+		registered := remoteSuffix.ReplaceAllString(status.Command, "")
+		if configured != registered {
+			drifted = append(drifted, Conflict{Name: status.Name, Configured: configured, Registered: registered})
+		}
+	}
+	return drifted
+}
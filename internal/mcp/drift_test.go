@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestFindDrift(t *testing.T) {
+	cfg := &config.Config{MCPServers: map[string]config.MCPServer{
+		"github": {Command: "npx", Args: []string{"-y", "server-github", "--token", "new"}},
+		"fetch":  {Command: "uvx", Args: []string{"mcp-server-fetch"}},
+		"docs":   {Type: "http", URL: "https://example.com/mcp"},
+		"self":   {Command: "node", Args: []string{"{{serverName}}.js"}},
+		"db-dev": {Command: "pg-mcp", Args: []string{"--db", "dev"}, Alias: "db"},
+	}}
+	servers := []ServerStatus{
+		{Name: "github", Command: "npx -y server-github --token old", InConfig: true, ConfigName: "github"},
+		{Name: "fetch", Command: "uvx mcp-server-fetch", InConfig: true, ConfigName: "fetch"},
+		{Name: "docs", Command: "https://example.com/mcp (HTTP)", InConfig: true, ConfigName: "docs"},
+		{Name: "self", Command: "node self.js", InConfig: true, ConfigName: "self"},
+		{Name: "db", Command: "pg-mcp --db dev", InConfig: true, ConfigName: "db-dev"},
+		{Name: "orphan", Command: "orphan-server"},
+	}
+
+	want := []Conflict{{
+		Name:       "github",
+		Configured: "npx -y server-github --token new",
+		Registered: "npx -y server-github --token old",
+	}}
+	if got := FindDrift(cfg, servers); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDrift() = %+v, want %+v", got, want)
+	}
+}
+
+func TestConfigNameFor(t *testing.T) {
+	cfg := &config.Config{MCPServers: map[string]config.MCPServer{
+		"github":          {Command: "npx", Args: []string{"server-github"}},
+		"github-work":     {Command: "npx", Args: []string{"server-github", "--org", "work"}, Alias: "gh"},
+		"github-personal": {Command: "npx", Args: []string{"server-github", "--org", "me"}, Alias: "gh"},
+		"db-dev":          {Command: "pg-mcp", Alias: "db"},
+	}}
+
+	tests := []struct {
+		claudeName string
+		command    string
+		want       string
+	}{
+		{"github", "npx server-github", "github"},
+		{"gh", "npx server-github --org work", "github-work"},
+		{"gh", "npx server-github --org me", "github-personal"},
+		{"gh", "npx something-else", "github-personal"}, // No match: the first by name
+		{"db", "pg-mcp --changed", "db-dev"},
+		{"db-dev", "pg-mcp", ""}, // Registered under its cmcp name, not its alias
+		{"orphan", "orphan-server", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.claudeName+" "+tt.command, func(t *testing.T) {
+			if got := ConfigNameFor(cfg, tt.claudeName, tt.command); got != tt.want {
+				t.Errorf("ConfigNameFor(%q, %q) = %q, want %q", tt.claudeName, tt.command, got, tt.want)
+			}
+		})
+	}
+}
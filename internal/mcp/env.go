@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// ResolveEnv returns a copy of the server with env entries set to config.InheritEnv
+// filled in from the environment cmcp runs in. A variable that is not set there is
+// an error rather than being passed to the server as an empty value.
+func ResolveEnv(server *config.MCPServer) (*config.MCPServer, error) {
+	if !inheritsEnv(server) {
+		return server, nil
+	}
+
+	resolved := *server
+	resolved.Env = make(map[string]string, len(server.Env))
+	var missing []string
+	for key, value := range server.Env {
+		if value == config.InheritEnv {
+			shellValue, ok := os.LookupEnv(key)
+			if !ok {
+				missing = append(missing, key)
+			}
+			value = shellValue
+		}
+		resolved.Env[key] = value
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("inherited env %s not set in this shell; export it before starting",
+			strings.Join(missing, ", "))
+	}
+	return &resolved, nil
+}
+
+// inheritsEnv reports whether any env entry of the server is inherited from the shell
+func inheritsEnv(server *config.MCPServer) bool {
+	for _, value := range server.Env {
+		if value == config.InheritEnv {
+			return true
+		}
+	}
+	return false
+}
+
+// claudeReference matches ${VAR} and ${VAR:-default}, which Claude expands in env
+// values when it launches a server
+var claudeReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// EnvVar is a variable of a server's env as the server would receive it
+type EnvVar struct {
+	Key       string
+	Value     string
+	Source    string   // Where the value comes from, e.g. "config literal" or "inherited from the shell"
+	Missing   []string // Shell variables the value needs that are not set
+	Sensitive bool     // The value is masked unless revealed
+}
+
+// ResolvedEnv returns a server's env as the server would receive it once started,
+// sorted by key: inherited entries filled in from the shell by cmcp, and ${VAR} and
+// ${VAR:-default} references expanded as Claude does at launch, here from this
+// shell's environment.
+func ResolvedEnv(server *config.MCPServer) []EnvVar {
+	return resolvedEnv(server, os.LookupEnv)
+}
+
+func resolvedEnv(server *config.MCPServer, lookupEnv func(string) (string, bool)) []EnvVar {
+	keys := make([]string, 0, len(server.Env))
+	for key := range server.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	vars := make([]EnvVar, 0, len(keys))
+	for _, key := range keys {
+		value := server.Env[key]
+		v := EnvVar{Key: key, Value: value, Source: "config literal", Sensitive: IsSensitiveServerKey(server, key)}
+
+		if value == config.InheritEnv {
+			v.Value = ""
+			v.Source = "inherited from the shell"
+			if shellValue, ok := lookupEnv(key); ok {
+				v.Value = shellValue
+			} else {
+				v.Missing = []string{key}
+			}
+			vars = append(vars, v)
+			continue
+		}
+
+		var refs []string
+		v.Value, refs, v.Missing = expandClaudeReferences(value, lookupEnv)
+		if len(refs) > 0 {
+			v.Source = "expanded by Claude from " + strings.Join(refs, ", ")
+		}
+		vars = append(vars, v)
+	}
+	return vars
+}
+
+// expandClaudeReferences expands ${VAR} and ${VAR:-default} in value the way Claude
+// does, returning the references found and the variables that are not set
+func expandClaudeReferences(value string, lookupEnv func(string) (string, bool)) (string, []string, []string) {
+	var refs, missing []string
+	expanded := claudeReference.ReplaceAllStringFunc(value, func(ref string) string {
+		match := claudeReference.FindStringSubmatch(ref)
+		refs = append(refs, match[0])
+		if shellValue, ok := lookupEnv(match[1]); ok {
+			return shellValue
+		}
+		if strings.Contains(ref, ":-") {
+			return match[2]
+		}
+		missing = append(missing, match[1])
+		return ""
+	})
+	return expanded, refs, missing
+}
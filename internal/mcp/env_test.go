@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestResolveEnv(t *testing.T) {
+	t.Setenv("CMCP_TEST_TOKEN", "from-shell")
+
+	server := config.MCPServer{Command: "npx", Env: map[string]string{"CMCP_TEST_TOKEN": config.InheritEnv, "PORT": "80"}}
+	resolved, err := ResolveEnv(&server)
+	if err != nil {
+		t.Fatalf("ResolveEnv() error = %v", err)
+	}
+	want := map[string]string{"CMCP_TEST_TOKEN": "from-shell", "PORT": "80"}
+	if !reflect.DeepEqual(resolved.Env, want) {
+		t.Errorf("ResolveEnv() env = %v, want %v", resolved.Env, want)
+	}
+	if server.Env["CMCP_TEST_TOKEN"] != config.InheritEnv {
+		t.Error("ResolveEnv() modified the configured env")
+	}
+
+	server.Env["CMCP_TEST_UNSET_B"] = config.InheritEnv
+	server.Env["CMCP_TEST_UNSET_A"] = config.InheritEnv
+	if _, err := ResolveEnv(&server); err == nil || !strings.Contains(err.Error(), "CMCP_TEST_UNSET_A, CMCP_TEST_UNSET_B") {
+		t.Errorf("ResolveEnv() error = %v, want both unset variables named", err)
+	}
+
+	plain := config.MCPServer{Command: "npx", Env: map[string]string{"PORT": "80"}}
+	if resolved, _ := ResolveEnv(&plain); resolved != &plain {
+		t.Error("ResolveEnv() should return servers without inherited env as is")
+	}
+}
+
+func TestResolvedEnv(t *testing.T) {
+	shell := map[string]string{"GH_TOKEN": "ghp_123", "REGION": "eu"}
+	lookup := func(key string) (string, bool) {
+		value, ok := shell[key]
+		return value, ok
+	}
+	server := config.MCPServer{Command: "npx", Env: map[string]string{
+		"GITHUB_TOKEN": "${GH_TOKEN}",
+		"REGION":       config.InheritEnv,
+		"ZONE":         config.InheritEnv,
+		"URL":          "https://${HOST:-localhost}:${PORT}",
+		"LEVEL":        "debug",
+	}}
+
+	want := []EnvVar{
+		{Key: "GITHUB_TOKEN", Value: "ghp_123", Source: "expanded by Claude from ${GH_TOKEN}", Sensitive: true},
+		{Key: "LEVEL", Value: "debug", Source: "config literal"},
+		{Key: "REGION", Value: "eu", Source: "inherited from the shell"},
+		{Key: "URL", Value: "https://localhost:", Source: "expanded by Claude from ${HOST:-localhost}, ${PORT}", Missing: []string{"PORT"}},
+		{Key: "ZONE", Source: "inherited from the shell", Missing: []string{"ZONE"}},
+	}
+	if got := resolvedEnv(&server, lookup); !reflect.DeepEqual(got, want) {
+		t.Errorf("resolvedEnv() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,172 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+var (
+	// ErrServerNotFound matches errors for servers missing from the configuration or from Claude
+	ErrServerNotFound = config.ErrServerNotFound
+	// ErrClaudeUnavailable matches errors from running claude when the binary cannot be found or started
+	ErrClaudeUnavailable = errors.New("claude CLI is not available")
+	// ErrVerificationTimeout matches errors for servers that had not connected when verification gave up
+	ErrVerificationTimeout = errors.New("server did not connect")
+	// ErrNoTools matches deep verification failures of servers that connect but advertise no tools
+	ErrNoTools = errors.New("server advertises no tools")
+)
+
+// ClaudeUnavailableError is returned when the claude binary at Path cannot be run
+type ClaudeUnavailableError struct {
+	Path string
+	Err  error
+}
+
+func (e *ClaudeUnavailableError) Error() string {
+	return fmt.Sprintf("claude CLI is not available: %v", e.Err)
+}
+
+func (e *ClaudeUnavailableError) Unwrap() error { return e.Err }
+
+func (e *ClaudeUnavailableError) Is(target error) bool {
+	return target == ErrClaudeUnavailable
+}
+
+// CommandError is a claude command that failed for a server. Operation is the
+// claude mcp subcommand, e.g. "add" or "remove".
+type CommandError struct {
+	Server    string
+	Operation string
+	DebugLog  string
+	Err       error
+}
+
+func (e *CommandError) Error() string {
+	var message string
+	switch e.Operation {
+	case "add":
+		message = fmt.Sprintf("failed to add server '%s' to Claude", e.Server)
+	case "remove":
+		message = fmt.Sprintf("failed to remove server '%s' from Claude", e.Server)
+	default:
+		message = fmt.Sprintf("failed to %s servers: %v", e.Operation, e.Err)
+	}
+	return withDebugLog(message, e.DebugLog, "detailed error information")
+}
+
+func (e *CommandError) Unwrap() error { return e.Err }
+
+// VerificationError is a started server that had not connected when verification
+// gave up. Failed is set when Claude last reported the server as failed rather than
+// still starting. Only a server still starting when time ran out matches
+// ErrVerificationTimeout with errors.Is.
+type VerificationError struct {
+	Server   string
+	Timeout  time.Duration // The server's startup timeout; zero when it was checked 3 times
+	Attempts int
+	Failed   bool
+	DebugLog string
+}
+
+func (e *VerificationError) Error() string {
+	var message string
+	switch {
+	case e.Timeout > 0 && e.Failed:
+		message = fmt.Sprintf("failed to connect within %v", e.Timeout)
+	case e.Timeout > 0:
+		message = fmt.Sprintf("did not connect within %v", e.Timeout)
+	case e.Failed:
+		message = "failed to connect"
+	default:
+		message = fmt.Sprintf("failed to connect after %d attempts", e.Attempts)
+	}
+	return withDebugLog(message, e.DebugLog, "detailed connection diagnostics")
+}
+
+func (e *VerificationError) Is(target error) bool {
+	return target == ErrVerificationTimeout && !e.Failed
+}
+
+// ProbeError is a server that connected in Claude but failed deep verification:
+// the direct handshake or tools/list failed, or no tools were advertised (ErrNoTools)
+type ProbeError struct {
+	Server string
+	Err    error
+}
+
+func (e *ProbeError) Error() string {
+	return fmt.Sprintf("connected, but the tools/list probe failed: %v", e.Err)
+}
+
+func (e *ProbeError) Unwrap() error { return e.Err }
+
+// PreflightError is a docker server that failed the checks made before adding it to
+// Claude, such as a stopped daemon or a missing bind-mount source
+type PreflightError struct {
+	Server   string
+	Problems []string
+}
+
+func (e *PreflightError) Error() string {
+	if len(e.Problems) == 1 {
+		return "docker pre-flight check failed: " + e.Problems[0]
+	}
+	return "docker pre-flight checks failed: " + strings.Join(e.Problems, "; ")
+}
+
+// DiagnosticError is a start failure explained by diagnostics. Err is the
+// verification error the diagnostics replace.
+type DiagnosticError struct {
+	Server      string
+	Diagnostics *DiagnosticInfo
+	DebugLog    string
+	Err         error
+}
+
+func (e *DiagnosticError) Error() string {
+	return strings.TrimSpace(FormatDiagnosticsWithDebugLog(e.Diagnostics, e.DebugLog))
+}
+
+func (e *DiagnosticError) Unwrap() error { return e.Err }
+
+// withDebugLog points an error message at the debug log that has the details
+func withDebugLog(message, debugLog, details string) string {
+	if debugLog == "" {
+		return message
+	}
+	return fmt.Sprintf("%s\n\nℹ Debug log saved to:\n  %s\n  View this file for %s", message, debugLog, details)
+}
+
+// debugLogOf returns the debug log an error points to, if any
+func debugLogOf(err error) string {
+	var diagnosed *DiagnosticError
+	if errors.As(err, &diagnosed) && diagnosed.DebugLog != "" {
+		return diagnosed.DebugLog
+	}
+	var verification *VerificationError
+	if errors.As(err, &verification) && verification.DebugLog != "" {
+		return verification.DebugLog
+	}
+	var command *CommandError
+	if errors.As(err, &command) {
+		return command.DebugLog
+	}
+	return ""
+}
+
+// claudeUnavailable wraps errors from starting the claude binary: not found on PATH,
+// or a configured path that does not exist or cannot be executed
+func claudeUnavailable(path string, err error) error {
+	var execErr *exec.Error
+	var pathErr *fs.PathError
+	if errors.As(err, &execErr) || errors.As(err, &pathErr) {
+		return &ClaudeUnavailableError{Path: path, Err: err}
+	}
+	return err
+}
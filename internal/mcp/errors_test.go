@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestVerificationError(t *testing.T) {
+	tests := []struct {
+		err  *VerificationError
+		want string
+	}{
+		{&VerificationError{Attempts: 3}, "failed to connect after 3 attempts"},
+		{&VerificationError{Failed: true}, "failed to connect"},
+		{&VerificationError{Timeout: time.Minute}, "did not connect within 1m0s"},
+		{&VerificationError{Timeout: time.Minute, Failed: true}, "failed to connect within 1m0s"},
+		{&VerificationError{Failed: true, DebugLog: "/tmp/v.log"}, "failed to connect\n\nℹ Debug log saved to:\n  /tmp/v.log\n  View this file for detailed connection diagnostics"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+			// Only servers still starting timed out; diagnostics replace the message but
+			// keep the verification error matchable
+			diagnosed := &DiagnosticError{Server: "srv", Diagnostics: &DiagnosticInfo{}, Err: tt.err}
+			if got := errors.Is(diagnosed, ErrVerificationTimeout); got != !tt.err.Failed {
+				t.Errorf("errors.Is(DiagnosticError, ErrVerificationTimeout) = %v, want %v", got, !tt.err.Failed)
+			}
+		})
+	}
+}
+
+func TestPreflightError(t *testing.T) {
+	one := &PreflightError{Server: "gh", Problems: []string{"the docker daemon is not running"}}
+	if want := "docker pre-flight check failed: the docker daemon is not running"; one.Error() != want {
+		t.Errorf("Error() = %q, want %q", one.Error(), want)
+	}
+	two := &PreflightError{Server: "gh", Problems: []string{"A is not set", "/data does not exist"}}
+	if want := "docker pre-flight checks failed: A is not set; /data does not exist"; two.Error() != want {
+		t.Errorf("Error() = %q, want %q", two.Error(), want)
+	}
+}
+
+// missingClaude fails every command the way exec does when claude is not installed
+type missingClaude struct{}
+
+func (missingClaude) Run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	return claudeUnavailable("claude", &exec.Error{Name: "claude", Err: exec.ErrNotFound})
+}
+
+func TestClaudeUnavailable(t *testing.T) {
+	if err := claudeUnavailable("claude", errors.New("exit status 1")); errors.Is(err, ErrClaudeUnavailable) {
+		t.Error("a failed claude command matched ErrClaudeUnavailable")
+	}
+	if err := (execRunner{path: "/nonexistent/claude"}).Run(context.Background(), nil, io.Discard, io.Discard); !errors.Is(err, ErrClaudeUnavailable) {
+		t.Errorf("missing configured binary = %v, want ErrClaudeUnavailable", err)
+	}
+
+	b := NewClaudeCmdBuilder()
+	b.Runner = missingClaude{}
+	_, err := b.GetServerStatuses(nil)
+	if !errors.Is(err, ErrClaudeUnavailable) || !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("GetServerStatuses() error = %v, want ErrClaudeUnavailable wrapping exec.ErrNotFound", err)
+	}
+
+	wrapped := &CommandError{Server: "srv", Operation: "add", Err: fmt.Errorf("run: %w", err)}
+	var unavailable *ClaudeUnavailableError
+	if !errors.As(wrapped, &unavailable) || unavailable.Path != "claude" {
+		t.Errorf("errors.As(CommandError, *ClaudeUnavailableError) = %+v", unavailable)
+	}
+}
@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/health"
+)
+
+// Supported status export formats
+const (
+	ExportPrometheus = "prometheus"
+	ExportStatusFile = "statusfile"
+)
+
+// ExportStatuses renders server statuses in the given format.
+// The health history is optional and adds failure counters when present.
+func ExportStatuses(format string, servers []ServerStatus, history *health.History, now time.Time) (string, error) {
+	sorted := make([]ServerStatus, len(servers))
+	copy(sorted, servers)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	switch format {
+	case ExportPrometheus:
+		return exportPrometheus(sorted, history, now), nil
+	case ExportStatusFile:
+		return exportStatusFile(sorted, history, now)
+	default:
+		return "", fmt.Errorf("unknown export format '%s' (use %s or %s)", format, ExportPrometheus, ExportStatusFile)
+	}
+}
+
+// exportPrometheus renders gauges in the node_exporter textfile collector format
+func exportPrometheus(servers []ServerStatus, history *health.History, now time.Time) string {
+	var sb strings.Builder
+
+	sb.WriteString("# HELP cmcp_server_up Whether the MCP server is connected in Claude (1) or not (0).\n")
+	sb.WriteString("# TYPE cmcp_server_up gauge\n")
+	for _, server := range servers {
+		up := 0
+		if server.Status == "connected" {
+			up = 1
+		}
+		sb.WriteString(fmt.Sprintf("cmcp_server_up{server=%q,status=%q,in_config=\"%t\"} %d\n",
+			server.Name, server.Status, server.InConfig, up))
+	}
+
+	if history != nil {
+		sb.WriteString("# HELP cmcp_server_failures_total Times the MCP server was observed entering the failed state.\n")
+		sb.WriteString("# TYPE cmcp_server_failures_total counter\n")
+		for _, server := range servers {
+			if h, ok := history.Get(server.Name); ok {
+				sb.WriteString(fmt.Sprintf("cmcp_server_failures_total{server=%q} %d\n", server.Name, h.Failures))
+			}
+		}
+	}
+
+	sb.WriteString("# HELP cmcp_last_check_timestamp_seconds Unix time of the last status check.\n")
+	sb.WriteString("# TYPE cmcp_last_check_timestamp_seconds gauge\n")
+	sb.WriteString(fmt.Sprintf("cmcp_last_check_timestamp_seconds %d\n", now.Unix()))
+
+	return sb.String()
+}
+
+// statusFileEntry is one server in the JSON status file
+type statusFileEntry struct {
+	Name          string     `json:"name"`
+	Status        string     `json:"status"`
+	Up            bool       `json:"up"`
+	InConfig      bool       `json:"inConfig"`
+	Failures      int        `json:"failures,omitempty"`
+	LastConnected *time.Time `json:"lastConnected,omitempty"`
+}
+
+// exportStatusFile renders a JSON document with one entry per server
+func exportStatusFile(servers []ServerStatus, history *health.History, now time.Time) (string, error) {
+	entries := []statusFileEntry{}
+	for _, server := range servers {
+		entry := statusFileEntry{
+			Name:     server.Name,
+			Status:   server.Status,
+			Up:       server.Status == "connected",
+			InConfig: server.InConfig,
+		}
+		if history != nil {
+			if h, ok := history.Get(server.Name); ok {
+				entry.Failures = h.Failures
+				if !h.LastConnected.IsZero() {
+					lastConnected := h.LastConnected
+					entry.LastConnected = &lastConnected
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"checkedAt": now.UTC().Format(time.RFC3339),
+		"servers":   entries,
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// WriteFileAtomic writes data to a temp file and renames it into place,
+// so collectors never read a partially written file
+func WriteFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/health"
+)
+
+var exportServers = []ServerStatus{
+	{Name: "orphan", Status: "failed", InConfig: false},
+	{Name: "github", Status: "connected", InConfig: true},
+}
+
+func TestExportPrometheus(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	history := health.New("")
+	history.Record("orphan", "failed", now)
+
+	out, err := ExportStatuses(ExportPrometheus, exportServers, history, now)
+	if err != nil {
+		t.Fatalf("ExportStatuses() error = %v", err)
+	}
+
+	expected := []string{
+		`cmcp_server_up{server="github",status="connected",in_config="true"} 1`,
+		`cmcp_server_up{server="orphan",status="failed",in_config="false"} 0`,
+		`cmcp_server_failures_total{server="orphan"} 1`,
+		`cmcp_last_check_timestamp_seconds 1700000000`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("output missing %q:\n%s", line, out)
+		}
+	}
+
+	// Servers are sorted for stable output
+	if strings.Index(out, `server="github"`) > strings.Index(out, `server="orphan"`) {
+		t.Error("expected servers sorted by name")
+	}
+}
+
+func TestExportStatusFile(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	out, err := ExportStatuses(ExportStatusFile, exportServers, nil, now)
+	if err != nil {
+		t.Fatalf("ExportStatuses() error = %v", err)
+	}
+
+	var doc struct {
+		CheckedAt string `json:"checkedAt"`
+		Servers   []struct {
+			Name string `json:"name"`
+			Up   bool   `json:"up"`
+		} `json:"servers"`
+	}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(doc.Servers) != 2 || doc.Servers[0].Name != "github" || !doc.Servers[0].Up || doc.Servers[1].Up {
+		t.Errorf("unexpected servers: %+v", doc.Servers)
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	if _, err := ExportStatuses("xml", exportServers, nil, time.Now()); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cmcp.prom")
+	if err := WriteFileAtomic(path, []byte("a 1\n")); err != nil {
+		t.Fatalf("WriteFileAtomic() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "a 1\n" {
+		t.Errorf("file content = %q", data)
+	}
+}
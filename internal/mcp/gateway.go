@@ -0,0 +1,312 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// GatewaySeparator joins a server's name and a tool's name in the gateway's tool names
+const GatewaySeparator = "__"
+
+// gatewayConnectTimeout bounds how long the gateway waits for each server to connect
+var gatewayConnectTimeout = 30 * time.Second
+
+// JSON-RPC error codes the gateway answers with
+const (
+	invalidParams = -32602
+	internalError = -32603
+)
+
+// Gateway is a single stdio MCP server in front of several configured servers. It
+// lists their tools as <server>__<tool> and forwards each call to the server that
+// owns the tool, so Claude needs only one registration for all of them.
+type Gateway struct {
+	servers map[string]*config.MCPServer
+	log     io.Writer // Connection progress and failures; stdout carries the protocol
+
+	// connect opens a server's connection; tests replace it
+	connect func(ctx context.Context, name string, server *config.MCPServer) (*Client, error)
+
+	ready     chan struct{} // Closed once every server has connected or failed
+	upstreams map[string]*gatewayUpstream
+	tools     []map[string]interface{} // Kept raw so fields cmcp does not know, such as annotations, pass through
+	routes    map[string]gatewayRoute
+}
+
+// gatewayUpstream is a connected server. Client handles one request at a time, so
+// calls to the same server are serialized.
+type gatewayUpstream struct {
+	client *Client
+	mu     sync.Mutex
+}
+
+// gatewayRoute is the server and original name behind a namespaced tool
+type gatewayRoute struct {
+	server string
+	tool   string
+}
+
+// gatewayMessage is a JSON-RPC message from Claude or to it. IDs are kept raw since
+// clients may use numbers or strings.
+type gatewayMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// NewGateway creates a gateway for the given servers by name, logging to log
+func NewGateway(servers map[string]*config.MCPServer, log io.Writer) *Gateway {
+	return &Gateway{
+		servers: servers,
+		log:     log,
+		connect: connectUpstream,
+		ready:   make(chan struct{}),
+	}
+}
+
+// connectUpstream launches or connects to a server as Claude would
+func connectUpstream(ctx context.Context, name string, server *config.MCPServer) (*Client, error) {
+	server, err := ResolveLaunch(name, server)
+	if err != nil {
+		return nil, err
+	}
+	return Connect(ctx, server)
+}
+
+// Serve answers MCP requests read from r on w until r is closed or ctx is cancelled,
+// then shuts the servers down.
+// Servers connect in the background, so initialize is answered right away and tool
+// requests wait until every server has connected or failed.
+func (g *Gateway) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	go g.connectAll(ctx)
+	defer g.close()
+
+	var writeMu sync.Mutex
+	reply := func(msg *gatewayMessage) {
+		msg.JSONRPC = "2.0"
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		w.Write(append(data, '\n'))
+	}
+
+	var handlers sync.WaitGroup
+	defer handlers.Wait()
+
+	// Read in the background so a cancelled ctx is not stuck behind a blocked read
+	lines := make(chan []byte)
+	var readErr error
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			select {
+			case lines <- bytes.Clone(scanner.Bytes()):
+			case <-ctx.Done():
+				return
+			}
+		}
+		readErr = scanner.Err()
+	}()
+
+	for {
+		var line []byte
+		select {
+		case <-ctx.Done():
+			return nil
+		case next, ok := <-lines:
+			if !ok {
+				return readErr
+			}
+			line = bytes.TrimSpace(next)
+		}
+		if len(line) == 0 {
+			continue
+		}
+		var msg gatewayMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			continue
+		}
+		// Notifications, such as notifications/initialized, need no answer
+		if len(msg.ID) == 0 || msg.Method == "" {
+			continue
+		}
+
+		handlers.Add(1)
+		go func(msg gatewayMessage) {
+			defer handlers.Done()
+			result, rpcErr := g.handle(ctx, msg.Method, msg.Params)
+			reply(&gatewayMessage{ID: msg.ID, Result: result, Error: rpcErr})
+		}(msg)
+	}
+}
+
+// handle answers a single request from Claude
+func (g *Gateway) handle(ctx context.Context, method string, params json.RawMessage) (interface{}, *RPCError) {
+	switch method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]interface{}{"name": "cmcp-gateway", "version": "1.0.0"},
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		if err := g.wait(ctx); err != nil {
+			return nil, &RPCError{Code: internalError, Message: err.Error()}
+		}
+		return map[string]interface{}{"tools": g.tools}, nil
+	case "tools/call":
+		return g.callTool(ctx, params)
+	}
+	return nil, &RPCError{Code: methodNotFound, Message: fmt.Sprintf("method not found: %s", method)}
+}
+
+// callTool forwards a tools/call to the server that owns the tool
+func (g *Gateway) callTool(ctx context.Context, params json.RawMessage) (interface{}, *RPCError) {
+	var call struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil {
+		return nil, &RPCError{Code: invalidParams, Message: fmt.Sprintf("invalid tools/call params: %v", err)}
+	}
+	if err := g.wait(ctx); err != nil {
+		return nil, &RPCError{Code: internalError, Message: err.Error()}
+	}
+	route, ok := g.routes[call.Name]
+	if !ok {
+		return nil, &RPCError{Code: invalidParams, Message: fmt.Sprintf("unknown tool '%s'", call.Name)}
+	}
+	if call.Arguments == nil {
+		call.Arguments = map[string]interface{}{}
+	}
+
+	upstream := g.upstreams[route.server]
+	upstream.mu.Lock()
+	defer upstream.mu.Unlock()
+	result, err := upstream.client.Call(ctx, "tools/call", map[string]interface{}{
+		"name":      route.tool,
+		"arguments": call.Arguments,
+	})
+	if err != nil {
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) {
+			return nil, rpcErr
+		}
+		return nil, &RPCError{Code: internalError, Message: fmt.Sprintf("server '%s': %v", route.server, err)}
+	}
+	return result, nil
+}
+
+// wait blocks until the servers have connected, or ctx is cancelled
+func (g *Gateway) wait(ctx context.Context) error {
+	select {
+	case <-g.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// connectAll connects to every server in parallel and collects their tools. Servers
+// that fail to connect are logged and left out.
+func (g *Gateway) connectAll(ctx context.Context) {
+	type connected struct {
+		name   string
+		client *Client
+		tools  []map[string]interface{}
+	}
+	results := make(chan connected, len(g.servers))
+	var wg sync.WaitGroup
+	for name, server := range g.servers {
+		wg.Add(1)
+		go func(name string, server *config.MCPServer) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, gatewayConnectTimeout)
+			defer cancel()
+
+			client, err := g.connect(ctx, name, server)
+			if err != nil {
+				fmt.Fprintf(g.log, "cmcp gateway: skipping '%s': %v\n", name, err)
+				return
+			}
+			tools, err := listRawTools(ctx, client)
+			if err != nil {
+				client.Close()
+				fmt.Fprintf(g.log, "cmcp gateway: skipping '%s': failed to list tools: %v\n", name, err)
+				return
+			}
+			results <- connected{name, client, tools}
+		}(name, server)
+	}
+	wg.Wait()
+	close(results)
+
+	g.upstreams = make(map[string]*gatewayUpstream)
+	g.routes = make(map[string]gatewayRoute)
+	var all []connected
+	for result := range results {
+		all = append(all, result)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].name < all[j].name })
+	for _, result := range all {
+		g.upstreams[result.name] = &gatewayUpstream{client: result.client}
+		for _, tool := range result.tools {
+			name, _ := tool["name"].(string)
+			namespaced := result.name + GatewaySeparator + name
+			g.routes[namespaced] = gatewayRoute{server: result.name, tool: name}
+			tool["name"] = namespaced
+			description, _ := tool["description"].(string)
+			tool["description"] = strings.TrimSpace(fmt.Sprintf("[%s] %s", result.name, description))
+			g.tools = append(g.tools, tool)
+		}
+		fmt.Fprintf(g.log, "cmcp gateway: '%s' connected with %d tool(s)\n", result.name, len(result.tools))
+	}
+	if g.tools == nil {
+		g.tools = []map[string]interface{}{}
+	}
+	close(g.ready)
+}
+
+// listRawTools returns a server's tools as decoded JSON objects, following pagination
+func listRawTools(ctx context.Context, client *Client) ([]map[string]interface{}, error) {
+	var tools []map[string]interface{}
+	err := client.paginate(ctx, "tools/list", func(raw json.RawMessage) (string, error) {
+		var page struct {
+			Tools      []map[string]interface{} `json:"tools"`
+			NextCursor string                   `json:"nextCursor"`
+		}
+		err := json.Unmarshal(raw, &page)
+		tools = append(tools, page.Tools...)
+		return page.NextCursor, err
+	})
+	return tools, err
+}
+
+// close shuts down every connected server once the servers have settled
+func (g *Gateway) close() {
+	<-g.ready
+	for _, upstream := range g.upstreams {
+		upstream.client.Close()
+	}
+}
@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// serveGateway runs a gateway over fake servers until the requests are answered and
+// returns the responses by ID
+func serveGateway(t *testing.T, requests ...string) (map[string]gatewayMessage, string) {
+	t.Helper()
+
+	calls := func(method string, params json.RawMessage) (interface{}, *RPCError) {
+		if method != "tools/call" {
+			return basicHandler(method, params)
+		}
+		var call struct {
+			Name      string                 `json:"name"`
+			Arguments map[string]interface{} `json:"arguments"`
+		}
+		json.Unmarshal(params, &call)
+		if call.Name != "echo" {
+			return nil, &RPCError{Code: invalidParams, Message: "no such tool"}
+		}
+		return map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": call.Arguments["text"]}}}, nil
+	}
+
+	var log bytes.Buffer
+	g := NewGateway(map[string]*config.MCPServer{
+		"alpha":  {Command: "alpha"},
+		"beta":   {Command: "beta"},
+		"broken": {Command: "broken"},
+	}, &log)
+	g.connect = func(ctx context.Context, name string, server *config.MCPServer) (*Client, error) {
+		if name == "broken" {
+			return nil, errors.New("failed to launch 'broken'")
+		}
+		return fakeServer(t, calls), nil
+	}
+
+	var out bytes.Buffer
+	if err := g.Serve(context.Background(), strings.NewReader(strings.Join(requests, "\n")), &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	responses := make(map[string]gatewayMessage)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var msg gatewayMessage
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("invalid response %q: %v", line, err)
+		}
+		responses[string(msg.ID)] = msg
+	}
+	return responses, log.String()
+}
+
+func TestGatewayToolsList(t *testing.T) {
+	responses, log := serveGateway(t,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":"list","method":"tools/list"}`,
+	)
+	if len(responses) != 2 {
+		t.Fatalf("got %d responses, want 2 (notifications are not answered)", len(responses))
+	}
+
+	var list struct {
+		Tools []Tool `json:"tools"`
+	}
+	data, _ := json.Marshal(responses[`"list"`].Result)
+	json.Unmarshal(data, &list)
+
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+	}
+	want := "alpha__echo alpha__add beta__echo beta__add"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("tools = %q, want %q", got, want)
+	}
+	if list.Tools[0].Description != "[alpha] Echo input" || list.Tools[1].Description != "[alpha]" {
+		t.Errorf("descriptions = %q, %q", list.Tools[0].Description, list.Tools[1].Description)
+	}
+	if !strings.Contains(log, "skipping 'broken': failed to launch 'broken'") {
+		t.Errorf("log should report the broken server:\n%s", log)
+	}
+}
+
+func TestGatewayToolsCall(t *testing.T) {
+	responses, _ := serveGateway(t,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"beta__echo","arguments":{"text":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"beta__add"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"broken__echo"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"resources/list"}`,
+	)
+
+	tests := []struct {
+		id      string
+		result  string
+		errCode int
+	}{
+		{"1", `{"content":[{"text":"hi","type":"text"}]}`, 0},
+		{"2", "", invalidParams}, // The server's own error passes through
+		{"3", "", invalidParams}, // Tools of servers that failed to connect are unknown
+		{"4", "", methodNotFound},
+	}
+	for _, tt := range tests {
+		resp := responses[tt.id]
+		if tt.errCode != 0 {
+			if resp.Error == nil || resp.Error.Code != tt.errCode {
+				t.Errorf("response %s error = %v, want code %d", tt.id, resp.Error, tt.errCode)
+			}
+			continue
+		}
+		data, _ := json.Marshal(resp.Result)
+		if resp.Error != nil || !equalJSON(data, json.RawMessage(tt.result)) {
+			t.Errorf("response %s = %s, %v; want %s", tt.id, data, resp.Error, tt.result)
+		}
+	}
+}
+
+func TestGatewayNoServers(t *testing.T) {
+	g := NewGateway(nil, io.Discard)
+	var out bytes.Buffer
+	if err := g.Serve(context.Background(), strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`), &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+	if !strings.Contains(out.String(), `"result":{"tools":[]}`) {
+		t.Errorf("tools/list without servers = %s, want an empty list", out.String())
+	}
+}
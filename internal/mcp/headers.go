@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/auth"
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// tokenCommandTimeout bounds how long a server's tokenCommand may run
+var tokenCommandTimeout = 30 * time.Second
+
+// headerRefPattern matches ${env:NAME} and ${token} references in header values
+var headerRefPattern = regexp.MustCompile(`\$\{(env:[A-Za-z_][A-Za-z0-9_]*|token)\}`)
+
+// ResolveHeaders returns a copy of the server with the references in its headers
+// replaced: ${env:NAME} by the environment variable and ${token} by the output of
+// tokenCommand. When no Authorization header is configured, "Authorization: Bearer
+// <token>" is added with the tokenCommand output or the token cached by 'cmcp auth login'.
+func ResolveHeaders(ctx context.Context, server *config.MCPServer) (*config.MCPServer, error) {
+	if len(server.Headers) == 0 && server.TokenCommand == "" && !server.IsRemote() {
+		return server, nil
+	}
+
+	var token string
+	if server.TokenCommand != "" {
+		var err error
+		if token, err = runTokenCommand(ctx, server.TokenCommand); err != nil {
+			return nil, err
+		}
+	}
+
+	resolved := *server
+	resolved.Headers = make(map[string]string, len(server.Headers)+1)
+	hasAuthorization := false
+	for key, value := range server.Headers {
+		var missing error
+		value = headerRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+			name := headerRefPattern.FindStringSubmatch(ref)[1]
+			if name == "token" {
+				if server.TokenCommand == "" && missing == nil {
+					missing = fmt.Errorf("header '%s' uses ${token} but no tokenCommand is configured", key)
+				}
+				return token
+			}
+			envName := strings.TrimPrefix(name, "env:")
+			envValue, ok := os.LookupEnv(envName)
+			if !ok && missing == nil {
+				missing = fmt.Errorf("header '%s' references unset environment variable %s", key, envName)
+			}
+			return envValue
+		})
+		if missing != nil {
+			return nil, missing
+		}
+		if strings.EqualFold(key, "Authorization") {
+			hasAuthorization = true
+		}
+		resolved.Headers[key] = value
+	}
+
+	if token == "" && !hasAuthorization && server.IsRemote() {
+		var err error
+		if token, err = TokenStore().AccessToken(ctx, server.URL); err != nil {
+			return nil, err
+		}
+	}
+	if token != "" && !hasAuthorization {
+		resolved.Headers["Authorization"] = "Bearer " + token
+	}
+	if len(resolved.Headers) == 0 {
+		resolved.Headers = nil
+	}
+	return &resolved, nil
+}
+
+// TokenStore returns the cache of OAuth tokens written by 'cmcp auth login'
+func TokenStore() *auth.Store {
+	return auth.NewStore(config.StatePath("tokens"))
+}
+
+// runTokenCommand runs a shell command and returns its trimmed output as a token
+func runTokenCommand(ctx context.Context, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, tokenCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("tokenCommand failed: %v: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("tokenCommand failed: %w", err)
+	}
+
+	token := strings.TrimSpace(string(output))
+	if token == "" {
+		return "", fmt.Errorf("tokenCommand printed no token")
+	}
+	return token, nil
+}
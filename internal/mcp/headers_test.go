@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/auth"
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestResolveHeaders(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("token commands use sh in these tests")
+	}
+	t.Setenv("CMCP_TEST_API_TOKEN", "env-secret")
+
+	tests := []struct {
+		name     string
+		server   config.MCPServer
+		expected map[string]string
+		errMsg   string
+	}{
+		{
+			name:     "no headers",
+			server:   config.MCPServer{Command: "npx"},
+			expected: nil,
+		},
+		{
+			name: "env reference",
+			server: config.MCPServer{Headers: map[string]string{
+				"Authorization": "Bearer ${env:CMCP_TEST_API_TOKEN}",
+				"X-Team":        "core",
+			}},
+			expected: map[string]string{"Authorization": "Bearer env-secret", "X-Team": "core"},
+		},
+		{
+			name:     "token command adds authorization",
+			server:   config.MCPServer{TokenCommand: "echo minted"},
+			expected: map[string]string{"Authorization": "Bearer minted"},
+		},
+		{
+			name: "token reference keeps custom header",
+			server: config.MCPServer{
+				TokenCommand: "echo minted",
+				Headers:      map[string]string{"authorization": "Token ${token}"},
+			},
+			expected: map[string]string{"authorization": "Token minted"},
+		},
+		{
+			name:   "unset env var",
+			server: config.MCPServer{Headers: map[string]string{"X-Key": "${env:CMCP_TEST_UNSET_VAR}"}},
+			errMsg: "unset environment variable CMCP_TEST_UNSET_VAR",
+		},
+		{
+			name:   "token without token command",
+			server: config.MCPServer{Headers: map[string]string{"Authorization": "Bearer ${token}"}},
+			errMsg: "no tokenCommand is configured",
+		},
+		{
+			name:   "failing token command",
+			server: config.MCPServer{TokenCommand: "echo denied >&2; exit 1"},
+			errMsg: "denied",
+		},
+		{
+			name:   "empty token",
+			server: config.MCPServer{TokenCommand: "true"},
+			errMsg: "printed no token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := ResolveHeaders(context.Background(), &tt.server)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("ResolveHeaders() error = %v, want it to contain %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveHeaders() unexpected error: %v", err)
+			}
+			if len(resolved.Headers) != len(tt.expected) {
+				t.Fatalf("ResolveHeaders() headers = %v, want %v", resolved.Headers, tt.expected)
+			}
+			for key, value := range tt.expected {
+				if resolved.Headers[key] != value {
+					t.Errorf("header %s = %q, want %q", key, resolved.Headers[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveHeadersLeavesServerUnchanged(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("token commands use sh in these tests")
+	}
+
+	server := config.MCPServer{
+		TokenCommand: "echo minted",
+		Headers:      map[string]string{"X-Token": "${token}"},
+	}
+	if _, err := ResolveHeaders(context.Background(), &server); err != nil {
+		t.Fatalf("ResolveHeaders() unexpected error: %v", err)
+	}
+	if server.Headers["X-Token"] != "${token}" || len(server.Headers) != 1 {
+		t.Errorf("ResolveHeaders() modified the configured headers: %v", server.Headers)
+	}
+}
+
+func TestResolveHeadersUsesLogin(t *testing.T) {
+	dir := t.TempDir()
+	orig, _ := config.GetConfigPath()
+	config.SetConfigPath(filepath.Join(dir, "config.json"))
+	defer config.SetConfigPath(orig)
+
+	server := config.MCPServer{Type: "http", URL: "https://example.com/mcp"}
+	resolved, err := ResolveHeaders(context.Background(), &server)
+	if err != nil {
+		t.Fatalf("ResolveHeaders() unexpected error: %v", err)
+	}
+	if len(resolved.Headers) != 0 {
+		t.Fatalf("ResolveHeaders() without login = %v, want no headers", resolved.Headers)
+	}
+
+	token := &auth.Token{Resource: "https://example.com/mcp", AccessToken: "oauth-token"}
+	if err := TokenStore().Save(token); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	resolved, err = ResolveHeaders(context.Background(), &server)
+	if err != nil {
+		t.Fatalf("ResolveHeaders() unexpected error: %v", err)
+	}
+	if resolved.Headers["Authorization"] != "Bearer oauth-token" {
+		t.Errorf("Authorization = %q, want the cached login token", resolved.Headers["Authorization"])
+	}
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// healthCheckTimeout bounds how long a server's healthCheck may run
+var healthCheckTimeout = 15 * time.Second
+
+// HealthCheckResult is the outcome of a server's own healthCheck
+type HealthCheckResult struct {
+	OK       bool
+	Detail   string // The HTTP status, or the first line of the command's output or error
+	Duration time.Duration
+}
+
+// RunHealthCheck runs a server's healthCheck to confirm it is usable beyond Claude's
+// connection check. A URL passes when a GET answers with a 2xx status, using the
+// server's tls and proxy settings; a shell command passes when it exits 0, and runs
+// with the server's env and cwd.
+func RunHealthCheck(ctx context.Context, name string, server *config.MCPServer) HealthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	var result HealthCheckResult
+	if IsHealthCheckURL(server.HealthCheck) {
+		result = checkURL(ctx, server)
+	} else {
+		result = checkCommand(ctx, name, server)
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// IsHealthCheckURL reports whether a healthCheck is a URL rather than a shell command
+func IsHealthCheckURL(check string) bool {
+	return strings.HasPrefix(check, "http://") || strings.HasPrefix(check, "https://")
+}
+
+func checkURL(ctx context.Context, server *config.MCPServer) HealthCheckResult {
+	client, err := remoteHTTPClient(server)
+	if err != nil {
+		return HealthCheckResult{Detail: err.Error()}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.HealthCheck, nil)
+	if err != nil {
+		return HealthCheckResult{Detail: err.Error()}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return HealthCheckResult{Detail: firstLine(wrapProxyError(err).Error())}
+	}
+	resp.Body.Close()
+	return HealthCheckResult{
+		OK:     resp.StatusCode >= 200 && resp.StatusCode < 300,
+		Detail: resp.Status,
+	}
+}
+
+func checkCommand(ctx context.Context, name string, server *config.MCPServer) HealthCheckResult {
+	expanded, err := ExpandPlaceholders(name, server)
+	if err != nil {
+		return HealthCheckResult{Detail: err.Error()}
+	}
+	expanded = ResolvePaths(expanded)
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", server.HealthCheck)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", server.HealthCheck)
+	}
+	cmd.Env = serverEnviron(expanded)
+	cmd.Dir = expanded.Cwd
+	cmd.WaitDelay = time.Second
+
+	output, err := cmd.CombinedOutput()
+	detail := firstLine(maskSensitiveOutput(string(output)))
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return HealthCheckResult{Detail: fmt.Sprintf("timed out after %v", healthCheckTimeout)}
+		}
+		if detail == "" {
+			detail = err.Error()
+		}
+		return HealthCheckResult{Detail: detail}
+	}
+	if detail == "" {
+		detail = "ok"
+	}
+	return HealthCheckResult{OK: true, Detail: detail}
+}
+
+// RunHealthChecks runs the healthCheck of each named server that has one, in
+// parallel, and returns the results by name
+func RunHealthChecks(ctx context.Context, cfg *config.Config, names []string) map[string]HealthCheckResult {
+	results := make(map[string]HealthCheckResult)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range names {
+		server, ok := cfg.FindServer(name)
+		if !ok || server.HealthCheck == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(name string, server *config.MCPServer) {
+			defer wg.Done()
+			result := RunHealthCheck(ctx, name, server)
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}(name, server)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestRunHealthCheckURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ok" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		check  string
+		wantOK bool
+		detail string
+	}{
+		{"2xx passes", srv.URL + "/ok", true, "200 OK"},
+		{"5xx fails", srv.URL + "/down", false, "503 Service Unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RunHealthCheck(context.Background(), "api", &config.MCPServer{HealthCheck: tt.check})
+			if result.OK != tt.wantOK || result.Detail != tt.detail {
+				t.Errorf("RunHealthCheck() = %+v, want OK=%v detail %q", result, tt.wantOK, tt.detail)
+			}
+		})
+	}
+}
+
+func TestRunHealthCheckCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	tests := []struct {
+		name   string
+		server config.MCPServer
+		wantOK bool
+		detail string
+	}{
+		{"exit 0 passes", config.MCPServer{HealthCheck: "echo ready"}, true, "ready"},
+		{"no output", config.MCPServer{HealthCheck: "true"}, true, "ok"},
+		{"non-zero fails", config.MCPServer{HealthCheck: "echo 'api down' >&2; exit 1"}, false, "api down"},
+		{"server env", config.MCPServer{HealthCheck: `test "$REGION" = eu && echo "$REGION"`, Env: map[string]string{"REGION": "eu"}}, true, "eu"},
+		{"cwd", config.MCPServer{HealthCheck: "pwd", Cwd: "/"}, true, "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RunHealthCheck(context.Background(), "api", &tt.server)
+			if result.OK != tt.wantOK || result.Detail != tt.detail {
+				t.Errorf("RunHealthCheck() = %+v, want OK=%v detail %q", result, tt.wantOK, tt.detail)
+			}
+		})
+	}
+}
+
+func TestRunHealthCheckTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	defer func(old time.Duration) { healthCheckTimeout = old }(healthCheckTimeout)
+	healthCheckTimeout = 100 * time.Millisecond
+
+	result := RunHealthCheck(context.Background(), "api", &config.MCPServer{HealthCheck: "sleep 5"})
+	if result.OK || result.Duration > 3*time.Second {
+		t.Errorf("RunHealthCheck() = %+v, want a timeout failure", result)
+	}
+}
+
+func TestRunHealthChecks(t *testing.T) {
+	cfg := &config.Config{MCPServers: map[string]config.MCPServer{
+		"checked":   {Command: "x", HealthCheck: "true"},
+		"unchecked": {Command: "x"},
+	}}
+
+	results := RunHealthChecks(context.Background(), cfg, []string{"checked", "unchecked", "missing"})
+	if len(results) != 1 || !results["checked"].OK {
+		t.Errorf("RunHealthChecks() = %+v, want only a passing 'checked'", results)
+	}
+}
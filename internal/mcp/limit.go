@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// DefaultMaxClaudeProcesses is how many claude subprocesses may run at once
+const DefaultMaxClaudeProcesses = 4
+
+var (
+	// claudeSlots holds one token per running claude subprocess
+	claudeSlots chan struct{}
+	slotsMu     sync.Mutex
+)
+
+func init() {
+	// Allow override via environment variable
+	max := DefaultMaxClaudeProcesses
+	if n, err := strconv.Atoi(os.Getenv("CMCP_MAX_CLAUDE_PROCS")); err == nil {
+		max = n
+	}
+	SetMaxClaudeProcesses(max)
+}
+
+// SetMaxClaudeProcesses changes how many claude subprocesses may run at once.
+// Subprocesses already running keep counting against the previous limit.
+func SetMaxClaudeProcesses(n int) {
+	if n < 1 {
+		n = 1
+	}
+	slotsMu.Lock()
+	claudeSlots = make(chan struct{}, n)
+	slotsMu.Unlock()
+}
+
+// runLimited runs fn while holding a claude process slot, waiting for one to free up
+func runLimited(ctx context.Context, fn func() error) error {
+	slotsMu.Lock()
+	slots := claudeSlots
+	slotsMu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-slots }()
+
+	return fn()
+}
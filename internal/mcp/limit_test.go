@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunLimitedBoundsConcurrency(t *testing.T) {
+	SetMaxClaudeProcesses(2)
+	defer SetMaxClaudeProcesses(DefaultMaxClaudeProcesses)
+
+	var running, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runLimited(context.Background(), func() error {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", peak)
+	}
+}
+
+func TestRunLimitedCancelled(t *testing.T) {
+	SetMaxClaudeProcesses(1)
+	defer SetMaxClaudeProcesses(DefaultMaxClaudeProcesses)
+
+	// Hold the only slot so the next caller has to wait
+	release := make(chan struct{})
+	go runLimited(context.Background(), func() error {
+		<-release
+		return nil
+	})
+	defer close(release)
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	called := false
+	err := runLimited(ctx, func() error {
+		called = true
+		return nil
+	})
+	if err == nil || called {
+		t.Errorf("runLimited() error = %v, called = %v; want context error without running", err, called)
+	}
+}
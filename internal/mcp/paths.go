@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// ResolvePaths returns a copy of the server with relative paths resolved against the
+// directory of the config file that defined it rather than wherever Claude runs: a
+// relative cwd, and, when no cwd is set, a command or args starting with ./ or ../.
+// With a cwd, relative commands and args are left for the server to resolve against it.
+func ResolvePaths(server *config.MCPServer) *config.MCPServer {
+	if server.BaseDir == "" || server.IsRemote() {
+		return server
+	}
+	resolved := *server
+	if server.Cwd != "" {
+		if !filepath.IsAbs(server.Cwd) {
+			resolved.Cwd = filepath.Join(server.BaseDir, server.Cwd)
+		}
+		return &resolved
+	}
+
+	resolve := func(path string) string {
+		if strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../") {
+			return filepath.Join(server.BaseDir, path)
+		}
+		return path
+	}
+	resolved.Command = resolve(server.Command)
+	if len(server.Args) > 0 {
+		resolved.Args = make([]string, len(server.Args))
+		for i, arg := range server.Args {
+			resolved.Args[i] = resolve(arg)
+		}
+	}
+	return &resolved
+}
@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestResolvePaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  config.MCPServer
+		command string
+		args    []string
+		cwd     string
+	}{
+		{
+			name:    "relative command and args",
+			server:  config.MCPServer{Command: "./bin/server", Args: []string{"../shared/run.js", "--port", "80", "build/index.js"}, BaseDir: "/work/app"},
+			command: "/work/app/bin/server",
+			args:    []string{"/work/shared/run.js", "--port", "80", "build/index.js"},
+		},
+		{
+			name:    "relative cwd keeps args relative to it",
+			server:  config.MCPServer{Command: "node", Args: []string{"./index.js"}, Cwd: "server", BaseDir: "/work/app"},
+			command: "node",
+			args:    []string{"./index.js"},
+			cwd:     "/work/app/server",
+		},
+		{
+			name:    "absolute cwd",
+			server:  config.MCPServer{Command: "node", Cwd: "/srv", BaseDir: "/work/app"},
+			command: "node",
+			cwd:     "/srv",
+		},
+		{
+			name:    "no base directory",
+			server:  config.MCPServer{Command: "./server", Args: []string{"./a.js"}},
+			command: "./server",
+			args:    []string{"./a.js"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ResolvePaths(&tt.server)
+			if got.Command != tt.command || !reflect.DeepEqual(got.Args, tt.args) || got.Cwd != tt.cwd {
+				t.Errorf("ResolvePaths() = %q %v cwd %q, want %q %v cwd %q", got.Command, got.Args, got.Cwd, tt.command, tt.args, tt.cwd)
+			}
+		})
+	}
+}
+
+func TestResolveLaunch(t *testing.T) {
+	t.Setenv("CMCP_TEST_REGION", "eu-west-1")
+	server := &config.MCPServer{
+		Command: "./bin/server",
+		Args:    []string{"--name", "{{serverName}}"},
+		Env:     map[string]string{"CMCP_TEST_REGION": config.InheritEnv},
+		BaseDir: "/work/app",
+	}
+
+	got, err := ResolveLaunch("api", server)
+	if err != nil {
+		t.Fatalf("ResolveLaunch() error = %v", err)
+	}
+	if got.Command != "/work/app/bin/server" || !reflect.DeepEqual(got.Args, []string{"--name", "api"}) || got.Env["CMCP_TEST_REGION"] != "eu-west-1" {
+		t.Errorf("ResolveLaunch() = %+v", got)
+	}
+	if server.Env["CMCP_TEST_REGION"] != config.InheritEnv || server.Args[1] != "{{serverName}}" {
+		t.Error("ResolveLaunch() modified the server")
+	}
+
+	server.Env["CMCP_TEST_UNSET"] = config.InheritEnv
+	if _, err := ResolveLaunch("api", server); err == nil {
+		t.Error("ResolveLaunch() should fail when inherited env is not set")
+	}
+}
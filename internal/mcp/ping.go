@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// PingResult holds the timings of a single launch-and-handshake run
+type PingResult struct {
+	Spawn      time.Duration // Time to launch the process (or prepare the HTTP connection)
+	Initialize time.Duration // Time from launch until the initialize response
+	ToolsList  time.Duration // Round trip of a tools/list request
+	Tools      int           // Number of tools advertised
+}
+
+// Ping launches a server, performs the handshake, lists its tools, and reports how long each phase took
+func Ping(ctx context.Context, server *config.MCPServer) (*PingResult, error) {
+	result := &PingResult{}
+
+	start := time.Now()
+	client, err := dial(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	result.Spawn = time.Since(start)
+
+	if err := client.initialize(ctx); err != nil {
+		return nil, err
+	}
+	result.Initialize = time.Since(start)
+
+	listStart := time.Now()
+	tools, err := client.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.ToolsList = time.Since(listStart)
+	result.Tools = len(tools)
+
+	return result, nil
+}
+
+// Percentile returns the p-th percentile (0-100) of the given durations using nearest-rank
+func Percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
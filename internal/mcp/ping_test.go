@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	durations := []time.Duration{
+		5 * time.Millisecond,
+		1 * time.Millisecond,
+		3 * time.Millisecond,
+		2 * time.Millisecond,
+		4 * time.Millisecond,
+	}
+
+	tests := []struct {
+		p        float64
+		expected time.Duration
+	}{
+		{0, 1 * time.Millisecond},
+		{50, 3 * time.Millisecond},
+		{90, 5 * time.Millisecond},
+		{100, 5 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		if got := Percentile(durations, tt.p); got != tt.expected {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.expected)
+		}
+	}
+
+	// The input must not be reordered
+	if durations[0] != 5*time.Millisecond {
+		t.Error("Percentile() should not modify its input")
+	}
+
+	// Nearest rank is ceil(p/100*n): 6.3 rounds up to the 7th of 7 values
+	seven := []time.Duration{1, 2, 3, 4, 5, 6, 7}
+	if got := Percentile(seven, 90); got != 7 {
+		t.Errorf("Percentile(7 values, 90) = %v, want the 7th value", got)
+	}
+
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("Percentile(nil) = %v, want 0", got)
+	}
+}
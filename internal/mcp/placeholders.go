@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// placeholderPattern matches {{name}} placeholders in args and cwd
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z]+)\s*\}\}`)
+
+// ExpandPlaceholders returns a copy of the server with {{projectDir}}, {{gitRoot}},
+// {{home}} and {{serverName}} in its args and cwd replaced, so one definition can point
+// at whichever project it is started in. Unknown placeholders are left as they are.
+func ExpandPlaceholders(name string, server *config.MCPServer) (*config.MCPServer, error) {
+	uses := placeholderPattern.MatchString(server.Cwd)
+	for _, arg := range server.Args {
+		uses = uses || placeholderPattern.MatchString(arg)
+	}
+	if !uses {
+		return server, nil
+	}
+
+	// Values are looked up once, and only if a placeholder needs them
+	values := map[string]string{"serverName": name}
+	var failed error
+	lookup := func(key string) (string, bool) {
+		if value, ok := values[key]; ok {
+			return value, true
+		}
+		var value string
+		var err error
+		switch key {
+		case "projectDir":
+			value, err = os.Getwd()
+		case "home":
+			value, err = os.UserHomeDir()
+		case "gitRoot":
+			value, err = gitRoot()
+		default:
+			return "", false
+		}
+		if err != nil && failed == nil {
+			failed = fmt.Errorf("failed to expand {{%s}}: %w", key, err)
+		}
+		values[key] = value
+		return value, true
+	}
+	expand := func(s string) string {
+		return placeholderPattern.ReplaceAllStringFunc(s, func(ref string) string {
+			if value, ok := lookup(placeholderPattern.FindStringSubmatch(ref)[1]); ok {
+				return value
+			}
+			return ref
+		})
+	}
+
+	expanded := *server
+	expanded.Cwd = expand(server.Cwd)
+	if len(server.Args) > 0 {
+		expanded.Args = make([]string, len(server.Args))
+		for i, arg := range server.Args {
+			expanded.Args[i] = expand(arg)
+		}
+	}
+	if failed != nil {
+		return nil, failed
+	}
+	return &expanded, nil
+}
+
+// gitRoot returns the top-level directory of the git repository cmcp runs in
+func gitRoot() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("not inside a git repository")
+	}
+	return strings.TrimSpace(string(output)), nil
+}
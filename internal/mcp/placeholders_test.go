@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestExpandPlaceholders(t *testing.T) {
+	cwd, _ := os.Getwd()
+	home, _ := os.UserHomeDir()
+
+	server := config.MCPServer{
+		Command: "npx",
+		Args:    []string{"-y", "server-filesystem", "{{projectDir}}", "{{ home }}/notes", "--name={{serverName}}", "{{unknown}}"},
+		Cwd:     "{{home}}",
+	}
+	expanded, err := ExpandPlaceholders("fs", &server)
+	if err != nil {
+		t.Fatalf("ExpandPlaceholders() error = %v", err)
+	}
+	want := []string{"-y", "server-filesystem", cwd, home + "/notes", "--name=fs", "{{unknown}}"}
+	if !reflect.DeepEqual(expanded.Args, want) || expanded.Cwd != home {
+		t.Errorf("ExpandPlaceholders() args = %v, cwd = %q; want %v, %q", expanded.Args, expanded.Cwd, want, home)
+	}
+	if server.Args[2] != "{{projectDir}}" || server.Cwd != "{{home}}" {
+		t.Error("ExpandPlaceholders() modified the configured server")
+	}
+
+	plain := config.MCPServer{Command: "npx", Args: []string{"-y", "pkg"}}
+	if got, _ := ExpandPlaceholders("plain", &plain); got != &plain {
+		t.Error("ExpandPlaceholders() should return servers without placeholders as is")
+	}
+}
+
+func TestExpandPlaceholdersOutsideGit(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	t.Setenv("GIT_CEILING_DIRECTORIES", dir)
+
+	server := config.MCPServer{Command: "node", Args: []string{"{{gitRoot}}/server.js"}}
+	if _, err := ExpandPlaceholders("app", &server); err == nil {
+		t.Error("ExpandPlaceholders() expected an error for {{gitRoot}} outside a repository")
+	}
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"encoding/json"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// PlanStep describes a single claude invocation that a dry-run would execute
+type PlanStep struct {
+	Operation string   `json:"operation"` // "start" or "stop"
+	Server    string   `json:"server"`
+	Method    string   `json:"method"` // "add", "add-json", or "remove"
+	Argv      []string `json:"argv"`
+}
+
+// Plan is the machine-readable form of a dry-run
+type Plan struct {
+	Steps []PlanStep `json:"steps"`
+}
+
+// UsesAddJSON reports whether a server must be registered with add-json
+// rather than the plain add command, which has no way to pass env or cwd
+func (b *ClaudeCmdBuilder) UsesAddJSON(server *config.MCPServer) bool {
+	return len(server.Env) > 0 || server.Cwd != "" || server.IsRemote()
+}
+
+// startArgs returns the full claude arguments used to start a server,
+// including the --debug flag that StartServer always adds
+func (b *ClaudeCmdBuilder) startArgs(name string, server *config.MCPServer) ([]string, string) {
+	var args []string
+	method := "add"
+	if b.UsesAddJSON(server) {
+		args = b.buildStartArgsJSON(name, server)
+		method = "add-json"
+	} else {
+		args = b.buildStartArgs(name, server)
+	}
+	args = append([]string{args[0], args[1], "--debug"}, args[2:]...)
+	return args, method
+}
+
+// PlanStart builds the plan step for starting a server, with sensitive values masked
+func (b *ClaudeCmdBuilder) PlanStart(name string, server *config.MCPServer) PlanStep {
+	args, method := b.startArgs(name, server)
+	if method == "add-json" {
+		// The JSON payload is always the last argument
+		masked, err := maskJSON([]byte(args[len(args)-1]), sensitiveKeysOf(server))
+		if err == nil {
+			args[len(args)-1] = string(masked)
+		}
+	} else {
+		args = maskArgs(args, sensitiveKeysOf(server))
+	}
+
+	return PlanStep{
+		Operation: "start",
+		Server:    name,
+		Method:    method,
+		Argv:      append([]string{b.claudeBin()}, args...),
+	}
+}
+
+// PlanStop builds the plan step for stopping a server
+func (b *ClaudeCmdBuilder) PlanStop(name string) PlanStep {
+	return PlanStep{
+		Operation: "stop",
+		Server:    name,
+		Method:    "remove",
+		Argv:      append(append([]string{b.claudeBin(), "mcp", "remove", "--debug"}, b.scopeArgs()...), name),
+	}
+}
+
+// PlanJSON renders a plan as indented JSON
+func PlanJSON(steps []PlanStep) (string, error) {
+	if steps == nil {
+		steps = []PlanStep{}
+	}
+	data, err := json.MarshalIndent(Plan{Steps: steps}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
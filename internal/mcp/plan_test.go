@@ -0,0 +1,120 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestPlanStart(t *testing.T) {
+	b := NewClaudeCmdBuilder()
+
+	t.Run("simple server uses add", func(t *testing.T) {
+		step := b.PlanStart("context7", &config.MCPServer{
+			Command: "npx",
+			Args:    []string{"-y", "@upstash/context7-mcp"},
+		})
+
+		expected := []string{"claude", "mcp", "add", "--debug", "context7", "--", "npx", "-y", "@upstash/context7-mcp"}
+		if step.Method != "add" {
+			t.Errorf("Method = %q, want %q", step.Method, "add")
+		}
+		if step.Operation != "start" || step.Server != "context7" {
+			t.Errorf("unexpected step: %+v", step)
+		}
+		if !slicesEqual(step.Argv, expected) {
+			t.Errorf("Argv = %v, want %v", step.Argv, expected)
+		}
+	})
+
+	t.Run("server with env uses add-json and masks secrets", func(t *testing.T) {
+		step := b.PlanStart("github", &config.MCPServer{
+			Command: "docker",
+			Args:    []string{"run", "-i", "--rm", "ghcr.io/github/github-mcp-server"},
+			Env: map[string]string{
+				"GITHUB_TOKEN": "ghp_secret123",
+			},
+		})
+
+		if step.Method != "add-json" {
+			t.Errorf("Method = %q, want %q", step.Method, "add-json")
+		}
+		if !slicesEqual(step.Argv[:5], []string{"claude", "mcp", "add-json", "--debug", "github"}) {
+			t.Errorf("unexpected argv prefix: %v", step.Argv)
+		}
+		joined := strings.Join(step.Argv, " ")
+		if strings.Contains(joined, "ghp_secret123") {
+			t.Errorf("plan should not expose secrets, got %v", joined)
+		}
+		if !strings.Contains(joined, `"GITHUB_TOKEN":"***"`) {
+			t.Errorf("plan should contain masked token, got %v", joined)
+		}
+	})
+
+	t.Run("server with cwd uses add-json", func(t *testing.T) {
+		step := b.PlanStart("local", &config.MCPServer{
+			Command: "go",
+			Args:    []string{"run", "./cmd/server"},
+			Cwd:     "/src/server",
+		})
+
+		if step.Method != "add-json" {
+			t.Errorf("Method = %q, want %q", step.Method, "add-json")
+		}
+		if payload := step.Argv[len(step.Argv)-1]; !strings.Contains(payload, `"cwd":"/src/server"`) {
+			t.Errorf("add-json payload should include cwd, got %v", payload)
+		}
+	})
+
+	t.Run("aliased server is added under its alias", func(t *testing.T) {
+		step := b.PlanStart("github-work", &config.MCPServer{Command: "npx", Args: []string{"server-github"}, Alias: "github"})
+
+		expected := []string{"claude", "mcp", "add", "--debug", "github", "--", "npx", "server-github"}
+		if step.Server != "github-work" || !slicesEqual(step.Argv, expected) {
+			t.Errorf("step = %+v, want server github-work and Argv %v", step, expected)
+		}
+
+		step = b.PlanStart("github-work", &config.MCPServer{Command: "npx", Env: map[string]string{"ORG": "work"}, Alias: "github"})
+		if step.Argv[4] != "github" || strings.Contains(step.Argv[5], "alias") {
+			t.Errorf("add-json should use the alias as name and leave it out of the payload, got %v", step.Argv)
+		}
+	})
+}
+
+func TestPlanStop(t *testing.T) {
+	b := NewClaudeCmdBuilder()
+
+	step := b.PlanStop("github")
+	expected := []string{"claude", "mcp", "remove", "--debug", "github"}
+	if step.Method != "remove" || step.Operation != "stop" {
+		t.Errorf("unexpected step: %+v", step)
+	}
+	if !slicesEqual(step.Argv, expected) {
+		t.Errorf("Argv = %v, want %v", step.Argv, expected)
+	}
+}
+
+func TestPlanJSON(t *testing.T) {
+	b := NewClaudeCmdBuilder()
+
+	out, err := PlanJSON([]PlanStep{b.PlanStop("a"), b.PlanStop("b")})
+	if err != nil {
+		t.Fatalf("PlanJSON() error = %v", err)
+	}
+
+	var plan Plan
+	if err := json.Unmarshal([]byte(out), &plan); err != nil {
+		t.Fatalf("PlanJSON() produced invalid JSON: %v", err)
+	}
+	if len(plan.Steps) != 2 || plan.Steps[1].Server != "b" {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+
+	// An empty plan should still be a valid, non-null list
+	out, _ = PlanJSON(nil)
+	if !strings.Contains(out, `"steps": []`) {
+		t.Errorf("empty plan should render an empty list, got %v", out)
+	}
+}
@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// probeTimeout bounds the direct handshake and tools/list of deep verification
+var probeTimeout = 30 * time.Second
+
+// ProbeTools connects to a server directly, as inspect does, and returns how many
+// tools it advertises. A server without tools fails with ErrNoTools, since one that
+// connects but offers nothing is usually misconfigured (e.g. a missing API key).
+func ProbeTools(ctx context.Context, server *config.MCPServer) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	client, err := Connect(ctx, server)
+	if err != nil {
+		return 0, err
+	}
+	defer client.Close()
+	return countTools(ctx, client)
+}
+
+// countTools lists a connected client's tools, failing when there are none
+func countTools(ctx context.Context, client *Client) (int, error) {
+	tools, err := client.ListTools(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list tools: %w", err)
+	}
+	if len(tools) == 0 {
+		return 0, ErrNoTools
+	}
+	return len(tools), nil
+}
+
+// deepVerify probes a server Claude reports as connected, when DeepVerify is set
+func (b *ClaudeCmdBuilder) deepVerify(name string, server *config.MCPServer, verbose bool) error {
+	if !b.DeepVerify {
+		return nil
+	}
+	if !verbose {
+		b.step("Listing the tools of '%s'...", name)
+		defer b.clearProgress()
+	}
+
+	done := b.Timings.Track(name, "tools/list probe", TimingServer)
+	count, err := ProbeTools(b.context(), server)
+	done()
+	if err != nil {
+		if ctxErr := b.context().Err(); ctxErr != nil {
+			return fmt.Errorf("interrupted while probing server '%s': %w", name, ctxErr)
+		}
+		return &ProbeError{Server: name, Err: err}
+	}
+	if verbose {
+		fmt.Fprintf(b.stdout(), "Server '%s' advertises %d tool(s)\n", name, count)
+	}
+	return nil
+}
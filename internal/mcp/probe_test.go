@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCountTools(t *testing.T) {
+	noTools := func(method string, params json.RawMessage) (interface{}, *RPCError) {
+		if method == "tools/list" {
+			return map[string]interface{}{"tools": []interface{}{}}, nil
+		}
+		return basicHandler(method, params)
+	}
+	broken := func(method string, params json.RawMessage) (interface{}, *RPCError) {
+		if method == "tools/list" {
+			return nil, &RPCError{Code: -32603, Message: "missing API key"}
+		}
+		return basicHandler(method, params)
+	}
+
+	tests := []struct {
+		name    string
+		handle  func(string, json.RawMessage) (interface{}, *RPCError)
+		want    int
+		wantErr string
+	}{
+		{"tools across pages", basicHandler, 2, ""},
+		{"no tools", noTools, 0, ErrNoTools.Error()},
+		{"tools/list error", broken, 0, "failed to list tools: server error -32603: missing API key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := countTools(context.Background(), fakeServer(t, tt.handle))
+			if count != tt.want {
+				t.Errorf("countTools() = %d, want %d", count, tt.want)
+			}
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("countTools() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProbeError(t *testing.T) {
+	err := error(&ProbeError{Server: "api", Err: ErrNoTools})
+	if !errors.Is(err, ErrNoTools) {
+		t.Error("ProbeError should match ErrNoTools")
+	}
+	if want := "connected, but the tools/list probe failed: server advertises no tools"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
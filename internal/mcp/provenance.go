@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// ValueSource tells where a value of a server's definition comes from
+type ValueSource struct {
+	Field     string   // "env GITHUB_TOKEN", "args[2]", "cwd" or "header Authorization"
+	Source    string   // e.g. "config literal" or "expanded by Claude from ${GH_TOKEN}"
+	Value     string   // What the server gets; empty when only known at start time
+	Missing   []string // Variables the value needs that are not set in this shell
+	Sensitive bool     // The value should be masked for display
+}
+
+// ValueSources describes where each env value of a server comes from, and the
+// templated args, cwd and headers, as resolved when it starts. It has no side
+// effects: tokenCommand is not run, so ${token} values are left out.
+func ValueSources(name string, server *config.MCPServer) []ValueSource {
+	return valueSources(name, server, os.LookupEnv)
+}
+
+func valueSources(name string, server *config.MCPServer, lookupEnv func(string) (string, bool)) []ValueSource {
+	var sources []ValueSource
+	for _, v := range resolvedEnv(server, lookupEnv) {
+		sources = append(sources, ValueSource{
+			Field:     "env " + v.Key,
+			Source:    v.Source,
+			Value:     v.Value,
+			Missing:   v.Missing,
+			Sensitive: v.Sensitive,
+		})
+	}
+
+	// Args and cwd take cmcp's {{placeholders}} first, then Claude's ${VAR} references
+	templated := func(field, value string) {
+		var origins []string
+		if placeholderPattern.MatchString(value) {
+			expanded, err := ExpandPlaceholders(name, &config.MCPServer{Args: []string{value}})
+			for _, match := range placeholderPattern.FindAllString(value, -1) {
+				origins = append(origins, match)
+			}
+			if err != nil {
+				sources = append(sources, ValueSource{Field: field, Source: fmt.Sprintf("cmcp placeholders %s (%v)", strings.Join(origins, ", "), err)})
+				return
+			}
+			// Unknown placeholders are left as they are
+			if expanded.Args[0] == value {
+				origins = nil
+			} else {
+				value = expanded.Args[0]
+				origins = []string{"cmcp placeholders " + strings.Join(origins, ", ")}
+			}
+		}
+		expanded, refs, missing := expandClaudeReferences(value, lookupEnv)
+		if len(refs) > 0 {
+			origins = append(origins, "expanded by Claude from "+strings.Join(refs, ", "))
+		}
+		if len(origins) == 0 {
+			return
+		}
+		sensitive := false
+		for _, ref := range refs {
+			sensitive = sensitive || IsSensitiveKey(claudeReference.FindStringSubmatch(ref)[1])
+		}
+		sources = append(sources, ValueSource{
+			Field:     field,
+			Source:    strings.Join(origins, ", then "),
+			Value:     expanded,
+			Missing:   missing,
+			Sensitive: sensitive,
+		})
+	}
+	for i, arg := range server.Args {
+		templated(fmt.Sprintf("args[%d]", i), arg)
+	}
+	if server.Cwd != "" {
+		templated("cwd", server.Cwd)
+	}
+
+	keys := make([]string, 0, len(server.Headers))
+	for key := range server.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		refs := headerRefPattern.FindAllString(server.Headers[key], -1)
+		if len(refs) == 0 {
+			continue
+		}
+		source := ValueSource{
+			Field:     "header " + key,
+			Source:    "filled in by cmcp at start from " + strings.Join(refs, ", "),
+			Sensitive: IsSensitiveServerKey(server, key),
+		}
+		for _, ref := range refs {
+			if ref == "${token}" {
+				source.Source += " (tokenCommand output)"
+				continue
+			}
+			variable := strings.TrimSuffix(strings.TrimPrefix(ref, "${env:"), "}")
+			if _, ok := lookupEnv(variable); !ok {
+				source.Missing = append(source.Missing, variable)
+			}
+		}
+		sources = append(sources, source)
+	}
+	return sources
+}
@@ -0,0 +1,35 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestValueSources(t *testing.T) {
+	shell := map[string]string{"GH_TOKEN": "ghp_123", "API_TOKEN": "secret"}
+	lookup := func(key string) (string, bool) {
+		value, ok := shell[key]
+		return value, ok
+	}
+	server := config.MCPServer{
+		Command: "npx",
+		Args:    []string{"-y", "srv", "--name={{serverName}}", "--token=${API_TOKEN}", "--port=${PORT:-80}", "{{unknown}}"},
+		Env:     map[string]string{"GITHUB_TOKEN": "${GH_TOKEN}", "LEVEL": "debug"},
+		Headers: map[string]string{"Authorization": "Bearer ${token}", "X-Region": "${env:REGION}", "X-Plain": "a"},
+	}
+
+	want := []ValueSource{
+		{Field: "env GITHUB_TOKEN", Source: "expanded by Claude from ${GH_TOKEN}", Value: "ghp_123", Sensitive: true},
+		{Field: "env LEVEL", Source: "config literal", Value: "debug"},
+		{Field: "args[2]", Source: "cmcp placeholders {{serverName}}", Value: "--name=gh"},
+		{Field: "args[3]", Source: "expanded by Claude from ${API_TOKEN}", Value: "--token=secret", Sensitive: true},
+		{Field: "args[4]", Source: "expanded by Claude from ${PORT:-80}", Value: "--port=80"},
+		{Field: "header Authorization", Source: "filled in by cmcp at start from ${token} (tokenCommand output)", Sensitive: true},
+		{Field: "header X-Region", Source: "filled in by cmcp at start from ${env:REGION}", Missing: []string{"REGION"}},
+	}
+	if got := valueSources("gh", &server, lookup); !reflect.DeepEqual(got, want) {
+		t.Errorf("valueSources() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// noProxy is the per-server proxy value that forces a direct connection
+const noProxy = "none"
+
+// proxyFunc returns how cmcp picks a proxy for a remote server: the server's proxy
+// setting when present, otherwise HTTP_PROXY, HTTPS_PROXY, and NO_PROXY
+func proxyFunc(server *config.MCPServer) (func(*http.Request) (*url.URL, error), error) {
+	switch server.Proxy {
+	case "":
+		return http.ProxyFromEnvironment, nil
+	case noProxy:
+		return nil, nil
+	}
+	proxyURL, err := url.Parse(server.Proxy)
+	if err != nil || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy '%s': expected a URL such as http://proxy.example.com:8080 or \"%s\"", server.Proxy, noProxy)
+	}
+	return http.ProxyURL(proxyURL), nil
+}
+
+// ProxyFor returns the proxy cmcp uses to reach a remote server, or nil for a direct connection
+func ProxyFor(server *config.MCPServer) (*url.URL, error) {
+	proxy, err := proxyFunc(server)
+	if err != nil || proxy == nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, server.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return proxy(req)
+}
+
+// isProxyError reports whether a request failed at the proxy rather than at the server
+func isProxyError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "proxyconnect") || strings.Contains(msg, "Proxy Authentication Required")
+}
+
+// wrapProxyError points proxy failures at the proxy settings instead of the server
+func wrapProxyError(err error) error {
+	if err == nil || !isProxyError(err) {
+		return err
+	}
+	return fmt.Errorf("%w (the proxy failed; check HTTPS_PROXY/NO_PROXY or the server's proxy setting)", err)
+}
+
+// getDiagnosticsForProxy explains which proxy applies to a remote server, for cmcp and for Claude
+func getDiagnosticsForProxy(server *config.MCPServer) []string {
+	host := server.URL
+	if u, err := url.Parse(server.URL); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+
+	proxyURL, err := ProxyFor(server)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var suggestions []string
+	switch {
+	case server.Proxy == noProxy:
+		if envProxy, _ := ProxyFor(&config.MCPServer{URL: server.URL}); envProxy != nil {
+			suggestions = append(suggestions, fmt.Sprintf("cmcp connects to %s directly, but Claude uses the proxy from its environment (%s); add %s to NO_PROXY", host, envProxy.Redacted(), host))
+		}
+	case server.Proxy != "":
+		suggestions = append(suggestions, fmt.Sprintf("Claude doesn't read the proxy setting; start Claude with HTTPS_PROXY=%s to reach %s the same way", proxyURL.Redacted(), host))
+	case proxyURL != nil:
+		suggestions = append(suggestions, fmt.Sprintf("Requests to %s go through the proxy %s from HTTPS_PROXY/HTTP_PROXY; if the server is internal, add %s to NO_PROXY", host, proxyURL.Redacted(), host))
+	}
+	return suggestions
+}
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// scriptExtensions are the argument suffixes treated as script paths that must exist
+var scriptExtensions = []string{".js", ".mjs", ".cjs", ".ts", ".py", ".rb", ".jar", ".sh", ".php"}
+
+// DeadServer is a configured server that can no longer be launched
+type DeadServer struct {
+	Name   string
+	Reason string
+}
+
+// launchChecks abstracts the lookups FindDeadServers makes, so they can be faked in tests
+type launchChecks struct {
+	lookPath     func(string) (string, error)
+	fileExists   func(string) bool
+	imagePresent func(string) bool // nil when docker is not reachable
+}
+
+// FindDeadServers returns the stdio servers whose command is no longer on PATH, whose
+// script is gone, or whose docker image was removed. Remote and disabled servers are
+// not checked, and images are only checked when the docker daemon answers.
+func FindDeadServers(cfg *config.Config) []DeadServer {
+	checks := launchChecks{
+		lookPath: exec.LookPath,
+		fileExists: func(path string) bool {
+			_, err := os.Stat(path)
+			return err == nil
+		},
+	}
+	if DockerAvailable() {
+		checks.imagePresent = ImagePresent
+	}
+
+	var dead []DeadServer
+	for _, name := range cfg.GetServerNames() {
+		server := cfg.MCPServers[name]
+		if server.IsRemote() || server.IsDisabled() {
+			continue
+		}
+		if reason := deadReason(ResolvePaths(&server), checks); reason != "" {
+			dead = append(dead, DeadServer{Name: name, Reason: reason})
+		}
+	}
+	return dead
+}
+
+// deadReason explains why a server cannot be launched, or returns "" if it looks fine
+func deadReason(server *config.MCPServer, checks launchChecks) string {
+	command := strings.TrimSpace(server.Command)
+	if command == "" {
+		return ""
+	}
+	if _, err := checks.lookPath(command); err != nil {
+		if strings.ContainsRune(command, filepath.Separator) {
+			return fmt.Sprintf("command '%s' no longer exists", command)
+		}
+		return fmt.Sprintf("command '%s' is not on PATH", command)
+	}
+
+	if filepath.Base(command) == "docker" {
+		if image, ok := DockerImage(server.Args); ok && checks.imagePresent != nil && !checks.imagePresent(image) {
+			return fmt.Sprintf("docker image '%s' was removed", image)
+		}
+		return ""
+	}
+
+	for _, arg := range server.Args {
+		// Placeholders are only expanded when the server starts
+		if !isScriptPath(arg) || strings.Contains(arg, "{{") || strings.Contains(server.Cwd, "{{") {
+			continue
+		}
+		path := arg
+		if !filepath.IsAbs(path) {
+			// Without a cwd, Claude resolves the script against whichever project it runs in
+			if server.Cwd == "" {
+				continue
+			}
+			path = filepath.Join(server.Cwd, path)
+		}
+		if !checks.fileExists(path) {
+			return fmt.Sprintf("script '%s' no longer exists", arg)
+		}
+	}
+	return ""
+}
+
+// isScriptPath reports whether an argument names a local script file rather than a
+// flag or a package such as @scope/server.js
+func isScriptPath(arg string) bool {
+	if strings.HasPrefix(arg, "-") || strings.HasPrefix(arg, "@") || strings.Contains(arg, "://") {
+		return false
+	}
+	for _, ext := range scriptExtensions {
+		if strings.HasSuffix(strings.ToLower(arg), ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestDeadReason(t *testing.T) {
+	checks := launchChecks{
+		lookPath: func(command string) (string, error) {
+			if command == "npx" || command == "node" || command == "docker" || command == "/usr/local/bin/server" {
+				return command, nil
+			}
+			return "", fmt.Errorf("not found")
+		},
+		fileExists:   func(path string) bool { return path == "/srv/app/index.js" },
+		imagePresent: func(image string) bool { return image == "ghcr.io/acme/kept:1" },
+	}
+
+	tests := []struct {
+		name   string
+		server config.MCPServer
+		want   string
+	}{
+		{name: "command on PATH", server: config.MCPServer{Command: "npx", Args: []string{"-y", "@acme/server.js"}}},
+		{name: "command missing", server: config.MCPServer{Command: "uvx", Args: []string{"mcp-server-fetch"}}, want: "command 'uvx' is not on PATH"},
+		{name: "absolute command removed", server: config.MCPServer{Command: "/opt/old/server"}, want: "command '/opt/old/server' no longer exists"},
+		{name: "script present", server: config.MCPServer{Command: "node", Args: []string{"/srv/app/index.js"}}},
+		{name: "script removed", server: config.MCPServer{Command: "node", Args: []string{"/srv/old/index.js"}}, want: "script '/srv/old/index.js' no longer exists"},
+		{name: "relative script resolved against cwd", server: config.MCPServer{Command: "node", Args: []string{"index.js"}, Cwd: "/srv/app"}},
+		{name: "relative script without cwd is not checked", server: config.MCPServer{Command: "node", Args: []string{"build/index.js"}}},
+		{name: "docker image present", server: config.MCPServer{Command: "docker", Args: []string{"run", "-i", "--rm", "ghcr.io/acme/kept:1"}}},
+		{name: "docker image removed", server: config.MCPServer{Command: "docker", Args: []string{"run", "-i", "-e", "TOKEN", "ghcr.io/acme/gone:2"}}, want: "docker image 'ghcr.io/acme/gone:2' was removed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deadReason(&tt.server, checks); got != tt.want {
+				t.Errorf("deadReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Without a reachable daemon, images are not reported as removed
+	checks.imagePresent = nil
+	server := config.MCPServer{Command: "docker", Args: []string{"run", "ghcr.io/acme/gone:2"}}
+	if got := deadReason(&server, checks); got != "" {
+		t.Errorf("deadReason() without docker = %q, want no reason", got)
+	}
+}
@@ -0,0 +1,29 @@
+package mcp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// shellSafe matches words a POSIX shell reads literally without quoting
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// shellVariableUnsafe matches the characters replaced when deriving a variable name
+var shellVariableUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// ShellQuote quotes a word for a POSIX shell, leaving words that need no quoting as they are
+func ShellQuote(word string) string {
+	if shellSafe.MatchString(word) {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// ShellJoin quotes each word and joins them into a command line that can be pasted into a shell
+func ShellJoin(words []string) string {
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = ShellQuote(word)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"npx", "npx"},
+		{"@scope/pkg@1.2", "@scope/pkg@1.2"},
+		{"--port=80", "--port=80"},
+		{"", "''"},
+		{"my dir", "'my dir'"},
+		{"API_KEY=***", "'API_KEY=***'"},
+		{"it's", `'it'\''s'`},
+		{`{"command":"npx"}`, `'{"command":"npx"}'`},
+		{"$HOME", "'$HOME'"},
+	}
+	for _, tt := range tests {
+		if got := ShellQuote(tt.word); got != tt.want {
+			t.Errorf("ShellQuote(%q) = %s, want %s", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestDisplayedCommandsAreQuoted(t *testing.T) {
+	b := NewClaudeCmdBuilder()
+	server := &config.MCPServer{Command: "node", Args: []string{"/Users/me/My Servers/index.js", "--greeting", "it's me"}}
+	want := `claude mcp add fs -- node '/Users/me/My Servers/index.js' --greeting 'it'\''s me'`
+	if got := b.BuildStartCommand("fs", server); got != want {
+		t.Errorf("BuildStartCommand() = %s, want %s", got, want)
+	}
+
+	server.Cwd = "/srv/it's"
+	got := b.BuildStartCommandJSON("fs", server, false)
+	if !strings.Contains(got, `"cwd":"/srv/it'\''s"`) || !strings.HasSuffix(got, "}'") {
+		t.Errorf("BuildStartCommandJSON() = %s, want the JSON single-quoted", got)
+	}
+}
+
+func TestBuildStartCommandPlain(t *testing.T) {
+	b := NewClaudeCmdBuilder()
+	server := &config.MCPServer{
+		Command: "npx",
+		Args:    []string{"-y", "server-github"},
+		Env:     map[string]string{"GITHUB_TOKEN": "ghp_secret", "MODE": "it's"},
+	}
+
+	got := b.BuildStartCommandPlain("github", server)
+	want := `claude mcp add-json github '{"args":["-y","server-github"],"command":"npx","env":{"GITHUB_TOKEN":"'"$GITHUB_TOKEN"'","MODE":"it'\''s"}}'`
+	if got != want {
+		t.Errorf("BuildStartCommandPlain() =\n%s\nwant\n%s", got, want)
+	}
+	if server.Env["GITHUB_TOKEN"] != "ghp_secret" {
+		t.Error("BuildStartCommandPlain() modified the server")
+	}
+
+	remote := &config.MCPServer{Type: "http", URL: "https://example.com/mcp", Headers: map[string]string{"X-Api-Key": "abc"}}
+	if got := b.BuildStartCommandPlain("docs", remote); !strings.Contains(got, `"X-Api-Key":"'"$X_API_KEY"'"`) || strings.Contains(got, "abc") {
+		t.Errorf("BuildStartCommandPlain() = %s, want the header read from $X_API_KEY", got)
+	}
+
+	stdio := &config.MCPServer{Command: "uvx", Args: []string{"mcp-server-fetch"}}
+	if got := b.BuildStartCommandPlain("fetch", stdio); got != "claude mcp add fetch -- uvx mcp-server-fetch" {
+		t.Errorf("BuildStartCommandPlain() = %s", got)
+	}
+
+	local := &config.MCPServer{
+		Command: "./bin/server",
+		Args:    []string{"--name", "{{serverName}}", "--api-key", "k123"},
+		Env:     map[string]string{"AWS_PROFILE": config.InheritEnv},
+		BaseDir: "/srv/team",
+	}
+	got = b.BuildStartCommandPlain("local", local)
+	want = `claude mcp add-json local '{"args":["--name","local","--api-key","'"$API_KEY"'"],"command":"/srv/team/bin/server","env":{"AWS_PROFILE":"'"$AWS_PROFILE"'"}}'`
+	if got != want {
+		t.Errorf("BuildStartCommandPlain() =\n%s\nwant\n%s", got, want)
+	}
+
+	flagged := &config.MCPServer{Command: "server", Args: []string{"--token", "abc"}}
+	if got := b.BuildStartCommandPlain("flagged", flagged); got != `claude mcp add flagged -- server --token ''"$TOKEN"''` {
+		t.Errorf("BuildStartCommandPlain() = %s", got)
+	}
+}
@@ -0,0 +1,222 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// RecordedMessage is one line of a session recording
+type RecordedMessage struct {
+	Time    time.Time       `json:"time"`
+	From    string          `json:"from"` // "client" or "server"
+	Message json.RawMessage `json:"message"`
+}
+
+// Recorder appends the JSON-RPC messages passing through a session to a recording
+type Recorder struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	err     error
+}
+
+// NewRecorder returns a recorder writing JSON lines to w
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{encoder: json.NewEncoder(w)}
+}
+
+// Err returns the first error writing the recording
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) record(from string, line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+	// Keep output that isn't JSON (such as logs on stdout) as a string, it is often the bug
+	message := json.RawMessage(line)
+	if !json.Valid(line) {
+		message, _ = json.Marshal(string(line))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.encoder.Encode(RecordedMessage{Time: time.Now().UTC(), From: from, Message: message}); err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+// lineTap records every complete line that passes through it
+type lineTap struct {
+	recorder *Recorder
+	from     string
+	partial  []byte
+}
+
+func (t *lineTap) observe(p []byte) {
+	t.partial = append(t.partial, p...)
+	for {
+		i := bytes.IndexByte(t.partial, '\n')
+		if i < 0 {
+			return
+		}
+		t.recorder.record(t.from, t.partial[:i])
+		t.partial = t.partial[i+1:]
+	}
+}
+
+type tapReader struct {
+	lineTap
+	r io.Reader
+}
+
+func (t *tapReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	t.observe(p[:n])
+	return n, err
+}
+
+type tapWriter struct {
+	lineTap
+	w io.Writer
+}
+
+func (t *tapWriter) Write(p []byte) (int, error) {
+	t.observe(p)
+	return t.w.Write(p)
+}
+
+// Client wraps the client side of a session, recording what the client sends
+func (r *Recorder) Client(stdin io.Reader) io.Reader {
+	return &tapReader{lineTap: lineTap{recorder: r, from: "client"}, r: stdin}
+}
+
+// Server wraps the server's output, recording what the server sends
+func (r *Recorder) Server(stdout io.Writer) io.Writer {
+	return &tapWriter{lineTap: lineTap{recorder: r, from: "server"}, w: stdout}
+}
+
+// LoadRecording reads a session recording
+func LoadRecording(r io.Reader) ([]RecordedMessage, error) {
+	var messages []RecordedMessage
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var msg RecordedMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			return nil, fmt.Errorf("invalid recording at line %d: %w", line, err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, scanner.Err()
+}
+
+// ReplayResult is the outcome of replaying one recorded request
+type ReplayResult struct {
+	Method   string
+	Response json.RawMessage // The new result or error
+	Recorded json.RawMessage // The recorded result or error, nil if the recording has none
+	Err      error           // Set when the request got no response
+}
+
+// Same reports whether the new response matches the recorded one
+func (r ReplayResult) Same() bool {
+	return r.Err == nil && r.Recorded != nil && equalJSON(r.Response, r.Recorded)
+}
+
+// recordedRPC is the part of a recorded JSON-RPC message replay needs
+type recordedRPC struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
+// outcome is the result or error of a response, which is what replay compares
+func (m recordedRPC) outcome() json.RawMessage {
+	data, _ := json.Marshal(map[string]json.RawMessage{"result": m.Result, "error": m.Error})
+	return data
+}
+
+// Replay sends the client messages of a recording to a server in order, including the
+// recorded initialize, and reports each response next to the recorded one
+func Replay(ctx context.Context, server *config.MCPServer, messages []RecordedMessage, each func(ReplayResult)) error {
+	recordedResponses := make(map[string]json.RawMessage)
+	var requests []recordedRPC
+	for _, msg := range messages {
+		var rpc recordedRPC
+		if json.Unmarshal(msg.Message, &rpc) != nil {
+			continue
+		}
+		switch {
+		case msg.From == "client" && rpc.Method != "":
+			requests = append(requests, rpc)
+		case msg.From == "server" && rpc.Method == "" && len(rpc.ID) > 0:
+			recordedResponses[string(rpc.ID)] = rpc.outcome()
+		}
+	}
+	if len(requests) == 0 {
+		return fmt.Errorf("the recording has no client requests")
+	}
+
+	client, err := dial(ctx, server)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	for _, req := range requests {
+		var params interface{}
+		if len(req.Params) > 0 {
+			params = req.Params
+		}
+		if len(req.ID) == 0 || string(req.ID) == "null" {
+			if err := client.Notify(ctx, req.Method, params); err != nil {
+				return fmt.Errorf("failed to send %s: %w", req.Method, err)
+			}
+			continue
+		}
+
+		result := ReplayResult{Method: req.Method, Recorded: recordedResponses[string(req.ID)]}
+		id := atomic.AddInt64(&client.nextID, 1)
+		resp, err := client.transport.roundTrip(ctx, &rpcMessage{JSONRPC: "2.0", ID: &id, Method: req.Method, Params: params})
+		if err != nil {
+			result.Err = err
+		} else {
+			errData, _ := json.Marshal(resp.Error)
+			if resp.Error == nil {
+				errData = nil
+			}
+			result.Response = recordedRPC{Result: resp.Result, Error: errData}.outcome()
+		}
+		each(result)
+	}
+	return nil
+}
+
+// equalJSON compares two JSON documents ignoring formatting and key order
+func equalJSON(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return bytes.Equal(a, b)
+	}
+	ca, _ := json.Marshal(va)
+	cb, _ := json.Marshal(vb)
+	return bytes.Equal(ca, cb)
+}
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestRecorder(t *testing.T) {
+	var recording, stdout bytes.Buffer
+	recorder := NewRecorder(&recording)
+
+	client := recorder.Client(strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize"}` + "\n\n"))
+	if _, err := io.ReadAll(client); err != nil {
+		t.Fatal(err)
+	}
+	server := recorder.Server(&stdout)
+	// Split writes and a stray log line must still be recorded line by line
+	server.Write([]byte(`{"jsonrpc":"2.0",`))
+	server.Write([]byte(`"id":1,"result":{}}` + "\nstarting up\n"))
+
+	if stdout.String() != `{"jsonrpc":"2.0","id":1,"result":{}}`+"\nstarting up\n" {
+		t.Errorf("server output not passed through: %q", stdout.String())
+	}
+
+	messages, err := LoadRecording(&recording)
+	if err != nil {
+		t.Fatalf("LoadRecording() error = %v", err)
+	}
+	want := []struct{ from, message string }{
+		{"client", `{"jsonrpc":"2.0","id":1,"method":"initialize"}`},
+		{"server", `{"jsonrpc":"2.0","id":1,"result":{}}`},
+		{"server", `"starting up"`},
+	}
+	if len(messages) != len(want) {
+		t.Fatalf("recorded %d messages, want %d", len(messages), len(want))
+	}
+	for i, w := range want {
+		if messages[i].From != w.from || string(messages[i].Message) != w.message {
+			t.Errorf("message %d = %s %s, want %s %s", i, messages[i].From, messages[i].Message, w.from, w.message)
+		}
+		if messages[i].Time.IsZero() {
+			t.Errorf("message %d has no time", i)
+		}
+	}
+
+	if _, err := LoadRecording(strings.NewReader("not json\n")); err == nil {
+		t.Error("LoadRecording() of an invalid file should fail")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test server is a shell script")
+	}
+
+	// Answers every request with its own ID
+	server := &config.MCPServer{
+		Command: "sh",
+		Args: []string{"-c", `while read line; do
+  id=$(echo "$line" | sed -n 's/.*"id":\([0-9]*\).*/\1/p')
+  [ -n "$id" ] && echo "{\"jsonrpc\":\"2.0\",\"id\":$id,\"result\":{\"n\":$id}}"
+done`},
+	}
+
+	message := func(from, msg string) RecordedMessage {
+		return RecordedMessage{Time: time.Now(), From: from, Message: json.RawMessage(msg)}
+	}
+	messages := []RecordedMessage{
+		message("client", `{"jsonrpc":"2.0","id":7,"method":"initialize","params":{}}`),
+		message("server", `{"jsonrpc":"2.0","id":7,"result":{ "n" : 1 }}`),
+		message("client", `{"jsonrpc":"2.0","method":"notifications/initialized"}`),
+		message("server", `"log line"`),
+		message("client", `{"jsonrpc":"2.0","id":8,"method":"tools/list"}`),
+		message("server", `{"jsonrpc":"2.0","id":8,"result":{"n":5}}`),
+		message("client", `{"jsonrpc":"2.0","id":9,"method":"ping"}`),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var results []ReplayResult
+	if err := Replay(ctx, server, messages, func(r ReplayResult) { results = append(results, r) }); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3 requests", len(results))
+	}
+	if results[0].Method != "initialize" || !results[0].Same() {
+		t.Errorf("initialize should match the recording: %+v", results[0])
+	}
+	if results[1].Method != "tools/list" || results[1].Same() {
+		t.Errorf("tools/list should differ from the recording: %+v", results[1])
+	}
+	if results[2].Recorded != nil || results[2].Same() || results[2].Err != nil {
+		t.Errorf("ping has no recorded response: %+v", results[2])
+	}
+
+	if err := Replay(ctx, server, messages[1:2], func(ReplayResult) {}); err == nil {
+		t.Error("Replay() without client requests should fail")
+	}
+}
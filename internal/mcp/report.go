@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ansiEscape matches the color codes embedded in error messages
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// Failure is one server that failed during a multi-server operation
+type Failure struct {
+	Server      string   `json:"server"`
+	Summary     string   `json:"summary"` // One line, for listing failures without their details
+	Error       string   `json:"error"`
+	ServerError string   `json:"serverError,omitempty"`
+	HealthCheck string   `json:"healthCheck,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+	DebugLog    string   `json:"debugLog,omitempty"`
+}
+
+// NewFailure records why a server failed, without the colors of the error message
+func NewFailure(server string, err error) Failure {
+	message := strings.TrimSpace(ansiEscape.ReplaceAllString(err.Error(), ""))
+	failure := Failure{Server: server, Error: message, Summary: firstLine(message), DebugLog: debugLogOf(err)}
+	var diagnosed *DiagnosticError
+	if errors.As(err, &diagnosed) && diagnosed.Diagnostics != nil {
+		diag := diagnosed.Diagnostics
+		failure.ServerError = maskSensitiveOutput(diag.StdErr)
+		failure.HealthCheck = diag.HealthCheck
+		failure.Suggestions = diag.Suggestions
+
+		// "Connection failed" says little on its own; add the cause
+		cause := firstLine(failure.ServerError)
+		if cause == "" && diag.Error != nil {
+			cause = firstLine(diag.Error.Error())
+		}
+		if cause != "" {
+			failure.Summary += ": " + cause
+		}
+	}
+	return failure
+}
+
+// FailureReport summarizes the failures of one multi-server operation
+type FailureReport struct {
+	Operation string    `json:"operation"`
+	Time      time.Time `json:"time"`
+	Total     int       `json:"total"`
+	Failures  []Failure `json:"failures"`
+}
+
+// Text renders the report for reading, one section per failed server
+func (r *FailureReport) Text() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("cmcp %s failure report, %s\n", r.Operation, r.Time.Format("2006-01-02 15:04:05")))
+	sb.WriteString(fmt.Sprintf("%d of %d server(s) failed\n", len(r.Failures), r.Total))
+	for _, failure := range r.Failures {
+		sb.WriteString(fmt.Sprintf("\n== %s ==\n%s\n", failure.Server, failure.Error))
+	}
+	return sb.String()
+}
+
+// WriteFailureReport saves the report as text and JSON next to the debug logs and
+// returns the path of the text version
+func WriteFailureReport(report *FailureReport) (string, error) {
+	return writeFailureReport(debugLogDir(), report)
+}
+
+func writeFailureReport(dir string, report *FailureReport) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create report dir: %w", err)
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("cmcp-%s-failures-%s", report.Operation, report.Time.Format("20060102-150405")))
+	if err := os.WriteFile(base+".json", append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write failure report: %w", err)
+	}
+	if err := os.WriteFile(base+".txt", []byte(report.Text()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write failure report: %w", err)
+	}
+	return base + ".txt", nil
+}
+
+// firstLine returns the first non-blank line of text, trimmed
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
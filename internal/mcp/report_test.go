@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Failure
+	}{
+		{
+			name: "plain error",
+			err:  errors.New("failed to add server: exit status 1"),
+			want: Failure{Server: "srv", Summary: "failed to add server: exit status 1", Error: "failed to add server: exit status 1"},
+		},
+		{
+			name: "colors stripped",
+			err:  fmt.Errorf("\033[0;31mexit status 1\033[0m"),
+			want: Failure{Server: "srv", Summary: "exit status 1", Error: "exit status 1"},
+		},
+		{
+			name: "debug log taken from the error",
+			err:  &VerificationError{Server: "srv", Failed: true, DebugLog: "/tmp/cmcp-debug/cmcp-verify-srv-20250807-150625.log"},
+			want: Failure{
+				Server:   "srv",
+				Summary:  "failed to connect",
+				Error:    "failed to connect\n\nℹ Debug log saved to:\n  /tmp/cmcp-debug/cmcp-verify-srv-20250807-150625.log\n  View this file for detailed connection diagnostics",
+				DebugLog: "/tmp/cmcp-debug/cmcp-verify-srv-20250807-150625.log",
+			},
+		},
+		{
+			name: "diagnostics without server output",
+			err:  &DiagnosticError{Server: "srv", Diagnostics: &DiagnosticInfo{Error: errors.New("exec: \"uvx\": executable file not found in $PATH")}},
+			want: Failure{
+				Server:  "srv",
+				Summary: `Connection failed: exec: "uvx": executable file not found in $PATH`,
+				Error:   "Connection failed\n\nError: exec: \"uvx\": executable file not found in $PATH",
+			},
+		},
+		{
+			name: "diagnostics kept structured",
+			err: &DiagnosticError{
+				Server:      "srv",
+				Diagnostics: &DiagnosticInfo{StdErr: "boom\nat line 1", HealthCheck: "srv: x - ✗ Failed", Suggestions: []string{"Install x"}},
+				DebugLog:    "/tmp/srv.log",
+			},
+			want: Failure{
+				Server:      "srv",
+				Summary:     "Connection failed: boom",
+				Error:       "Connection failed\n\nHealth check output:\n  srv: x - ✗ Failed\n\nServer error:\nboom\nat line 1\n\nPossible solutions:\n  1. Install x\n\nℹ Debug log saved to:\n  /tmp/srv.log\n  View this file for detailed connection diagnostics and Claude CLI debug output",
+				DebugLog:    "/tmp/srv.log",
+				ServerError: "boom\nat line 1",
+				HealthCheck: "srv: x - ✗ Failed",
+				Suggestions: []string{"Install x"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewFailure("srv", tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewFailure() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteFailureReport(t *testing.T) {
+	dir := t.TempDir()
+	report := &FailureReport{
+		Operation: "start",
+		Time:      time.Date(2025, 8, 7, 15, 6, 25, 0, time.UTC),
+		Total:     3,
+		Failures: []Failure{
+			{Server: "github", Error: "failed to connect"},
+			{Server: "fetch", Error: "Connection failed", Suggestions: []string{"Install uv"}},
+		},
+	}
+
+	path, err := writeFailureReport(dir, report)
+	if err != nil {
+		t.Fatalf("writeFailureReport() error = %v", err)
+	}
+	if want := filepath.Join(dir, "cmcp-start-failures-20250807-150625.txt"); path != want {
+		t.Errorf("writeFailureReport() = %s, want %s", path, want)
+	}
+
+	text, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"2 of 3 server(s) failed", "== github ==\nfailed to connect", "== fetch =="} {
+		if !strings.Contains(string(text), want) {
+			t.Errorf("text report missing %q:\n%s", want, text)
+		}
+	}
+
+	data, err := os.ReadFile(strings.TrimSuffix(path, ".txt") + ".json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded FailureReport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("JSON report does not decode: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, report) {
+		t.Errorf("JSON report = %+v, want %+v", decoded, report)
+	}
+}
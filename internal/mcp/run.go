@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// ExitError reports a server run in the foreground that exited with a non-zero status
+type ExitError struct {
+	Server string
+	Code   int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("server '%s' exited with status %d", e.Server, e.Code)
+}
+
+// Run launches a server in the foreground the way Claude launches it, with its env and
+// cwd, and connects its stdio to the given streams. Ctrl-C reaches the server through
+// the terminal and SIGTERM is forwarded, so the server decides how to shut down.
+func Run(name string, server *config.MCPServer, stdin io.Reader, stdout, stderr io.Writer) error {
+	if server.IsRemote() {
+		return fmt.Errorf("server '%s' is a remote server (%s); there is no process to run", name, server.URL)
+	}
+	if server.Command == "" {
+		return fmt.Errorf("server '%s' has no command configured", name)
+	}
+	server, err := ResolveLaunch(name, server)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(server.Command, server.Args...)
+	cmd.Env = serverEnviron(server)
+	cmd.Dir = server.Cwd
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, stdout, stderr
+	// Don't wait for a stdin that isn't a file (such as a recording tap) once the server exits
+	cmd.WaitDelay = time.Second
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to launch '%s': %w", server.Command, err)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				if sig == syscall.SIGTERM {
+					cmd.Process.Signal(sig)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	if err := cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.Is(err, exec.ErrWaitDelay) {
+			return nil
+		}
+		if !errors.As(err, &exitErr) {
+			return fmt.Errorf("server '%s' stopped: %w", name, err)
+		}
+		// A negative code means the server was stopped by a signal, usually Ctrl-C
+		if exitErr.ExitCode() > 0 {
+			return &ExitError{Server: name, Code: exitErr.ExitCode()}
+		}
+	}
+	return nil
+}
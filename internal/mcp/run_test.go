@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"errors"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test server is a shell script")
+	}
+
+	dir := t.TempDir()
+	server := &config.MCPServer{
+		Command: "sh",
+		Args:    []string{"-c", `read line; echo "$line from $GREETING in $(pwd)"; echo diag >&2; exit 3`},
+		Env:     map[string]string{"GREETING": "cmcp"},
+		Cwd:     dir,
+	}
+
+	var stdout, stderr strings.Builder
+	err := Run("echo", server, strings.NewReader("ping\n"), &stdout, &stderr)
+
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 || exitErr.Server != "echo" {
+		t.Fatalf("Run() error = %v, want exit status 3", err)
+	}
+	resolved, _ := filepath.EvalSymlinks(dir)
+	if got := strings.TrimSpace(stdout.String()); got != "ping from cmcp in "+dir && got != "ping from cmcp in "+resolved {
+		t.Errorf("stdout = %q", got)
+	}
+	if stderr.String() != "diag\n" {
+		t.Errorf("stderr = %q, want the server's stderr", stderr.String())
+	}
+
+	if err := Run("ok", &config.MCPServer{Command: "true"}, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Errorf("Run() of a clean exit = %v", err)
+	}
+	if err := Run("remote", &config.MCPServer{URL: "https://example.com/mcp"}, nil, nil, nil); err == nil {
+		t.Error("Run() of a remote server should fail")
+	}
+}
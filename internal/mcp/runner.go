@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultClaudeTimeout is how long a single claude command may run before it is killed
+const DefaultClaudeTimeout = 30 * time.Second
+
+var (
+	claudeTimeout   time.Duration
+	claudeTimeoutMu sync.Mutex
+)
+
+func init() {
+	// Allow override via environment variable, as a duration ("2m") or seconds ("120")
+	timeout := DefaultClaudeTimeout
+	if value := os.Getenv("CMCP_CLAUDE_TIMEOUT"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			timeout = d
+		} else if n, err := strconv.Atoi(value); err == nil {
+			timeout = time.Duration(n) * time.Second
+		}
+	}
+	SetClaudeTimeout(timeout)
+}
+
+// SetClaudeTimeout changes how long a single claude command may run; zero or less
+// means no limit
+func SetClaudeTimeout(d time.Duration) {
+	claudeTimeoutMu.Lock()
+	claudeTimeout = d
+	claudeTimeoutMu.Unlock()
+}
+
+// ClaudeTimeoutError is a claude command killed for running longer than the timeout,
+// e.g. because claude was waiting on a lock or an auth prompt
+type ClaudeTimeoutError struct {
+	Command string // The claude subcommand, e.g. "mcp list", without arguments that may hold secrets
+	Timeout time.Duration
+}
+
+func (e *ClaudeTimeoutError) Error() string {
+	return fmt.Sprintf("'claude %s' did not finish within %v (raise the limit with CMCP_CLAUDE_TIMEOUT)", e.Command, e.Timeout)
+}
+
+func (e *ClaudeTimeoutError) Unwrap() error { return context.DeadlineExceeded }
+
+// CommandRunner runs the claude CLI. The builder uses the real binary by default;
+// tests substitute a fake so exec paths can run without a claude install.
+type CommandRunner interface {
+	Run(ctx context.Context, args []string, stdout, stderr io.Writer) error
+}
+
+// execRunner runs a claude binary, by default the one found on PATH
+type execRunner struct {
+	path string // Command or path of the claude binary; empty finds claude on PATH
+}
+
+func (r execRunner) Run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	path := r.path
+	if path == "" {
+		path = findClaude()
+	}
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Don't wait forever on output pipes held open by claude's own children once it is killed
+	cmd.WaitDelay = time.Second
+	return claudeUnavailable(path, cmd.Run())
+}
+
+// runClaude runs claude through the runner within the process limit. The timeout
+// starts once the command gets a process slot.
+func runClaude(ctx context.Context, runner CommandRunner, stdout, stderr io.Writer, args ...string) error {
+	return runLimited(ctx, func() error {
+		claudeTimeoutMu.Lock()
+		timeout := claudeTimeout
+		claudeTimeoutMu.Unlock()
+		if timeout <= 0 {
+			return runner.Run(ctx, args, stdout, stderr)
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		err := runner.Run(runCtx, args, stdout, stderr)
+		if ctx.Err() == nil && runCtx.Err() == context.DeadlineExceeded {
+			return &ClaudeTimeoutError{Command: claudeSubcommand(args), Timeout: timeout}
+		}
+		return err
+	})
+}
+
+// claudeSubcommand returns the leading words of claude arguments, up to the first flag
+// and at most two, e.g. "mcp add"
+func claudeSubcommand(args []string) string {
+	var words []string
+	for _, arg := range args {
+		if len(words) == 2 || strings.HasPrefix(arg, "-") {
+			break
+		}
+		words = append(words, arg)
+	}
+	return strings.Join(words, " ")
+}
+
+// claudeOutput runs claude and returns its stdout
+func claudeOutput(ctx context.Context, runner CommandRunner, args ...string) ([]byte, error) {
+	var stdout bytes.Buffer
+	err := runClaude(ctx, runner, &stdout, nil, args...)
+	return stdout.Bytes(), err
+}
+
+// claudeCombinedOutput runs claude and returns stdout and stderr together
+func claudeCombinedOutput(ctx context.Context, runner CommandRunner, args ...string) ([]byte, error) {
+	var output bytes.Buffer
+	err := runClaude(ctx, runner, &output, &output, args...)
+	return output.Bytes(), err
+}
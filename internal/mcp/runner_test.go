@@ -0,0 +1,221 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// fakeRunner answers claude commands from a script instead of running the binary
+type fakeRunner struct {
+	mu      sync.Mutex
+	calls   [][]string
+	respond func(args []string) (string, error)
+}
+
+func (f *fakeRunner) Run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	f.mu.Lock()
+	f.calls = append(f.calls, args)
+	f.mu.Unlock()
+
+	output, err := f.respond(args)
+	if stdout != nil {
+		io.WriteString(stdout, output)
+	}
+	return err
+}
+
+// subcommand returns the claude mcp subcommand of a call, e.g. "add" or "list"
+func subcommand(args []string) string {
+	if len(args) < 2 {
+		return ""
+	}
+	return args[1]
+}
+
+func listResponder(listOutput string) func(args []string) (string, error) {
+	return func(args []string) (string, error) {
+		switch subcommand(args) {
+		case "add", "add-json":
+			return "Added stdio MCP server test\nFile modified: /tmp/.claude.json\n", nil
+		case "get":
+			return "test:\n  Scope: Local config\n", nil
+		case "list":
+			return listOutput, nil
+		}
+		return "", fmt.Errorf("unexpected command: %v", args)
+	}
+}
+
+func TestStartServerWithRunner(t *testing.T) {
+	runner := &fakeRunner{respond: listResponder("Checking MCP server health...\n\ntest: npx pkg - ✓ Connected\n")}
+	b := NewClaudeCmdBuilder()
+	b.Runner = runner
+
+	server := &config.MCPServer{Command: "npx", Args: []string{"pkg"}}
+	if err := b.StartServer("test", server, false); err != nil {
+		t.Fatalf("StartServer() error = %v", err)
+	}
+
+	if len(runner.calls) < 2 {
+		t.Fatalf("expected add and list calls, got %v", runner.calls)
+	}
+	add := strings.Join(runner.calls[0], " ")
+	if add != "mcp add --debug test -- npx pkg" {
+		t.Errorf("add call = %q", add)
+	}
+	if subcommand(runner.calls[1]) != "list" {
+		t.Errorf("second call = %v, want mcp list", runner.calls[1])
+	}
+}
+
+func TestStartServerConnectionFailure(t *testing.T) {
+	runner := &fakeRunner{respond: listResponder("test: nonexistent-command - ✗ Failed to connect\n")}
+	b := NewClaudeCmdBuilder()
+	b.Runner = runner
+
+	server := &config.MCPServer{Command: "nonexistent-command"}
+	if err := b.StartServer("test", server, false); err == nil {
+		t.Error("StartServer() should fail when the server does not connect")
+	}
+}
+
+func TestStartServerAddFailure(t *testing.T) {
+	runner := &fakeRunner{respond: func(args []string) (string, error) {
+		return "", fmt.Errorf("exit status 1")
+	}}
+	b := NewClaudeCmdBuilder()
+	b.Runner = runner
+
+	err := b.StartServer("test", &config.MCPServer{Command: "npx"}, false)
+	if err == nil || !strings.Contains(err.Error(), "failed to add server 'test'") {
+		t.Errorf("StartServer() error = %v, want add failure", err)
+	}
+	if len(runner.calls) != 1 {
+		t.Errorf("verification should not run after a failed add, calls = %v", runner.calls)
+	}
+}
+
+func TestStartServerWithRetry(t *testing.T) {
+	defer func(backoff time.Duration) { retryBackoff = backoff }(retryBackoff)
+	retryBackoff = 10 * time.Millisecond
+
+	// The first add fails transiently, the second one succeeds
+	adds := 0
+	respond := listResponder("test: npx pkg - ✓ Connected\n")
+	runner := &fakeRunner{respond: func(args []string) (string, error) {
+		switch subcommand(args) {
+		case "add":
+			adds++
+			if adds == 1 {
+				return "", fmt.Errorf("exit status 1")
+			}
+		case "get":
+			return "", fmt.Errorf("exit status 1")
+		}
+		return respond(args)
+	}}
+	b := NewClaudeCmdBuilder()
+	b.Runner = runner
+
+	server := &config.MCPServer{Command: "npx", Args: []string{"pkg"}}
+	if err := b.StartServerWithRetry("test", server, false, 0); err == nil {
+		t.Error("StartServerWithRetry() without retries should fail on the first attempt")
+	}
+
+	adds = 0
+	if err := b.StartServerWithRetry("test", server, false, 2); err != nil {
+		t.Fatalf("StartServerWithRetry() error = %v", err)
+	}
+	if adds != 2 {
+		t.Errorf("StartServerWithRetry() added %d times, want 2", adds)
+	}
+}
+
+func TestGetServerStatusesWithRunner(t *testing.T) {
+	output := `Checking MCP server health...
+
+github: docker run -i --rm ghcr.io/github/github-mcp-server - ✓ Connected
+broken: nonexistent-command --fail - ✗ Failed to connect
+remote: https://example.com/mcp (HTTP) - ✓ Connected
+`
+	b := NewClaudeCmdBuilder()
+	b.Runner = &fakeRunner{respond: listResponder(output)}
+
+	cfg := &config.Config{MCPServers: map[string]config.MCPServer{
+		"github": {Command: "docker"},
+		"broken": {Command: "nonexistent-command"},
+	}}
+
+	servers, err := b.GetServerStatuses(cfg)
+	if err != nil {
+		t.Fatalf("GetServerStatuses() error = %v", err)
+	}
+
+	expected := []ServerStatus{
+		{Name: "github", Command: "docker run -i --rm ghcr.io/github/github-mcp-server", Status: "connected", InConfig: true, ConfigName: "github"},
+		{Name: "broken", Command: "nonexistent-command --fail", Status: "failed", InConfig: true, ConfigName: "broken"},
+		{Name: "remote", Command: "https://example.com/mcp (HTTP)", Status: "connected", InConfig: false},
+	}
+	if len(servers) != len(expected) {
+		t.Fatalf("GetServerStatuses() = %+v, want %+v", servers, expected)
+	}
+	for i := range expected {
+		if servers[i] != expected[i] {
+			t.Errorf("servers[%d] = %+v, want %+v", i, servers[i], expected[i])
+		}
+	}
+}
+
+func TestIsRunningWithRunner(t *testing.T) {
+	b := NewClaudeCmdBuilder()
+	b.Runner = &fakeRunner{respond: func(args []string) (string, error) {
+		if args[len(args)-1] == "known" {
+			return "", nil
+		}
+		return "", fmt.Errorf("No MCP server found with name: %s", args[len(args)-1])
+	}}
+
+	if !b.IsRunning("known") {
+		t.Error("IsRunning(known) = false, want true")
+	}
+	if b.IsRunning("unknown") {
+		t.Error("IsRunning(unknown) = true, want false")
+	}
+}
+
+// hangingRunner blocks like a claude waiting on a lock until its context ends
+type hangingRunner struct{}
+
+func (hangingRunner) Run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestRunClaudeTimeout(t *testing.T) {
+	SetClaudeTimeout(20 * time.Millisecond)
+	defer SetClaudeTimeout(DefaultClaudeTimeout)
+
+	err := runClaude(context.Background(), hangingRunner{}, nil, nil, "mcp", "add", "-e", "TOKEN=secret", "srv", "cmd")
+	var timeoutErr *ClaudeTimeoutError
+	if !errors.As(err, &timeoutErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("runClaude() error = %v, want ClaudeTimeoutError", err)
+	}
+	if timeoutErr.Command != "mcp add" || strings.Contains(err.Error(), "secret") {
+		t.Errorf("error = %q, want the subcommand without its arguments", err)
+	}
+
+	// An interrupt is reported as such, not as a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := runClaude(ctx, hangingRunner{}, nil, nil, "mcp", "list"); errors.As(err, &timeoutErr) {
+		t.Errorf("runClaude() on a cancelled context = %v, want context.Canceled", err)
+	}
+}
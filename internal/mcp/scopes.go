@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// Scope names as 'claude mcp get' reports them
+const (
+	ScopeProject = "Project config (shared via .mcp.json)"
+	ScopeUser    = "User config (available in all your projects)"
+)
+
+// claudeScopeFile is the part of Claude's settings files that holds server definitions.
+// In the user file, local scope servers are kept per project directory.
+type claudeScopeFile struct {
+	MCPServers map[string]config.MCPServer `json:"mcpServers"`
+	Projects   map[string]claudeScopeFile  `json:"projects,omitempty"`
+}
+
+// claudeUserConfigPath returns the file Claude keeps user scope servers in
+func claudeUserConfigPath() string {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, ".claude.json")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".claude.json")
+}
+
+// FindShadowed checks the named servers against the definitions Claude keeps in its
+// user scope and in this project's .mcp.json. cmcp starts servers in the local scope,
+// which shadows both, so a different definition there is live whenever the server
+// is not running through cmcp. Only server definitions are read from those files.
+func FindShadowed(cfg *config.Config, names []string) []Conflict {
+	cwd, _ := os.Getwd()
+	return findShadowed(cfg, names, claudeUserConfigPath(), filepath.Join(cwd, ".mcp.json"))
+}
+
+func findShadowed(cfg *config.Config, names []string, userFile, projectFile string) []Conflict {
+	scopes := []struct {
+		scope   string
+		servers map[string]config.MCPServer
+	}{
+		{ScopeProject, readScopeServers(projectFile)},
+		{ScopeUser, readScopeServers(userFile)},
+	}
+
+	var conflicts []Conflict
+	for _, name := range names {
+		server, exists := cfg.FindServer(name)
+		if !exists {
+			continue
+		}
+		// Claude knows the server by its alias, and launches it resolved
+		claudeName, configured := server.ClaudeName(name), launchString(name, server)
+		for _, scope := range scopes {
+			other, defined := scope.servers[claudeName]
+			if !defined || describeServer(&other) == configured {
+				continue
+			}
+			conflicts = append(conflicts, Conflict{
+				Name:       name,
+				Scope:      scope.scope,
+				Configured: configured,
+				Registered: describeServer(&other),
+			})
+		}
+	}
+	return conflicts
+}
+
+// readScopeServers returns the top-level servers of a Claude settings file, or nil
+// when the file is missing or unreadable
+func readScopeServers(path string) map[string]config.MCPServer {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var file claudeScopeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil
+	}
+	return file.MCPServers
+}
+
+// ScopeServerNames returns the servers registered in one of Claude's scopes ("local",
+// "project" or "user") for the current project, sorted by name. They are read from
+// Claude's settings files, since 'claude mcp list' does not say which scope a server is in.
+func ScopeServerNames(scope string) []string {
+	cwd, _ := os.Getwd()
+	return scopeServerNames(scope, claudeUserConfigPath(), cwd)
+}
+
+func scopeServerNames(scope, userFile, projectDir string) []string {
+	var servers map[string]config.MCPServer
+	switch scope {
+	case "user":
+		servers = readScopeServers(userFile)
+	case "project":
+		servers = readScopeServers(filepath.Join(projectDir, ".mcp.json"))
+	case "local":
+		if data, err := os.ReadFile(userFile); err == nil {
+			var file claudeScopeFile
+			if json.Unmarshal(data, &file) == nil {
+				servers = file.Projects[projectDir].MCPServers
+			}
+		}
+	}
+
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// claudeScopes maps the --scope values Claude accepts to the scope names it reports
+var claudeScopes = map[string]string{
+	"local":   "Local config",
+	"project": "Project config",
+	"user":    "User config",
+}
+
+// ValidateScope checks a --scope value, accepting empty for Claude's default
+func ValidateScope(scope string) error {
+	if _, ok := claudeScopes[scope]; !ok && scope != "" {
+		return fmt.Errorf("invalid scope '%s': expected local, project or user", scope)
+	}
+	return nil
+}
+
+// scopeArgs returns the -s flag for the builder's scope, if one is set
+func (b *ClaudeCmdBuilder) scopeArgs() []string {
+	if b.Scope == "" {
+		return nil
+	}
+	return []string{"-s", b.Scope}
+}
+
+// ClaudeCommand returns "claude mcp <subcommand>" with the builder's claude binary and
+// scope flag, for display
+func (b *ClaudeCmdBuilder) ClaudeCommand(subcommand string) string {
+	return ShellJoin(append([]string{b.claudeBin(), "mcp", subcommand}, b.scopeArgs()...))
+}
+
+// isRegisteredInScope reports whether the server is registered in the builder's
+// scope. 'claude mcp get' shows only the definition that wins (local over project
+// over user), so a project or user definition hidden by another scope is looked up
+// in the scope's file instead.
+func (b *ClaudeCmdBuilder) isRegisteredInScope(name string) bool {
+	if def := b.GetClaudeDefinition(name); def != nil && strings.HasPrefix(def.Scope, claudeScopes[b.Scope]) {
+		return true
+	}
+	cwd, _ := os.Getwd()
+	switch b.Scope {
+	case "project":
+		_, ok := readScopeServers(filepath.Join(cwd, ".mcp.json"))[name]
+		return ok
+	case "user":
+		_, ok := readScopeServers(claudeUserConfigPath())[name]
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package mcp
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestFindShadowed(t *testing.T) {
+	dir := t.TempDir()
+	userFile := filepath.Join(dir, ".claude.json")
+	projectFile := filepath.Join(dir, ".mcp.json")
+	user := `{"numStartups": 3, "mcpServers": {
+		"github": {"type": "stdio", "command": "npx", "args": ["-y", "server-github@1"]},
+		"fetch": {"command": "uvx", "args": ["mcp-server-fetch"]},
+		"search": {"command": "/work/app/search", "args": ["--name", "search-work"]},
+		"notes": {"command": "old-notes"}
+	}, "projects": {"/work": {"mcpServers": {"memory": {"command": "old"}}}}}`
+	project := `{"mcpServers": {"docs": {"type": "http", "url": "https://docs.example.com/mcp"}}}`
+	if err := os.WriteFile(userFile, []byte(user), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(projectFile, []byte(project), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{MCPServers: map[string]config.MCPServer{
+		"github": {Command: "npx", Args: []string{"-y", "server-github@2"}},
+		"fetch":  {Command: "uvx", Args: []string{"mcp-server-fetch"}},
+		"docs":   {Type: "http", URL: "https://docs.internal/mcp"},
+		"memory": {Command: "npx", Args: []string{"server-memory"}},
+		// Compared under its alias, once resolved
+		"search-work": {Command: "./search", Args: []string{"--name", "{{serverName}}"}, Alias: "search", BaseDir: "/work/app"},
+		// The user scope's notes server is another one than this aliased variant
+		"notes": {Command: "notes", Alias: "notes-work"},
+	}}
+
+	got := findShadowed(cfg, []string{"docs", "fetch", "github", "memory", "search-work", "notes"}, userFile, projectFile)
+	if len(got) != 2 {
+		t.Fatalf("findShadowed() = %+v, want docs and github", got)
+	}
+	if got[0].Name != "docs" || got[0].Scope != ScopeProject || got[0].Registered != "https://docs.example.com/mcp" {
+		t.Errorf("findShadowed()[0] = %+v", got[0])
+	}
+	if got[1].Name != "github" || got[1].Scope != ScopeUser || got[1].Registered != "npx -y server-github@1" {
+		t.Errorf("findShadowed()[1] = %+v", got[1])
+	}
+
+	// Missing files mean nothing is shadowed
+	if got := findShadowed(cfg, []string{"github"}, filepath.Join(dir, "none"), filepath.Join(dir, "none")); len(got) != 0 {
+		t.Errorf("findShadowed() without files = %+v", got)
+	}
+}
+
+func TestScopeArgs(t *testing.T) {
+	b := &ClaudeCmdBuilder{Scope: "user"}
+
+	stdio := config.MCPServer{Command: "npx", Args: []string{"-y", "pkg"}}
+	if got := b.PlanStart("fs", &stdio).Argv; !reflect.DeepEqual(got, []string{"claude", "mcp", "add", "--debug", "-s", "user", "fs", "--", "npx", "-y", "pkg"}) {
+		t.Errorf("PlanStart() argv = %v", got)
+	}
+	remote := config.MCPServer{Type: "http", URL: "https://example.com/mcp"}
+	if got := b.PlanStart("docs", &remote).Argv; !reflect.DeepEqual(got[:7], []string{"claude", "mcp", "add-json", "--debug", "-s", "user", "docs"}) {
+		t.Errorf("PlanStart() argv = %v", got)
+	}
+	if got := b.PlanStop("fs").Argv; !reflect.DeepEqual(got, []string{"claude", "mcp", "remove", "--debug", "-s", "user", "fs"}) {
+		t.Errorf("PlanStop() argv = %v", got)
+	}
+	if got := b.BuildStopCommand("fs"); got != "claude mcp remove -s user fs" {
+		t.Errorf("BuildStopCommand() = %q", got)
+	}
+
+	if err := ValidateScope("global"); err == nil {
+		t.Error("ValidateScope(global) expected error")
+	}
+	if err := ValidateScope(""); err != nil {
+		t.Errorf("ValidateScope(\"\") error = %v", err)
+	}
+}
+
+func TestIsRunningInScope(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", dir)
+	user := `{"mcpServers": {"github": {"command": "npx", "args": ["-y", "server-github"]}}}`
+	if err := os.WriteFile(filepath.Join(dir, ".claude.json"), []byte(user), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Claude reports the local definitions of github and fetch, which hide any other scope
+	runner := &fakeRunner{respond: func(args []string) (string, error) {
+		if args[2] == "github" || args[2] == "fetch" {
+			return args[2] + ":\n  Scope: Local config (private to you in this project)\n  Command: npx\n", nil
+		}
+		return "No MCP server found with name: " + args[2], errors.New("exit status 1")
+	}}
+
+	tests := []struct {
+		scope string
+		name  string
+		want  bool
+	}{
+		{"local", "github", true},
+		{"local", "memory", false},
+		{"user", "github", true},
+		{"user", "fetch", false},
+		{"", "fetch", true},
+	}
+	for _, tt := range tests {
+		b := &ClaudeCmdBuilder{Runner: runner, Scope: tt.scope}
+		if got := b.IsRunning(tt.name); got != tt.want {
+			t.Errorf("IsRunning(%q) in scope %q = %v, want %v", tt.name, tt.scope, got, tt.want)
+		}
+	}
+}
+
+func TestScopeServerNames(t *testing.T) {
+	dir := t.TempDir()
+	projectDir := filepath.Join(dir, "work")
+	userFile := filepath.Join(dir, ".claude.json")
+	user := `{"mcpServers": {"github": {"command": "npx"}, "fetch": {"command": "uvx"}},
+		"projects": {"` + projectDir + `": {"mcpServers": {"memory": {"command": "npx"}}}, "/other": {"mcpServers": {"db": {"command": "pg"}}}}}`
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(userFile, []byte(user), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, ".mcp.json"), []byte(`{"mcpServers": {"docs": {"url": "https://example.com/mcp"}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		scope string
+		want  []string
+	}{
+		{"user", []string{"fetch", "github"}},
+		{"project", []string{"docs"}},
+		{"local", []string{"memory"}},
+	}
+	for _, tt := range tests {
+		if got := scopeServerNames(tt.scope, userFile, projectDir); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("scopeServerNames(%q) = %v, want %v", tt.scope, got, tt.want)
+		}
+	}
+
+	if got := scopeServerNames("local", userFile, filepath.Join(dir, "elsewhere")); len(got) != 0 {
+		t.Errorf("scopeServerNames() for another project = %v, want none", got)
+	}
+}
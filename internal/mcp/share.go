@@ -0,0 +1,176 @@
+package mcp
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// nonIdentifier matches runs of characters not allowed in an environment variable name
+var nonIdentifier = regexp.MustCompile(`[^A-Z0-9_]+`)
+
+// SharedServer is a server definition made safe to hand to someone else
+type SharedServer struct {
+	Server    config.MCPServer
+	Variables []string // Environment variables the recipient must set, sorted
+}
+
+// ShareServer returns a copy of the server with every secret replaced by a reference
+// to an environment variable: ${KEY} for env values, args, the URL, proxy and health
+// check, and ${env:NAME} for headers, which cmcp fills in at start. Values that are
+// already references or inherited from the shell are kept. The token command, TLS
+// files and fields cmcp does not know are left out.
+func ShareServer(server config.MCPServer) SharedServer {
+	isSensitive := sensitiveKeysOf(&server)
+	variables := make(map[string]bool)
+	reference := func(name string) string {
+		name = envName(name)
+		variables[name] = true
+		return "${" + name + "}"
+	}
+
+	shared := server
+	if len(server.Env) > 0 {
+		shared.Env = make(map[string]string, len(server.Env))
+		for key, value := range server.Env {
+			if isSensitive(key) && isLiteral(value) {
+				value = reference(key)
+			}
+			shared.Env[key] = value
+		}
+	}
+	if len(server.Args) > 0 {
+		shared.Args = shareArgs(server.Args, isSensitive, reference)
+	}
+	shared.URL = shareInline(server.URL, reference)
+	if len(server.Headers) > 0 {
+		shared.Headers = make(map[string]string, len(server.Headers))
+		for key, value := range server.Headers {
+			if isSensitive(key) && isLiteral(value) {
+				name := envName(key)
+				variables[name] = true
+				if match := authSchemes.FindStringSubmatch(value); match != nil {
+					value = match[1] + " ${env:" + name + "}"
+				} else {
+					value = "${env:" + name + "}"
+				}
+			}
+			shared.Headers[key] = value
+		}
+	}
+	shared.Proxy = shareInline(server.Proxy, reference)
+	shared.HealthCheck = shareInline(server.HealthCheck, reference)
+	// The token command and certificate files only work on the sender's machine, and
+	// unknown fields may hold anything
+	shared.TokenCommand = ""
+	shared.TLS = nil
+	shared.Extra = nil
+
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return SharedServer{Server: shared, Variables: names}
+}
+
+// ShareSnippet returns the shared server as an indented {"mcpServers": {...}} document
+// that 'cmcp config import --url' and 'cmcp apply' accept
+func ShareSnippet(name string, server config.MCPServer) ([]byte, []string, error) {
+	shared := ShareServer(server)
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"mcpServers": map[string]interface{}{name: shared.Server.ToMap()},
+	}, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(data, '\n'), shared.Variables, nil
+}
+
+// shareArgs replaces the secret values of KEY=VALUE, --flag VALUE and header arguments,
+// recognized as in maskArgs, with references
+func shareArgs(args []string, isSensitive func(string) bool, reference func(string) string) []string {
+	shared := make([]string, len(args))
+	copy(shared, args)
+
+	for i := 0; i < len(shared); i++ {
+		if (strings.HasPrefix(shared[i], "--env") || shared[i] == "-e") && i+1 < len(shared) {
+			if key, value, ok := strings.Cut(shared[i+1], "="); ok && isSensitive(key) && isLiteral(value) {
+				shared[i+1] = key + "=" + reference(key)
+			}
+		}
+		if strings.HasPrefix(shared[i], "-e") && strings.Contains(shared[i], "=") {
+			prefix, value, _ := strings.Cut(shared[i], "=")
+			if key := strings.TrimPrefix(prefix, "-e"); isSensitive(key) && isLiteral(value) {
+				shared[i] = prefix + "=" + reference(key)
+			}
+		}
+		if strings.HasPrefix(shared[i], "-") {
+			if name, value, ok := strings.Cut(shared[i], "="); ok {
+				if isSensitiveFlag(name) && isLiteral(value) {
+					shared[i] = name + "=" + reference(name)
+				} else {
+					shared[i] = shareInline(shared[i], reference)
+				}
+			} else if isSensitiveFlag(shared[i]) && i+1 < len(shared) && !strings.HasPrefix(shared[i+1], "-") {
+				if isLiteral(shared[i+1]) {
+					shared[i+1] = reference(shared[i])
+				}
+				i++
+			}
+			continue
+		}
+		if match := headerArg.FindStringSubmatch(shared[i]); match != nil && isSensitiveFlag(match[1]) && !authSchemes.MatchString(match[2]) {
+			if isLiteral(match[2]) {
+				shared[i] = match[1] + ": " + reference(match[1])
+			}
+			continue
+		}
+		shared[i] = shareInline(shared[i], reference)
+	}
+	return shared
+}
+
+// shareInline replaces credentials embedded in text, as found by maskInline, with references
+func shareInline(text string, reference func(string) string) string {
+	text = replaceSubmatch(urlCredentials, text, func(match []string) string {
+		return match[1] + reference("PASSWORD") + "@"
+	})
+	text = replaceSubmatch(urlSecretParams, text, func(match []string) string {
+		return match[1] + reference(strings.TrimRight(match[1][1:], "="))
+	})
+	return replaceSubmatch(authSchemes, text, func(match []string) string {
+		return match[1] + " " + reference("TOKEN")
+	})
+}
+
+// replaceSubmatch is ReplaceAllStringFunc with access to the submatches. Matches that
+// already hold a reference are kept.
+func replaceSubmatch(re *regexp.Regexp, text string, replace func([]string) string) string {
+	return re.ReplaceAllStringFunc(text, func(match string) string {
+		if strings.Contains(match, "${") {
+			return match
+		}
+		return replace(re.FindStringSubmatch(match))
+	})
+}
+
+// isLiteral reports whether a value is a plain secret rather than empty, a reference
+// or a value inherited from the shell
+func isLiteral(value string) bool {
+	return value != "" && value != config.InheritEnv && !strings.Contains(value, "${")
+}
+
+// envName turns a key, flag or header name like --api-key or X-Api-Key into an
+// environment variable name like API_KEY or X_API_KEY
+func envName(name string) string {
+	name = nonIdentifier.ReplaceAllString(strings.ToUpper(strings.TrimLeft(name, "-")), "_")
+	name = strings.Trim(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "SECRET_" + name
+	}
+	return name
+}
@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ShellRequest is one request typed at the cmcp shell prompt
+type ShellRequest struct {
+	Method string
+	Params interface{}
+	Notify bool // Send as a notification, without waiting for a response
+}
+
+// ParseShellLine turns a shell line into a request. Lines are either a raw JSON-RPC
+// message ({"method": ..., "params": ...}; the ID is assigned by the client and
+// notifications/* without an ID are sent as notifications), "<method> [params-json]",
+// "notify <method> [params-json]", or one of the shortcuts:
+//
+//	tools/call <tool> [arguments-json]
+//	prompts/get <prompt> [arguments-json]
+//	resources/read <uri>
+func ParseShellLine(line string) (*ShellRequest, error) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "{") {
+		var msg struct {
+			ID     interface{}     `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		if msg.Method == "" {
+			return nil, fmt.Errorf("message has no method")
+		}
+		req := &ShellRequest{Method: msg.Method, Notify: msg.ID == nil && strings.HasPrefix(msg.Method, "notifications/")}
+		if len(msg.Params) > 0 {
+			req.Params = msg.Params
+		}
+		return req, nil
+	}
+
+	notify := false
+	if rest, ok := cutWord(line, "notify"); ok {
+		notify, line = true, rest
+	}
+	method, rest := splitWord(line)
+	if method == "" {
+		return nil, fmt.Errorf("expected a method, e.g. tools/list")
+	}
+	req := &ShellRequest{Method: method, Notify: notify}
+
+	switch method {
+	case "tools/call", "prompts/get":
+		name, argsJSON := splitWord(rest)
+		if name == "" || strings.HasPrefix(name, "{") {
+			return nil, fmt.Errorf("usage: %s <name> [arguments-json]", method)
+		}
+		params := map[string]interface{}{"name": name}
+		if argsJSON != "" {
+			args, err := parseObject(argsJSON)
+			if err != nil {
+				return nil, err
+			}
+			params["arguments"] = args
+		}
+		req.Params = params
+		return req, nil
+	case "resources/read":
+		if rest != "" && !strings.HasPrefix(rest, "{") {
+			req.Params = map[string]interface{}{"uri": rest}
+			return req, nil
+		}
+	}
+
+	if rest != "" {
+		params, err := parseObject(rest)
+		if err != nil {
+			return nil, err
+		}
+		req.Params = params
+	}
+	return req, nil
+}
+
+// parseObject parses a JSON object typed at the prompt
+func parseObject(text string) (map[string]interface{}, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &obj); err != nil {
+		return nil, fmt.Errorf("expected a JSON object, got %s: %w", text, err)
+	}
+	return obj, nil
+}
+
+// splitWord returns the first whitespace-separated word and the trimmed rest
+func splitWord(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	i := strings.IndexAny(line, " \t")
+	if i < 0 {
+		return line, ""
+	}
+	return line[:i], strings.TrimSpace(line[i:])
+}
+
+// cutWord removes a leading word from the line
+func cutWord(line, word string) (string, bool) {
+	first, rest := splitWord(line)
+	if first != word {
+		return line, false
+	}
+	return rest, true
+}
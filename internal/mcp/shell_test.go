@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseShellLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		method string
+		params string // JSON of the expected params, empty for none
+		notify bool
+		errMsg bool
+	}{
+		{name: "method only", line: "tools/list", method: "tools/list"},
+		{name: "method with params", line: `resources/list {"cursor": "abc"}`, method: "resources/list", params: `{"cursor":"abc"}`},
+		{name: "tool call shortcut", line: `tools/call search {"query": "mcp"}`, method: "tools/call", params: `{"arguments":{"query":"mcp"},"name":"search"}`},
+		{name: "tool call without arguments", line: "tools/call\tlist_repos", method: "tools/call", params: `{"name":"list_repos"}`},
+		{name: "prompt shortcut", line: `prompts/get review {"lang": "go"}`, method: "prompts/get", params: `{"arguments":{"lang":"go"},"name":"review"}`},
+		{name: "resource shortcut", line: "resources/read file:///tmp/a.txt", method: "resources/read", params: `{"uri":"file:///tmp/a.txt"}`},
+		{name: "resource with params", line: `resources/read {"uri": "x"}`, method: "resources/read", params: `{"uri":"x"}`},
+		{name: "notification", line: "notify notifications/roots/list_changed", method: "notifications/roots/list_changed", notify: true},
+		{name: "raw request", line: `{"jsonrpc": "2.0", "id": 7, "method": "tools/list", "params": {"cursor": "c"}}`, method: "tools/list", params: `{"cursor":"c"}`},
+		{name: "raw notification", line: `{"method": "notifications/initialized"}`, method: "notifications/initialized", notify: true},
+		{name: "tool call without name", line: "tools/call", errMsg: true},
+		{name: "params not an object", line: "tools/list [1]", errMsg: true},
+		{name: "raw without method", line: `{"id": 1}`, errMsg: true},
+		{name: "invalid raw JSON", line: `{"method": `, errMsg: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := ParseShellLine(tt.line)
+			if tt.errMsg {
+				if err == nil {
+					t.Fatalf("ParseShellLine(%q) expected an error, got %+v", tt.line, req)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseShellLine(%q) error = %v", tt.line, err)
+			}
+			if req.Method != tt.method || req.Notify != tt.notify {
+				t.Errorf("ParseShellLine(%q) = %s (notify %v), want %s (notify %v)", tt.line, req.Method, req.Notify, tt.method, tt.notify)
+			}
+
+			params := ""
+			if req.Params != nil {
+				data, _ := json.Marshal(req.Params)
+				params = string(data)
+			}
+			if params != tt.params {
+				t.Errorf("params = %s, want %s", params, tt.params)
+			}
+		})
+	}
+}
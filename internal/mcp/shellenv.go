@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// ShellSyntaxes are the shells ShellEnv writes for; "sh" covers bash and zsh
+var ShellSyntaxes = []string{"sh", "fish", "powershell"}
+
+// envKeyPattern matches the variable names every supported shell accepts as is
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ShellEnv returns the lines that set a server's env in the given shell, with each
+// value as the server would receive it (see ResolvedEnv), for use with eval. It fails
+// when a value needs shell variables that are not set, rather than exporting it empty.
+func ShellEnv(server *config.MCPServer, shell string) ([]string, error) {
+	return shellEnv(ResolvedEnv(server), shell)
+}
+
+func shellEnv(vars []EnvVar, shell string) ([]string, error) {
+	var missing []string
+	for _, v := range vars {
+		missing = append(missing, v.Missing...)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("%s not set in this shell; export them first", strings.Join(missing, ", "))
+	}
+
+	lines := make([]string, 0, len(vars))
+	for _, v := range vars {
+		line, err := shellExport(shell, v.Key, v.Value)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// shellExport returns the line setting an environment variable in the given shell. Keys
+// are written unquoted, so one that is not a plain variable name is refused rather than
+// letting it inject commands into the eval.
+func shellExport(shell, key, value string) (string, error) {
+	if !envKeyPattern.MatchString(key) {
+		return "", fmt.Errorf("env key %q is not a valid shell variable name", key)
+	}
+	switch shell {
+	case "sh":
+		return "export " + key + "=" + ShellQuote(value), nil
+	case "fish":
+		return "set -gx " + key + " '" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'", nil
+	case "powershell":
+		return "$env:" + key + " = '" + strings.ReplaceAll(value, "'", "''") + "'", nil
+	}
+	return "", fmt.Errorf("unknown shell '%s' (supported: %s)", shell, strings.Join(ShellSyntaxes, ", "))
+}
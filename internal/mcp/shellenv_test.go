@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShellEnv(t *testing.T) {
+	vars := []EnvVar{
+		{Key: "API_KEY", Value: "it's secret"},
+		{Key: "DEBUG", Value: "1"},
+	}
+
+	tests := []struct {
+		shell   string
+		want    string
+		wantErr string
+	}{
+		{"sh", `export API_KEY='it'\''s secret'|export DEBUG=1`, ""},
+		{"fish", `set -gx API_KEY 'it\'s secret'|set -gx DEBUG '1'`, ""},
+		{"powershell", `$env:API_KEY = 'it''s secret'|$env:DEBUG = '1'`, ""},
+		{"cmd", "", "unknown shell 'cmd'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			lines, err := shellEnv(vars, tt.shell)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("shellEnv() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("shellEnv() error = %v", err)
+			}
+			if got := strings.Join(lines, "|"); got != tt.want {
+				t.Errorf("shellEnv() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	missing := append(vars, EnvVar{Key: "TOKEN", Missing: []string{"GH_TOKEN"}})
+	if _, err := shellEnv(missing, "sh"); err == nil || !strings.Contains(err.Error(), "GH_TOKEN not set") {
+		t.Errorf("shellEnv() with a missing variable error = %v", err)
+	}
+
+	for _, key := range []string{"X; rm -rf ~", "$(id)", "1ST", "API-KEY", ""} {
+		if _, err := shellEnv([]EnvVar{{Key: key, Value: "v"}}, "sh"); err == nil || !strings.Contains(err.Error(), "not a valid shell variable name") {
+			t.Errorf("shellEnv() with key %q error = %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+)
+
+// statusRank orders statuses so the ones needing attention come first
+var statusRank = map[string]int{"failed": 0, "unknown": 1, "connected": 2}
+
+func rank(status string) int {
+	if r, ok := statusRank[status]; ok {
+		return r
+	}
+	return len(statusRank) // e.g. "stopped" for configured servers not in Claude
+}
+
+// SortStatuses sorts servers by name, or by status (failed first) and then name
+func SortStatuses(servers []ServerStatus, by string) error {
+	switch by {
+	case "name":
+		sort.SliceStable(servers, func(i, j int) bool { return servers[i].Name < servers[j].Name })
+	case "status":
+		sort.SliceStable(servers, func(i, j int) bool {
+			if ri, rj := rank(servers[i].Status), rank(servers[j].Status); ri != rj {
+				return ri < rj
+			}
+			return servers[i].Name < servers[j].Name
+		})
+	default:
+		return fmt.Errorf("unknown sort '%s' (use name or status)", by)
+	}
+	return nil
+}
+
+// FilterStatuses keeps connected or failed servers, or orphaned ones (in Claude but
+// not in the config). An empty filter keeps everything.
+func FilterStatuses(servers []ServerStatus, filter string) ([]ServerStatus, error) {
+	var keep func(ServerStatus) bool
+	switch filter {
+	case "":
+		return servers, nil
+	case "connected", "failed":
+		keep = func(s ServerStatus) bool { return s.Status == filter }
+	case "orphaned":
+		keep = func(s ServerStatus) bool { return !s.InConfig }
+	default:
+		return nil, fmt.Errorf("unknown filter '%s' (use connected, failed, or orphaned)", filter)
+	}
+
+	var filtered []ServerStatus
+	for _, server := range servers {
+		if keep(server) {
+			filtered = append(filtered, server)
+		}
+	}
+	return filtered, nil
+}
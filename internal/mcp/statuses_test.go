@@ -0,0 +1,84 @@
+package mcp
+
+import "testing"
+
+func statusNames(servers []ServerStatus) []string {
+	names := make([]string, len(servers))
+	for i, s := range servers {
+		names[i] = s.Name
+	}
+	return names
+}
+
+func testStatuses() []ServerStatus {
+	return []ServerStatus{
+		{Name: "zeta", Status: "connected", InConfig: true},
+		{Name: "alpha", Status: "failed", InConfig: true},
+		{Name: "orphan", Status: "connected"},
+		{Name: "beta", Status: "stopped", InConfig: true},
+		{Name: "gamma", Status: "failed", InConfig: true},
+	}
+}
+
+func TestSortStatuses(t *testing.T) {
+	tests := []struct {
+		by      string
+		want    []string
+		wantErr bool
+	}{
+		{by: "name", want: []string{"alpha", "beta", "gamma", "orphan", "zeta"}},
+		{by: "status", want: []string{"alpha", "gamma", "orphan", "zeta", "beta"}},
+		{by: "age", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			servers := testStatuses()
+			err := SortStatuses(servers, tt.by)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("SortStatuses() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SortStatuses() error = %v", err)
+			}
+			if got := statusNames(servers); !slicesEqual(got, tt.want) {
+				t.Errorf("SortStatuses(%q) = %v, want %v", tt.by, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterStatuses(t *testing.T) {
+	tests := []struct {
+		filter  string
+		want    []string
+		wantErr bool
+	}{
+		{filter: "", want: []string{"zeta", "alpha", "orphan", "beta", "gamma"}},
+		{filter: "connected", want: []string{"zeta", "orphan"}},
+		{filter: "failed", want: []string{"alpha", "gamma"}},
+		{filter: "orphaned", want: []string{"orphan"}},
+		{filter: "running", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			got, err := FilterStatuses(testStatuses(), tt.filter)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("FilterStatuses() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FilterStatuses() error = %v", err)
+			}
+			if names := statusNames(got); !slicesEqual(names, tt.want) {
+				t.Errorf("FilterStatuses(%q) = %v, want %v", tt.filter, names, tt.want)
+			}
+		})
+	}
+}
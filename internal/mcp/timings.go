@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Timing categories, telling whose time a phase spent
+const (
+	TimingCmcp   = "cmcp"   // cmcp's own work, such as resolving a definition or retry waits
+	TimingClaude = "claude" // claude commands that don't wait on the server
+	TimingServer = "server" // Waiting for the server to connect or answer
+)
+
+// Phase is a timed step of an operation. Server is empty for steps shared by
+// several servers, such as verifying a batch.
+type Phase struct {
+	Server   string
+	Name     string
+	Category string
+	Duration time.Duration
+
+	end time.Time
+}
+
+// Timings collects how long each phase of an operation took, for --timings. All
+// methods may be called on a nil *Timings, which records nothing.
+type Timings struct {
+	mu     sync.Mutex
+	start  time.Time
+	phases []Phase
+}
+
+// NewTimings starts timing an operation
+func NewTimings() *Timings {
+	return &Timings{start: time.Now()}
+}
+
+// Record adds a phase that took d
+func (t *Timings) Record(server, name, category string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.phases = append(t.phases, Phase{Server: server, Name: name, Category: category, Duration: d, end: time.Now()})
+}
+
+// Track starts a phase and returns the function that ends it
+func (t *Timings) Track(server, name, category string) func() {
+	if t == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { t.Record(server, name, category, time.Since(start)) }
+}
+
+// Phases returns the recorded phases in the order they ended
+func (t *Timings) Phases() []Phase {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Phase(nil), t.phases...)
+}
+
+// Total returns the time since the operation started
+func (t *Timings) Total() time.Duration {
+	if t == nil {
+		return 0
+	}
+	return time.Since(t.start)
+}
+
+// ByCategory sums the phases per category. cmcp's share is the wall-clock time no
+// claude or server phase covers, which includes untracked work such as loading the
+// config or printing. Phases may overlap, e.g. a claude command run while diagnosing
+// a failed verification, so the shares are not subtracted from the total.
+func (t *Timings) ByCategory() map[string]time.Duration {
+	sums := map[string]time.Duration{TimingCmcp: 0, TimingClaude: 0, TimingServer: 0}
+	var waits []Phase
+	for _, phase := range t.Phases() {
+		if phase.Category != TimingCmcp {
+			sums[phase.Category] += phase.Duration
+			waits = append(waits, phase)
+		}
+	}
+	if own := t.Total() - covered(waits); own > 0 {
+		sums[TimingCmcp] = own
+	}
+	return sums
+}
+
+// covered returns the wall-clock time during which at least one of the phases ran
+func covered(phases []Phase) time.Duration {
+	sort.Slice(phases, func(i, j int) bool {
+		return phases[i].end.Add(-phases[i].Duration).Before(phases[j].end.Add(-phases[j].Duration))
+	})
+	var total time.Duration
+	var spanStart, spanEnd time.Time
+	for _, phase := range phases {
+		start := phase.end.Add(-phase.Duration)
+		if start.After(spanEnd) {
+			total += spanEnd.Sub(spanStart)
+			spanStart, spanEnd = start, phase.end
+			continue
+		}
+		if phase.end.After(spanEnd) {
+			spanEnd = phase.end
+		}
+	}
+	return total + spanEnd.Sub(spanStart)
+}
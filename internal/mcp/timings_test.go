@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimings(t *testing.T) {
+	var none *Timings
+	none.Record("a", "claude add", TimingClaude, time.Second)
+	none.Track("a", "claude add", TimingClaude)()
+	if none.Phases() != nil {
+		t.Error("a nil Timings should record nothing")
+	}
+
+	timings := NewTimings()
+	timings.start = time.Now().Add(-10 * time.Second)
+	timings.Record("a", "claude add", TimingClaude, 2*time.Second)
+	timings.Record("a", "verification (2 check(s))", TimingServer, 5*time.Second)
+	timings.Record("", "config load", TimingCmcp, time.Second)
+
+	if phases := timings.Phases(); len(phases) != 3 || phases[1].Server != "a" || phases[2].Name != "config load" {
+		t.Errorf("Phases() = %+v", phases)
+	}
+	// The claude phase ran during the server one; cmcp's share is the 10s minus the
+	// 5s during which claude or the server was busy
+	now := time.Now()
+	timings.phases[0].end = now.Add(-5 * time.Second)
+	timings.phases[1].end = now.Add(-4 * time.Second)
+	sums := timings.ByCategory()
+	if sums[TimingClaude] != 2*time.Second || sums[TimingServer] != 5*time.Second {
+		t.Errorf("ByCategory() = %v", sums)
+	}
+	if cmcp := sums[TimingCmcp]; cmcp < 5*time.Second || cmcp > 6*time.Second {
+		t.Errorf("ByCategory() cmcp = %v, want about 5s", cmcp)
+	}
+
+	// Overlapping phases never make cmcp's share negative
+	busy := NewTimings()
+	busy.Record("a", "claude get", TimingClaude, time.Hour)
+	busy.Record("a", "verification (1 check(s))", TimingServer, time.Hour)
+	if cmcp := busy.ByCategory()[TimingCmcp]; cmcp < 0 {
+		t.Errorf("ByCategory() cmcp = %v, want it not negative", cmcp)
+	}
+}
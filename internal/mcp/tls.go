@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// remoteHTTPClient returns the client cmcp uses to reach a remote server,
+// applying the server's tls and proxy settings
+func remoteHTTPClient(server *config.MCPServer) (*http.Client, error) {
+	if server.TLS == nil && server.Proxy == "" {
+		return http.DefaultClient, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if server.TLS != nil {
+		tlsConfig, err := loadTLSConfig(server.TLS)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	proxy, err := proxyFunc(server)
+	if err != nil {
+		return nil, err
+	}
+	transport.Proxy = proxy
+	return &http.Client{Transport: transport}, nil
+}
+
+// loadTLSConfig builds a TLS configuration from a server's tls settings. Extra CAs
+// are trusted in addition to the system ones.
+func loadTLSConfig(settings *config.TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: settings.InsecureSkipVerify}
+
+	if settings.CAFile != "" {
+		data, err := os.ReadFile(settings.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls.caFile: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("tls.caFile %s contains no PEM certificates", settings.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	switch {
+	case settings.CertFile != "":
+		keyFile := settings.KeyFile
+		if keyFile == "" {
+			keyFile = settings.CertFile
+		}
+		cert, err := tls.LoadX509KeyPair(settings.CertFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	case settings.KeyFile != "":
+		return nil, fmt.Errorf("tls.keyFile is set without tls.certFile")
+	}
+
+	return tlsConfig, nil
+}
+
+// getDiagnosticsForTLS explains that Claude makes its own connection and doesn't
+// read the tls settings, which cmcp only applies to inspect, call, and ping
+func getDiagnosticsForTLS(settings *config.TLSConfig) []string {
+	var suggestions []string
+	if settings.CAFile != "" {
+		suggestions = append(suggestions, fmt.Sprintf("Claude doesn't read tls.caFile; start Claude with NODE_EXTRA_CA_CERTS=%s to trust the server's CA", settings.CAFile))
+	}
+	if settings.CertFile != "" {
+		suggestions = append(suggestions, "Claude doesn't present the tls.certFile client certificate; check the server with 'cmcp inspect' and configure the certificate in Claude's environment")
+	}
+	if settings.InsecureSkipVerify {
+		suggestions = append(suggestions, "Claude still verifies the server certificate; tls.insecureSkipVerify only applies to cmcp's own checks")
+	}
+	return suggestions
+}
@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// writePEM writes PEM blocks to a file in dir and returns its path
+func writePEM(t *testing.T, dir, name string, blocks ...*pem.Block) string {
+	t.Helper()
+	var data []byte
+	for _, block := range blocks {
+		data = append(data, pem.EncodeToMemory(block)...)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// newClientCert creates a self-signed client certificate and its key
+func newClientCert(t *testing.T) (*x509.Certificate, *pem.Block, *pem.Block) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "cmcp-test-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, &pem.Block{Type: "CERTIFICATE", Bytes: der}, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}
+}
+
+func TestRemoteHTTPClient(t *testing.T) {
+	dir := t.TempDir()
+	clientCert, certBlock, keyBlock := newClientCert(t)
+	certFile := writePEM(t, dir, "client.pem", certBlock)
+	keyFile := writePEM(t, dir, "client-key.pem", keyBlock)
+	bundleFile := writePEM(t, dir, "bundle.pem", certBlock, keyBlock)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) > 0 {
+			w.Header().Set("X-Client-Cert", r.TLS.PeerCertificates[0].Subject.CommonName)
+		}
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	srv.TLS = &tls.Config{ClientAuth: tls.VerifyClientCertIfGiven, ClientCAs: clientCAs}
+	srv.StartTLS()
+	defer srv.Close()
+	caFile := writePEM(t, dir, "ca.pem", &pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	tests := []struct {
+		name       string
+		tls        *config.TLSConfig
+		wantCert   bool
+		errMsg     string // From building the client
+		requestErr bool
+	}{
+		{name: "system roots only", tls: nil, requestErr: true},
+		{name: "custom CA", tls: &config.TLSConfig{CAFile: caFile}},
+		{name: "insecure", tls: &config.TLSConfig{InsecureSkipVerify: true}},
+		{name: "client certificate", tls: &config.TLSConfig{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}, wantCert: true},
+		{name: "certificate and key in one file", tls: &config.TLSConfig{CAFile: caFile, CertFile: bundleFile}, wantCert: true},
+		{name: "missing CA file", tls: &config.TLSConfig{CAFile: filepath.Join(dir, "missing.pem")}, errMsg: "failed to read tls.caFile"},
+		{name: "CA file without certificates", tls: &config.TLSConfig{CAFile: keyFile}, errMsg: "contains no PEM certificates"},
+		{name: "key without certificate", tls: &config.TLSConfig{KeyFile: keyFile}, errMsg: "without tls.certFile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := remoteHTTPClient(&config.MCPServer{URL: srv.URL, TLS: tt.tls})
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("remoteHTTPClient() error = %v, want it to contain %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("remoteHTTPClient() unexpected error: %v", err)
+			}
+
+			resp, err := client.Get(srv.URL)
+			if tt.requestErr {
+				if err == nil {
+					resp.Body.Close()
+					t.Fatal("expected the request to fail certificate verification")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+			if sentCert := resp.Header.Get("X-Client-Cert") == "cmcp-test-client"; sentCert != tt.wantCert {
+				t.Errorf("client certificate sent = %v, want %v", sentCert, tt.wantCert)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Prefix is the executable name prefix that marks a cmcp plugin
+const Prefix = "cmcp-"
+
+// Plugin is an executable on PATH surfaced as a cmcp subcommand
+type Plugin struct {
+	Name string // Subcommand name, e.g. "backup" for cmcp-backup
+	Path string
+}
+
+// Context is the JSON document passed to a plugin on stdin
+type Context struct {
+	Plugin     string   `json:"plugin"`
+	ConfigPath string   `json:"configPath"`
+	Cwd        string   `json:"cwd"`
+	Args       []string `json:"args"`
+}
+
+// ExitError reports a plugin that exited with a non-zero status
+type ExitError struct {
+	Plugin string
+	Code   int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("plugin '%s' exited with status %d", e.Plugin, e.Code)
+}
+
+// Find returns the plugins found in the given PATH-style directory list, sorted by
+// name. When several directories provide the same plugin, the first one wins.
+func Find(pathList string) []Plugin {
+	seen := make(map[string]bool)
+	var plugins []Plugin
+
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok || seen[name] || entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// pluginName extracts the subcommand name from an executable file name
+func pluginName(file string) (string, bool) {
+	if !strings.HasPrefix(file, Prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(file, Prefix)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	if name == "" || strings.ContainsAny(name, " \t.") {
+		return "", false
+	}
+	return name, true
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode()&0111 != 0
+}
+
+// Run executes the plugin with args, passing the config path in CMCP_CONFIG_PATH
+// and the JSON context on stdin. Output goes straight to the terminal.
+func Run(p Plugin, args []string, configPath string) error {
+	cwd, _ := os.Getwd()
+	ctx := Context{Plugin: p.Name, ConfigPath: configPath, Cwd: cwd, Args: args}
+	if ctx.Args == nil {
+		ctx.Args = []string{}
+	}
+	data, err := json.Marshal(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to encode plugin context: %w", err)
+	}
+
+	cmd := exec.Command(p.Path, args...)
+	cmd.Stdin = bytes.NewReader(append(data, '\n'))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "CMCP_CONFIG_PATH="+configPath)
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return &ExitError{Plugin: p.Name, Code: exitErr.ExitCode()}
+		}
+		return fmt.Errorf("failed to run plugin '%s': %w", p.Name, err)
+	}
+	return nil
+}
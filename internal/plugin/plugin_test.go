@@ -0,0 +1,79 @@
+package plugin
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func writeExecutable(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	writeExecutable(t, first, "cmcp-backup", "#!/bin/sh\n")
+	writeExecutable(t, second, "cmcp-backup", "#!/bin/sh\n") // shadowed by first
+	writeExecutable(t, second, "cmcp-audit", "#!/bin/sh\n")
+	writeExecutable(t, second, "other-tool", "#!/bin/sh\n")
+	os.WriteFile(filepath.Join(second, "cmcp-notes"), []byte("not executable"), 0644)
+	os.Mkdir(filepath.Join(second, "cmcp-dir"), 0755)
+
+	plugins := Find(first + string(os.PathListSeparator) + second)
+
+	var names []string
+	for _, p := range plugins {
+		names = append(names, p.Name)
+	}
+	expected := []string{"audit", "backup"}
+	if runtime.GOOS == "windows" {
+		expected = []string{"audit", "backup", "notes"}
+	}
+	if len(names) != len(expected) {
+		t.Fatalf("Find() names = %v, want %v", names, expected)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("Find() names = %v, want %v", names, expected)
+		}
+	}
+	if plugins[1].Path != filepath.Join(first, "cmcp-backup") {
+		t.Errorf("backup plugin path = %q, want the first PATH entry", plugins[1].Path)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins are not supported on Windows")
+	}
+
+	dir := t.TempDir()
+	stdinFile, envFile := filepath.Join(dir, "stdin"), filepath.Join(dir, "env")
+	// Save stdin and the config path so the test can inspect them
+	writeExecutable(t, dir, "cmcp-dump", "#!/bin/sh\ncat > "+stdinFile+"\nprintf %s \"$CMCP_CONFIG_PATH\" > "+envFile+"\nexit 3\n")
+
+	err := Run(Plugin{Name: "dump", Path: filepath.Join(dir, "cmcp-dump")}, []string{"--flag", "x"}, "/tmp/config.json")
+	exitErr, ok := err.(*ExitError)
+	if !ok || exitErr.Code != 3 {
+		t.Fatalf("Run() error = %v, want exit status 3", err)
+	}
+
+	data, _ := os.ReadFile(stdinFile)
+	var ctx Context
+	if err := json.Unmarshal(data, &ctx); err != nil {
+		t.Fatalf("plugin stdin is not JSON: %v\n%s", err, data)
+	}
+	if ctx.Plugin != "dump" || ctx.ConfigPath != "/tmp/config.json" || len(ctx.Args) != 2 || ctx.Args[1] != "x" {
+		t.Errorf("context = %+v", ctx)
+	}
+
+	env, _ := os.ReadFile(envFile)
+	if string(env) != "/tmp/config.json" {
+		t.Errorf("CMCP_CONFIG_PATH = %q, want /tmp/config.json", env)
+	}
+}
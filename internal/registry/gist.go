@@ -0,0 +1,111 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Endpoints for sharing snippets as gists, overridable for testing
+var (
+	GistAPIURL  = "https://api.github.com"
+	GistRawHost = "gist.githubusercontent.com"
+)
+
+// maxSnippetSize bounds how much of a shared snippet is downloaded
+const maxSnippetSize = 1 << 20
+
+// CreateGist uploads one file as a secret gist and returns the gist's URL. Secret
+// gists are unlisted: anyone with the URL can read them.
+func CreateGist(ctx context.Context, token, filename, description string, content []byte) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"description": description,
+		"public":      false,
+		"files":       map[string]interface{}{filename: map[string]string{"content": string(content)}},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, GistAPIURL+"/gists", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("gist request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Message != "" {
+			return "", fmt.Errorf("GitHub returned HTTP %d: %s", resp.StatusCode, apiErr.Message)
+		}
+		return "", fmt.Errorf("GitHub returned HTTP %d", resp.StatusCode)
+	}
+	var gist struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&gist); err != nil || gist.HTMLURL == "" {
+		return "", fmt.Errorf("invalid GitHub response")
+	}
+	return gist.HTMLURL, nil
+}
+
+// FetchSnippet downloads a shared snippet. Gist page URLs are turned into the URL of
+// the gist's raw content, so the link printed by 'cmcp share --gist' works as is.
+func FetchSnippet(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := snippetURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned HTTP %d", u.Host, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSnippetSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	if len(data) > maxSnippetSize {
+		return nil, fmt.Errorf("snippet is larger than %d bytes", maxSnippetSize)
+	}
+	return data, nil
+}
+
+// snippetURL returns where to download a snippet from, pointing gist pages at their
+// raw content
+func snippetURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL '%s'", rawURL)
+	}
+	if u.Host == "gist.github.com" {
+		u.Host = GistRawHost
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/raw"
+		u.Fragment = ""
+	}
+	return u, nil
+}
@@ -0,0 +1,80 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreateGist(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/gists" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer gh-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var body struct {
+			Public bool                         `json:"public"`
+			Files  map[string]map[string]string `json:"files"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Public || body.Files["github.cmcp.json"]["content"] != "{}" {
+			t.Errorf("body = %+v, want one secret file", body)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"html_url":"https://gist.github.com/octo/abc123"}`))
+	}))
+	defer srv.Close()
+
+	orig := GistAPIURL
+	GistAPIURL = srv.URL
+	defer func() { GistAPIURL = orig }()
+
+	got, err := CreateGist(context.Background(), "gh-token", "github.cmcp.json", "cmcp server", []byte("{}"))
+	if err != nil {
+		t.Fatalf("CreateGist() error = %v", err)
+	}
+	if got != "https://gist.github.com/octo/abc123" {
+		t.Errorf("CreateGist() = %q", got)
+	}
+}
+
+func TestFetchSnippet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"mcpServers":{}}`))
+	}))
+	defer srv.Close()
+
+	data, err := FetchSnippet(context.Background(), srv.URL+"/snippet.json")
+	if err != nil || string(data) != `{"mcpServers":{}}` {
+		t.Errorf("FetchSnippet() = %q, %v", data, err)
+	}
+	if _, err := FetchSnippet(context.Background(), srv.URL+"/missing"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("FetchSnippet() error = %v, want HTTP 404", err)
+	}
+	if _, err := FetchSnippet(context.Background(), "file:///etc/passwd"); err == nil {
+		t.Error("FetchSnippet() should reject non-HTTP URLs")
+	}
+}
+
+func TestSnippetURL(t *testing.T) {
+	tests := map[string]string{
+		"https://gist.github.com/octo/abc123":         "https://gist.githubusercontent.com/octo/abc123/raw",
+		"https://gist.github.com/octo/abc123/#file-x": "https://gist.githubusercontent.com/octo/abc123/raw",
+		"https://example.com/team/github.cmcp.json":   "https://example.com/team/github.cmcp.json",
+	}
+	for in, want := range tests {
+		got, err := snippetURL(in)
+		if err != nil || got.String() != want {
+			t.Errorf("snippetURL(%q) = %v, %v, want %s", in, got, err, want)
+		}
+	}
+}
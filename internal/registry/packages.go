@@ -0,0 +1,122 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+// PyPIURL is the PyPI JSON API endpoint, overridable for testing
+var PyPIURL = "https://pypi.org"
+
+// Package ecosystems that can be checked for updates
+const (
+	EcosystemNPM  = "npm"
+	EcosystemPyPI = "pypi"
+)
+
+// PackageRef identifies the package a server is launched from
+type PackageRef struct {
+	Ecosystem string
+	Name      string
+	Version   string // Pinned version, empty when unpinned
+	ArgIndex  int    // Index of the package spec in the server's args
+}
+
+// Spec returns the package spec with the given version pinned
+func (p PackageRef) Spec(version string) string {
+	if version == "" {
+		return p.Name
+	}
+	if p.Ecosystem == EcosystemPyPI {
+		return p.Name + "==" + version
+	}
+	return p.Name + "@" + version
+}
+
+// FindPackage locates the package spec of an npx or uvx launched server
+func FindPackage(server *config.MCPServer) (*PackageRef, bool) {
+	switch server.Command {
+	case "npx":
+		for i, arg := range server.Args {
+			if strings.HasPrefix(arg, "-") {
+				// npx --package <pkg> names the package explicitly
+				if (arg == "-p" || arg == "--package") && i+1 < len(server.Args) {
+					return parseNPMSpec(server.Args[i+1], i+1), true
+				}
+				continue
+			}
+			return parseNPMSpec(arg, i), true
+		}
+	case "uvx":
+		for i, arg := range server.Args {
+			if arg == "--from" && i+1 < len(server.Args) {
+				return parsePyPISpec(server.Args[i+1], i+1), true
+			}
+		}
+		for i, arg := range server.Args {
+			if strings.HasPrefix(arg, "-") {
+				continue
+			}
+			// Skip values of options like --python 3.12
+			if i > 0 && (server.Args[i-1] == "--python" || server.Args[i-1] == "--with") {
+				continue
+			}
+			return parsePyPISpec(arg, i), true
+		}
+	}
+	return nil, false
+}
+
+func parseNPMSpec(spec string, index int) *PackageRef {
+	ref := &PackageRef{Ecosystem: EcosystemNPM, Name: spec, ArgIndex: index}
+	// Scoped packages start with @, so only look for a version separator after it
+	if at := strings.LastIndex(spec, "@"); at > 0 {
+		ref.Name = spec[:at]
+		ref.Version = spec[at+1:]
+	}
+	return ref
+}
+
+func parsePyPISpec(spec string, index int) *PackageRef {
+	ref := &PackageRef{Ecosystem: EcosystemPyPI, Name: spec, ArgIndex: index}
+	if eq := strings.Index(spec, "=="); eq > 0 {
+		ref.Name = spec[:eq]
+		ref.Version = spec[eq+2:]
+	} else if at := strings.Index(spec, "@"); at > 0 {
+		// uvx also accepts name@version
+		ref.Name = spec[:at]
+		ref.Version = spec[at+1:]
+	}
+	return ref
+}
+
+// LatestVersion asks the package's registry for its newest release
+func LatestVersion(ctx context.Context, ref *PackageRef) (string, error) {
+	switch ref.Ecosystem {
+	case EcosystemNPM:
+		var resp struct {
+			Version string `json:"version"`
+		}
+		// Scoped names keep their @ but the slash must be escaped
+		name := strings.Replace(ref.Name, "/", "%2F", 1)
+		if err := getJSON(ctx, NPMRegistryURL+"/"+name+"/latest", &resp); err != nil {
+			return "", err
+		}
+		return resp.Version, nil
+	case EcosystemPyPI:
+		var resp struct {
+			Info struct {
+				Version string `json:"version"`
+			} `json:"info"`
+		}
+		if err := getJSON(ctx, PyPIURL+"/pypi/"+url.PathEscape(ref.Name)+"/json", &resp); err != nil {
+			return "", err
+		}
+		return resp.Info.Version, nil
+	}
+	return "", fmt.Errorf("unsupported ecosystem '%s'", ref.Ecosystem)
+}
@@ -0,0 +1,107 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/config"
+)
+
+func TestFindPackage(t *testing.T) {
+	tests := []struct {
+		name      string
+		server    config.MCPServer
+		ecosystem string
+		pkg       string
+		version   string
+		index     int
+	}{
+		{
+			name:      "unpinned npx",
+			server:    config.MCPServer{Command: "npx", Args: []string{"-y", "@modelcontextprotocol/server-github"}},
+			ecosystem: EcosystemNPM, pkg: "@modelcontextprotocol/server-github", index: 1,
+		},
+		{
+			name:      "pinned scoped npx",
+			server:    config.MCPServer{Command: "npx", Args: []string{"-y", "@upstash/context7-mcp@1.0.14"}},
+			ecosystem: EcosystemNPM, pkg: "@upstash/context7-mcp", version: "1.0.14", index: 1,
+		},
+		{
+			name:      "npx --package",
+			server:    config.MCPServer{Command: "npx", Args: []string{"--package", "tool@2.0.0", "tool-bin"}},
+			ecosystem: EcosystemNPM, pkg: "tool", version: "2.0.0", index: 1,
+		},
+		{
+			name:      "pinned uvx",
+			server:    config.MCPServer{Command: "uvx", Args: []string{"mcp-server-fetch==2025.4.7"}},
+			ecosystem: EcosystemPyPI, pkg: "mcp-server-fetch", version: "2025.4.7", index: 0,
+		},
+		{
+			name:      "uvx with python option",
+			server:    config.MCPServer{Command: "uvx", Args: []string{"--python", "3.12", "mcp-server-git"}},
+			ecosystem: EcosystemPyPI, pkg: "mcp-server-git", index: 2,
+		},
+		{
+			name:      "uvx --from",
+			server:    config.MCPServer{Command: "uvx", Args: []string{"--from", "mcp-server-time@0.6.2", "mcp-server-time"}},
+			ecosystem: EcosystemPyPI, pkg: "mcp-server-time", version: "0.6.2", index: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, ok := FindPackage(&tt.server)
+			if !ok {
+				t.Fatal("FindPackage() found nothing")
+			}
+			if ref.Ecosystem != tt.ecosystem || ref.Name != tt.pkg || ref.Version != tt.version || ref.ArgIndex != tt.index {
+				t.Errorf("FindPackage() = %+v", ref)
+			}
+		})
+	}
+
+	if _, ok := FindPackage(&config.MCPServer{Command: "docker", Args: []string{"run", "image"}}); ok {
+		t.Error("docker servers should not have a package")
+	}
+}
+
+func TestPackageSpec(t *testing.T) {
+	npm := PackageRef{Ecosystem: EcosystemNPM, Name: "@scope/pkg"}
+	if got := npm.Spec("1.2.3"); got != "@scope/pkg@1.2.3" {
+		t.Errorf("Spec() = %q", got)
+	}
+	pypi := PackageRef{Ecosystem: EcosystemPyPI, Name: "pkg"}
+	if got := pypi.Spec("1.2.3"); got != "pkg==1.2.3" {
+		t.Errorf("Spec() = %q", got)
+	}
+}
+
+func TestLatestVersion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/@scope%2Fpkg/latest":
+			w.Write([]byte(`{"version":"3.0.0"}`))
+		case "/pypi/mcp-server-fetch/json":
+			w.Write([]byte(`{"info":{"version":"2025.4.7"}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	origNPM, origPyPI := NPMRegistryURL, PyPIURL
+	NPMRegistryURL, PyPIURL = srv.URL, srv.URL
+	defer func() { NPMRegistryURL, PyPIURL = origNPM, origPyPI }()
+
+	v, err := LatestVersion(context.Background(), &PackageRef{Ecosystem: EcosystemNPM, Name: "@scope/pkg"})
+	if err != nil || v != "3.0.0" {
+		t.Errorf("npm LatestVersion() = %q, %v", v, err)
+	}
+	v, err = LatestVersion(context.Background(), &PackageRef{Ecosystem: EcosystemPyPI, Name: "mcp-server-fetch"})
+	if err != nil || v != "2025.4.7" {
+		t.Errorf("pypi LatestVersion() = %q, %v", v, err)
+	}
+}
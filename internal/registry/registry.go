@@ -0,0 +1,230 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Endpoints for the supported registries, overridable for testing
+var (
+	MCPRegistryURL = "https://registry.modelcontextprotocol.io"
+	NPMRegistryURL = "https://registry.npmjs.org"
+)
+
+// Sources that can be searched
+const (
+	SourceMCP = "mcp"
+	SourceNPM = "npm"
+)
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// Entry is a server found in a registry
+type Entry struct {
+	Name        string
+	Description string
+	Version     string
+	Source      string
+	Command     string   // Launcher command for stdio servers (e.g. "npx")
+	Args        []string // Launcher arguments
+	URL         string   // Endpoint for remote servers
+}
+
+// InstallHint returns a short description of how to run the server
+func (e Entry) InstallHint() string {
+	if e.Command != "" {
+		return strings.TrimSpace(e.Command + " " + strings.Join(e.Args, " "))
+	}
+	return e.URL
+}
+
+// Search queries the given source for servers matching the query
+func Search(ctx context.Context, source, query string, limit int) ([]Entry, error) {
+	switch source {
+	case SourceMCP:
+		return searchMCP(ctx, query, limit)
+	case SourceNPM:
+		return searchNPM(ctx, query, limit)
+	default:
+		return nil, fmt.Errorf("unknown registry source '%s' (use %s or %s)", source, SourceMCP, SourceNPM)
+	}
+}
+
+// mcpPackage is a package entry in the official MCP registry
+type mcpPackage struct {
+	RegistryType    string `json:"registryType"`
+	RegistryTypeOld string `json:"registry_type"`
+	Identifier      string `json:"identifier"`
+	Name            string `json:"name"`
+	Version         string `json:"version"`
+	RegistryName    string `json:"registry_name"`
+	RuntimeHint     string `json:"runtimeHint"`
+	RuntimeHintOld  string `json:"runtime_hint"`
+}
+
+// mcpServer is a server entry in the official MCP registry
+type mcpServer struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Version       string `json:"version"`
+	VersionDetail struct {
+		Version string `json:"version"`
+	} `json:"version_detail"`
+	Packages []mcpPackage `json:"packages"`
+	Remotes  []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"remotes"`
+}
+
+func searchMCP(ctx context.Context, query string, limit int) ([]Entry, error) {
+	params := url.Values{}
+	params.Set("search", query)
+	params.Set("limit", fmt.Sprintf("%d", limit))
+
+	var resp struct {
+		Servers []json.RawMessage `json:"servers"`
+	}
+	if err := getJSON(ctx, MCPRegistryURL+"/v0/servers?"+params.Encode(), &resp); err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, raw := range resp.Servers {
+		// Newer registry versions wrap each server with metadata
+		var wrapped struct {
+			Server *mcpServer `json:"server"`
+		}
+		var server mcpServer
+		if json.Unmarshal(raw, &wrapped) == nil && wrapped.Server != nil {
+			server = *wrapped.Server
+		} else if err := json.Unmarshal(raw, &server); err != nil {
+			continue
+		}
+
+		entry := Entry{
+			Name:        server.Name,
+			Description: server.Description,
+			Version:     server.Version,
+			Source:      SourceMCP,
+		}
+		if entry.Version == "" {
+			entry.Version = server.VersionDetail.Version
+		}
+
+		for _, pkg := range server.Packages {
+			if command, args := packageCommand(pkg); command != "" {
+				entry.Command, entry.Args = command, args
+				break
+			}
+		}
+		if entry.Command == "" && len(server.Remotes) > 0 {
+			entry.URL = server.Remotes[0].URL
+		}
+
+		entries = append(entries, entry)
+		if len(entries) >= limit {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// packageCommand maps a registry package to the launcher that runs it
+func packageCommand(pkg mcpPackage) (string, []string) {
+	registryType := firstNonEmpty(pkg.RegistryType, pkg.RegistryTypeOld, pkg.RegistryName)
+	identifier := firstNonEmpty(pkg.Identifier, pkg.Name)
+	if identifier == "" {
+		return "", nil
+	}
+
+	switch registryType {
+	case "npm":
+		return "npx", []string{"-y", identifier}
+	case "pypi":
+		return "uvx", []string{identifier}
+	case "oci", "docker":
+		return "docker", []string{"run", "-i", "--rm", identifier}
+	}
+	if hint := firstNonEmpty(pkg.RuntimeHint, pkg.RuntimeHintOld); hint != "" {
+		return hint, []string{identifier}
+	}
+	return "", nil
+}
+
+func searchNPM(ctx context.Context, query string, limit int) ([]Entry, error) {
+	params := url.Values{}
+	params.Set("text", query+" keywords:mcp")
+	params.Set("size", fmt.Sprintf("%d", limit))
+
+	var resp struct {
+		Objects []struct {
+			Package struct {
+				Name        string `json:"name"`
+				Description string `json:"description"`
+				Version     string `json:"version"`
+			} `json:"package"`
+		} `json:"objects"`
+	}
+	if err := getJSON(ctx, NPMRegistryURL+"/-/v1/search?"+params.Encode(), &resp); err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, obj := range resp.Objects {
+		entries = append(entries, Entry{
+			Name:        obj.Package.Name,
+			Description: obj.Package.Description,
+			Version:     obj.Package.Version,
+			Source:      SourceNPM,
+			Command:     "npx",
+			Args:        []string{"-y", obj.Package.Name},
+		})
+	}
+	return entries, nil
+}
+
+// getJSON fetches a URL and decodes the JSON response
+func getJSON(ctx context.Context, rawURL string, v interface{}) error {
+	return getJSONWithToken(ctx, rawURL, "", v)
+}
+
+// getJSONWithToken is getJSON with an optional bearer token
+func getJSONWithToken(ctx context.Context, rawURL, token string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("registry request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry returned HTTP %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid registry response: %w", err)
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
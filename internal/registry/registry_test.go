@@ -0,0 +1,80 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearchMCP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v0/servers" || r.URL.Query().Get("search") != "github" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		w.Write([]byte(`{"servers":[
+			{"server":{"name":"io.github/github","description":"GitHub tools","version":"1.2.0",
+				"packages":[{"registryType":"oci","identifier":"ghcr.io/github/github-mcp-server"}]}},
+			{"name":"com.example/remote","description":"Remote only","version_detail":{"version":"0.1.0"},
+				"remotes":[{"type":"streamable-http","url":"https://example.com/mcp"}]},
+			{"name":"io.example/py","packages":[{"registry_type":"pypi","identifier":"mcp-server-py"}]}
+		]}`))
+	}))
+	defer srv.Close()
+
+	orig := MCPRegistryURL
+	MCPRegistryURL = srv.URL
+	defer func() { MCPRegistryURL = orig }()
+
+	entries, err := Search(context.Background(), SourceMCP, "github", 10)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	tests := []struct {
+		index   int
+		version string
+		hint    string
+	}{
+		{0, "1.2.0", "docker run -i --rm ghcr.io/github/github-mcp-server"},
+		{1, "0.1.0", "https://example.com/mcp"},
+		{2, "", "uvx mcp-server-py"},
+	}
+	for _, tt := range tests {
+		entry := entries[tt.index]
+		if entry.Version != tt.version {
+			t.Errorf("entry %d: Version = %q, want %q", tt.index, entry.Version, tt.version)
+		}
+		if entry.InstallHint() != tt.hint {
+			t.Errorf("entry %d: InstallHint() = %q, want %q", tt.index, entry.InstallHint(), tt.hint)
+		}
+	}
+}
+
+func TestSearchNPM(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"objects":[{"package":{"name":"@modelcontextprotocol/server-github","description":"GitHub","version":"2025.1.1"}}]}`))
+	}))
+	defer srv.Close()
+
+	orig := NPMRegistryURL
+	NPMRegistryURL = srv.URL
+	defer func() { NPMRegistryURL = orig }()
+
+	entries, err := Search(context.Background(), SourceNPM, "github", 5)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].InstallHint() != "npx -y @modelcontextprotocol/server-github" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestSearchUnknownSource(t *testing.T) {
+	if _, err := Search(context.Background(), "bogus", "x", 5); err == nil {
+		t.Error("expected error for unknown source")
+	}
+}
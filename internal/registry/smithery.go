@@ -0,0 +1,275 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lopezm94/cmcp/internal/catalog"
+	"github.com/lopezm94/cmcp/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// SmitheryURL is the Smithery registry API endpoint, overridable for testing
+var SmitheryURL = "https://registry.smithery.ai"
+
+// smitherySchema is the JSON schema Smithery servers use to declare their config
+type smitherySchema struct {
+	Required   []string `yaml:"required" json:"required"`
+	Properties map[string]struct {
+		Type        string      `yaml:"type" json:"type"`
+		Description string      `yaml:"description" json:"description"`
+		Default     interface{} `yaml:"default" json:"default"`
+	} `yaml:"properties" json:"properties"`
+}
+
+// smitheryManifest is the relevant part of a smithery.yaml
+type smitheryManifest struct {
+	StartCommand struct {
+		Type            string         `yaml:"type"`
+		ConfigSchema    smitherySchema `yaml:"configSchema"`
+		CommandFunction string         `yaml:"commandFunction"`
+	} `yaml:"startCommand"`
+}
+
+// smitheryServer is a server as returned by the Smithery registry API
+type smitheryServer struct {
+	QualifiedName string `json:"qualifiedName"`
+	DisplayName   string `json:"displayName"`
+	Description   string `json:"description"`
+	Connections   []struct {
+		Type          string         `json:"type"`
+		DeploymentURL string         `json:"deploymentUrl"`
+		ConfigSchema  smitherySchema `json:"configSchema"`
+		StdioFunction string         `json:"stdioFunction"`
+	} `json:"connections"`
+}
+
+// LoadSmitheryManifest converts a local smithery.yaml into a catalog entry.
+// The server runs from the manifest's directory, as Smithery would run it.
+func LoadSmitheryManifest(file string) (catalog.Entry, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return catalog.Entry{}, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	var manifest smitheryManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return catalog.Entry{}, fmt.Errorf("invalid smithery.yaml: %w", err)
+	}
+	start := manifest.StartCommand
+	if start.Type != "stdio" {
+		return catalog.Entry{}, fmt.Errorf("start command type '%s' is not supported from a file; import HTTP servers by name from the Smithery registry", start.Type)
+	}
+
+	entry, err := stdioEntry(start.CommandFunction, start.ConfigSchema)
+	if err != nil {
+		return catalog.Entry{}, err
+	}
+	dir, err := filepath.Abs(filepath.Dir(file))
+	if err != nil {
+		return catalog.Entry{}, err
+	}
+	entry.ID = filepath.Base(dir)
+	entry.Server.Cwd = dir
+	return entry, nil
+}
+
+// GetSmitheryServer looks up a server in the Smithery registry and converts it into a
+// catalog entry, preferring a hosted HTTP deployment. SMITHERY_API_KEY is sent when set.
+func GetSmitheryServer(ctx context.Context, name string) (catalog.Entry, error) {
+	var server smitheryServer
+	rawURL := SmitheryURL + "/servers/" + url.PathEscape(name)
+	if err := getJSONWithToken(ctx, rawURL, os.Getenv("SMITHERY_API_KEY"), &server); err != nil {
+		return catalog.Entry{}, err
+	}
+
+	var entry catalog.Entry
+	var err error
+	found := false
+	for _, conn := range server.Connections {
+		if conn.Type == "http" && conn.DeploymentURL != "" {
+			entry, found = httpEntry(conn.DeploymentURL, conn.ConfigSchema), true
+			break
+		}
+	}
+	if !found {
+		for _, conn := range server.Connections {
+			if conn.Type == "stdio" && conn.StdioFunction != "" {
+				if entry, err = stdioEntry(conn.StdioFunction, conn.ConfigSchema); err != nil {
+					return catalog.Entry{}, err
+				}
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return catalog.Entry{}, fmt.Errorf("'%s' has no connection cmcp can import", name)
+	}
+
+	entry.ID = path.Base(strings.TrimPrefix(firstNonEmpty(server.QualifiedName, name), "@"))
+	entry.Description = firstNonEmpty(server.Description, server.DisplayName)
+	return entry, nil
+}
+
+// httpEntry builds a remote server that passes its config as query parameters,
+// which is how Smithery's hosted deployments receive it
+func httpEntry(deploymentURL string, schema smitherySchema) catalog.Entry {
+	inputs := schemaInputs(schema, func(string) bool { return true })
+	inputs = append(inputs, catalog.Input{Name: "smithery_api_key", Prompt: "Smithery API key", Secret: true})
+
+	endpoint := strings.TrimSuffix(deploymentURL, "/")
+	if !strings.HasSuffix(endpoint, "/mcp") {
+		endpoint += "/mcp"
+	}
+	params := []string{"api_key=${input:smithery_api_key}"}
+	for _, input := range inputs[:len(inputs)-1] {
+		params = append(params, input.Name+"=${input:"+input.Name+"}")
+	}
+
+	return catalog.Entry{
+		Server: config.MCPServer{Type: "http", URL: endpoint + "?" + strings.Join(params, "&")},
+		Inputs: inputs,
+	}
+}
+
+// stdioEntry converts a Smithery command function into a server definition
+func stdioEntry(function string, schema smitherySchema) (catalog.Entry, error) {
+	command, args, env, err := parseCommandFunction(function)
+	if err != nil {
+		return catalog.Entry{}, err
+	}
+
+	referenced := make(map[string]bool)
+	for _, value := range append(append([]string{}, args...), envValues(env)...) {
+		if m := configRef.FindStringSubmatch(value); m != nil {
+			referenced[m[1]] = true
+		}
+	}
+	inputs := schemaInputs(schema, func(name string) bool { return referenced[name] })
+	provided := make(map[string]bool)
+	for _, input := range inputs {
+		provided[input.Name] = true
+	}
+
+	// Optional settings without a default are left out rather than prompted for
+	for key, value := range env {
+		if m := configRef.FindStringSubmatch(value); m != nil && !provided[m[1]] {
+			delete(env, key)
+		}
+	}
+	var keptArgs []string
+	for _, arg := range args {
+		if m := configRef.FindStringSubmatch(arg); m == nil || provided[m[1]] {
+			keptArgs = append(keptArgs, arg)
+		}
+	}
+
+	return catalog.Entry{
+		Server: config.MCPServer{Command: command, Args: keptArgs, Env: env},
+		Inputs: inputs,
+	}, nil
+}
+
+// schemaInputs returns prompts for the required settings plus the optional
+// settings with a default that the server uses
+func schemaInputs(schema smitherySchema, uses func(name string) bool) []catalog.Input {
+	required := make(map[string]bool)
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var inputs []catalog.Input
+	for _, name := range names {
+		prop := schema.Properties[name]
+		input := catalog.Input{
+			Name:   name,
+			Prompt: firstNonEmpty(prop.Description, name),
+			Secret: looksSecret(name),
+		}
+		if prop.Default != nil {
+			input.Default = fmt.Sprint(prop.Default)
+		}
+		if required[name] || (input.Default != "" && uses(name)) {
+			inputs = append(inputs, input)
+		}
+	}
+	return inputs
+}
+
+// looksSecret reports whether a setting name suggests a credential
+func looksSecret(name string) bool {
+	lower := strings.ToLower(name)
+	for _, word := range []string{"key", "token", "secret", "password"} {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// configRef matches a ${input:name} placeholder produced from config.name
+	configRef      = regexp.MustCompile(`^\$\{input:([A-Za-z0-9_]+)\}$`)
+	commandPattern = regexp.MustCompile(`command\s*:\s*['"\x60]([^'"\x60]+)['"\x60]`)
+	argsPattern    = regexp.MustCompile(`args\s*:\s*\[([^\]]*)\]`)
+	envPattern     = regexp.MustCompile(`env\s*:\s*\{([^}]*)\}`)
+	itemPattern    = regexp.MustCompile(`['"\x60]([^'"\x60]*)['"\x60]|config\.([A-Za-z0-9_]+)`)
+	envPairPattern = regexp.MustCompile(`['"]?([A-Za-z_][A-Za-z0-9_]*)['"]?\s*:\s*(?:config\.([A-Za-z0-9_]+)|['"\x60]([^'"\x60]*)['"\x60])`)
+)
+
+// parseCommandFunction extracts command, args, and env from a Smithery command
+// function such as (config) => ({ command: 'node', args: ['index.js'], env: { KEY: config.key } }).
+// References to config.name become ${input:name} placeholders.
+func parseCommandFunction(function string) (string, []string, map[string]string, error) {
+	m := commandPattern.FindStringSubmatch(function)
+	if m == nil {
+		return "", nil, nil, fmt.Errorf("could not find a command in the Smithery command function; add the server with 'cmcp config add' instead")
+	}
+	command := m[1]
+
+	var args []string
+	if m := argsPattern.FindStringSubmatch(function); m != nil {
+		for _, item := range itemPattern.FindAllStringSubmatch(m[1], -1) {
+			if item[2] != "" {
+				args = append(args, "${input:"+item[2]+"}")
+			} else {
+				args = append(args, item[1])
+			}
+		}
+	}
+
+	var env map[string]string
+	if m := envPattern.FindStringSubmatch(function); m != nil {
+		env = make(map[string]string)
+		for _, pair := range envPairPattern.FindAllStringSubmatch(m[1], -1) {
+			if pair[2] != "" {
+				env[pair[1]] = "${input:" + pair[2] + "}"
+			} else {
+				env[pair[1]] = pair[3]
+			}
+		}
+	}
+	return command, args, env, nil
+}
+
+func envValues(env map[string]string) []string {
+	values := make([]string, 0, len(env))
+	for _, value := range env {
+		values = append(values, value)
+	}
+	return values
+}
@@ -0,0 +1,118 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseCommandFunction(t *testing.T) {
+	function := `(config) => ({
+  command: 'node',
+  args: ['dist/index.js', "--root", config.root],
+  env: { API_KEY: config.apiKey, "LOG_LEVEL": 'info' }
+})`
+
+	command, args, env, err := parseCommandFunction(function)
+	if err != nil {
+		t.Fatalf("parseCommandFunction() error = %v", err)
+	}
+	if command != "node" {
+		t.Errorf("command = %q, want node", command)
+	}
+	if want := []string{"dist/index.js", "--root", "${input:root}"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+	if want := map[string]string{"API_KEY": "${input:apiKey}", "LOG_LEVEL": "info"}; !reflect.DeepEqual(env, want) {
+		t.Errorf("env = %v, want %v", env, want)
+	}
+
+	if _, _, _, err := parseCommandFunction(`(config) => buildCommand(config)`); err == nil {
+		t.Error("expected error when no command can be found")
+	}
+}
+
+func TestLoadSmitheryManifest(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "weather-server")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `startCommand:
+  type: stdio
+  configSchema:
+    type: object
+    required: [apiKey]
+    properties:
+      apiKey:
+        type: string
+        description: OpenWeather API key
+      units:
+        type: string
+        default: metric
+      debug:
+        type: boolean
+  commandFunction: |-
+    (config) => ({ command: 'node', args: ['build/index.js'], env: { API_KEY: config.apiKey, UNITS: config.units, DEBUG: config.debug } })
+`
+	file := filepath.Join(dir, "smithery.yaml")
+	if err := os.WriteFile(file, []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := LoadSmitheryManifest(file)
+	if err != nil {
+		t.Fatalf("LoadSmitheryManifest() error = %v", err)
+	}
+	if entry.ID != "weather-server" || entry.Server.Cwd != dir {
+		t.Errorf("ID = %q, Cwd = %q", entry.ID, entry.Server.Cwd)
+	}
+
+	// Optional settings without a default are dropped
+	if want := map[string]string{"API_KEY": "${input:apiKey}", "UNITS": "${input:units}"}; !reflect.DeepEqual(entry.Server.Env, want) {
+		t.Errorf("Env = %v, want %v", entry.Server.Env, want)
+	}
+	if len(entry.Inputs) != 2 || entry.Inputs[0].Name != "apiKey" || !entry.Inputs[0].Secret || entry.Inputs[1].Default != "metric" {
+		t.Errorf("Inputs = %+v", entry.Inputs)
+	}
+}
+
+func TestGetSmitheryServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servers/@acme/search" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization = %q", got)
+		}
+		w.Write([]byte(`{"qualifiedName":"@acme/search","displayName":"Acme Search","connections":[
+			{"type":"stdio","stdioFunction":"(config) => ({command: 'npx', args: ['acme']})"},
+			{"type":"http","deploymentUrl":"https://server.smithery.ai/@acme/search",
+				"configSchema":{"required":["token"],"properties":{"token":{"type":"string"}}}}
+		]}`))
+	}))
+	defer srv.Close()
+
+	orig := SmitheryURL
+	SmitheryURL = srv.URL
+	defer func() { SmitheryURL = orig }()
+	t.Setenv("SMITHERY_API_KEY", "test-key")
+
+	entry, err := GetSmitheryServer(context.Background(), "@acme/search")
+	if err != nil {
+		t.Fatalf("GetSmitheryServer() error = %v", err)
+	}
+	if entry.ID != "search" || entry.Description != "Acme Search" {
+		t.Errorf("ID = %q, Description = %q", entry.ID, entry.Description)
+	}
+	wantURL := "https://server.smithery.ai/@acme/search/mcp?api_key=${input:smithery_api_key}&token=${input:token}"
+	if entry.Server.URL != wantURL || entry.Server.Type != "http" {
+		t.Errorf("Server = %+v, want URL %s", entry.Server, wantURL)
+	}
+	if len(entry.Inputs) != 2 {
+		t.Errorf("Inputs = %+v, want token and API key", entry.Inputs)
+	}
+}
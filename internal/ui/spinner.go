@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// clearLine moves the cursor to the start of the line and erases it
+const clearLine = "\r\033[K"
+
+// Spinner shows an animated indicator with the current step while silent work
+// is in progress. It only animates when stdout is a terminal.
+type Spinner struct {
+	mu       sync.Mutex
+	out      io.Writer
+	enabled  bool
+	interval time.Duration
+	message  string
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewSpinner creates a spinner writing to stdout
+func NewSpinner() *Spinner {
+	return &Spinner{
+		out:      os.Stdout,
+		enabled:  isTerminal(os.Stdout),
+		interval: 100 * time.Millisecond,
+	}
+}
+
+// Step sets the message shown next to the spinner, starting it if needed
+func (s *Spinner) Step(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.message = message
+	if !s.enabled || s.stop != nil {
+		return
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.done)
+}
+
+// Clear stops the spinner and erases its line so regular output can follow
+func (s *Spinner) Clear() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+	fmt.Fprint(s.out, clearLine)
+}
+
+func (s *Spinner) run(stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for frame := 0; ; frame++ {
+		s.mu.Lock()
+		message := s.message
+		s.mu.Unlock()
+
+		fmt.Fprintf(s.out, "%s%s %s", clearLine, Info.Sprint(spinnerFrames[frame%len(spinnerFrames)]), Muted.Sprint(message))
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
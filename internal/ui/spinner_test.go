@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a strings.Builder safe for use from the spinner goroutine
+type syncBuffer struct {
+	mu sync.Mutex
+	sb strings.Builder
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sb.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sb.String()
+}
+
+func TestSpinnerStepAndClear(t *testing.T) {
+	out := &syncBuffer{}
+	s := &Spinner{out: out, enabled: true, interval: time.Millisecond}
+
+	s.Step("Adding 'github' to Claude...")
+	time.Sleep(10 * time.Millisecond)
+	s.Step("Verifying connection (attempt 2/3)...")
+	time.Sleep(10 * time.Millisecond)
+	s.Clear()
+
+	got := out.String()
+	if !strings.Contains(got, "Adding 'github' to Claude...") || !strings.Contains(got, "attempt 2/3") {
+		t.Errorf("output missing step messages: %q", got)
+	}
+	if !strings.HasSuffix(got, clearLine) {
+		t.Errorf("output should end by clearing the line: %q", got)
+	}
+
+	// Clearing twice is a no-op
+	s.Clear()
+	if out.String() != got {
+		t.Error("second Clear() should not write")
+	}
+}
+
+func TestSpinnerDisabled(t *testing.T) {
+	out := &syncBuffer{}
+	s := &Spinner{out: out, enabled: false, interval: time.Millisecond}
+
+	s.Step("working")
+	time.Sleep(5 * time.Millisecond)
+	s.Clear()
+
+	if out.String() != "" {
+		t.Errorf("disabled spinner wrote %q", out.String())
+	}
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"golang.org/x/term"
+)
+
+// columnGap is the space between columns
+const columnGap = "  "
+
+// minFlexWidth is the narrowest a flexible column is truncated to
+const minFlexWidth = 10
+
+// Column describes one table column
+type Column struct {
+	Header string
+	Flex   bool // Truncated with an ellipsis when the table is wider than the terminal
+}
+
+// Cell is a table value; the color is applied after alignment so it doesn't affect widths
+type Cell struct {
+	Text  string
+	Color *color.Color
+}
+
+// Table renders rows as aligned columns
+type Table struct {
+	Columns []Column
+	Rows    [][]Cell
+	Width   int // Maximum line width; 0 means unlimited
+
+	out io.Writer
+}
+
+// NewTable creates a table writing to stdout, fitted to the terminal width
+func NewTable(columns ...Column) *Table {
+	return &Table{Columns: columns, Width: TerminalWidth(), out: os.Stdout}
+}
+
+// TerminalWidth returns the width of the terminal on stdout, or 0 when stdout is not a terminal
+func TerminalWidth() int {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// Add appends a row; missing cells are left empty
+func (t *Table) Add(cells ...Cell) {
+	t.Rows = append(t.Rows, cells)
+}
+
+// Render writes the header and rows
+func (t *Table) Render() {
+	widths := t.columnWidths()
+
+	header := make([]Cell, len(t.Columns))
+	for i, column := range t.Columns {
+		header[i] = Cell{Text: column.Header, Color: Muted.Color()}
+	}
+	t.renderRow(header, widths)
+	for _, row := range t.Rows {
+		t.renderRow(row, widths)
+	}
+}
+
+// columnWidths sizes each column to its widest cell, then shrinks flexible
+// columns (widest first) until the table fits within Width
+func (t *Table) columnWidths() []int {
+	widths := make([]int, len(t.Columns))
+	for i, column := range t.Columns {
+		widths[i] = utf8.RuneCountInString(column.Header)
+	}
+	for _, row := range t.Rows {
+		for i, cell := range row {
+			if i < len(widths) {
+				if w := utf8.RuneCountInString(cell.Text); w > widths[i] {
+					widths[i] = w
+				}
+			}
+		}
+	}
+
+	if t.Width <= 0 {
+		return widths
+	}
+	total := len(columnGap) * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+
+	for total > t.Width {
+		widest := -1
+		for i, column := range t.Columns {
+			if column.Flex && widths[i] > minFlexWidth && (widest < 0 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+	return widths
+}
+
+func (t *Table) renderRow(row []Cell, widths []int) {
+	// Trailing empty cells are dropped so lines don't end in padding
+	last := len(widths) - 1
+	for last > 0 && (last >= len(row) || row[last].Text == "") {
+		last--
+	}
+
+	var line strings.Builder
+	for i := 0; i <= last; i++ {
+		var cell Cell
+		if i < len(row) {
+			cell = row[i]
+		}
+		text := Truncate(cell.Text, widths[i])
+		if i < last {
+			text += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(text)) + columnGap
+		}
+		if cell.Color != nil {
+			// Color the value only, not its padding
+			trimmed := strings.TrimRight(text, " ")
+			text = cell.Color.Sprint(trimmed) + text[len(trimmed):]
+		}
+		line.WriteString(text)
+	}
+	fmt.Fprintln(t.out, line.String())
+}
+
+// Truncate shortens s to at most width characters, ending it with an ellipsis when cut
+func Truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string([]rune(s)[:width])
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly10!", 10, "exactly10!"},
+		{"npx -y @modelcontextprotocol/server-github", 12, "npx -y @mod…"},
+		{"✓ connected", 3, "✓ …"},
+	}
+
+	for _, tt := range tests {
+		if got := Truncate(tt.s, tt.width); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestTableRender(t *testing.T) {
+	var out strings.Builder
+	table := &Table{
+		Columns: []Column{{Header: "NAME"}, {Header: "COMMAND", Flex: true}, {Header: "TAGS"}},
+		out:     &out,
+	}
+	table.Add(Cell{Text: "github"}, Cell{Text: "npx -y @modelcontextprotocol/server-github"}, Cell{Text: "work"})
+	table.Add(Cell{Text: "a-much-longer-name"}, Cell{Text: "uvx fetch"})
+	table.Render()
+
+	want := "NAME                COMMAND                                     TAGS\n" +
+		"github              npx -y @modelcontextprotocol/server-github  work\n" +
+		"a-much-longer-name  uvx fetch\n"
+	if out.String() != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestTableRenderFitsWidth(t *testing.T) {
+	var out strings.Builder
+	table := &Table{
+		Columns: []Column{{Header: "NAME"}, {Header: "COMMAND", Flex: true}, {Header: "TAGS"}},
+		Width:   40,
+		out:     &out,
+	}
+	table.Add(Cell{Text: "github"}, Cell{Text: "npx -y @modelcontextprotocol/server-github"}, Cell{Text: "work"})
+	table.Render()
+
+	for _, line := range strings.Split(strings.TrimRight(out.String(), "\n"), "\n") {
+		if n := len([]rune(line)); n > 40 {
+			t.Errorf("line %q is %d wide, want at most 40", line, n)
+		}
+	}
+	if !strings.Contains(out.String(), "…  work") {
+		t.Errorf("Render() should truncate the flexible column, got\n%s", out.String())
+	}
+}
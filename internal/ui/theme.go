@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// Role is what a piece of colored output means. The active theme decides its color,
+// so output is written through roles rather than fixed colors.
+type Role string
+
+const (
+	Success  Role = "success"  // Completed actions, connected servers
+	Failure  Role = "failure"  // Errors and failed servers
+	Warning  Role = "warning"  // Things that need attention
+	Info     Role = "info"     // Progress and notes
+	Command  Role = "command"  // Commands and definitions
+	Secret   Role = "secret"   // Environment variables and shell placeholders that may hold secrets
+	Muted    Role = "muted"    // Hints and secondary details
+	Emphasis Role = "emphasis" // Names and headings
+)
+
+// Roles are all the roles a theme colors
+var Roles = []Role{Success, Failure, Warning, Info, Command, Secret, Muted, Emphasis}
+
+// DefaultTheme is the preset used when none is chosen
+const DefaultTheme = "default"
+
+// Presets are the built-in themes, as color specs for each role
+var Presets = map[string]map[Role]string{
+	DefaultTheme: {
+		Success: "green", Failure: "red", Warning: "yellow", Info: "cyan",
+		Command: "blue", Secret: "yellow", Muted: "hi-black", Emphasis: "bold",
+	},
+	// Bright, bold colors and no dim gray, for low-contrast terminals and low vision
+	"high-contrast": {
+		Success: "bold hi-green", Failure: "bold hi-red", Warning: "bold hi-yellow", Info: "bold hi-cyan",
+		Command: "bold hi-white", Secret: "bold hi-magenta", Muted: "white", Emphasis: "bold underline",
+	},
+	// Blue and yellow instead of green and red, which look alike with red-green color blindness
+	"colorblind": {
+		Success: "hi-blue", Failure: "bold hi-yellow", Warning: "hi-magenta", Info: "cyan",
+		Command: "hi-cyan", Secret: "magenta", Muted: "hi-black", Emphasis: "bold",
+	},
+}
+
+// colorNames are the color spec words and the attributes they stand for
+var colorNames = map[string]color.Attribute{
+	"black": color.FgBlack, "red": color.FgRed, "green": color.FgGreen, "yellow": color.FgYellow,
+	"blue": color.FgBlue, "magenta": color.FgMagenta, "cyan": color.FgCyan, "white": color.FgWhite,
+	"hi-black": color.FgHiBlack, "hi-red": color.FgHiRed, "hi-green": color.FgHiGreen, "hi-yellow": color.FgHiYellow,
+	"hi-blue": color.FgHiBlue, "hi-magenta": color.FgHiMagenta, "hi-cyan": color.FgHiCyan, "hi-white": color.FgHiWhite,
+	"bold": color.Bold, "faint": color.Faint, "italic": color.Italic, "underline": color.Underline,
+}
+
+var (
+	themeMu sync.RWMutex
+	theme   = mustTheme(DefaultTheme, nil)
+)
+
+// ParseColor turns a color spec such as "bold hi-green" into attributes. Words are
+// separated by spaces or "+"; "none" leaves the output uncolored.
+func ParseColor(spec string) ([]color.Attribute, error) {
+	words := strings.FieldsFunc(strings.ToLower(spec), func(r rune) bool { return r == ' ' || r == '+' })
+	if len(words) == 0 {
+		return nil, fmt.Errorf("empty color")
+	}
+	var attrs []color.Attribute
+	for _, word := range words {
+		if word == "none" {
+			continue
+		}
+		attr, ok := colorNames[word]
+		if !ok {
+			return nil, fmt.Errorf("unknown color '%s' (known: %s, none)", word, strings.Join(ColorNames(), ", "))
+		}
+		attrs = append(attrs, attr)
+	}
+	return attrs, nil
+}
+
+// ColorNames returns the words a color spec can use, sorted
+func ColorNames() []string {
+	names := make([]string, 0, len(colorNames))
+	for name := range colorNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PresetNames returns the built-in theme names, sorted
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// buildTheme resolves a preset (the default when empty) with per-role overrides
+func buildTheme(preset string, overrides map[string]string) (map[Role][]color.Attribute, error) {
+	if preset == "" {
+		preset = DefaultTheme
+	}
+	specs, ok := Presets[preset]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme '%s' (available: %s)", preset, strings.Join(PresetNames(), ", "))
+	}
+
+	resolved := make(map[Role][]color.Attribute, len(Roles))
+	for role, spec := range specs {
+		attrs, err := ParseColor(spec)
+		if err != nil {
+			return nil, fmt.Errorf("theme '%s' %s: %w", preset, role, err)
+		}
+		resolved[role] = attrs
+	}
+	for name, spec := range overrides {
+		role := Role(name)
+		if _, known := specs[role]; !known {
+			return nil, fmt.Errorf("unknown theme role '%s' (roles: %s)", name, roleList())
+		}
+		attrs, err := ParseColor(spec)
+		if err != nil {
+			return nil, fmt.Errorf("theme %s: %w", name, err)
+		}
+		resolved[role] = attrs
+	}
+	return resolved, nil
+}
+
+func mustTheme(preset string, overrides map[string]string) map[Role][]color.Attribute {
+	resolved, err := buildTheme(preset, overrides)
+	if err != nil {
+		panic(err)
+	}
+	return resolved
+}
+
+// roleList joins the role names for error messages
+func roleList() string {
+	names := make([]string, len(Roles))
+	for i, role := range Roles {
+		names[i] = string(role)
+	}
+	return strings.Join(names, ", ")
+}
+
+// SetTheme makes a preset, with colors overridden per role, the active theme. On
+// error the active theme is left as it was.
+func SetTheme(preset string, overrides map[string]string) error {
+	resolved, err := buildTheme(preset, overrides)
+	if err != nil {
+		return err
+	}
+	themeMu.Lock()
+	theme = resolved
+	themeMu.Unlock()
+	return nil
+}
+
+// Color returns the role's color in the active theme
+func (r Role) Color() *color.Color {
+	themeMu.RLock()
+	defer themeMu.RUnlock()
+	return color.New(theme[r]...)
+}
+
+// Print, Printf and Println write to stdout in the role's color, like *color.Color
+func (r Role) Print(a ...interface{})                 { r.Color().Print(a...) }
+func (r Role) Printf(format string, a ...interface{}) { r.Color().Printf(format, a...) }
+func (r Role) Println(a ...interface{})               { r.Color().Println(a...) }
+
+// Fprintf writes to w in the role's color
+func (r Role) Fprintf(w io.Writer, format string, a ...interface{}) {
+	r.Color().Fprintf(w, format, a...)
+}
+
+// Sprint and Sprintf return text in the role's color
+func (r Role) Sprint(a ...interface{}) string                 { return r.Color().Sprint(a...) }
+func (r Role) Sprintf(format string, a ...interface{}) string { return r.Color().Sprintf(format, a...) }
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []color.Attribute
+		wantErr bool
+	}{
+		{spec: "green", want: []color.Attribute{color.FgGreen}},
+		{spec: "bold hi-red", want: []color.Attribute{color.Bold, color.FgHiRed}},
+		{spec: "Bold+Underline", want: []color.Attribute{color.Bold, color.Underline}},
+		{spec: "none", want: nil},
+		{spec: "", wantErr: true},
+		{spec: "orange", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseColor(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseColor(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseColor(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPresetsColorEveryRole(t *testing.T) {
+	for name, specs := range Presets {
+		for _, role := range Roles {
+			if _, err := ParseColor(specs[role]); err != nil {
+				t.Errorf("preset %s %s: %v", name, role, err)
+			}
+		}
+		if len(specs) != len(Roles) {
+			t.Errorf("preset %s has %d roles, want %d", name, len(specs), len(Roles))
+		}
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	defer SetTheme(DefaultTheme, nil)
+
+	if err := SetTheme("colorblind", map[string]string{"secret": "bold"}); err != nil {
+		t.Fatalf("SetTheme() error = %v", err)
+	}
+	if !Success.Color().Equals(color.New(color.FgHiBlue)) || !Secret.Color().Equals(color.New(color.Bold)) {
+		t.Error("SetTheme() did not apply the preset and override")
+	}
+
+	for _, bad := range []struct {
+		preset    string
+		overrides map[string]string
+	}{
+		{preset: "neon"},
+		{overrides: map[string]string{"sucess": "green"}},
+		{overrides: map[string]string{"failure": "orange"}},
+	} {
+		if err := SetTheme(bad.preset, bad.overrides); err == nil {
+			t.Errorf("SetTheme(%q, %v) error = nil", bad.preset, bad.overrides)
+		}
+	}
+	if !Success.Color().Equals(color.New(color.FgHiBlue)) {
+		t.Error("a rejected theme replaced the active one")
+	}
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// nonInteractive forbids prompts even on a terminal
+var nonInteractive bool
+
+// SetNonInteractive forbids or allows prompts for the rest of the process
+func SetNonInteractive(forbid bool) {
+	nonInteractive = forbid
+}
+
+// NonInteractive reports whether prompts were forbidden with SetNonInteractive
+func NonInteractive() bool {
+	return nonInteractive
+}
+
+// IsInteractive reports whether cmcp can prompt: prompts must not be forbidden and
+// stdin and stdout must both be terminals, so prompts neither hang on a pipe nor
+// draw into captured output
+func IsInteractive() bool {
+	return !nonInteractive && isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
+// isTerminal reports whether f is a terminal, including Cygwin and MSYS terminals
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
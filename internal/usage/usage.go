@@ -0,0 +1,255 @@
+package usage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// maxDays caps how many days of counts are kept
+const maxDays = 365
+
+// dayFormat keys the daily counts by local date
+const dayFormat = "2006-01-02"
+
+// ServerCounts counts the starts of a server in a day
+type ServerCounts struct {
+	Starts   int `json:"starts"`   // Starts that connected
+	Failures int `json:"failures"` // Starts that failed
+}
+
+// Day holds the counts of one day
+type Day struct {
+	Commands map[string]int           `json:"commands,omitempty"` // Invocations by command path, e.g. "config add"
+	Servers  map[string]*ServerCounts `json:"servers,omitempty"`
+}
+
+// Usage is the locally persisted count of commands run and server starts. It is
+// never sent anywhere.
+type Usage struct {
+	Days map[string]*Day `json:"days"`
+
+	path string
+}
+
+// New returns empty usage that will be saved to path
+func New(path string) *Usage {
+	return &Usage{Days: make(map[string]*Day), path: path}
+}
+
+// Load reads the usage file, returning empty usage if it doesn't exist
+func Load(path string) (*Usage, error) {
+	u := New(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return u, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, u); err != nil {
+		return nil, err
+	}
+	if u.Days == nil {
+		u.Days = make(map[string]*Day)
+	}
+	return u, nil
+}
+
+// Save writes the usage back to the file it was loaded from, dropping days older
+// than a year
+func (u *Usage) Save() error {
+	if len(u.Days) > maxDays {
+		days := u.days()
+		for _, day := range days[:len(days)-maxDays] {
+			delete(u.Days, day)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(u.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(u, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(u.path, data, 0644)
+}
+
+// RecordCommand counts an invocation of a command
+func (u *Usage) RecordCommand(command string, at time.Time) {
+	day := u.day(at)
+	if day.Commands == nil {
+		day.Commands = make(map[string]int)
+	}
+	day.Commands[command]++
+}
+
+// RecordStart counts a start of a server and whether it connected
+func (u *Usage) RecordStart(server string, ok bool, at time.Time) {
+	day := u.day(at)
+	if day.Servers == nil {
+		day.Servers = make(map[string]*ServerCounts)
+	}
+	counts, exists := day.Servers[server]
+	if !exists {
+		counts = &ServerCounts{}
+		day.Servers[server] = counts
+	}
+	if ok {
+		counts.Starts++
+	} else {
+		counts.Failures++
+	}
+}
+
+// Forget drops the counts of a server. It reports whether there were any.
+func (u *Usage) Forget(server string) bool {
+	found := false
+	for _, day := range u.Days {
+		if _, ok := day.Servers[server]; ok {
+			delete(day.Servers, server)
+			found = true
+		}
+	}
+	return found
+}
+
+// Clear drops all counts
+func (u *Usage) Clear() {
+	u.Days = make(map[string]*Day)
+}
+
+func (u *Usage) day(at time.Time) *Day {
+	key := at.Format(dayFormat)
+	day, ok := u.Days[key]
+	if !ok {
+		day = &Day{}
+		u.Days[key] = day
+	}
+	return day
+}
+
+// days returns the recorded dates, oldest first
+func (u *Usage) days() []string {
+	days := make([]string, 0, len(u.Days))
+	for day := range u.Days {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	return days
+}
+
+// CommandCount is how often a command was run
+type CommandCount struct {
+	Command string `json:"command"`
+	Count   int    `json:"count"`
+}
+
+// ServerSummary sums the starts of a server over a period
+type ServerSummary struct {
+	Name        string `json:"name"`
+	Starts      int    `json:"starts"`
+	Failures    int    `json:"failures"`
+	LastFailure string `json:"lastFailure,omitempty"` // Date of the last failed start
+}
+
+// Attempts returns the number of starts, connected or not
+func (s ServerSummary) Attempts() int {
+	return s.Starts + s.Failures
+}
+
+// FailureRate returns the fraction of starts that failed
+func (s ServerSummary) FailureRate() float64 {
+	if s.Attempts() == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(s.Attempts())
+}
+
+// Summary sums the usage over a period
+type Summary struct {
+	Since    string          `json:"since,omitempty"` // First date counted; empty when nothing was recorded
+	Commands []CommandCount  `json:"commands"`        // Most run first
+	Servers  []ServerSummary `json:"servers"`         // Most started first
+}
+
+// Summarize sums the counts of the last days up to now, or of every recorded day
+// when days is 0
+func (u *Usage) Summarize(now time.Time, days int) Summary {
+	first := ""
+	if days > 0 {
+		first = now.AddDate(0, 0, 1-days).Format(dayFormat)
+	}
+
+	commands := make(map[string]int)
+	servers := make(map[string]*ServerSummary)
+	summary := Summary{Commands: []CommandCount{}, Servers: []ServerSummary{}}
+	for _, date := range u.days() {
+		if date < first {
+			continue
+		}
+		if summary.Since == "" {
+			summary.Since = date
+		}
+		day := u.Days[date]
+		for command, count := range day.Commands {
+			commands[command] += count
+		}
+		for name, counts := range day.Servers {
+			server, ok := servers[name]
+			if !ok {
+				server = &ServerSummary{Name: name}
+				servers[name] = server
+			}
+			server.Starts += counts.Starts
+			server.Failures += counts.Failures
+			if counts.Failures > 0 {
+				server.LastFailure = date
+			}
+		}
+	}
+
+	for command, count := range commands {
+		summary.Commands = append(summary.Commands, CommandCount{Command: command, Count: count})
+	}
+	sort.Slice(summary.Commands, func(i, j int) bool {
+		a, b := summary.Commands[i], summary.Commands[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Command < b.Command
+	})
+
+	for _, server := range servers {
+		summary.Servers = append(summary.Servers, *server)
+	}
+	sort.Slice(summary.Servers, func(i, j int) bool {
+		a, b := summary.Servers[i], summary.Servers[j]
+		if a.Attempts() != b.Attempts() {
+			return a.Attempts() > b.Attempts()
+		}
+		return a.Name < b.Name
+	})
+	return summary
+}
+
+// Flaky returns the servers of the summary that failed to start at least once,
+// the highest failure rate first
+func (s Summary) Flaky() []ServerSummary {
+	var flaky []ServerSummary
+	for _, server := range s.Servers {
+		if server.Failures > 0 {
+			flaky = append(flaky, server)
+		}
+	}
+	sort.SliceStable(flaky, func(i, j int) bool {
+		if flaky[i].FailureRate() != flaky[j].FailureRate() {
+			return flaky[i].FailureRate() > flaky[j].FailureRate()
+		}
+		return flaky[i].Failures > flaky[j].Failures
+	})
+	return flaky
+}
@@ -0,0 +1,76 @@
+package usage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	u, err := Load(filepath.Join(t.TempDir(), "usage.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	u.RecordCommand("start", now.AddDate(0, 0, -40))
+	u.RecordStart("old", false, now.AddDate(0, 0, -40))
+	for i := 0; i < 3; i++ {
+		u.RecordCommand("start", now.AddDate(0, 0, -i))
+		u.RecordStart("github", true, now.AddDate(0, 0, -i))
+	}
+	u.RecordCommand("config add", now)
+	u.RecordStart("slack", true, now.AddDate(0, 0, -5))
+	u.RecordStart("slack", false, now.AddDate(0, 0, -2))
+	u.RecordStart("github", false, now.AddDate(0, 0, -1))
+
+	summary := u.Summarize(now, 30)
+	if summary.Since != "2026-03-05" {
+		t.Errorf("Since = %q, want the first day with counts", summary.Since)
+	}
+	if len(summary.Commands) != 2 || summary.Commands[0] != (CommandCount{"start", 3}) {
+		t.Errorf("Commands = %+v, want start counted 3 times first", summary.Commands)
+	}
+	if len(summary.Servers) != 2 || summary.Servers[0].Name != "github" || summary.Servers[0].Attempts() != 4 {
+		t.Errorf("Servers = %+v, want github with 4 starts first", summary.Servers)
+	}
+
+	flaky := summary.Flaky()
+	if len(flaky) != 2 || flaky[0].Name != "slack" || flaky[0].FailureRate() != 0.5 || flaky[0].LastFailure != "2026-03-08" {
+		t.Errorf("Flaky() = %+v, want slack at 50%% first", flaky)
+	}
+
+	if all := u.Summarize(now, 0); len(all.Servers) != 3 || all.Commands[0].Count != 4 {
+		t.Errorf("Summarize(0) = %+v, want every recorded day", all)
+	}
+}
+
+func TestSaveLoadForget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "usage.json")
+	u, _ := Load(path)
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local)
+	for i := 0; i < maxDays+5; i++ {
+		u.RecordStart("github", true, start.AddDate(0, 0, i))
+	}
+	if err := u.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded.Days) != maxDays {
+		t.Errorf("kept %d days, want %d", len(loaded.Days), maxDays)
+	}
+	if _, ok := loaded.Days["2025-01-01"]; ok {
+		t.Error("the oldest days should be dropped")
+	}
+
+	if !loaded.Forget("github") || loaded.Forget("github") {
+		t.Error("Forget() should report whether the server had counts")
+	}
+	if summary := loaded.Summarize(start, 0); len(summary.Servers) != 0 {
+		t.Errorf("Servers = %+v after Forget()", summary.Servers)
+	}
+}
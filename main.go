@@ -1,15 +1,28 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
-	"cmcp/cmd"
+	"github.com/lopezm94/cmcp/cmd"
+	"github.com/lopezm94/cmcp/internal/mcp"
+	"github.com/lopezm94/cmcp/internal/plugin"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
+		// Plugins report their own errors; just pass their exit status through
+		var exitErr *plugin.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		// Pass the exit status of a server run in the foreground through
+		var serverExitErr *mcp.ExitError
+		if errors.As(err, &serverExitErr) {
+			os.Exit(serverExitErr.Code)
+		}
 		os.Exit(1)
 	}
 }
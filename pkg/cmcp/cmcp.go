@@ -0,0 +1,183 @@
+// Package cmcp is the programmatic API for managing MCP servers with cmcp.
+//
+// It covers loading and saving the cmcp config, starting and stopping servers in
+// Claude for the current project, querying their status, and diagnosing failures,
+// without depending on cobra or exiting the process. Server lifecycle calls still
+// run the claude CLI; their messages go to Options.Output rather than stdout.
+package cmcp
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/lopezm94/cmcp/internal/config"
+	"github.com/lopezm94/cmcp/internal/mcp"
+)
+
+// Server is a single MCP server definition from the cmcp config
+type Server = config.MCPServer
+
+// Config is the cmcp config file contents
+type Config = config.Config
+
+// Status is the connection state of a server registered in Claude
+type Status = mcp.ServerStatus
+
+// Diagnostics describes why a server may be failing and how to fix it
+type Diagnostics = mcp.DiagnosticInfo
+
+// CommandRunner runs the claude CLI; supply one to Options to intercept calls
+type CommandRunner = mcp.CommandRunner
+
+// Errors returned by Manager, for matching with errors.Is
+var (
+	ErrServerNotFound      = mcp.ErrServerNotFound
+	ErrClaudeUnavailable   = mcp.ErrClaudeUnavailable
+	ErrVerificationTimeout = mcp.ErrVerificationTimeout
+	ErrNoTools             = mcp.ErrNoTools
+)
+
+// ServerNotFoundError names a server missing from the config or from Claude
+type ServerNotFoundError = config.ServerNotFoundError
+
+// ClaudeUnavailableError is returned when the claude binary cannot be run
+type ClaudeUnavailableError = mcp.ClaudeUnavailableError
+
+// CommandError is a claude command that failed for a server
+type CommandError = mcp.CommandError
+
+// VerificationError is a server that had not connected when verification gave up
+type VerificationError = mcp.VerificationError
+
+// ProbeError is a server that connected but failed the tools/list probe of Options.DeepVerify
+type ProbeError = mcp.ProbeError
+
+// PreflightError is a docker server that failed its checks before being added to Claude
+type PreflightError = mcp.PreflightError
+
+// DiagnosticError is a start failure with diagnostics explaining it
+type DiagnosticError = mcp.DiagnosticError
+
+// ClaudeTimeoutError is a claude command killed for running past Options.ClaudeTimeout
+type ClaudeTimeoutError = mcp.ClaudeTimeoutError
+
+// Options configures a Manager
+type Options struct {
+	// ConfigPath overrides the config location (default ~/.cmcp/config.json
+	// or $CMCP_CONFIG_PATH). It applies process-wide.
+	ConfigPath string
+
+	// Runner executes claude commands; nil runs ClaudeBin
+	Runner CommandRunner
+
+	// ClaudeBin is the claude binary to run, as a command on PATH or a path
+	// (default claude on PATH)
+	ClaudeBin string
+
+	// Verbose streams claude's debug output to Output
+	Verbose bool
+
+	// Output receives the messages printed while starting and stopping servers, such
+	// as the files claude modified or the command that failed; nil discards them
+	Output io.Writer
+
+	// ClaudeTimeout limits how long each claude command may run (default 30s or
+	// $CMCP_CLAUDE_TIMEOUT). It applies process-wide.
+	ClaudeTimeout time.Duration
+
+	// DeepVerify makes Start also connect to the server directly and fail it when
+	// tools/list errors or is empty (ErrNoTools)
+	DeepVerify bool
+}
+
+// Manager manages MCP servers in Claude for the current project
+type Manager struct {
+	opts Options
+}
+
+// New creates a Manager
+func New(opts Options) *Manager {
+	if opts.ConfigPath != "" {
+		config.SetConfigPath(opts.ConfigPath)
+	}
+	if opts.ClaudeTimeout > 0 {
+		mcp.SetClaudeTimeout(opts.ClaudeTimeout)
+	}
+	return &Manager{opts: opts}
+}
+
+// builder returns a claude command builder bound to ctx
+func (m *Manager) builder(ctx context.Context) *mcp.ClaudeCmdBuilder {
+	b := mcp.NewClaudeCmdBuilder()
+	b.Context = ctx
+	b.Runner = m.opts.Runner
+	b.ClaudeBin = m.opts.ClaudeBin
+	b.DeepVerify = m.opts.DeepVerify
+	b.Stdout, b.Stderr = io.Discard, io.Discard
+	if m.opts.Output != nil {
+		b.Stdout, b.Stderr = m.opts.Output, m.opts.Output
+	}
+	return b
+}
+
+// ConfigPath returns the location of the cmcp config file
+func (m *Manager) ConfigPath() string {
+	path, _ := config.GetConfigPath()
+	return path
+}
+
+// LoadConfig reads the cmcp config; a missing file yields an empty config
+func (m *Manager) LoadConfig() (*Config, error) {
+	return config.Load()
+}
+
+// SaveConfig writes the cmcp config
+func (m *Manager) SaveConfig(cfg *Config) error {
+	return config.Save(cfg)
+}
+
+// Start registers a server from the config with Claude and waits until it connects,
+// retrying as many times as the server's retries setting allows
+func (m *Manager) Start(ctx context.Context, name string, server *Server) error {
+	return m.builder(ctx).StartServerWithRetry(name, server, m.opts.Verbose, server.Retries)
+}
+
+// claudeName returns the name a configured server is registered under in Claude,
+// following its alias
+func (m *Manager) claudeName(name string) string {
+	if cfg, err := config.Load(); err == nil {
+		if server, ok := cfg.FindServer(name); ok {
+			return server.ClaudeName(name)
+		}
+	}
+	return name
+}
+
+// Stop removes a server from Claude. Configured servers with an alias are removed
+// under the alias.
+func (m *Manager) Stop(ctx context.Context, name string) error {
+	return m.builder(ctx).StopServer(m.claudeName(name), m.opts.Verbose)
+}
+
+// IsRunning reports whether a server is registered in Claude, under its alias if it
+// has one
+func (m *Manager) IsRunning(ctx context.Context, name string) bool {
+	return m.builder(ctx).IsRunning(m.claudeName(name))
+}
+
+// Statuses returns the connection status of every server registered in Claude,
+// marking which of them are defined in cfg and under which name (ConfigName)
+func (m *Manager) Statuses(ctx context.Context, cfg *Config) ([]Status, error) {
+	return m.builder(ctx).GetServerStatuses(cfg)
+}
+
+// Diagnose gathers diagnostics for a server that fails to connect
+func (m *Manager) Diagnose(ctx context.Context, name string, server *Server) (*Diagnostics, error) {
+	return m.builder(ctx).Diagnose(name, server)
+}
+
+// FormatDiagnostics renders diagnostics as plain text for display
+func FormatDiagnostics(diag *Diagnostics) string {
+	return mcp.FormatDiagnostics(diag)
+}
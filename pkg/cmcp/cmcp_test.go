@@ -0,0 +1,96 @@
+package cmcp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// scriptedRunner answers claude commands without running the binary
+type scriptedRunner struct {
+	calls [][]string
+}
+
+func (r *scriptedRunner) Run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	r.calls = append(r.calls, args)
+	switch args[1] {
+	case "list":
+		io.WriteString(stdout, "Checking MCP server health...\n\ngithub: npx -y server-github - ✓ Connected\nstray: uvx stray - ✗ Failed to connect\n")
+		return nil
+	case "get":
+		if args[len(args)-1] == "github" {
+			return nil
+		}
+		return fmt.Errorf("No MCP server found with name: %s", args[len(args)-1])
+	case "remove":
+		io.WriteString(stdout, "Removed MCP server github\nFile modified: /work/.claude.json\n")
+		return nil
+	}
+	return fmt.Errorf("unexpected command: %v", args)
+}
+
+func TestManagerConfigRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	m := New(Options{ConfigPath: path, Runner: &scriptedRunner{}})
+
+	if m.ConfigPath() != path {
+		t.Fatalf("ConfigPath() = %q, want %q", m.ConfigPath(), path)
+	}
+
+	cfg, err := m.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	cfg.MCPServers["github"] = Server{Command: "npx", Args: []string{"-y", "server-github"}}
+	if err := m.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	loaded, err := m.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if server, ok := loaded.FindServer("github"); !ok || server.Command != "npx" {
+		t.Errorf("loaded config = %+v, want github server", loaded.MCPServers)
+	}
+}
+
+func TestManagerStatusesAndStop(t *testing.T) {
+	runner := &scriptedRunner{}
+	var output bytes.Buffer
+	m := New(Options{ConfigPath: filepath.Join(t.TempDir(), "config.json"), Runner: runner, Output: &output})
+	ctx := context.Background()
+
+	cfg := &Config{MCPServers: map[string]Server{"github": {Command: "npx"}}}
+	statuses, err := m.Statuses(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Statuses() error = %v", err)
+	}
+	if len(statuses) != 2 || statuses[0].Status != "connected" || !statuses[0].InConfig ||
+		statuses[1].Status != "failed" || statuses[1].InConfig {
+		t.Errorf("Statuses() = %+v", statuses)
+	}
+
+	if !m.IsRunning(ctx, "github") || m.IsRunning(ctx, "missing") {
+		t.Error("IsRunning() did not reflect claude mcp get")
+	}
+
+	if err := m.Stop(ctx, "missing"); !errors.Is(err, ErrServerNotFound) {
+		t.Errorf("Stop(missing) error = %v, want ErrServerNotFound", err)
+	}
+	if err := m.Stop(ctx, "github"); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	last := runner.calls[len(runner.calls)-1]
+	if last[1] != "remove" || last[len(last)-1] != "github" {
+		t.Errorf("last call = %v, want mcp remove github", last)
+	}
+	if !strings.Contains(output.String(), "File modified: /work/.claude.json") {
+		t.Errorf("Output = %q, want claude's notice", output.String())
+	}
+}
@@ -41,6 +41,9 @@ run_test_section "Unit Tests" "go test ./..."
 
 # 2. The unit tests above already cover dry-run functionality
 
+# 3. End-to-end tests against the mock claude CLI (temp config and state only)
+run_test_section "End-to-End Tests (mock claude)" "CMCP_MOCK=1 go test ./tests/e2e/"
+
 # Summary
 echo -e "${BLUE}=== Test Summary ===${NC}"
 if [ $OVERALL_STATUS -eq 0 ]; then
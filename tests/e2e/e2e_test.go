@@ -0,0 +1,374 @@
+// Package e2e drives the real cmcp binary against the mock claude CLI and mock
+// MCP servers. The tests only run with CMCP_MOCK=1, since they build binaries.
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lopezm94/cmcp/internal/mcp"
+)
+
+// binDir holds the cmcp, claude, and mock server binaries built in TestMain
+var binDir string
+
+func TestMain(m *testing.M) {
+	if os.Getenv("CMCP_MOCK") != "1" {
+		fmt.Println("skipping end-to-end tests (set CMCP_MOCK=1 to run)")
+		os.Exit(0)
+	}
+
+	dir, err := os.MkdirTemp("", "cmcp-e2e-bin")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create bin dir: %v\n", err)
+		os.Exit(1)
+	}
+	binDir = dir
+
+	builds := []struct {
+		dir  string
+		args []string
+	}{
+		{"../..", []string{"build", "-o", filepath.Join(dir, "cmcp"), "."}},
+		{"../mock-claude", []string{"build", "-o", filepath.Join(dir, "claude"), "."}},
+		{"../mock-servers", []string{"build", "-o", filepath.Join(dir, "mock-mcp-basic"), "mock-mcp-basic.go"}},
+		{"../mock-servers", []string{"build", "-o", filepath.Join(dir, "mock-mcp-scenario"), "mock-mcp-scenario.go"}},
+	}
+	for _, b := range builds {
+		cmd := exec.Command("go", b.args...)
+		cmd.Dir = b.dir
+		cmd.Env = append(os.Environ(), "CGO_ENABLED=0")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to build %s: %v\n%s", b.dir, err, output)
+			os.RemoveAll(dir)
+			os.Exit(1)
+		}
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// harness is an isolated cmcp config and mock claude state for one test
+type harness struct {
+	t    *testing.T
+	home string
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+	h := &harness{t: t, home: t.TempDir()}
+	h.writeConfig(map[string]interface{}{
+		"basic":  map[string]interface{}{"command": filepath.Join(binDir, "mock-mcp-basic")},
+		"second": map[string]interface{}{"command": filepath.Join(binDir, "mock-mcp-basic"), "args": []string{"--second"}},
+		"broken": map[string]interface{}{"command": "nonexistent-command", "args": []string{"--fail"}},
+		"tools":  scenarioServer("healthy.json"),
+		"hung":   scenarioServer("unresponsive.json"),
+	})
+	return h
+}
+
+// scenarioServer returns a config entry running mock-mcp-scenario with a scenario file
+func scenarioServer(scenario string) map[string]interface{} {
+	path, _ := filepath.Abs(filepath.Join("..", "mock-servers", "scenarios", scenario))
+	return map[string]interface{}{
+		"command": filepath.Join(binDir, "mock-mcp-scenario"),
+		"args":    []string{"--scenario", path},
+	}
+}
+
+// writeConfig replaces the cmcp config with the given servers
+func (h *harness) writeConfig(servers map[string]interface{}) {
+	h.t.Helper()
+
+	data, _ := json.MarshalIndent(map[string]interface{}{"mcpServers": servers}, "", "  ")
+	if err := os.WriteFile(filepath.Join(h.home, "config.json"), data, 0644); err != nil {
+		h.t.Fatalf("failed to write config: %v", err)
+	}
+}
+
+// run executes cmcp with the given stdin and returns its combined output and exit code
+func (h *harness) run(stdin string, args ...string) (string, int) {
+	h.t.Helper()
+
+	cmd := exec.Command(filepath.Join(binDir, "cmcp"), args...)
+	cmd.Dir = h.home
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Env = append(os.Environ(),
+		"PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"),
+		"HOME="+h.home,
+		"CMCP_CONFIG_PATH="+filepath.Join(h.home, "config.json"),
+		"MOCK_CLAUDE_STATE="+filepath.Join(h.home, "claude-state.json"),
+		"NO_COLOR=1",
+	)
+
+	output, err := cmd.CombinedOutput()
+	code := 0
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		code = exitErr.ExitCode()
+	} else if err != nil {
+		h.t.Fatalf("failed to run cmcp %v: %v", args, err)
+	}
+	return string(output), code
+}
+
+// mustRun runs cmcp, requires a zero exit code, and checks the output contains each string
+func (h *harness) mustRun(stdin string, args []string, contains ...string) string {
+	h.t.Helper()
+
+	output, code := h.run(stdin, args...)
+	if code != 0 {
+		h.t.Fatalf("cmcp %v exited with %d:\n%s", args, code, output)
+	}
+	for _, want := range contains {
+		if !strings.Contains(output, want) {
+			h.t.Errorf("cmcp %v output missing %q:\n%s", args, want, output)
+		}
+	}
+	return output
+}
+
+func TestStartOnlineStop(t *testing.T) {
+	h := newHarness(t)
+
+	h.mustRun("", []string{"start", "basic"}, "✓ Successfully started server 'basic'", "Started 1 server(s)")
+	h.mustRun("", []string{"online"}, "✓  basic ", "Connected")
+	h.mustRun("", []string{"start", "basic"}, "Server 'basic' is already running.")
+
+	h.mustRun("", []string{"stop", "basic"}, "✓ Successfully stopped server 'basic'")
+	h.mustRun("", []string{"online"}, "No servers are currently running")
+}
+
+func TestStartUnknownServer(t *testing.T) {
+	h := newHarness(t)
+
+	output, code := h.run("", "start", "nope")
+	if code == 0 {
+		t.Fatalf("expected non-zero exit code, got 0:\n%s", output)
+	}
+	if !strings.Contains(output, "server 'nope' not found in configuration") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+}
+
+func TestSelectionWithoutTerminal(t *testing.T) {
+	h := newHarness(t)
+	h.mustRun("", []string{"start", "basic"})
+
+	for _, args := range [][]string{{"start"}, {"stop"}, {"config", "rm"}} {
+		output, code := h.run("", args...)
+		if code == 0 {
+			t.Fatalf("cmcp %v: expected non-zero exit code, got 0:\n%s", args, output)
+		}
+		if !strings.Contains(output, "server names required in non-interactive mode") {
+			t.Errorf("cmcp %v: unexpected output:\n%s", args, output)
+		}
+	}
+
+	// Patterns need no confirmation when no one could give it
+	h.mustRun("", []string{"start", "sec*"}, "Started 1 server(s)")
+	h.mustRun("", []string{"stop", "sec*"}, "✓ Successfully stopped server 'second'")
+}
+
+func TestStartFailingServerAndStopFailed(t *testing.T) {
+	h := newHarness(t)
+
+	h.mustRun("", []string{"start", "broken", "basic"},
+		"✗ Failed to start server 'broken'",
+		"✓ Successfully started server 'basic'",
+		"Failed to start 1 server(s)")
+	h.mustRun("", []string{"online"}, "✗  broken ", "✓  basic ")
+
+	h.mustRun("", []string{"stop", "--failed"}, "✓ Successfully stopped server 'broken'")
+	output := h.mustRun("", []string{"online"}, "✓  basic ")
+	if strings.Contains(output, "broken") {
+		t.Errorf("broken server should have been stopped:\n%s", output)
+	}
+}
+
+func TestReset(t *testing.T) {
+	h := newHarness(t)
+
+	h.mustRun("", []string{"start", "basic", "second"}, "Started 2 server(s)")
+	h.mustRun("", []string{"reset", "--keep", "second", "--yes"}, "  - basic", "Successfully stopped all servers.")
+	h.mustRun("", []string{"online"}, "✓  second ")
+
+	h.mustRun("", []string{"reset", "--yes"}, "Successfully stopped all servers.")
+	h.mustRun("", []string{"online"}, "No servers are currently running")
+}
+
+func TestNonInteractive(t *testing.T) {
+	h := newHarness(t)
+	h.mustRun("", []string{"start", "basic"}, "Started 1 server(s)")
+
+	// Piped answers are ignored; confirmation has to come from --yes
+	output, code := h.run("y\n", "reset", "--non-interactive")
+	if code == 0 || !strings.Contains(output, "confirmation required in non-interactive mode; pass --yes") {
+		t.Fatalf("reset --non-interactive: exit %d, unexpected output:\n%s", code, output)
+	}
+	h.mustRun("", []string{"online"}, "✓  basic ")
+
+	// Without a terminal there is no one to answer either
+	output, code = h.run("y\n", "reset")
+	if code == 0 || !strings.Contains(output, "pass --yes") {
+		t.Fatalf("reset without a terminal: exit %d, unexpected output:\n%s", code, output)
+	}
+
+	h.mustRun("", []string{"reset", "--non-interactive", "--yes"}, "Successfully stopped all servers.")
+	h.mustRun("", []string{"config", "rm", "basic", "--non-interactive", "-y"}, "basic")
+}
+
+func TestOnlineClear(t *testing.T) {
+	h := newHarness(t)
+
+	h.mustRun("", []string{"start", "basic", "second"}, "Started 2 server(s)")
+
+	// Dropping a server from the config file leaves it orphaned in Claude
+	h.writeConfig(map[string]interface{}{
+		"basic": map[string]interface{}{"command": filepath.Join(binDir, "mock-mcp-basic")},
+	})
+	h.mustRun("", []string{"online"}, "⚠ Found 1 server(s) in Claude that are not in your cmcp config", "  - second")
+	h.mustRun("y\n", []string{"online", "--clear"}, "second")
+
+	output := h.mustRun("", []string{"online"}, "✓  basic ")
+	if strings.Contains(output, "second") {
+		t.Errorf("orphaned server should have been cleared:\n%s", output)
+	}
+}
+
+func TestDryRunJSONPlan(t *testing.T) {
+	h := newHarness(t)
+
+	output := h.mustRun("", []string{"start", "basic", "--dry-run", "--json"})
+	var plan mcp.Plan
+	if err := json.Unmarshal([]byte(output), &plan); err != nil {
+		t.Fatalf("dry-run output is not a JSON plan: %v\n%s", err, output)
+	}
+	if len(plan.Steps) != 1 || plan.Steps[0].Server != "basic" || plan.Steps[0].Method != "add" {
+		t.Errorf("plan = %+v, want one add step for basic", plan)
+	}
+
+	// A dry run must not register anything
+	h.mustRun("", []string{"online"}, "No servers are currently running")
+}
+
+func TestInspectCallPing(t *testing.T) {
+	h := newHarness(t)
+
+	h.mustRun("", []string{"inspect", "tools"}, "mock-mcp-scenario", "Tools (2)", "echo", "Add two numbers")
+	h.mustRun("", []string{"call", "tools", "add", "--args", `{"a": 2, "b": 3}`}, "5")
+	h.mustRun("", []string{"ping", "tools", "--count", "2"}, "initialize", "tools/list", "p50")
+}
+
+func TestGateway(t *testing.T) {
+	h := newHarness(t)
+
+	requests := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"tools__add","arguments":{"a":2,"b":3}}}`,
+	}, "\n")
+	// mock-mcp-basic exits after initialize, so it is left out
+	h.mustRun(requests, []string{"gateway", "tools", "basic"},
+		`"name":"cmcp-gateway"`, `"name":"tools__echo"`, `"id":3,"result":{"content":[{"text":"5"`,
+		"skipping 'basic'", "'tools' connected with 2 tool(s)")
+}
+
+func TestShellenv(t *testing.T) {
+	h := newHarness(t)
+	h.writeConfig(map[string]interface{}{
+		"envy": map[string]interface{}{
+			"command": "envy-server",
+			"env":     map[string]interface{}{"API_KEY": "it's secret", "LEVEL": "${CMCP_E2E_LEVEL:-debug}"},
+		},
+	})
+
+	h.mustRun("", []string{"shellenv", "envy"}, `export API_KEY='it'\''s secret'`, "export LEVEL=debug", "Then run: envy-server")
+	h.mustRun("", []string{"shellenv", "envy", "--shell", "fish"}, `set -gx API_KEY 'it\'s secret'`)
+}
+
+func TestStats(t *testing.T) {
+	h := newHarness(t)
+	h.mustRun("", []string{"stats"}, "No usage recorded yet.")
+
+	h.mustRun("", []string{"start", "broken", "basic"})
+	h.mustRun("", []string{"stop", "basic"})
+	h.mustRun("", []string{"start", "basic"})
+	h.mustRun("", []string{"stats"},
+		"Most used commands", "start    2",
+		"Most started servers", "basic   2       0", "broken  1       1",
+		"Most flaky servers", "broken  100% (1/1)")
+	h.mustRun("", []string{"stats", "--clear", "--yes"}, "Cleared the usage stats")
+}
+
+func TestBackupRestore(t *testing.T) {
+	h := newHarness(t)
+	h.mustRun("", []string{"profile", "create", "work"})
+	h.mustRun("", []string{"backup", "create", "bundle.tar.gz"}, "Wrote bundle.tar.gz", "Profiles:   default, work")
+
+	h.writeConfig(map[string]interface{}{})
+	if output, code := h.run("", "--non-interactive", "backup", "restore", "bundle.tar.gz"); code == 0 || !strings.Contains(output, "pass --yes") {
+		t.Errorf("restore over existing files should need --yes, got %d:\n%s", code, output)
+	}
+	h.mustRun("", []string{"backup", "restore", "bundle.tar.gz", "--yes"}, "Restored 2 file(s)")
+	h.mustRun("", []string{"config", "list"}, "basic")
+}
+
+func TestShareAndImportURL(t *testing.T) {
+	h := newHarness(t)
+	h.writeConfig(map[string]interface{}{
+		"github": map[string]interface{}{
+			"command": "npx",
+			"env":     map[string]interface{}{"GITHUB_TOKEN": "ghp_secret"},
+		},
+	})
+	snippet := h.mustRun("", []string{"share", "github"}, `"GITHUB_TOKEN": "${GITHUB_TOKEN}"`)
+	if strings.Contains(snippet, "ghp_secret") {
+		t.Fatalf("share leaked the secret:\n%s", snippet)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(snippet[:strings.LastIndex(snippet, "}")+1]))
+	}))
+	defer srv.Close()
+
+	h.writeConfig(map[string]interface{}{})
+	h.mustRun("", []string{"config", "import", "--url", srv.URL, "--name", "gh"}, "Added server 'gh'")
+	data, _ := os.ReadFile(filepath.Join(h.home, "config.json"))
+	if !strings.Contains(string(data), `"GITHUB_TOKEN": "${GITHUB_TOKEN}"`) {
+		t.Errorf("imported config = %s", data)
+	}
+}
+
+func TestEnvironmentOverlay(t *testing.T) {
+	h := newHarness(t)
+	overlay := `{"mcpServers": {"basic": {"env": {"STAGE": "staging"}}}}`
+	if err := os.WriteFile(filepath.Join(h.home, "config.staging.json"), []byte(overlay), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	h.mustRun("", []string{"env", "basic", "--env-name", "staging"}, "STAGE", "staging")
+	if output, code := h.run("", "config", "list", "--env-name", "prod"); code == 0 || !strings.Contains(output, "config.prod.json") {
+		t.Errorf("an environment without an overlay should fail, got %d:\n%s", code, output)
+	}
+}
+
+func TestInspectUnresponsiveServer(t *testing.T) {
+	h := newHarness(t)
+
+	output, code := h.run("", "inspect", "hung", "--timeout", "1s")
+	if code == 0 {
+		t.Fatalf("expected inspect of an unresponsive server to fail:\n%s", output)
+	}
+	if !strings.Contains(output, "failed to connect to server 'hung'") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+}
@@ -0,0 +1,18 @@
+#!/bin/bash
+# Build the mock claude CLI for testing
+
+echo "Building mock claude..."
+
+# Go to the directory containing the mock claude
+cd "$(dirname "$0")"
+
+# The binary is named claude so it can shadow the real CLI via PATH
+CGO_ENABLED=0 go build -o claude main.go
+if [ $? -eq 0 ]; then
+    echo "✓ Built mock claude"
+else
+    echo "✗ Failed to build mock claude"
+    exit 1
+fi
+
+echo "Mock claude built successfully!"
@@ -0,0 +1,3 @@
+module mock-claude
+
+go 1.21
@@ -0,0 +1,255 @@
+// Mock claude CLI implementing the `claude mcp` subcommands cmcp uses.
+//
+// Servers are kept in a JSON state file (MOCK_CLAUDE_STATE, default
+// $TMPDIR/mock-claude-state.json). A server is reported as failed when its
+// name is listed in MOCK_CLAUDE_FAILING (comma-separated) or its command is
+// not found on PATH; remote servers are always connected.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type server struct {
+	Name    string            `json:"name"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	Type    string            `json:"type,omitempty"`
+	URL     string            `json:"url,omitempty"`
+}
+
+func statePath() string {
+	if path := os.Getenv("MOCK_CLAUDE_STATE"); path != "" {
+		return path
+	}
+	return filepath.Join(os.TempDir(), "mock-claude-state.json")
+}
+
+func load() []server {
+	var servers []server
+	data, err := os.ReadFile(statePath())
+	if err == nil {
+		json.Unmarshal(data, &servers)
+	}
+	return servers
+}
+
+func save(servers []server) {
+	data, _ := json.MarshalIndent(servers, "", "  ")
+	if err := os.WriteFile(statePath(), data, 0644); err != nil {
+		fail("failed to write state: %v", err)
+	}
+}
+
+func find(servers []server, name string) int {
+	for i, s := range servers {
+		if s.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
+
+// stripFlags removes --debug and scope flags, which the mock ignores
+func stripFlags(args []string) []string {
+	var result []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--debug":
+			continue
+		case "-s", "--scope":
+			i++
+			continue
+		case "--":
+			return append(result, args[i:]...)
+		}
+		result = append(result, args[i])
+	}
+	return result
+}
+
+func failing(s server) bool {
+	for _, name := range strings.Split(os.Getenv("MOCK_CLAUDE_FAILING"), ",") {
+		if name == s.Name {
+			return true
+		}
+	}
+	if s.URL != "" {
+		return false
+	}
+	_, err := exec.LookPath(s.Command)
+	return err != nil
+}
+
+func describe(s server) string {
+	if s.URL != "" {
+		transport := strings.ToUpper(s.Type)
+		if transport == "" {
+			transport = "HTTP"
+		}
+		return fmt.Sprintf("%s (%s)", s.URL, transport)
+	}
+	return strings.TrimSpace(s.Command + " " + strings.Join(s.Args, " "))
+}
+
+func add(args []string) {
+	// add <name> [--env K=V]... -- <command> [args...]
+	if len(args) == 0 {
+		fail("error: missing required argument 'name'")
+	}
+	s := server{Name: args[0], Env: map[string]string{}}
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "-e", "--env":
+			if i+1 < len(args) {
+				kv := strings.SplitN(args[i+1], "=", 2)
+				if len(kv) == 2 {
+					s.Env[kv[0]] = kv[1]
+				}
+				i++
+			}
+		case "--":
+			if i+1 < len(args) {
+				s.Command = args[i+1]
+				s.Args = args[i+2:]
+			}
+			i = len(args)
+		default:
+			s.Command = args[i]
+			s.Args = args[i+1:]
+			i = len(args)
+		}
+	}
+	if s.Command == "" {
+		fail("error: missing required argument 'commandOrUrl'")
+	}
+	register(s)
+	fmt.Printf("Added stdio MCP server %s with command: %s to local config\n", s.Name, describe(s))
+	fmt.Printf("File modified: %s\n", statePath())
+}
+
+func addJSON(args []string) {
+	// add-json <name> <json>
+	if len(args) < 2 {
+		fail("error: missing required argument 'json'")
+	}
+	s := server{Name: args[0]}
+	if err := json.Unmarshal([]byte(args[1]), &s); err != nil {
+		fail("Error: Invalid JSON: %v", err)
+	}
+	s.Name = args[0]
+	register(s)
+	transport := "stdio"
+	if s.Type != "" {
+		transport = s.Type
+	}
+	fmt.Printf("Added %s MCP server %s to local config\n", transport, s.Name)
+	fmt.Printf("File modified: %s\n", statePath())
+}
+
+func register(s server) {
+	servers := load()
+	if find(servers, s.Name) >= 0 {
+		fail("MCP server %s already exists in local config", s.Name)
+	}
+	save(append(servers, s))
+}
+
+func remove(args []string) {
+	if len(args) == 0 {
+		fail("error: missing required argument 'name'")
+	}
+	servers := load()
+	i := find(servers, args[0])
+	if i < 0 {
+		fail("No MCP server found with name: %s", args[0])
+	}
+	save(append(servers[:i], servers[i+1:]...))
+	fmt.Printf("Removed MCP server %s from local config\n", args[0])
+	fmt.Printf("File modified: %s\n", statePath())
+}
+
+func get(args []string) {
+	if len(args) == 0 {
+		fail("error: missing required argument 'name'")
+	}
+	servers := load()
+	i := find(servers, args[0])
+	if i < 0 {
+		fail("No MCP server found with name: %s", args[0])
+	}
+	s := servers[i]
+	fmt.Printf("%s:\n  Scope: Local config (private to you in this project)\n", s.Name)
+	if s.URL != "" {
+		fmt.Printf("  Type: %s\n  URL: %s\n", s.Type, s.URL)
+	} else {
+		fmt.Printf("  Type: stdio\n  Command: %s\n  Args: %s\n", s.Command, strings.Join(s.Args, " "))
+	}
+	if len(s.Env) > 0 {
+		keys := make([]string, 0, len(s.Env))
+		for k := range s.Env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fmt.Println("  Environment:")
+		for _, k := range keys {
+			fmt.Printf("    %s=%s\n", k, s.Env[k])
+		}
+	}
+}
+
+func list() {
+	servers := load()
+	if len(servers) == 0 {
+		fmt.Println("No MCP servers configured. Use `claude mcp add` to add a server.")
+		return
+	}
+	fmt.Println("Checking MCP server health...")
+	fmt.Println()
+	for _, s := range servers {
+		status := "✓ Connected"
+		if failing(s) {
+			status = "✗ Failed to connect"
+		}
+		fmt.Printf("%s: %s - %s\n", s.Name, describe(s), status)
+	}
+}
+
+func main() {
+	args := os.Args[1:]
+	if len(args) == 1 && (args[0] == "--version" || args[0] == "-v") {
+		fmt.Println("0.0.0 (mock claude)")
+		return
+	}
+	if len(args) < 2 || args[0] != "mcp" {
+		fail("mock claude only supports: claude mcp <add|add-json|remove|get|list>")
+	}
+
+	rest := stripFlags(args[2:])
+	switch args[1] {
+	case "add":
+		add(rest)
+	case "add-json":
+		addJSON(rest)
+	case "remove":
+		remove(rest)
+	case "get":
+		get(rest)
+	case "list":
+		list()
+	default:
+		fail("error: unknown command '%s'", args[1])
+	}
+}
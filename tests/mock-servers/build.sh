@@ -24,4 +24,13 @@ else
     exit 1
 fi
 
+# Build the scenario-driven mock server as a standalone binary
+CGO_ENABLED=0 go build -o mock-mcp-scenario mock-mcp-scenario.go
+if [ $? -eq 0 ]; then
+    echo "✓ Built mock-mcp-scenario"
+else
+    echo "✗ Failed to build mock-mcp-scenario"
+    exit 1
+fi
+
 echo "Mock servers built successfully!"
\ No newline at end of file
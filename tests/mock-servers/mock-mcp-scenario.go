@@ -0,0 +1,211 @@
+// Mock MCP server with working tools and scripted, time-based behavior.
+//
+// Usage:
+//
+//	mock-mcp-scenario [--latency 500ms] [--scenario scenarios/flaky.json]
+//
+// Without a scenario the server stays healthy. A scenario file lists stages
+// that take effect a given time after startup, e.g. connect normally and
+// then crash after 10 seconds.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+type JSONRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type JSONRPCResponse struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      interface{} `json:"id"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   interface{} `json:"error,omitempty"`
+}
+
+// Stage is a behavior that starts After the given time since startup.
+// Behaviors: "healthy", "slow" (each response waits Delay), "unresponsive"
+// (requests are ignored), "error" (requests fail), "crash" (exit with ExitCode).
+type Stage struct {
+	After    string `json:"after"`
+	Behavior string `json:"behavior"`
+	Delay    string `json:"delay,omitempty"`
+	ExitCode int    `json:"exitCode,omitempty"`
+}
+
+type Scenario struct {
+	StartupLatency string  `json:"startupLatency,omitempty"`
+	Stages         []Stage `json:"stages"`
+}
+
+var tools = []map[string]interface{}{
+	{
+		"name":        "echo",
+		"description": "Echo the given message",
+		"inputSchema": map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"message": map[string]interface{}{"type": "string"}},
+			"required":   []string{"message"},
+		},
+	},
+	{
+		"name":        "add",
+		"description": "Add two numbers",
+		"inputSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"a": map[string]interface{}{"type": "number"},
+				"b": map[string]interface{}{"type": "number"},
+			},
+			"required": []string{"a", "b"},
+		},
+	},
+}
+
+func mustDuration(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid duration %q: %v\n", value, err)
+		os.Exit(1)
+	}
+	return d
+}
+
+func loadScenario(path string) Scenario {
+	var scenario Scenario
+	if path == "" {
+		return scenario
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to read scenario: %v\n", err)
+		os.Exit(1)
+	}
+	if err := json.Unmarshal(data, &scenario); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid scenario: %v\n", err)
+		os.Exit(1)
+	}
+	return scenario
+}
+
+// currentStage returns the last stage whose start time has passed
+func currentStage(stages []Stage, started time.Time) Stage {
+	current := Stage{Behavior: "healthy"}
+	elapsed := time.Since(started)
+	for _, stage := range stages {
+		if elapsed >= mustDuration(stage.After) {
+			current = stage
+		}
+	}
+	return current
+}
+
+func handle(req JSONRPCRequest) (interface{}, interface{}) {
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": "2025-03-26",
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]interface{}{"name": "mock-mcp-scenario", "version": "1.0.0"},
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": tools}, nil
+	case "tools/call":
+		var params struct {
+			Name      string                 `json:"name"`
+			Arguments map[string]interface{} `json:"arguments"`
+		}
+		json.Unmarshal(req.Params, &params)
+		switch params.Name {
+		case "echo":
+			return toolText(fmt.Sprint(params.Arguments["message"]), false), nil
+		case "add":
+			a, _ := params.Arguments["a"].(float64)
+			b, _ := params.Arguments["b"].(float64)
+			return toolText(fmt.Sprint(a+b), false), nil
+		}
+		return toolText("unknown tool: "+params.Name, true), nil
+	}
+	return nil, map[string]interface{}{"code": -32601, "message": "Method not found: " + req.Method}
+}
+
+func toolText(text string, isError bool) map[string]interface{} {
+	return map[string]interface{}{
+		"content": []map[string]interface{}{{"type": "text", "text": text}},
+		"isError": isError,
+	}
+}
+
+func main() {
+	latency := flag.Duration("latency", 0, "Delay before the server starts reading requests")
+	scenarioPath := flag.String("scenario", "", "Scenario file with staged behavior")
+	flag.Parse()
+
+	started := time.Now()
+	scenario := loadScenario(*scenarioPath)
+
+	// Simulate server startup time
+	startup := *latency
+	if scenario.StartupLatency != "" {
+		startup = mustDuration(scenario.StartupLatency)
+	}
+	time.Sleep(startup)
+
+	var mu sync.Mutex
+	writer := bufio.NewWriter(os.Stdout)
+	write := func(resp JSONRPCResponse) {
+		mu.Lock()
+		defer mu.Unlock()
+		data, _ := json.Marshal(resp)
+		fmt.Fprintf(writer, "%s\n", data)
+		writer.Flush()
+	}
+
+	// Crash stages take effect even while no requests arrive
+	for _, stage := range scenario.Stages {
+		if stage.Behavior == "crash" {
+			stage := stage
+			time.AfterFunc(mustDuration(stage.After)-time.Since(started), func() {
+				fmt.Fprintf(os.Stderr, "mock-mcp-scenario: crashing as scripted\n")
+				os.Exit(stage.ExitCode)
+			})
+		}
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req JSONRPCRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil || req.ID == nil {
+			continue // Ignore malformed messages and notifications
+		}
+
+		stage := currentStage(scenario.Stages, started)
+		switch stage.Behavior {
+		case "unresponsive":
+			continue
+		case "error":
+			write(JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Error: map[string]interface{}{"code": -32603, "message": "scripted failure"}})
+			continue
+		case "slow":
+			time.Sleep(mustDuration(stage.Delay))
+		}
+
+		result, rpcErr := handle(req)
+		write(JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr})
+	}
+}